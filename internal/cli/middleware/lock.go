@@ -3,9 +3,11 @@ package middleware
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
 	"github.com/pweiskircher/jira-issue-sync/internal/lock"
+	"github.com/pweiskircher/jira-issue-sync/internal/logging"
 )
 
 type Runner func(ctx context.Context) error
@@ -37,3 +39,33 @@ func WithCommandLock(command contracts.CommandName, locker lock.Locker, next Run
 		return next(ctx)
 	}
 }
+
+// WithLockLogging reports lock wait times and stale-lock recovery at debug
+// level. A nil logger returns locker unchanged.
+func WithLockLogging(locker lock.Locker, logger logging.Logger) lock.Locker {
+	if locker == nil || logger == nil {
+		return locker
+	}
+	return loggingLocker{next: locker, logger: logger}
+}
+
+type loggingLocker struct {
+	next   lock.Locker
+	logger logging.Logger
+}
+
+func (l loggingLocker) Acquire(ctx context.Context) (lock.Lease, error) {
+	started := time.Now()
+	lease, err := l.next.Acquire(ctx)
+	waited := time.Since(started).Round(time.Millisecond)
+	if err != nil {
+		logging.Debugf(l.logger, "lock acquisition failed after %s: %v", waited, err)
+		return nil, err
+	}
+	if lease.RecoveredStale() {
+		logging.Debugf(l.logger, "lock acquired in %s after recovering a stale lock", waited)
+	} else {
+		logging.Debugf(l.logger, "lock acquired in %s", waited)
+	}
+	return lease, nil
+}
@@ -2,18 +2,24 @@ package cli
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/pweiskircher/jira-issue-sync/internal/cli/middleware"
+	"github.com/pweiskircher/jira-issue-sync/internal/clock"
 	"github.com/pweiskircher/jira-issue-sync/internal/commands"
+	"github.com/pweiskircher/jira-issue-sync/internal/config"
 	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
 	"github.com/pweiskircher/jira-issue-sync/internal/lock"
+	"github.com/pweiskircher/jira-issue-sync/internal/logging"
 	"github.com/pweiskircher/jira-issue-sync/internal/output"
 	"github.com/spf13/cobra"
 )
@@ -21,12 +27,27 @@ import (
 type AppContext struct {
 	Stdout  io.Writer
 	Stderr  io.Writer
-	Now     func() time.Time
+	Clock   clock.Clock
 	WorkDir string
+	Logger  logging.Logger
 }
 
 type GlobalFlags struct {
-	JSON bool
+	JSON    bool
+	EnvFile string
+	Debug   bool
+	Color   bool
+	NoColor bool
+	Quiet   bool
+	// NoLock skips the workspace lock for this invocation. It exists for
+	// recovering from a stuck lock and always prints a warning.
+	NoLock bool
+	// Insecure skips TLS certificate verification for Jira requests. It is
+	// meant for dev servers with self-signed certificates and always warns.
+	Insecure bool
+	// Strict makes warning messages on otherwise successful issues exit
+	// non-zero, for CI runs that treat any warning as a failure.
+	Strict bool
 }
 
 type CommandContext struct {
@@ -34,6 +55,8 @@ type CommandContext struct {
 	GlobalFlags *GlobalFlags
 	CommandName contracts.CommandName
 	DryRun      bool
+	// Format is the parsed --format template for list and status.
+	Format *template.Template
 }
 
 func (ctx CommandContext) OutputMode() contracts.OutputMode {
@@ -43,6 +66,39 @@ func (ctx CommandContext) OutputMode() contracts.OutputMode {
 	return contracts.OutputModeHuman
 }
 
+// RenderOptions enables color only for human output; --color and
+// --no-color override NO_COLOR and terminal detection.
+func (ctx CommandContext) RenderOptions() output.RenderOptions {
+	if ctx.OutputMode() != contracts.OutputModeHuman {
+		return output.RenderOptions{}
+	}
+
+	mode := output.ColorAuto
+	if ctx.GlobalFlags != nil {
+		switch {
+		case ctx.GlobalFlags.NoColor:
+			mode = output.ColorNever
+		case ctx.GlobalFlags.Color:
+			mode = output.ColorAlways
+		}
+	}
+	quiet := ctx.GlobalFlags != nil && ctx.GlobalFlags.Quiet
+	target := ctx.App.Stdout
+	if quiet {
+		target = ctx.App.Stderr
+	}
+	return output.RenderOptions{
+		Color:  output.ShouldColor(mode, target, os.LookupEnv),
+		Quiet:  quiet,
+		Format: ctx.Format,
+	}
+}
+
+// ExitOptions applies --strict to exit code resolution.
+func (ctx CommandContext) ExitOptions() output.ExitOptions {
+	return output.ExitOptions{Strict: ctx.GlobalFlags != nil && ctx.GlobalFlags.Strict}
+}
+
 type executionState struct {
 	global      GlobalFlags
 	commandName string
@@ -56,6 +112,15 @@ func (state *executionState) outputMode() contracts.OutputMode {
 	return contracts.OutputModeHuman
 }
 
+// logger routes debug output to stderr when --debug is set; otherwise the
+// app-provided logger (a no-op by default) is used.
+func (state *executionState) logger(app AppContext) logging.Logger {
+	if state.global.Debug {
+		return logging.New(app.Stderr, logging.LevelDebug)
+	}
+	return app.Logger
+}
+
 func (state *executionState) resolvedCommandName() string {
 	if state.commandName != "" {
 		return state.commandName
@@ -63,6 +128,13 @@ func (state *executionState) resolvedCommandName() string {
 	return "root"
 }
 
+var (
+	runPullCommand = commands.RunPull
+	runSyncCommand = commands.RunSync
+
+	runDiffRemoteCommand = commands.RunDiffRemote
+)
+
 type commandDefinition struct {
 	Name           contracts.CommandName
 	Short          string
@@ -71,7 +143,7 @@ type commandDefinition struct {
 
 var mvpCommandDefinitions = []commandDefinition{
 	{Name: contracts.CommandInit, Short: "Initialize local issue sync workspace"},
-	{Name: contracts.CommandPull, Short: "Pull Jira issues into local Markdown files"},
+	{Name: contracts.CommandPull, Short: "Pull Jira issues into local Markdown files", SupportsDryRun: true},
 	{Name: contracts.CommandPush, Short: "Push local issue changes to Jira", SupportsDryRun: true},
 	{Name: contracts.CommandSync, Short: "Push local changes then pull remote updates", SupportsDryRun: true},
 	{Name: contracts.CommandStatus, Short: "Show local issue modification status"},
@@ -81,6 +153,10 @@ var mvpCommandDefinitions = []commandDefinition{
 	{Name: contracts.CommandView, Short: "Render a local issue"},
 	{Name: contracts.CommandDiff, Short: "Show local issue diff against last synced snapshot"},
 	{Name: contracts.CommandFields, Short: "List Jira fields and custom field IDs"},
+	{Name: contracts.CommandFsck, Short: "Check local workspace files, snapshots, and cache for consistency"},
+	{Name: contracts.CommandExplain, Short: "Print stable exit codes and reason codes as JSON"},
+	{Name: contracts.CommandConfig, Short: "Check the config for likely mistakes (config lint)"},
+	{Name: contracts.CommandSchema, Short: "Print the issue file front matter schema as JSON Schema"},
 }
 
 // Run executes the CLI using shared output and exit-code plumbing.
@@ -88,7 +164,7 @@ func Run(args []string, stdout io.Writer, stderr io.Writer) int {
 	app := normalizeAppContext(AppContext{
 		Stdout: stdout,
 		Stderr: stderr,
-		Now:    time.Now,
+		Clock:  clock.System(),
 	})
 
 	root, state := newRootCommand(app)
@@ -121,8 +197,6 @@ func NewRootCommand(app AppContext) *cobra.Command {
 func newRootCommand(app AppContext) (*cobra.Command, *executionState) {
 	app = normalizeAppContext(app)
 	state := &executionState{}
-	lockPath := filepath.Join(app.WorkDir, contracts.DefaultLockFilePath)
-	locker := lock.NewFileLock(lockPath, lock.Options{})
 
 	root := &cobra.Command{
 		Use:           "jira-issue-sync",
@@ -132,47 +206,79 @@ func newRootCommand(app AppContext) (*cobra.Command, *executionState) {
 	}
 
 	root.PersistentFlags().BoolVar(&state.global.JSON, "json", false, "emit machine-readable JSON envelope output")
+	root.PersistentFlags().BoolVar(&state.global.Color, "color", false, "force colorized human output")
+	root.PersistentFlags().BoolVar(&state.global.NoColor, "no-color", false, "disable colorized human output")
+	root.MarkFlagsMutuallyExclusive("color", "no-color")
+	root.PersistentFlags().BoolVar(&state.global.Quiet, "quiet", false, "human mode: print only error and conflict diagnostics to stderr")
+	root.PersistentFlags().BoolVar(&state.global.Debug, "debug", false, "write debug logs to stderr")
+	root.PersistentFlags().StringVar(&state.global.EnvFile, "env-file", "", "load Jira credentials from a dotenv file (process environment wins)")
+	root.PersistentFlags().BoolVar(&state.global.NoLock, "no-lock", false, "skip the workspace lock for this run (unsafe; concurrent runs may corrupt state)")
+	root.PersistentFlags().BoolVar(&state.global.Insecure, "insecure", false, "skip TLS certificate verification for Jira requests (unsafe; dev servers only)")
+	root.PersistentFlags().BoolVar(&state.global.Strict, "strict", false, "exit non-zero when any issue reports a warning")
 
 	for _, def := range mvpCommandDefinitions {
-		root.AddCommand(newStubCommand(app, state, def, locker))
+		root.AddCommand(newStubCommand(app, state, def))
 	}
 
 	return root, state
 }
 
-func newStubCommand(app AppContext, state *executionState, def commandDefinition, locker lock.Locker) *cobra.Command {
+func newStubCommand(app AppContext, state *executionState, def commandDefinition) *cobra.Command {
 	dryRun := false
 	stateFilter := "all"
 	keyFilter := ""
+	var reasonFilter []string
 	includeUnchanged := false
+	formatFlag := ""
+	listLimit := 0
+	outputDir := ""
 
 	initProjectKey := ""
 	initProfile := "default"
-	initBaseURL := ""
-	initEmail := ""
+	jiraBaseURL := ""
+	jiraEmail := ""
 	initDefaultJQL := ""
 	initProfileJQL := ""
+	initIssuesDir := ""
 	initForce := false
+	initDiscover := false
 
 	newSummary := ""
-	newIssueType := "Task"
+	newIssueType := ""
 	newStatus := "Open"
 	newPriority := ""
 	newAssignee := ""
 	newLabels := ""
 	newBody := ""
+	newOpen := false
+	newRenumber := false
 
 	editEditor := ""
 	pushProfile := ""
+	pushChangedSince := ""
+	var pushExclude []string
+	pushNoTransition := false
+	maxErrors := 0
+	maxResultsTotal := 0
 	pullProfile := ""
 	pullJQL := ""
+	pullJQLFile := ""
 	pullPageSize := 0
 	pullConcurrency := 0
+	pullWatch := false
+	pullFlatten := false
+	pullInterval := defaultPullWatchInterval
+	recordPath := ""
+	var pullRepair []string
 	syncProfile := ""
 	syncJQL := ""
+	syncJQLFile := ""
 	syncPageSize := 0
 	syncConcurrency := 0
+	stopOnConflict := false
 	fieldsProfile := ""
+	diffRemote := false
+	diffProfile := ""
 	fieldsAll := false
 	fieldsSearch := ""
 
@@ -184,56 +290,149 @@ func newStubCommand(app AppContext, state *executionState, def commandDefinition
 			state.dryRun = dryRun
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			runner := middleware.WithCommandLock(def.Name, locker, func(ctx context.Context) error {
-				start := app.Now()
+			if def.Name == contracts.CommandExplain {
+				return writeExplain(app.Stdout)
+			}
+			if def.Name == contracts.CommandSchema {
+				return writeSchema(app.Stdout)
+			}
+
+			if def.Name == contracts.CommandPull {
+				err := validatePullWatch(pullWatch, pullInterval, cmd.Flags().Changed("interval"))
+				if err == nil && pullWatch && len(pullRepair) > 0 {
+					err = &config.ResolveError{Code: config.ResolveErrorCodeInvalidFlag, Message: "--repair-snapshots cannot be combined with --watch"}
+				}
+				if err != nil {
+					context := CommandContext{App: app, GlobalFlags: &state.global, CommandName: def.Name, DryRun: dryRun}
+					return renderAndResolveExit(context, output.Report{CommandName: string(def.Name), DryRun: dryRun}, 0, err)
+				}
+			}
+
+			var format *template.Template
+			if formatFlag != "" {
+				parsed, err := output.ParseFormat(formatFlag)
+				if err != nil {
+					context := CommandContext{App: app, GlobalFlags: &state.global, CommandName: def.Name, DryRun: dryRun}
+					return renderAndResolveExit(context, output.Report{CommandName: string(def.Name), DryRun: dryRun}, 0, &config.ResolveError{Code: config.ResolveErrorCodeInvalidFlag, Message: err.Error()})
+				}
+				format = parsed
+			}
+
+			watchCycle := 0
+			logger := state.logger(app)
+			locker := lock.NewFileLock(workspaceLockPath(app.WorkDir), lock.Options{Clock: app.Clock})
+			commandLocker := middleware.WithLockLogging(locker, logger)
+			if state.global.NoLock && contracts.RequiresLock(def.Name) {
+				_, _ = fmt.Fprintln(app.Stderr, noLockWarning)
+				commandLocker = nil
+			}
+			if state.global.Insecure && callsJira(def.Name, diffRemote) {
+				_, _ = fmt.Fprintln(app.Stderr, insecureWarning)
+			}
+			runner := middleware.WithCommandLock(def.Name, commandLocker, func(ctx context.Context) error {
+				start := app.Clock.Now()
 				context := CommandContext{
 					App:         app,
 					GlobalFlags: &state.global,
 					CommandName: def.Name,
 					DryRun:      dryRun,
+					Format:      format,
+				}
+
+				environment, envErr := resolveEnvironment(app.WorkDir, state.global.EnvFile)
+				if envErr != nil {
+					return renderAndResolveExit(context, output.Report{CommandName: string(def.Name), DryRun: dryRun}, app.Clock.Now().Sub(start), envErr)
 				}
 
-				report, fatalErr, handled := runInspectionCommand(def.Name, app.WorkDir, stateFilter, keyFilter, includeUnchanged)
+				var report output.Report
+				var fatalErr error
+				handled := false
+				// diff --remote needs credentials, so it runs with the Jira
+				// commands below.
+				if def.Name != contracts.CommandDiff || !diffRemote {
+					report, fatalErr, handled = runInspectionCommand(def.Name, app.WorkDir, stateFilter, keyFilter, reasonFilter, includeUnchanged, outputDir, listLimit)
+				}
 				if !handled {
 					report, fatalErr, handled = runAuthoringCommand(ctx, def.Name, app.WorkDir, args, authoringRunOptions{
-						initProjectKey:  initProjectKey,
-						initProfile:     initProfile,
-						initBaseURL:     initBaseURL,
-						initEmail:       initEmail,
-						initDefaultJQL:  initDefaultJQL,
-						initProfileJQL:  initProfileJQL,
-						initForce:       initForce,
-						newSummary:      newSummary,
-						newIssueType:    newIssueType,
-						newStatus:       newStatus,
-						newPriority:     newPriority,
-						newAssignee:     newAssignee,
-						newLabels:       newLabels,
-						newBody:         newBody,
-						editEditor:      editEditor,
-						pushProfile:     pushProfile,
-						pushDryRun:      dryRun,
-						pullProfile:     pullProfile,
-						pullJQL:         pullJQL,
-						pullPageSize:    pullPageSize,
-						pullConcurrency: pullConcurrency,
-						syncProfile:     syncProfile,
-						syncJQL:         syncJQL,
-						syncPageSize:    syncPageSize,
-						syncConcurrency: syncConcurrency,
-						fieldsProfile:   fieldsProfile,
-						fieldsAll:       fieldsAll,
-						fieldsSearch:    fieldsSearch,
+						environment:      environment,
+						logger:           logger,
+						clock:            app.Clock,
+						initProjectKey:   initProjectKey,
+						initProfile:      initProfile,
+						jiraBaseURL:      jiraBaseURL,
+						jiraEmail:        jiraEmail,
+						initDefaultJQL:   initDefaultJQL,
+						initProfileJQL:   initProfileJQL,
+						initIssuesDir:    initIssuesDir,
+						initForce:        initForce,
+						initDiscover:     initDiscover,
+						newSummary:       newSummary,
+						newIssueType:     newIssueType,
+						newStatus:        newStatus,
+						newPriority:      newPriority,
+						newAssignee:      newAssignee,
+						newLabels:        newLabels,
+						newBody:          newBody,
+						newOpen:          newOpen,
+						newRenumber:      newRenumber,
+						editEditor:       editEditor,
+						viewOutputDir:    outputDir,
+						includeUnchanged: includeUnchanged,
+						pushProfile:      pushProfile,
+						pushChanged:      pushChangedSince,
+						pushExclude:      pushExclude,
+						pushNoTransition: pushNoTransition,
+						dryRun:           dryRun,
+						maxErrors:        maxErrors,
+						maxResultsTotal:  maxResultsTotal,
+						pullProfile:      pullProfile,
+						pullJQL:          pullJQL,
+						pullJQLFile:      pullJQLFile,
+						pullPageSize:     pullPageSize,
+						pullConcurrency:  pullConcurrency,
+						pullRepair:       pullRepair,
+						pullFlatten:      pullFlatten,
+						syncProfile:      syncProfile,
+						syncJQL:          syncJQL,
+						syncJQLFile:      syncJQLFile,
+						syncPageSize:     syncPageSize,
+						syncConcurrency:  syncConcurrency,
+						stopOnConflict:   stopOnConflict,
+						fieldsProfile:    fieldsProfile,
+						fieldsAll:        fieldsAll,
+						fieldsSearch:     fieldsSearch,
+						diffProfile:      diffProfile,
+						stateFilter:      stateFilter,
+						keyFilter:        keyFilter,
+						reasonFilter:     reasonFilter,
+						insecure:         state.global.Insecure,
+						recordPath:       recordPath,
 					})
 				}
 				if !handled {
-					return runStub(context, app.Now().Sub(start))
+					return runStub(context, app.Clock.Now().Sub(start))
 				}
 
 				report.CommandName = string(def.Name)
 				report.DryRun = dryRun
-				return renderAndResolveExit(context, report, app.Now().Sub(start), fatalErr)
+				if pullWatch {
+					return renderWatchCycle(context, watchCycle, report, app.Clock.Now().Sub(start), fatalErr)
+				}
+				return renderAndResolveExit(context, report, app.Clock.Now().Sub(start), fatalErr)
 			})
+			if pullWatch {
+				watchContext := CommandContext{App: app, GlobalFlags: &state.global, CommandName: def.Name, DryRun: dryRun}
+				return runWatch(cmd.Context(), app.Clock, pullInterval, func(ctx context.Context) error {
+					watchCycle++
+					err := runner(ctx)
+					var exitErr *codedExitError
+					if err != nil && !errors.As(err, &exitErr) {
+						// Lock and other pre-run failures end only this cycle.
+						err = renderWatchCycle(watchContext, watchCycle, output.Report{CommandName: string(def.Name), DryRun: dryRun}, 0, err)
+					}
+					return err
+				})
+			}
 			return runner(cmd.Context())
 		},
 	}
@@ -245,42 +444,84 @@ func newStubCommand(app AppContext, state *executionState, def commandDefinition
 	if supportsInspectionFilters(def.Name) {
 		cmd.Flags().StringVar(&stateFilter, "state", "all", "filter issues by local state (all|open|closed)")
 		cmd.Flags().StringVar(&keyFilter, "key", "", "filter issues by key substring")
+		cmd.Flags().StringArrayVar(&reasonFilter, "reason", nil, "only show issues carrying this reason code (repeatable)")
 	}
 	if supportsIncludeUnchanged(def.Name) {
 		cmd.Flags().BoolVar(&includeUnchanged, "all", false, "include unchanged issues")
 	}
+	if def.Name == contracts.CommandView || def.Name == contracts.CommandDiff {
+		cmd.Flags().StringVar(&outputDir, "output-dir", "", "write each issue's output to <dir>/<KEY> files instead of stdout; existing files are never overwritten")
+	}
+	if def.Name == contracts.CommandList || def.Name == contracts.CommandStatus {
+		cmd.Flags().StringVar(&formatFlag, "format", "", "human mode: render each issue with this template, e.g. '{{.Key}} {{.Status}} {{.Summary}}'")
+	}
 
 	switch def.Name {
+	case contracts.CommandList:
+		cmd.Flags().IntVar(&listLimit, "limit", 0, "return at most this many issues; the JSON pagination block reports the full match count (0 = all)")
 	case contracts.CommandInit:
 		cmd.Flags().StringVar(&initProjectKey, "project-key", "", "project key for the default profile")
 		cmd.Flags().StringVar(&initProfile, "profile", "default", "profile name to initialize")
-		cmd.Flags().StringVar(&initBaseURL, "jira-base-url", "", "default Jira base URL")
-		cmd.Flags().StringVar(&initEmail, "jira-email", "", "default Jira account email")
+		cmd.Flags().StringVar(&jiraBaseURL, "jira-base-url", "", "default Jira base URL")
+		cmd.Flags().StringVar(&jiraEmail, "jira-email", "", "default Jira account email")
 		cmd.Flags().StringVar(&initDefaultJQL, "default-jql", "", "global default JQL")
 		cmd.Flags().StringVar(&initProfileJQL, "profile-jql", "", "profile-specific default JQL")
+		cmd.Flags().StringVar(&initIssuesDir, "issues-root", "", "workspace-relative directory for issue files (default .issues)")
 		cmd.Flags().BoolVar(&initForce, "force", false, "overwrite existing config if present")
+		cmd.Flags().BoolVar(&initDiscover, "discover", false, "verify the project and write a starter field_config from Jira fields")
 	case contracts.CommandNew:
 		cmd.Flags().StringVar(&newSummary, "summary", "", "summary for the new local draft")
-		cmd.Flags().StringVar(&newIssueType, "issue-type", "Task", "issue type for the new local draft")
+		cmd.Flags().StringVar(&newIssueType, "issue-type", "", "issue type for the new local draft (default: profile default_issue_type, else Task)")
 		cmd.Flags().StringVar(&newStatus, "status", "Open", "initial local status")
 		cmd.Flags().StringVar(&newPriority, "priority", "", "initial local priority")
 		cmd.Flags().StringVar(&newAssignee, "assignee", "", "initial local assignee")
 		cmd.Flags().StringVar(&newLabels, "labels", "", "comma-separated labels")
 		cmd.Flags().StringVar(&newBody, "body", "", "optional markdown body for the draft")
+		cmd.Flags().BoolVar(&newOpen, "open", false, "open the new draft in the editor")
+		cmd.Flags().StringVar(&editEditor, "editor", "", "editor command for --open (defaults to VISUAL/EDITOR)")
+		cmd.Flags().BoolVar(&newRenumber, "renumber-drafts", false, "give existing local drafts sequential keys instead of creating a draft")
+		cmd.MarkFlagsMutuallyExclusive("renumber-drafts", "summary")
+		cmd.MarkFlagsMutuallyExclusive("renumber-drafts", "open")
 	case contracts.CommandEdit:
 		cmd.Flags().StringVar(&editEditor, "editor", "", "editor command (defaults to VISUAL/EDITOR)")
 	case contracts.CommandPush:
 		cmd.Flags().StringVar(&pushProfile, "profile", "", "profile name for transition overrides and Jira defaults")
+		cmd.Flags().StringVar(&pushChangedSince, "changed-since", "", "only push issue files git reports as changed since this ref")
+		cmd.Flags().StringArrayVar(&pushExclude, "exclude", nil, "skip a writable field while pushing the rest (repeatable or comma-separated)")
+		cmd.Flags().BoolVar(&pushNoTransition, "no-transition", false, "leave Jira status unchanged this run (same as --exclude status)")
+		cmd.Flags().IntVar(&maxErrors, "max-errors", 0, "stop after more than this many per-issue errors (0 = unlimited)")
+		addJiraCredentialFlags(cmd, &jiraBaseURL, &jiraEmail)
+		addRecordFlag(cmd, &recordPath)
 	case contracts.CommandPull:
 		cmd.Flags().StringVar(&pullProfile, "profile", "", "profile name for Jira defaults")
 		cmd.Flags().StringVar(&pullJQL, "jql", "", "override JQL for pull")
+		cmd.Flags().StringVar(&pullJQLFile, "jql-file", "", "read the pull JQL from a file ('#' comment lines are ignored)")
+		cmd.MarkFlagsMutuallyExclusive("jql", "jql-file")
 		cmd.Flags().IntVar(&pullPageSize, "page-size", 0, "override pull page size")
 		cmd.Flags().IntVar(&pullConcurrency, "concurrency", 0, "override pull worker concurrency")
+		cmd.Flags().IntVar(&maxErrors, "max-errors", 0, "stop after more than this many per-issue errors (0 = unlimited)")
+		cmd.Flags().IntVar(&maxResultsTotal, "max-results-total", 0, "abort before writing if the search matches more issues (0 = use max_pull_issues)")
+		cmd.Flags().BoolVar(&pullWatch, "watch", false, "keep pulling on --interval until interrupted")
+		cmd.Flags().DurationVar(&pullInterval, "interval", defaultPullWatchInterval, "wait between --watch pull cycles")
+		cmd.Flags().BoolVar(&pullFlatten, "flatten", false, "write issues directly under the issues root instead of open/ and closed/")
+		cmd.Flags().StringArrayVar(&pullRepair, "repair-snapshots", nil, "rebuild original snapshots for these keys from Jira without touching issue files (repeatable or comma-separated)")
+		addJiraCredentialFlags(cmd, &jiraBaseURL, &jiraEmail)
+		addRecordFlag(cmd, &recordPath)
+		cmd.MarkFlagsMutuallyExclusive("watch", "record")
 	case contracts.CommandSync:
 		cmd.Flags().StringVar(&syncProfile, "profile", "", "profile name for push/pull defaults")
 		cmd.Flags().StringVar(&syncJQL, "jql", "", "override JQL for sync pull stage")
+		cmd.Flags().StringVar(&syncJQLFile, "jql-file", "", "read the sync pull-stage JQL from a file ('#' comment lines are ignored)")
+		cmd.MarkFlagsMutuallyExclusive("jql", "jql-file")
 		cmd.Flags().IntVar(&syncPageSize, "page-size", 0, "override sync pull page size")
 		cmd.Flags().IntVar(&syncConcurrency, "concurrency", 0, "override sync pull worker concurrency")
+		cmd.Flags().BoolVar(&stopOnConflict, "stop-on-conflict", false, "skip the pull stage when push reports conflicts")
+		addJiraCredentialFlags(cmd, &jiraBaseURL, &jiraEmail)
+	case contracts.CommandDiff:
+		cmd.Flags().BoolVar(&diffRemote, "remote", false, "diff working copies against the live Jira issues instead of the snapshots")
+		cmd.Flags().StringVar(&diffProfile, "profile", "", "profile name for Jira defaults (with --remote)")
+		cmd.MarkFlagsMutuallyExclusive("remote", "output-dir")
+		addJiraCredentialFlags(cmd, &jiraBaseURL, &jiraEmail)
 	case contracts.CommandFields:
 		cmd.Flags().StringVar(&fieldsProfile, "profile", "", "profile name for Jira defaults")
 		cmd.Flags().BoolVar(&fieldsAll, "all", false, "include non-custom Jira fields")
@@ -290,6 +531,22 @@ func newStubCommand(app AppContext, state *executionState, def commandDefinition
 	return cmd
 }
 
+// addJiraCredentialFlags lets a single run target another Jira instance
+// without editing config. The flags win over env and config in
+// config.Resolve.
+func addJiraCredentialFlags(cmd *cobra.Command, baseURL *string, email *string) {
+	cmd.Flags().StringVar(baseURL, "jira-base-url", "", "Jira base URL for this run (overrides env and config)")
+	cmd.Flags().StringVar(email, "jira-email", "", "Jira account email for this run (overrides env and config)")
+}
+
+// addRecordFlag registers the hidden --record flag, which writes every Jira
+// call of the run to a file that jira.NewReplayAdapter can serve back when
+// reproducing a bug report.
+func addRecordFlag(cmd *cobra.Command, path *string) {
+	cmd.Flags().StringVar(path, "record", "", "record every Jira call of this run to a file (credentials redacted)")
+	_ = cmd.Flags().MarkHidden("record")
+}
+
 func supportsInspectionFilters(name contracts.CommandName) bool {
 	switch name {
 	case contracts.CommandList, contracts.CommandStatus, contracts.CommandDiff:
@@ -301,23 +558,26 @@ func supportsInspectionFilters(name contracts.CommandName) bool {
 
 func supportsIncludeUnchanged(name contracts.CommandName) bool {
 	switch name {
-	case contracts.CommandStatus, contracts.CommandDiff:
+	case contracts.CommandStatus, contracts.CommandDiff, contracts.CommandPull:
 		return true
 	default:
 		return false
 	}
 }
 
-func runInspectionCommand(commandName contracts.CommandName, workDir string, stateFilter string, keyFilter string, includeUnchanged bool) (output.Report, error, bool) {
+func runInspectionCommand(commandName contracts.CommandName, workDir string, stateFilter string, keyFilter string, reasonFilter []string, includeUnchanged bool, outputDir string, listLimit int) (output.Report, error, bool) {
 	switch commandName {
 	case contracts.CommandList:
-		report, err := commands.RunList(workDir, commands.ListOptions{State: stateFilter, Key: keyFilter})
+		report, err := commands.RunList(workDir, commands.ListOptions{State: stateFilter, Key: keyFilter, Reasons: reasonFilter, Limit: listLimit})
 		return report, err, true
 	case contracts.CommandStatus:
-		report, err := commands.RunStatus(workDir, commands.StatusOptions{State: stateFilter, Key: keyFilter, IncludeUnchanged: includeUnchanged})
+		report, err := commands.RunStatus(workDir, commands.StatusOptions{State: stateFilter, Key: keyFilter, Reasons: reasonFilter, IncludeUnchanged: includeUnchanged})
 		return report, err, true
 	case contracts.CommandDiff:
-		report, err := commands.RunDiff(workDir, commands.DiffOptions{State: stateFilter, Key: keyFilter, IncludeUnchanged: includeUnchanged})
+		report, err := commands.RunDiff(workDir, commands.DiffOptions{State: stateFilter, Key: keyFilter, Reasons: reasonFilter, IncludeUnchanged: includeUnchanged, OutputDir: outputDir})
+		return report, err, true
+	case contracts.CommandFsck:
+		report, err := commands.RunFsck(workDir, commands.FsckOptions{})
 		return report, err, true
 	default:
 		return output.Report{}, nil, false
@@ -325,51 +585,81 @@ func runInspectionCommand(commandName contracts.CommandName, workDir string, sta
 }
 
 type authoringRunOptions struct {
-	initProjectKey  string
-	initProfile     string
-	initBaseURL     string
-	initEmail       string
-	initDefaultJQL  string
-	initProfileJQL  string
-	initForce       bool
-	newSummary      string
-	newIssueType    string
-	newStatus       string
-	newPriority     string
-	newAssignee     string
-	newLabels       string
-	newBody         string
-	editEditor      string
-	pushProfile     string
-	pushDryRun      bool
-	pullProfile     string
-	pullJQL         string
-	pullPageSize    int
-	pullConcurrency int
-	syncProfile     string
-	syncJQL         string
-	syncPageSize    int
-	syncConcurrency int
-	fieldsProfile   string
-	fieldsAll       bool
-	fieldsSearch    string
+	environment      config.Environment
+	logger           logging.Logger
+	initProjectKey   string
+	initProfile      string
+	jiraBaseURL      string
+	jiraEmail        string
+	initDefaultJQL   string
+	initProfileJQL   string
+	initIssuesDir    string
+	initForce        bool
+	initDiscover     bool
+	newSummary       string
+	newIssueType     string
+	newStatus        string
+	newPriority      string
+	newAssignee      string
+	newLabels        string
+	newBody          string
+	newOpen          bool
+	newRenumber      bool
+	editEditor       string
+	viewOutputDir    string
+	includeUnchanged bool
+	pushProfile      string
+	pushChanged      string
+	pushExclude      []string
+	pushNoTransition bool
+	dryRun           bool
+	maxErrors        int
+	maxResultsTotal  int
+	pullProfile      string
+	pullJQL          string
+	pullJQLFile      string
+	pullPageSize     int
+	pullConcurrency  int
+	pullRepair       []string
+	pullFlatten      bool
+	syncProfile      string
+	syncJQL          string
+	syncJQLFile      string
+	syncPageSize     int
+	syncConcurrency  int
+	stopOnConflict   bool
+	fieldsProfile    string
+	fieldsAll        bool
+	fieldsSearch     string
+	diffProfile      string
+	stateFilter      string
+	keyFilter        string
+	reasonFilter     []string
+	insecure         bool
+	recordPath       string
+	clock            clock.Clock
 }
 
 func runAuthoringCommand(ctx context.Context, commandName contracts.CommandName, workDir string, args []string, options authoringRunOptions) (output.Report, error, bool) {
 	switch commandName {
 	case contracts.CommandInit:
-		report, err := commands.RunInit(workDir, commands.InitOptions{
+		report, err := commands.RunInit(ctx, workDir, commands.InitOptions{
 			ProjectKey:  options.initProjectKey,
 			Profile:     options.initProfile,
-			JiraBaseURL: options.initBaseURL,
-			JiraEmail:   options.initEmail,
+			JiraBaseURL: options.jiraBaseURL,
+			JiraEmail:   options.jiraEmail,
 			DefaultJQL:  options.initDefaultJQL,
 			ProfileJQL:  options.initProfileJQL,
+			IssuesDir:   options.initIssuesDir,
 			Force:       options.initForce,
+			Discover:    options.initDiscover,
+			Environment: options.environment,
+			Logger:      options.logger,
+			Insecure:    options.insecure,
 		})
 		return report, err, true
 	case contracts.CommandNew:
-		report, err := commands.RunNew(workDir, commands.NewOptions{
+		report, err := commands.RunNew(ctx, workDir, commands.NewOptions{
 			Summary:   options.newSummary,
 			IssueType: options.newIssueType,
 			Status:    options.newStatus,
@@ -377,6 +667,9 @@ func runAuthoringCommand(ctx context.Context, commandName contracts.CommandName,
 			Assignee:  options.newAssignee,
 			Labels:    parseLabels(options.newLabels),
 			Body:      options.newBody,
+			Open:      options.newOpen,
+			Editor:    options.editEditor,
+			Renumber:  options.newRenumber,
 		})
 		return report, err, true
 	case contracts.CommandEdit:
@@ -389,33 +682,83 @@ func runAuthoringCommand(ctx context.Context, commandName contracts.CommandName,
 		if len(args) != 1 {
 			return output.Report{}, fmt.Errorf("view requires exactly one issue key argument"), true
 		}
-		report, err := commands.RunView(workDir, commands.ViewOptions{Key: args[0]})
+		report, err := commands.RunView(workDir, commands.ViewOptions{Key: args[0], OutputDir: options.viewOutputDir})
 		return report, err, true
 	case contracts.CommandPush:
-		report, err := commands.RunPush(ctx, workDir, commands.PushOptions{Profile: options.pushProfile, DryRun: options.pushDryRun})
+		report, err := commands.RunPush(ctx, workDir, commands.PushOptions{Profile: options.pushProfile, DryRun: options.dryRun, MaxErrors: options.maxErrors, ChangedSince: options.pushChanged, Exclude: options.pushExclude, NoTransition: options.pushNoTransition, JiraBaseURL: options.jiraBaseURL, JiraEmail: options.jiraEmail, Environment: options.environment, Logger: options.logger, Clock: options.clock, Insecure: options.insecure, RecordPath: options.recordPath})
 		return report, err, true
 	case contracts.CommandPull:
-		report, err := commands.RunPull(ctx, workDir, commands.PullOptions{
-			Profile:     options.pullProfile,
-			JQL:         options.pullJQL,
-			PageSize:    options.pullPageSize,
-			Concurrency: options.pullConcurrency,
+		report, err := runPullCommand(ctx, workDir, commands.PullOptions{
+			Profile:          options.pullProfile,
+			JQL:              options.pullJQL,
+			JQLFile:          options.pullJQLFile,
+			PageSize:         options.pullPageSize,
+			Concurrency:      options.pullConcurrency,
+			DryRun:           options.dryRun,
+			MaxErrors:        options.maxErrors,
+			MaxResultsTotal:  options.maxResultsTotal,
+			Environment:      options.environment,
+			Logger:           options.logger,
+			Clock:            options.clock,
+			RepairSnapshots:  options.pullRepair,
+			Flatten:          options.pullFlatten,
+			IncludeUnchanged: options.includeUnchanged,
+			JiraBaseURL:      options.jiraBaseURL,
+			JiraEmail:        options.jiraEmail,
+			Insecure:         options.insecure,
+			RecordPath:       options.recordPath,
 		})
 		return report, err, true
 	case contracts.CommandSync:
-		report, err := commands.RunSync(ctx, workDir, commands.SyncOptions{
-			Profile:     options.syncProfile,
-			JQL:         options.syncJQL,
-			PageSize:    options.syncPageSize,
-			Concurrency: options.syncConcurrency,
-			DryRun:      options.pushDryRun,
+		report, err := runSyncCommand(ctx, workDir, commands.SyncOptions{
+			Profile:        options.syncProfile,
+			JQL:            options.syncJQL,
+			JQLFile:        options.syncJQLFile,
+			PageSize:       options.syncPageSize,
+			Concurrency:    options.syncConcurrency,
+			DryRun:         options.dryRun,
+			StopOnConflict: options.stopOnConflict,
+			JiraBaseURL:    options.jiraBaseURL,
+			JiraEmail:      options.jiraEmail,
+			Environment:    options.environment,
+			Logger:         options.logger,
+			Clock:          options.clock,
+			Insecure:       options.insecure,
 		})
 		return report, err, true
 	case contracts.CommandFields:
 		report, err := commands.RunFields(ctx, workDir, commands.FieldsOptions{
-			Profile: options.fieldsProfile,
-			All:     options.fieldsAll,
-			Search:  options.fieldsSearch,
+			Profile:     options.fieldsProfile,
+			All:         options.fieldsAll,
+			Search:      options.fieldsSearch,
+			Environment: options.environment,
+			Logger:      options.logger,
+			Insecure:    options.insecure,
+		})
+		return report, err, true
+	case contracts.CommandDiff:
+		report, err := runDiffRemoteCommand(ctx, workDir, commands.DiffRemoteOptions{
+			Profile:          options.diffProfile,
+			State:            options.stateFilter,
+			Key:              options.keyFilter,
+			IncludeUnchanged: options.includeUnchanged,
+			Reasons:          options.reasonFilter,
+			Clock:            options.clock,
+			Environment:      options.environment,
+			Logger:           options.logger,
+			Insecure:         options.insecure,
+			JiraBaseURL:      options.jiraBaseURL,
+			JiraEmail:        options.jiraEmail,
+		})
+		return report, err, true
+	case contracts.CommandConfig:
+		if len(args) != 1 || args[0] != "lint" {
+			return output.Report{CommandName: string(commandName)}, &config.ResolveError{Code: config.ResolveErrorCodeInvalidFlag, Message: "config requires the lint subcommand: jira-issue-sync config lint"}, true
+		}
+		report, err := commands.RunConfigLint(ctx, workDir, commands.ConfigLintOptions{
+			Environment: options.environment,
+			Logger:      options.logger,
+			Insecure:    options.insecure,
 		})
 		return report, err, true
 	default:
@@ -423,6 +766,52 @@ func runAuthoringCommand(ctx context.Context, commandName contracts.CommandName,
 	}
 }
 
+// resolveEnvironment returns the zero Environment when no --env-file is
+// given so commands keep reading credentials from the process environment.
+// Relative paths resolve against the workspace directory.
+// workspaceLockPath places the lock under the configured issues root. A
+// missing or unreadable config falls back to the default root; the command
+// itself reports a broken config.
+func workspaceLockPath(workDir string) string {
+	cfg, err := config.Read(filepath.Join(workDir, contracts.DefaultConfigFilePath))
+	if err != nil {
+		return filepath.Join(workDir, contracts.DefaultLockFilePath)
+	}
+	return filepath.Join(workDir, contracts.ResolveIssuesRootDir(cfg), ".sync", "lock")
+}
+
+func resolveEnvironment(workDir string, envFile string) (config.Environment, error) {
+	envFile = strings.TrimSpace(envFile)
+	if envFile == "" {
+		return config.Environment{}, nil
+	}
+	if !filepath.IsAbs(envFile) {
+		envFile = filepath.Join(workDir, envFile)
+	}
+	return config.EnvironmentFromFile(envFile)
+}
+
+// writeExplain always emits JSON: the document describes the machine
+// contract itself, so there is no human rendering.
+func writeExplain(stdout io.Writer) error {
+	encoder := json.NewEncoder(stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(commands.RunExplain()); err != nil {
+		return fmt.Errorf("failed to write explain output: %w", err)
+	}
+	return nil
+}
+
+func writeSchema(stdout io.Writer) error {
+	encoder := json.NewEncoder(stdout)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(commands.RunSchema()); err != nil {
+		return fmt.Errorf("failed to write schema output: %w", err)
+	}
+	return nil
+}
+
 func parseLabels(raw string) []string {
 	if strings.TrimSpace(raw) == "" {
 		return nil
@@ -439,11 +828,11 @@ func parseLabels(raw string) []string {
 }
 
 func renderAndResolveExit(context CommandContext, report output.Report, duration time.Duration, fatalErr error) error {
-	if err := output.Write(context.OutputMode(), context.App.Stdout, context.App.Stderr, report, duration, fatalErr); err != nil {
+	if err := output.WriteWithOptions(context.OutputMode(), context.App.Stdout, context.App.Stderr, report, duration, fatalErr, context.RenderOptions()); err != nil {
 		return err
 	}
 
-	exitCode := output.ResolveExitCode(report, fatalErr)
+	exitCode := output.ResolveExitCodeWithOptions(report, fatalErr, context.ExitOptions())
 	if exitCode == contracts.ExitCodeSuccess {
 		return nil
 	}
@@ -451,9 +840,98 @@ func renderAndResolveExit(context CommandContext, report output.Report, duration
 	return &codedExitError{Code: exitCode}
 }
 
+const noLockWarning = "WARNING: --no-lock is set; running without the workspace lock. A concurrent jira-issue-sync run in this workspace can corrupt issue files, snapshots, or the cache."
+
+const insecureWarning = "WARNING: --insecure is set; TLS certificates from Jira are not verified. Anyone on the network path can read or alter requests, including your API token."
+
+// callsJira reports whether a command talks to Jira, so --insecure only
+// warns where it changes anything. diff only does with --remote.
+func callsJira(command contracts.CommandName, diffRemote bool) bool {
+	switch command {
+	case contracts.CommandInit, contracts.CommandPull, contracts.CommandPush, contracts.CommandSync, contracts.CommandFields, contracts.CommandConfig:
+		return true
+	case contracts.CommandDiff:
+		return diffRemote
+	default:
+		return false
+	}
+}
+
+const defaultPullWatchInterval = 5 * time.Minute
+
+func validatePullWatch(watch bool, interval time.Duration, intervalSet bool) error {
+	if intervalSet && !watch {
+		return &config.ResolveError{Code: config.ResolveErrorCodeInvalidFlag, Message: "--interval requires --watch"}
+	}
+	if watch && interval <= 0 {
+		return &config.ResolveError{Code: config.ResolveErrorCodeInvalidFlag, Message: fmt.Sprintf("--interval must be positive, got %s", interval)}
+	}
+	return nil
+}
+
+// runWatch runs cycle, then waits interval, until ctx is cancelled or the
+// process is interrupted. Cycles run back to back on one goroutine, so they
+// never overlap, and a failed cycle does not end the loop. The result of the
+// last cycle decides the exit code.
+func runWatch(ctx context.Context, c clock.Clock, interval time.Duration, cycle func(context.Context) error) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	for {
+		last := cycle(ctx)
+		if err := clock.SleepContext(ctx, c, interval); err != nil {
+			return last
+		}
+	}
+}
+
+// renderWatchCycle reports one --watch cycle: a JSON envelope per cycle on
+// stdout (NDJSON), or a one-line count summary on stderr in human mode.
+func renderWatchCycle(context CommandContext, cycle int, report output.Report, duration time.Duration, fatalErr error) error {
+	if context.OutputMode() == contracts.OutputModeJSON {
+		return renderAndResolveExit(context, report, duration, fatalErr)
+	}
+
+	errorCount := report.Counts.Errors
+	if fatalErr != nil && errorCount == 0 {
+		errorCount = 1
+	}
+	if _, err := fmt.Fprintf(
+		context.App.Stderr,
+		"%s watch cycle %d at %s: processed=%d updated=%d created=%d conflicts=%d warnings=%d errors=%d\n",
+		report.CommandName,
+		cycle,
+		context.App.Clock.Now().UTC().Format(time.RFC3339),
+		report.Counts.Processed,
+		report.Counts.Updated,
+		report.Counts.Created,
+		report.Counts.Conflicts,
+		report.Counts.Warnings,
+		errorCount,
+	); err != nil {
+		return fmt.Errorf("failed to write watch summary: %w", err)
+	}
+	if fatalErr != nil {
+		if _, err := fmt.Fprintln(context.App.Stderr, output.FormatDiagnostic(fatalErr)); err != nil {
+			return fmt.Errorf("failed to write diagnostics: %w", err)
+		}
+	}
+
+	if exitCode := output.ResolveExitCodeWithOptions(report, fatalErr, context.ExitOptions()); exitCode != contracts.ExitCodeSuccess {
+		return &codedExitError{Code: exitCode}
+	}
+	return nil
+}
+
 func normalizeAppContext(app AppContext) AppContext {
-	if app.Now == nil {
-		app.Now = time.Now
+	if app.Clock == nil {
+		app.Clock = clock.System()
+	}
+	if app.Logger == nil {
+		app.Logger = logging.Nop()
 	}
 	if app.WorkDir == "" {
 		if wd, err := os.Getwd(); err == nil {
@@ -472,7 +950,7 @@ func runStub(context CommandContext, duration time.Duration) error {
 	}
 
 	fatalErr := fmt.Errorf("command %q is not implemented yet", context.CommandName)
-	if err := output.Write(context.OutputMode(), context.App.Stdout, context.App.Stderr, report, duration, fatalErr); err != nil {
+	if err := output.WriteWithOptions(context.OutputMode(), context.App.Stdout, context.App.Stderr, report, duration, fatalErr, context.RenderOptions()); err != nil {
 		return err
 	}
 
@@ -2,13 +2,25 @@ package cli
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"reflect"
 	"sort"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/pweiskircher/jira-issue-sync/internal/clock"
+	"github.com/pweiskircher/jira-issue-sync/internal/commands"
+	"github.com/pweiskircher/jira-issue-sync/internal/config"
 	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
+	"github.com/pweiskircher/jira-issue-sync/internal/lock"
+	"github.com/pweiskircher/jira-issue-sync/internal/logging"
+	"github.com/pweiskircher/jira-issue-sync/internal/output"
 )
 
 func TestNewRootCommandRegistersMVPCommandsAndGlobalJSONFlag(t *testing.T) {
@@ -30,7 +42,7 @@ func TestNewRootCommandRegistersMVPCommandsAndGlobalJSONFlag(t *testing.T) {
 	}
 	sort.Strings(names)
 
-	expected := []string{"diff", "edit", "fields", "init", "list", "new", "pull", "push", "status", "sync", "view"}
+	expected := []string{"config", "diff", "edit", "explain", "fields", "fsck", "init", "list", "new", "pull", "push", "schema", "status", "sync", "view"}
 	if len(names) != len(expected) {
 		t.Fatalf("unexpected command count: got=%d want=%d (%v)", len(names), len(expected), names)
 	}
@@ -129,3 +141,428 @@ func TestRunStatusReportsPartialViaJSONEnvelopeWithoutCrashingBatch(t *testing.T
 		t.Fatalf("expected two issue results, got %d", len(env.Issues))
 	}
 }
+
+func TestRunPullPassesTuningFlagsToPipeline(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd failed: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = os.Chdir(cwd)
+	})
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatalf("chdir failed: %v", err)
+	}
+
+	var captured commands.PullOptions
+	previous := runPullCommand
+	runPullCommand = func(_ context.Context, _ string, options commands.PullOptions) (output.Report, error) {
+		captured = options
+		return output.Report{}, nil
+	}
+	t.Cleanup(func() { runPullCommand = previous })
+
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	exitCode := Run([]string{"--json", "pull", "--page-size", "50", "--concurrency", "8", "--jql", "project = PROJ", "--record", "calls.jsonl"}, stdout, stderr)
+	if exitCode != int(contracts.ExitCodeSuccess) {
+		t.Fatalf("expected success exit code, got %d (stderr=%q)", exitCode, stderr.String())
+	}
+	if captured.PageSize != 50 || captured.Concurrency != 8 || captured.JQL != "project = PROJ" || captured.RecordPath != "calls.jsonl" {
+		t.Fatalf("unexpected pull options: %#v", captured)
+	}
+}
+
+func TestRunPullWatchEmitsOneEnvelopePerCycle(t *testing.T) {
+	start := time.Date(2026, time.March, 1, 9, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(start)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	calls := 0
+	previous := runPullCommand
+	runPullCommand = func(context.Context, string, commands.PullOptions) (output.Report, error) {
+		calls++
+		if calls == 2 {
+			cancel()
+		}
+		return output.Report{Counts: contracts.AggregateCounts{Processed: calls}}, nil
+	}
+	t.Cleanup(func() { runPullCommand = previous })
+
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	root := NewRootCommand(AppContext{Stdout: stdout, Stderr: stderr, Clock: fake, WorkDir: t.TempDir()})
+	root.SetArgs([]string{"--json", "pull", "--watch", "--interval", "30s", "--jql", "project = PROJ"})
+	if err := root.ExecuteContext(ctx); err != nil {
+		t.Fatalf("expected watch to stop cleanly, got %v (stderr=%q)", err, stderr.String())
+	}
+
+	if calls != 2 {
+		t.Fatalf("expected two pull cycles, got %d", calls)
+	}
+	// One interval separates the cycles; cancellation skips the wait after
+	// the second.
+	if got := fake.Now(); !got.Equal(start.Add(30 * time.Second)) {
+		t.Fatalf("expected one interval to elapse, got %s", got)
+	}
+
+	if lines := strings.Count(stdout.String(), "\n"); lines != 2 {
+		t.Fatalf("expected one envelope per line, got %q", stdout.String())
+	}
+	decoder := json.NewDecoder(stdout)
+	for cycle := 1; cycle <= 2; cycle++ {
+		var env contracts.CommandEnvelope
+		if err := decoder.Decode(&env); err != nil {
+			t.Fatalf("expected envelope for cycle %d: %v (stdout=%q)", cycle, err, stdout.String())
+		}
+		if env.Counts.Processed != cycle {
+			t.Fatalf("unexpected counts for cycle %d: %#v", cycle, env.Counts)
+		}
+	}
+	if decoder.More() {
+		t.Fatalf("expected exactly two envelopes, got %q", stdout.String())
+	}
+}
+
+func TestRunNoLockSkipsHeldLockAndWarns(t *testing.T) {
+	workDir := t.TempDir()
+	held, err := lock.NewFileLock(filepath.Join(workDir, contracts.DefaultLockFilePath), lock.Options{}).Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("acquire lock failed: %v", err)
+	}
+	defer held.Release()
+
+	previous := runPullCommand
+	runPullCommand = func(context.Context, string, commands.PullOptions) (output.Report, error) {
+		return output.Report{}, nil
+	}
+	t.Cleanup(func() { runPullCommand = previous })
+
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	root := NewRootCommand(AppContext{Stdout: stdout, Stderr: stderr, WorkDir: workDir})
+	root.SetArgs([]string{"--json", "--no-lock", "pull", "--jql", "project = PROJ"})
+	if err := root.ExecuteContext(ctx); err != nil {
+		t.Fatalf("expected pull to run while the lock is held, got %v (stderr=%q)", err, stderr.String())
+	}
+
+	if !strings.Contains(stderr.String(), "WARNING: --no-lock is set") {
+		t.Fatalf("expected no-lock warning on stderr, got %q", stderr.String())
+	}
+	if strings.Contains(stdout.String(), "WARNING") {
+		t.Fatalf("warning leaked into JSON stdout: %q", stdout.String())
+	}
+}
+
+func TestRunTakesLockUnderConfiguredIssuesRoot(t *testing.T) {
+	workDir := t.TempDir()
+	cfg := contracts.Config{
+		ConfigVersion: contracts.ConfigSchemaVersionV1,
+		IssuesRoot:    "tracker",
+		Profiles:      map[string]contracts.ProjectProfile{"default": {ProjectKey: "PROJ"}},
+	}
+	if err := config.Write(filepath.Join(workDir, contracts.DefaultConfigFilePath), cfg); err != nil {
+		t.Fatalf("write config failed: %v", err)
+	}
+
+	lockHeld := false
+	previous := runPullCommand
+	runPullCommand = func(context.Context, string, commands.PullOptions) (output.Report, error) {
+		_, err := os.Stat(filepath.Join(workDir, "tracker", ".sync", "lock"))
+		lockHeld = err == nil
+		return output.Report{}, nil
+	}
+	t.Cleanup(func() { runPullCommand = previous })
+
+	root := NewRootCommand(AppContext{Stdout: new(bytes.Buffer), Stderr: new(bytes.Buffer), WorkDir: workDir})
+	root.SetArgs([]string{"--json", "pull", "--jql", "project = PROJ"})
+	if err := root.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("pull failed: %v", err)
+	}
+	if !lockHeld {
+		t.Fatalf("expected pull to hold the lock under the configured issues root")
+	}
+	if _, err := os.Stat(filepath.Join(workDir, contracts.DefaultLockFilePath)); !os.IsNotExist(err) {
+		t.Fatalf("expected no lock under the default root, got %v", err)
+	}
+}
+
+func TestInsecureFlagWarnsAndReachesPull(t *testing.T) {
+	var captured []bool
+	previous := runPullCommand
+	runPullCommand = func(_ context.Context, _ string, options commands.PullOptions) (output.Report, error) {
+		captured = append(captured, options.Insecure)
+		return output.Report{}, nil
+	}
+	t.Cleanup(func() { runPullCommand = previous })
+
+	for _, insecure := range []bool{false, true} {
+		args := []string{"--json", "pull", "--jql", "project = PROJ"}
+		if insecure {
+			args = append([]string{"--insecure"}, args...)
+		}
+		stdout := new(bytes.Buffer)
+		stderr := new(bytes.Buffer)
+		root := NewRootCommand(AppContext{Stdout: stdout, Stderr: stderr, WorkDir: t.TempDir()})
+		root.SetArgs(args)
+		if err := root.Execute(); err != nil {
+			t.Fatalf("expected pull to run, got %v (stderr=%q)", err, stderr.String())
+		}
+		if warned := strings.Contains(stderr.String(), "WARNING: --insecure is set"); warned != insecure {
+			t.Fatalf("insecure=%v: unexpected warning state, stderr=%q", insecure, stderr.String())
+		}
+	}
+
+	if len(captured) != 2 || captured[0] || !captured[1] {
+		t.Fatalf("expected Insecure only on the flagged run, got %v", captured)
+	}
+
+	var diffCaptured []bool
+	previousDiff := runDiffRemoteCommand
+	runDiffRemoteCommand = func(_ context.Context, _ string, options commands.DiffRemoteOptions) (output.Report, error) {
+		diffCaptured = append(diffCaptured, options.Insecure)
+		return output.Report{}, nil
+	}
+	t.Cleanup(func() { runDiffRemoteCommand = previousDiff })
+
+	for _, remote := range []bool{false, true} {
+		args := []string{"--insecure", "--json", "diff"}
+		if remote {
+			args = append(args, "--remote")
+		}
+		stdout := new(bytes.Buffer)
+		stderr := new(bytes.Buffer)
+		root := NewRootCommand(AppContext{Stdout: stdout, Stderr: stderr, WorkDir: t.TempDir()})
+		root.SetArgs(args)
+		_ = root.Execute()
+		if warned := strings.Contains(stderr.String(), "WARNING: --insecure is set"); warned != remote {
+			t.Fatalf("diff remote=%v: unexpected warning state, stderr=%q", remote, stderr.String())
+		}
+	}
+
+	if len(diffCaptured) != 1 || !diffCaptured[0] {
+		t.Fatalf("expected only diff --remote to reach Jira with Insecure, got %v", diffCaptured)
+	}
+}
+
+func TestJiraCredentialFlagsOverrideEnvAndConfigForPull(t *testing.T) {
+	var requests []*http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		_, _ = w.Write([]byte(`{"issues":[],"isLast":true}`))
+	}))
+	t.Cleanup(server.Close)
+
+	t.Setenv(config.EnvJiraAPIToken, "token-123")
+	t.Setenv(config.EnvJiraBaseURL, "https://env.example.invalid")
+	t.Setenv(config.EnvJiraEmail, "env@example.com")
+
+	workDir := t.TempDir()
+	for _, args := range [][]string{
+		{"--json", "init", "--project-key", "PROJ", "--jira-base-url", "https://config.example.invalid", "--jira-email", "config@example.com"},
+		{"--json", "pull", "--jql", "project = PROJ", "--jira-base-url", server.URL, "--jira-email", "flag@example.com"},
+	} {
+		stdout := new(bytes.Buffer)
+		stderr := new(bytes.Buffer)
+		root := NewRootCommand(AppContext{Stdout: stdout, Stderr: stderr, WorkDir: workDir})
+		root.SetArgs(args)
+		if err := root.Execute(); err != nil {
+			t.Fatalf("%s failed: %v (stdout=%q stderr=%q)", args[1], err, stdout.String(), stderr.String())
+		}
+	}
+
+	if len(requests) == 0 {
+		t.Fatalf("expected pull to reach the --jira-base-url server")
+	}
+	email, _, ok := requests[0].BasicAuth()
+	if !ok || email != "flag@example.com" {
+		t.Fatalf("expected --jira-email to authenticate the request, got %q", email)
+	}
+}
+
+func TestRunPullRejectsIntervalWithoutWatch(t *testing.T) {
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	root := NewRootCommand(AppContext{Stdout: stdout, Stderr: stderr, WorkDir: t.TempDir()})
+	root.SetArgs([]string{"pull", "--interval", "30s"})
+	if err := root.Execute(); err == nil {
+		t.Fatalf("expected --interval without --watch to fail")
+	}
+	if !strings.Contains(stderr.String(), "--interval requires --watch") {
+		t.Fatalf("unexpected diagnostics: %q", stderr.String())
+	}
+}
+
+func TestRunDebugLogsGoToStderrAndKeepJSONEnvelopeClean(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd failed: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = os.Chdir(cwd)
+	})
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatalf("chdir failed: %v", err)
+	}
+
+	previous := runPullCommand
+	runPullCommand = func(_ context.Context, _ string, options commands.PullOptions) (output.Report, error) {
+		if options.Logger == nil {
+			t.Fatalf("expected pull to receive a logger")
+		}
+		options.Logger.Logf(logging.LevelDebug, "pipeline phase from stub")
+		return output.Report{}, nil
+	}
+	t.Cleanup(func() { runPullCommand = previous })
+
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	exitCode := Run([]string{"--json", "--debug", "pull", "--jql", "project = PROJ"}, stdout, stderr)
+	if exitCode != int(contracts.ExitCodeSuccess) {
+		t.Fatalf("expected success exit code, got %d (stderr=%q)", exitCode, stderr.String())
+	}
+
+	if !strings.Contains(stderr.String(), "[debug] lock acquired") || !strings.Contains(stderr.String(), "[debug] pipeline phase from stub") {
+		t.Fatalf("expected debug logs on stderr, got %q", stderr.String())
+	}
+
+	decoder := json.NewDecoder(stdout)
+	var envelope map[string]any
+	if err := decoder.Decode(&envelope); err != nil {
+		t.Fatalf("stdout is not a JSON envelope: %v (stdout=%q)", err, stdout.String())
+	}
+	if decoder.More() {
+		t.Fatalf("expected exactly one JSON value on stdout, got %q", stdout.String())
+	}
+	if strings.Contains(stdout.String(), "[debug]") {
+		t.Fatalf("debug logs leaked into stdout: %q", stdout.String())
+	}
+}
+
+func TestRunQuietSuppressesCleanOutputButKeepsFailureExitCode(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd failed: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = os.Chdir(cwd)
+	})
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatalf("chdir failed: %v", err)
+	}
+
+	var next output.Report
+	previous := runPullCommand
+	runPullCommand = func(context.Context, string, commands.PullOptions) (output.Report, error) {
+		return next, nil
+	}
+	t.Cleanup(func() { runPullCommand = previous })
+
+	next = output.Report{
+		Counts: contracts.AggregateCounts{Processed: 1, Updated: 1},
+		Issues: []contracts.PerIssueResult{{Key: "PROJ-1", Action: "pull", Status: contracts.PerIssueStatusSuccess}},
+	}
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	if exitCode := Run([]string{"--quiet", "pull", "--jql", "project = PROJ"}, stdout, stderr); exitCode != int(contracts.ExitCodeSuccess) {
+		t.Fatalf("expected success exit code, got %d (stderr=%q)", exitCode, stderr.String())
+	}
+	if stdout.Len() != 0 || stderr.Len() != 0 {
+		t.Fatalf("expected no output for a clean quiet pull, got stdout=%q stderr=%q", stdout.String(), stderr.String())
+	}
+
+	next = output.Report{
+		Counts: contracts.AggregateCounts{Processed: 2, Updated: 1, Errors: 1},
+		Issues: []contracts.PerIssueResult{
+			{Key: "PROJ-1", Action: "pull", Status: contracts.PerIssueStatusSuccess},
+			{Key: "PROJ-2", Action: "pull-error", Status: contracts.PerIssueStatusError, Messages: []contracts.IssueMessage{{Level: "error", Text: "bad description"}}},
+		},
+	}
+	stdout.Reset()
+	stderr.Reset()
+	if exitCode := Run([]string{"--quiet", "pull", "--jql", "project = PROJ"}, stdout, stderr); exitCode != int(contracts.ExitCodePartial) {
+		t.Fatalf("expected partial exit code, got %d", exitCode)
+	}
+	if stdout.Len() != 0 {
+		t.Fatalf("expected no stdout under --quiet, got %q", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "PROJ-2 [error] pull-error") || strings.Contains(stderr.String(), "PROJ-1") {
+		t.Fatalf("expected only the failing issue on stderr, got %q", stderr.String())
+	}
+}
+
+func TestRunSchemaListsEveryFrontMatterKey(t *testing.T) {
+	t.Parallel()
+
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	if exitCode := Run([]string{"schema"}, stdout, stderr); exitCode != int(contracts.ExitCodeSuccess) {
+		t.Fatalf("expected success exit code, got %d (stderr=%q)", exitCode, stderr.String())
+	}
+
+	var schema commands.IssueFileSchema
+	if err := json.Unmarshal(stdout.Bytes(), &schema); err != nil {
+		t.Fatalf("schema output is not JSON: %v (%q)", err, stdout.String())
+	}
+
+	if !reflect.DeepEqual(schema.Required, contracts.RequiredFrontMatterKeys) {
+		t.Fatalf("expected required keys %v, got %v", contracts.RequiredFrontMatterKeys, schema.Required)
+	}
+	if len(schema.Properties) != len(contracts.AllFrontMatterKeys()) {
+		t.Fatalf("expected %d properties, got %#v", len(contracts.AllFrontMatterKeys()), schema.Properties)
+	}
+	for _, key := range contracts.AllFrontMatterKeys() {
+		property, ok := schema.Properties[key]
+		if !ok || property.Type == "" {
+			t.Fatalf("expected a typed property for %s, got %#v", key, property)
+		}
+	}
+	if schema.AdditionalProperties {
+		t.Fatalf("unsupported front matter keys must be rejected by the schema")
+	}
+	if schema.Body.DescriptionFence != "```jira-adf" || schema.Body.EnvironmentFence != "```jira-adf environment" {
+		t.Fatalf("unexpected ADF fence convention: %#v", schema.Body)
+	}
+}
+
+func TestRunExplainDumpsStableCodes(t *testing.T) {
+	t.Parallel()
+
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	if exitCode := Run([]string{"explain"}, stdout, stderr); exitCode != int(contracts.ExitCodeSuccess) {
+		t.Fatalf("expected success exit code, got %d (stderr=%q)", exitCode, stderr.String())
+	}
+
+	var document commands.ExplainDocument
+	if err := json.Unmarshal(stdout.Bytes(), &document); err != nil {
+		t.Fatalf("explain output is not JSON: %v (%q)", err, stdout.String())
+	}
+
+	if len(document.ReasonCodes) != len(contracts.StableReasonCodes) {
+		t.Fatalf("expected every stable reason code, got %#v", document.ReasonCodes)
+	}
+	found := false
+	for _, reason := range document.ReasonCodes {
+		if reason.Description == "" {
+			t.Fatalf("missing description for %s", reason.Code)
+		}
+		if reason.Code == contracts.ReasonCodeConflictFieldChangedBoth {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected %s in explain output", contracts.ReasonCodeConflictFieldChangedBoth)
+	}
+
+	codes := make([]int, 0, len(document.ExitCodes))
+	for _, exit := range document.ExitCodes {
+		codes = append(codes, exit.Code)
+	}
+	if len(codes) != 3 || codes[0] != int(contracts.ExitCodeSuccess) || codes[1] != int(contracts.ExitCodeFatal) || codes[2] != int(contracts.ExitCodePartial) {
+		t.Fatalf("unexpected exit codes: %v", codes)
+	}
+}
@@ -0,0 +1,86 @@
+package clock
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Clock is the single time source threaded from the CLI into the lock, the
+// retry client, and the sync pipelines, so tests can drive time uniformly.
+type Clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+}
+
+// System returns the wall clock.
+func System() Clock {
+	return systemClock{}
+}
+
+// OrSystem substitutes the wall clock for nil.
+func OrSystem(c Clock) Clock {
+	if c == nil {
+		return System()
+	}
+	return c
+}
+
+// SleepContext waits d on c, returning ctx.Err() once ctx is done. The wall
+// clock waits on a timer so cancellation interrupts it; any other clock
+// sleeps first (a Fake returns at once) and then reports cancellation.
+func SleepContext(ctx context.Context, c Clock, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	c = OrSystem(c)
+	if _, ok := c.(systemClock); !ok {
+		c.Sleep(d)
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time        { return time.Now() }
+func (systemClock) Sleep(d time.Duration) { time.Sleep(d) }
+
+// Fake is a manually advanced clock. Sleep returns immediately after moving
+// the clock forward, so backoff and staleness logic run without real delays.
+type Fake struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func NewFake(start time.Time) *Fake {
+	return &Fake{now: start}
+}
+
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *Fake) Sleep(d time.Duration) {
+	f.Advance(d)
+}
+
+// Advance moves the clock forward by d; negative values are ignored.
+func (f *Fake) Advance(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+}
@@ -0,0 +1,53 @@
+package clock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFakeSleepAdvancesWithoutBlocking(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, time.March, 1, 9, 0, 0, 0, time.UTC)
+	fake := NewFake(start)
+
+	fake.Sleep(90 * time.Second)
+	fake.Advance(-time.Hour)
+
+	if got := fake.Now(); !got.Equal(start.Add(90 * time.Second)) {
+		t.Fatalf("unexpected fake time: %s", got)
+	}
+}
+
+func TestSleepContextHonorsCancellation(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, time.March, 1, 9, 0, 0, 0, time.UTC)
+	fake := NewFake(start)
+	if err := SleepContext(context.Background(), fake, time.Minute); err != nil {
+		t.Fatalf("unexpected sleep error: %v", err)
+	}
+	if got := fake.Now(); !got.Equal(start.Add(time.Minute)) {
+		t.Fatalf("expected fake clock to advance, got %s", got)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := SleepContext(ctx, System(), time.Hour); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancellation, got %v", err)
+	}
+}
+
+func TestOrSystemKeepsInjectedClock(t *testing.T) {
+	t.Parallel()
+
+	fake := NewFake(time.Unix(0, 0))
+	if OrSystem(fake) != Clock(fake) {
+		t.Fatalf("expected injected clock to be kept")
+	}
+	if _, ok := OrSystem(nil).(systemClock); !ok {
+		t.Fatalf("expected nil clock to fall back to the system clock")
+	}
+}
@@ -8,23 +8,22 @@ import (
 
 	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
 	"github.com/pweiskircher/jira-issue-sync/internal/issue"
+	"github.com/pweiskircher/jira-issue-sync/internal/store"
 )
 
-func findIssuePathByKey(workDir string, key string) (string, error) {
+func findIssuePathByKey(issuesRoot string, key string) (string, error) {
 	trimmedKey := strings.TrimSpace(key)
 	if trimmedKey == "" {
 		return "", fmt.Errorf("issue key is required")
 	}
 
-	if !contracts.JiraIssueKeyPattern.MatchString(trimmedKey) && !contracts.LocalDraftKeyPattern.MatchString(trimmedKey) {
+	if !contracts.JiraIssueKeyPattern.MatchString(trimmedKey) && !contracts.IsLocalDraftKey(trimmedKey) {
 		return "", fmt.Errorf("invalid issue key %q", key)
 	}
 
-	issuesRoot := filepath.Join(workDir, contracts.DefaultIssuesRootDir)
-	stateDirs := []string{"open", "closed"}
 	matches := make([]string, 0, 1)
 
-	for _, stateDir := range stateDirs {
+	for _, stateDir := range store.IssueDirs {
 		dirPath := filepath.Join(issuesRoot, stateDir)
 		entries, err := os.ReadDir(dirPath)
 		if err != nil {
@@ -35,7 +34,7 @@ func findIssuePathByKey(workDir string, key string) (string, error) {
 		}
 
 		for _, entry := range entries {
-			if entry.IsDir() || strings.ToLower(filepath.Ext(entry.Name())) != ".md" {
+			if entry.IsDir() || !store.IsIssueFile(stateDir, entry.Name()) {
 				continue
 			}
 
@@ -7,15 +7,17 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/pweiskircher/jira-issue-sync/internal/config"
 	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
 	"github.com/pweiskircher/jira-issue-sync/internal/issue"
+	"github.com/pweiskircher/jira-issue-sync/internal/jira"
 	"github.com/pweiskircher/jira-issue-sync/internal/store"
 )
 
 func TestRunInitCreatesWorkspaceLayoutAndConfig(t *testing.T) {
 	workspace := t.TempDir()
 
-	report, err := RunInit(workspace, InitOptions{
+	report, err := RunInit(context.Background(), workspace, InitOptions{
 		ProjectKey:  "PROJ",
 		Profile:     "core",
 		JiraBaseURL: "https://example.atlassian.net",
@@ -42,14 +44,93 @@ func TestRunInitCreatesWorkspaceLayoutAndConfig(t *testing.T) {
 	}
 }
 
+func TestRunInitDiscoverWritesStarterFieldConfigAndCache(t *testing.T) {
+	workspace := t.TempDir()
+	adapter := &initAdapterStub{pullAdapterStub: pullAdapterStub{projects: []jira.Project{{Key: "PROJ"}}}, fields: []jira.FieldDefinition{
+		{ID: "summary", Name: "Summary"},
+		{ID: "customfield_10010", Name: "Story Points", Custom: true},
+		{ID: "customfield_10020", Name: "Story points", Custom: true},
+		{ID: "customfield_10030", Name: "Team (new)", Custom: true},
+	}}
+
+	report, err := RunInit(context.Background(), workspace, InitOptions{
+		ProjectKey:  "PROJ",
+		JiraBaseURL: "https://example.atlassian.net",
+		JiraEmail:   "dev@example.com",
+		Discover:    true,
+		Environment: config.Environment{JiraAPIToken: "token"},
+		Adapter:     adapter,
+	})
+	if err != nil {
+		t.Fatalf("run init failed: %v", err)
+	}
+	if report.Issues[0].Status != contracts.PerIssueStatusSuccess {
+		t.Fatalf("expected success, got %#v", report.Issues[0])
+	}
+
+	cfg, err := config.Read(filepath.Join(workspace, contracts.DefaultConfigFilePath))
+	if err != nil {
+		t.Fatalf("load config failed: %v", err)
+	}
+	aliases := cfg.Profiles["default"].FieldConfig.Aliases
+	want := map[string]string{
+		"customfield_10010": "story_points",
+		"customfield_10020": "story_points_10020",
+		"customfield_10030": "team_new",
+	}
+	if len(aliases) != len(want) {
+		t.Fatalf("unexpected aliases: %#v", aliases)
+	}
+	for id, alias := range want {
+		if aliases[id] != alias {
+			t.Fatalf("expected alias %q for %s, got %#v", alias, id, aliases)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(workspace, ".issues", ".sync", "fields.json")); err != nil {
+		t.Fatalf("expected fields cache: %v", err)
+	}
+}
+
+func TestRunInitDiscoverDegradesWithoutToken(t *testing.T) {
+	workspace := t.TempDir()
+
+	report, err := RunInit(context.Background(), workspace, InitOptions{
+		ProjectKey:  "PROJ",
+		JiraBaseURL: "https://example.atlassian.net",
+		JiraEmail:   "dev@example.com",
+		Discover:    true,
+		Environment: config.Environment{JiraBaseURL: "https://example.atlassian.net"},
+		Adapter:     &initAdapterStub{},
+	})
+	if err != nil {
+		t.Fatalf("run init failed: %v", err)
+	}
+	if report.Issues[0].Status != contracts.PerIssueStatusWarning {
+		t.Fatalf("expected warning status, got %#v", report.Issues[0])
+	}
+	if _, err := os.Stat(filepath.Join(workspace, contracts.DefaultConfigFilePath)); err != nil {
+		t.Fatalf("expected config to be written: %v", err)
+	}
+}
+
+type initAdapterStub struct {
+	pullAdapterStub
+	fields []jira.FieldDefinition
+}
+
+func (s *initAdapterStub) ListFields(context.Context) ([]jira.FieldDefinition, error) {
+	return s.fields, nil
+}
+
 func TestRunNewAndViewEndToEnd(t *testing.T) {
 	workspace := t.TempDir()
 
-	if _, err := RunInit(workspace, InitOptions{ProjectKey: "PROJ"}); err != nil {
+	if _, err := RunInit(context.Background(), workspace, InitOptions{ProjectKey: "PROJ"}); err != nil {
 		t.Fatalf("init failed: %v", err)
 	}
 
-	newReport, err := RunNew(workspace, NewOptions{
+	newReport, err := RunNew(context.Background(), workspace, NewOptions{
 		Summary:   "Authoring flow",
 		IssueType: "Task",
 		Status:    "Open",
@@ -64,7 +145,7 @@ func TestRunNewAndViewEndToEnd(t *testing.T) {
 	}
 
 	key := newReport.Issues[0].Key
-	if !contracts.LocalDraftKeyPattern.MatchString(key) {
+	if !contracts.IsLocalDraftKey(key) {
 		t.Fatalf("expected local draft key, got %q", key)
 	}
 
@@ -85,6 +166,237 @@ func TestRunNewAndViewEndToEnd(t *testing.T) {
 	}
 }
 
+func TestRunNewUsesConfiguredDraftKeyPrefix(t *testing.T) {
+	workspace := t.TempDir()
+
+	cfg := contracts.Config{
+		ConfigVersion:  contracts.ConfigSchemaVersionV1,
+		DraftKeyPrefix: "draft",
+		Profiles:       map[string]contracts.ProjectProfile{"default": {ProjectKey: "PROJ"}},
+	}
+	if err := config.Write(filepath.Join(workspace, contracts.DefaultConfigFilePath), cfg); err != nil {
+		t.Fatalf("write config failed: %v", err)
+	}
+
+	newReport, err := RunNew(context.Background(), workspace, NewOptions{Summary: "Imported draft"})
+	if err != nil {
+		t.Fatalf("run new failed: %v", err)
+	}
+
+	key := newReport.Issues[0].Key
+	if !strings.HasPrefix(key, "draft-") || !contracts.IsLocalDraftKey(key) {
+		t.Fatalf("expected draft- local key, got %q", key)
+	}
+
+	statusReport, err := RunStatus(workspace, StatusOptions{State: "all"})
+	if err != nil {
+		t.Fatalf("run status failed: %v", err)
+	}
+	if len(statusReport.Issues) != 1 || statusReport.Issues[0].Key != key || statusReport.Issues[0].Action != "new" {
+		t.Fatalf("expected status to recognize the draft, got %#v", statusReport.Issues)
+	}
+}
+
+func TestViewAndEditFindDraftsWithConfiguredPrefix(t *testing.T) {
+	workspace := t.TempDir()
+
+	cfg := contracts.Config{
+		ConfigVersion:  contracts.ConfigSchemaVersionV1,
+		DraftKeyPrefix: "D",
+		Profiles:       map[string]contracts.ProjectProfile{"default": {ProjectKey: "PROJ"}},
+	}
+	if err := config.Write(filepath.Join(workspace, contracts.DefaultConfigFilePath), cfg); err != nil {
+		t.Fatalf("write config failed: %v", err)
+	}
+
+	newReport, err := RunNew(context.Background(), workspace, NewOptions{Summary: "Hello draft"})
+	if err != nil {
+		t.Fatalf("run new failed: %v", err)
+	}
+	key := newReport.Issues[0].Key
+	if !strings.HasPrefix(key, "D-") {
+		t.Fatalf("expected D- local key, got %q", key)
+	}
+
+	viewReport, err := RunView(workspace, ViewOptions{Key: key})
+	if err != nil {
+		t.Fatalf("run view failed: %v", err)
+	}
+	if viewReport.Issues[0].Status != contracts.PerIssueStatusSuccess {
+		t.Fatalf("expected view to find the draft, got %#v", viewReport.Issues[0])
+	}
+
+	var editedPath string
+	if _, err := RunEdit(context.Background(), workspace, EditOptions{
+		Key:    key,
+		Editor: "fake-editor",
+		RunEditor: func(ctx context.Context, editor string, absolutePath string) error {
+			editedPath = absolutePath
+			return nil
+		},
+	}); err != nil {
+		t.Fatalf("run edit failed: %v", err)
+	}
+	if !strings.HasSuffix(editedPath, filepath.Join(".issues", "open", key+"-hello-draft.md")) {
+		t.Fatalf("unexpected edited path %q", editedPath)
+	}
+}
+
+func TestRunNewUsesProfileDefaultIssueTypeUnlessFlagGiven(t *testing.T) {
+	workspace := t.TempDir()
+
+	cfg := contracts.Config{
+		ConfigVersion: contracts.ConfigSchemaVersionV1,
+		Profiles:      map[string]contracts.ProjectProfile{"default": {ProjectKey: "PROJ", DefaultIssueType: "Story"}},
+	}
+	if err := config.Write(filepath.Join(workspace, contracts.DefaultConfigFilePath), cfg); err != nil {
+		t.Fatalf("write config failed: %v", err)
+	}
+
+	for _, tc := range []struct {
+		flag string
+		want string
+	}{
+		{flag: "", want: "Story"},
+		{flag: "Bug", want: "Bug"},
+	} {
+		report, err := RunNew(context.Background(), workspace, NewOptions{Summary: "Typed " + tc.want, IssueType: tc.flag})
+		if err != nil {
+			t.Fatalf("run new failed: %v", err)
+		}
+
+		viewReport, err := RunView(workspace, ViewOptions{Key: report.Issues[0].Key})
+		if err != nil {
+			t.Fatalf("run view failed: %v", err)
+		}
+		if rendered := viewReport.Issues[0].Messages[1].Text; !strings.Contains(rendered, `issue_type: "`+tc.want+`"`) {
+			t.Fatalf("expected issue type %q for flag %q, got:\n%s", tc.want, tc.flag, rendered)
+		}
+	}
+}
+
+func TestRunNewOpenLaunchesEditorOnDraft(t *testing.T) {
+	workspace := t.TempDir()
+	if _, err := RunInit(context.Background(), workspace, InitOptions{ProjectKey: "PROJ"}); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+
+	var openedPath string
+	report, err := RunNew(context.Background(), workspace, NewOptions{
+		Summary: "Open me",
+		Open:    true,
+		Editor:  "fake-editor",
+		RunEditor: func(_ context.Context, editor string, absolutePath string) error {
+			if editor != "fake-editor" {
+				t.Fatalf("unexpected editor %q", editor)
+			}
+			openedPath = absolutePath
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("run new failed: %v", err)
+	}
+
+	key := report.Issues[0].Key
+	expected := filepath.Join(workspace, contracts.DefaultIssuesRootDir, "open", key+"-open-me.md")
+	if openedPath != expected {
+		t.Fatalf("expected editor on %q, got %q", expected, openedPath)
+	}
+	if report.Issues[0].Status != contracts.PerIssueStatusSuccess || report.Counts.Created != 1 {
+		t.Fatalf("unexpected report: %#v", report)
+	}
+}
+
+func TestRunNewOpenWithoutEditorStillCreatesDraft(t *testing.T) {
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "")
+
+	workspace := t.TempDir()
+	if _, err := RunInit(context.Background(), workspace, InitOptions{ProjectKey: "PROJ"}); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+
+	report, err := RunNew(context.Background(), workspace, NewOptions{Summary: "No editor", Open: true})
+	if err != nil {
+		t.Fatalf("expected draft creation to succeed without an editor, got %v", err)
+	}
+	result := report.Issues[0]
+	if result.Status != contracts.PerIssueStatusSuccess || !contracts.IsLocalDraftKey(result.Key) {
+		t.Fatalf("unexpected result: %#v", result)
+	}
+	if last := result.Messages[len(result.Messages)-1].Text; !strings.Contains(last, "no editor configured") {
+		t.Fatalf("expected no-editor note, got %#v", result.Messages)
+	}
+}
+
+func TestRunNewRenumberDraftsRewritesReferencesAcrossFiles(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	writePushConfig(t, workspace)
+
+	draftDoc := func(key string, summary string, body string) string {
+		return mustRenderDoc(t, issue.Document{FrontMatter: issue.FrontMatter{SchemaVersion: contracts.IssueFileSchemaVersionV1, Key: key, Summary: summary, IssueType: "Task", Status: "Open"}, CanonicalKey: key, MarkdownBody: body})
+	}
+	published := mustRenderDoc(t, issue.Document{FrontMatter: issue.FrontMatter{SchemaVersion: contracts.IssueFileSchemaVersionV1, Key: "PROJ-1", Summary: "Published", IssueType: "Task", Status: "Open"}, CanonicalKey: "PROJ-1", MarkdownBody: "Blocked by #L-aaa111"})
+	pending := draftDoc("L-ccc333", "Pending", "Mid-publish")
+
+	writeIssueFile(t, workspace, filepath.Join("closed", "L-aaa111-first.md"), draftDoc("L-aaa111", "First", "Blocks #L-bbb222 and #L-ccc333"))
+	writeIssueFile(t, workspace, filepath.Join("open", "L-bbb222-second.md"), draftDoc("L-bbb222", "Second", "Follows #L-aaa111"))
+	writeIssueFile(t, workspace, filepath.Join("open", "L-ccc333-pending.md"), pending)
+	writeIssueFile(t, workspace, filepath.Join(".sync", "publish", "L-ccc333"), "PROJ-7\n")
+	writeIssueFile(t, workspace, filepath.Join("open", "PROJ-1-published.md"), published)
+
+	report, err := RunNew(context.Background(), workspace, NewOptions{Renumber: true})
+	if err != nil {
+		t.Fatalf("renumber failed: %v", err)
+	}
+	if report.Counts.Updated != 2 {
+		t.Fatalf("expected two renumbered drafts, got %#v", report)
+	}
+
+	issuesRoot := filepath.Join(workspace, ".issues")
+	readDoc := func(relativePath string) issue.Document {
+		content, err := os.ReadFile(filepath.Join(issuesRoot, relativePath))
+		if err != nil {
+			t.Fatalf("read %s failed: %v", relativePath, err)
+		}
+		doc, err := issue.ParseDocument(relativePath, string(content))
+		if err != nil {
+			t.Fatalf("parse %s failed: %v", relativePath, err)
+		}
+		return doc
+	}
+
+	first := readDoc(filepath.Join("closed", "L-1-first.md"))
+	if first.FrontMatter.Key != "L-1" || first.MarkdownBody != "Blocks #L-2 and #L-ccc333" {
+		t.Fatalf("unexpected first draft: key=%q body=%q", first.FrontMatter.Key, first.MarkdownBody)
+	}
+	second := readDoc(filepath.Join("open", "L-2-second.md"))
+	if second.FrontMatter.Key != "L-2" || second.MarkdownBody != "Follows #L-1" {
+		t.Fatalf("unexpected second draft: key=%q body=%q", second.FrontMatter.Key, second.MarkdownBody)
+	}
+
+	for _, relativePath := range []string{filepath.Join("closed", "L-aaa111-first.md"), filepath.Join("open", "L-bbb222-second.md")} {
+		if _, err := os.Stat(filepath.Join(issuesRoot, relativePath)); !os.IsNotExist(err) {
+			t.Fatalf("expected %s to be gone, got %v", relativePath, err)
+		}
+	}
+	if staged, _ := filepath.Glob(filepath.Join(issuesRoot, "*", "*"+renumberTempSuffix)); len(staged) != 0 {
+		t.Fatalf("expected staged files to be cleaned up, got %v", staged)
+	}
+	for relativePath, want := range map[string]string{
+		filepath.Join("open", "L-ccc333-pending.md"): pending,
+		filepath.Join("open", "PROJ-1-published.md"): published,
+	} {
+		content, err := os.ReadFile(filepath.Join(issuesRoot, relativePath))
+		if err != nil || string(content) != want {
+			t.Fatalf("expected %s to be untouched, err=%v content=%q", relativePath, err, content)
+		}
+	}
+}
+
 func TestRunEditUsesConfiguredRunner(t *testing.T) {
 	workspace := t.TempDir()
 	issuesRoot := filepath.Join(workspace, contracts.DefaultIssuesRootDir)
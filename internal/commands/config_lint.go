@@ -0,0 +1,235 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pweiskircher/jira-issue-sync/internal/config"
+	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
+	httpclient "github.com/pweiskircher/jira-issue-sync/internal/http"
+	"github.com/pweiskircher/jira-issue-sync/internal/jira"
+	"github.com/pweiskircher/jira-issue-sync/internal/logging"
+	"github.com/pweiskircher/jira-issue-sync/internal/output"
+)
+
+// Lint codes are the code= prefix of `config lint` warnings.
+const (
+	lintCodeProfileUnused           = "profile_unused"
+	lintCodeAliasUnmatched          = "alias_unmatched"
+	lintCodeTransitionStatusUnknown = "transition_status_unknown"
+)
+
+type ConfigLintOptions struct {
+	Environment config.Environment
+	Adapter     jira.Adapter
+	Logger      logging.Logger
+	Insecure    bool
+}
+
+// RunConfigLint looks for config that is valid but probably wrong. Checks
+// that need Jira metadata fall back to the fields cache or are skipped with
+// a note when Jira is unreachable.
+func RunConfigLint(ctx context.Context, workDir string, options ConfigLintOptions) (output.Report, error) {
+	report := output.Report{CommandName: string(contracts.CommandConfig)}
+
+	cfg, err := config.Read(filepath.Join(workDir, contracts.DefaultConfigFilePath))
+	if err != nil {
+		return report, fmt.Errorf("failed to load config: %w", err)
+	}
+	issuesRoot := issuesRootFromConfig(workDir, cfg)
+	records, err := loadIssueRecords(issuesRoot, inspectFilter{state: stateFilterAll}, profilePreservesLabelCase(cfg))
+	if err != nil {
+		return report, fmt.Errorf("failed to read local issues: %w", err)
+	}
+
+	lintUnusedProfiles(&report, cfg, records)
+
+	adapter, skipReason := lintAdapter(workDir, cfg, options)
+
+	fieldIDs := map[string]bool{}
+	if adapter != nil {
+		fields, fieldsErr := adapter.ListFields(ctx)
+		if fieldsErr != nil {
+			skipReason = "failed to list Jira fields: " + strings.TrimSpace(fieldsErr.Error())
+			adapter = nil
+		}
+		for _, field := range fields {
+			fieldIDs[strings.TrimSpace(field.ID)] = true
+		}
+	}
+	if adapter == nil {
+		for id := range cachedFieldNames(issuesRoot, cfg, options.Logger) {
+			fieldIDs[id] = true
+		}
+	}
+	if len(fieldIDs) > 0 {
+		lintAliases(&report, cfg, fieldIDs)
+	} else {
+		if skipReason == "" {
+			skipReason = "Jira listed no fields"
+		}
+		addLintSkip(&report, "alias check skipped: "+skipReason)
+	}
+
+	if adapter == nil {
+		addLintSkip(&report, "transition status check skipped: "+skipReason)
+		return report, nil
+	}
+	statuses, err := adapter.ListStatuses(ctx)
+	if err != nil {
+		addLintSkip(&report, "transition status check skipped: failed to list Jira statuses: "+strings.TrimSpace(err.Error()))
+		return report, nil
+	}
+	lintTransitionStatuses(&report, cfg, statuses)
+
+	return report, nil
+}
+
+// lintAdapter builds an adapter from the first usable profile. Lint never
+// fails for lack of credentials; it returns why remote checks are skipped.
+func lintAdapter(workDir string, cfg contracts.Config, options ConfigLintOptions) (jira.Adapter, string) {
+	if options.Adapter != nil {
+		return options.Adapter, ""
+	}
+
+	environment := options.Environment
+	if environment == (config.Environment{}) {
+		environment = config.EnvironmentFromOS()
+	}
+	profile := strings.TrimSpace(cfg.DefaultProfile)
+	if profile == "" {
+		if names := sortedProfileNames(cfg); len(names) > 0 {
+			profile = names[0]
+		}
+	}
+	settings, err := config.Resolve(cfg, config.RuntimeFlags{Profile: profile}, environment, config.ResolveOptions{RequireToken: true})
+	if err != nil {
+		return nil, err.Error()
+	}
+
+	adapter, err := jira.NewCloudAdapter(withTLSFiles(jira.CloudAdapterOptions{
+		BaseURL:  settings.JiraBaseURL,
+		Email:    settings.JiraEmail,
+		APIToken: settings.JiraAPIToken,
+		Logger:   options.Logger,
+		RetryOptions: httpclient.Options{
+			Budget:   retryBudgetFor(nil, cfg),
+			ProxyURL: contracts.ResolveProxyURL(cfg),
+		},
+		RedactionPatterns:  redactionPatternsFor(cfg),
+		InsecureSkipVerify: options.Insecure,
+	}, workDir, cfg))
+	if err != nil {
+		return nil, err.Error()
+	}
+	return adapter, ""
+}
+
+// lintUnusedProfiles flags profiles whose project has no local issues while
+// other projects do. A fresh workspace has nothing to compare against.
+func lintUnusedProfiles(report *output.Report, cfg contracts.Config, records []issueRecord) {
+	if len(cfg.Profiles) < 2 {
+		return
+	}
+	projects := make(map[string]bool)
+	for _, record := range records {
+		if index := strings.LastIndex(record.Key, "-"); index > 0 && contracts.JiraIssueKeyPattern.MatchString(record.Key) {
+			projects[record.Key[:index]] = true
+		}
+	}
+	if len(projects) == 0 {
+		return
+	}
+
+	for _, name := range sortedProfileNames(cfg) {
+		if name == strings.TrimSpace(cfg.DefaultProfile) {
+			continue
+		}
+		projectKey := strings.TrimSpace(cfg.Profiles[name].ProjectKey)
+		if projects[projectKey] {
+			continue
+		}
+		addLintWarning(report, "profiles."+name, lintCodeProfileUnused,
+			fmt.Sprintf("profile %q (project %s) matches no local issue; remove it if it is no longer used", name, projectKey))
+	}
+}
+
+func lintAliases(report *output.Report, cfg contracts.Config, fieldIDs map[string]bool) {
+	for _, name := range sortedProfileNames(cfg) {
+		aliases := cfg.Profiles[name].FieldConfig.Aliases
+		names := make([]string, 0, len(aliases))
+		for alias := range aliases {
+			names = append(names, alias)
+		}
+		sort.Strings(names)
+		for _, alias := range names {
+			fieldID := strings.TrimSpace(aliases[alias])
+			if fieldIDs[fieldID] {
+				continue
+			}
+			addLintWarning(report, "profiles."+name+".field_config.aliases."+alias, lintCodeAliasUnmatched,
+				fmt.Sprintf("alias %q maps to field %q, which Jira does not list; pulls will never fill it", alias, fieldID))
+		}
+	}
+}
+
+func lintTransitionStatuses(report *output.Report, cfg contracts.Config, statuses []jira.StatusRef) {
+	known := make(map[string]bool, len(statuses))
+	for _, status := range statuses {
+		known[strings.ToLower(strings.TrimSpace(status.Name))] = true
+	}
+
+	for _, name := range sortedProfileNames(cfg) {
+		overrides := cfg.Profiles[name].TransitionOverrides
+		targets := make([]string, 0, len(overrides))
+		for target := range overrides {
+			targets = append(targets, target)
+		}
+		sort.Strings(targets)
+		for _, target := range targets {
+			path := "profiles." + name + ".transition_overrides." + target
+			// The wildcard names no status of its own; only its dynamic
+			// target below can be checked.
+			if target != contracts.TransitionOverrideWildcard && !known[strings.ToLower(strings.TrimSpace(target))] {
+				addLintWarning(report, path, lintCodeTransitionStatusUnknown,
+					fmt.Sprintf("transition override for status %q matches no Jira workflow status", target))
+			}
+			if dynamic := overrides[target].Dynamic; dynamic != nil {
+				if status := strings.TrimSpace(dynamic.TargetStatus); status != "" && !known[strings.ToLower(status)] {
+					addLintWarning(report, path+".dynamic.target_status", lintCodeTransitionStatusUnknown,
+						fmt.Sprintf("dynamic target status %q matches no Jira workflow status", status))
+				}
+			}
+		}
+	}
+}
+
+func sortedProfileNames(cfg contracts.Config) []string {
+	names := make([]string, 0, len(cfg.Profiles))
+	for name := range cfg.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func addLintWarning(report *output.Report, path string, code string, message string) {
+	addIssueResult(report, contracts.PerIssueResult{
+		Key:      path,
+		Action:   "lint",
+		Status:   contracts.PerIssueStatusWarning,
+		Messages: []contracts.IssueMessage{buildTypedDiagnostic("warning", contracts.ReasonCodeValidationFailed, code, message, path)},
+	})
+}
+
+func addLintSkip(report *output.Report, reason string) {
+	addIssueResult(report, contracts.PerIssueResult{
+		Key:      "config",
+		Action:   "lint",
+		Status:   contracts.PerIssueStatusSkipped,
+		Messages: []contracts.IssueMessage{{Level: "info", Text: reason}},
+	})
+}
@@ -1,39 +1,59 @@
 package commands
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
 	"github.com/pweiskircher/jira-issue-sync/internal/issue"
 	"github.com/pweiskircher/jira-issue-sync/internal/output"
+	"github.com/pweiskircher/jira-issue-sync/internal/sync/push/plan"
 )
 
 type DiffOptions struct {
 	State            string
 	Key              string
 	IncludeUnchanged bool
+	Reasons          []string
+	// OutputDir, when set, receives each issue's diff as <KEY>.diff instead
+	// of printing it.
+	OutputDir string
 }
 
 func RunDiff(workDir string, options DiffOptions) (output.Report, error) {
 	report := output.Report{CommandName: string(contracts.CommandDiff)}
 
-	filter, err := normalizeFilter(options.State, options.Key)
+	filter, err := normalizeFilter(options.State, options.Key, options.Reasons)
 	if err != nil {
 		return report, err
 	}
 
-	records, err := loadIssueRecords(workDir, filter)
+	cfg, err := resolveWorkspaceConfig(workDir)
+	if err != nil {
+		return report, err
+	}
+	issuesRoot := issuesRootFromConfig(workDir, cfg)
+	preserveLabelCase := profilePreservesLabelCase(cfg)
+
+	records, err := loadIssueRecords(issuesRoot, filter, preserveLabelCase)
 	if err != nil {
 		return report, fmt.Errorf("failed to read local issues: %w", err)
 	}
 
+	outputDir, err := resolveOutputDir(workDir, options.OutputDir)
+	if err != nil {
+		return report, err
+	}
+
 	for _, record := range records {
 		if record.Err != nil {
-			addIssueResult(&report, contracts.PerIssueResult{
+			filter.addResult(&report, contracts.PerIssueResult{
 				Key:    record.Key,
 				Action: "parse-error",
 				Status: contracts.PerIssueStatusError,
@@ -44,23 +64,26 @@ func RunDiff(workDir string, options DiffOptions) (output.Report, error) {
 			continue
 		}
 
-		result := buildDiffResult(workDir, record)
+		result := buildDiffResult(issuesRoot, record, preserveLabelCase)
 		if !options.IncludeUnchanged && result.Action == "unchanged" {
 			continue
 		}
-		addIssueResult(&report, result)
+		if outputDir != "" && (result.Action == "different" || result.Action == "new") && filter.matchesReason(result) {
+			result = artifactResult(result, outputDir, ".diff", result.Messages[0].Text)
+		}
+		filter.addResult(&report, result)
 	}
 
 	return report, nil
 }
 
-func buildDiffResult(workDir string, record issueRecord) contracts.PerIssueResult {
+func buildDiffResult(issuesRoot string, record issueRecord, preserveLabelCase bool) contracts.PerIssueResult {
 	snapshotRelativePath := filepath.Join(".sync", "originals", record.Key+".md")
-	snapshotAbsolutePath := filepath.Join(workDir, contracts.DefaultIssuesRootDir, snapshotRelativePath)
+	snapshotAbsolutePath := filepath.Join(issuesRoot, snapshotRelativePath)
 	snapshotContent, err := os.ReadFile(snapshotAbsolutePath)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
-			if contracts.LocalDraftKeyPattern.MatchString(record.Key) {
+			if contracts.IsLocalDraftKey(record.Key) {
 				return contracts.PerIssueResult{
 					Key:    record.Key,
 					Action: "new",
@@ -98,7 +121,7 @@ func buildDiffResult(workDir string, record issueRecord) contracts.PerIssueResul
 		}
 	}
 
-	snapshotDoc, parseErr := issue.ParseDocument(snapshotRelativePath, string(snapshotContent))
+	snapshotDoc, parseErr := parseIssueDocument(snapshotRelativePath, string(snapshotContent), preserveLabelCase)
 	if parseErr != nil {
 		reason := contracts.ReasonCodeValidationFailed
 		code := "snapshot_parse_failed"
@@ -129,7 +152,7 @@ func buildDiffResult(workDir string, record issueRecord) contracts.PerIssueResul
 		}
 	}
 
-	if snapshotCanonical == record.Canonical {
+	if issue.EqualIgnoringVolatile(snapshotCanonical, record.Canonical) {
 		return contracts.PerIssueResult{
 			Key:    record.Key,
 			Action: "unchanged",
@@ -147,17 +170,96 @@ func buildDiffResult(workDir string, record issueRecord) contracts.PerIssueResul
 		Status: contracts.PerIssueStatusSuccess,
 		Messages: []contracts.IssueMessage{{
 			Level: "info",
-			Text:  deterministicDiff(snapshotCanonical, record.Canonical),
+			Text:  structuredDiff("original", snapshotDoc, record.Document, snapshotCanonical, record.Canonical),
 		}},
 	}
 }
 
+// structuredDiff line-diffs everything except labels and custom fields, which
+// are reported per label and per key so a one-label edit does not show up as
+// the whole block being rewritten. baseLabel names the original side in the
+// diff header.
+func structuredDiff(baseLabel string, original issue.Document, local issue.Document, originalCanonical string, localCanonical string) string {
+	originalText, originalErr := renderWithoutSetFields(original)
+	localText, localErr := renderWithoutSetFields(local)
+	if originalErr != nil || localErr != nil {
+		return labeledDiff(baseLabel, issue.StripVolatileFrontMatter(originalCanonical), issue.StripVolatileFrontMatter(localCanonical))
+	}
+
+	lines := []string{labeledDiff(baseLabel, originalText, localText)}
+	lines = append(lines, labelChanges(original.FrontMatter.Labels, local.FrontMatter.Labels)...)
+	lines = append(lines, customFieldChanges(original.FrontMatter.CustomFields, local.FrontMatter.CustomFields)...)
+	return strings.Join(lines, "\n")
+}
+
+func renderWithoutSetFields(doc issue.Document) (string, error) {
+	doc.FrontMatter.Labels = nil
+	doc.FrontMatter.CustomFields = nil
+	rendered, err := issue.RenderDocument(doc)
+	if err != nil {
+		return "", err
+	}
+	return issue.StripVolatileFrontMatter(rendered), nil
+}
+
+func labelChanges(original []string, local []string) []string {
+	added, removed := plan.LabelDelta(contracts.NormalizeLabelsPreservingCase(original), contracts.NormalizeLabelsPreservingCase(local))
+	changes := make([]string, 0, len(added)+len(removed))
+	for _, label := range added {
+		changes = append(changes, "added label "+label)
+	}
+	for _, label := range removed {
+		changes = append(changes, "removed label "+label)
+	}
+	return changes
+}
+
+func customFieldChanges(original map[string]json.RawMessage, local map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(original)+len(local))
+	for key := range original {
+		keys = append(keys, key)
+	}
+	for key := range local {
+		if _, ok := original[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	changes := make([]string, 0)
+	for _, key := range keys {
+		before, hadBefore := original[key]
+		after, hasAfter := local[key]
+		switch {
+		case !hadBefore:
+			changes = append(changes, fmt.Sprintf("added custom field %s: %s", key, compactJSON(after)))
+		case !hasAfter:
+			changes = append(changes, fmt.Sprintf("removed custom field %s", key))
+		case compactJSON(before) != compactJSON(after):
+			changes = append(changes, fmt.Sprintf("changed custom field %s: %s -> %s", key, compactJSON(before), compactJSON(after)))
+		}
+	}
+	return changes
+}
+
+func compactJSON(raw json.RawMessage) string {
+	var buffer bytes.Buffer
+	if err := json.Compact(&buffer, raw); err != nil {
+		return string(raw)
+	}
+	return buffer.String()
+}
+
 func deterministicDiff(original string, local string) string {
+	return labeledDiff("original", original, local)
+}
+
+func labeledDiff(baseLabel string, original string, local string) string {
 	originalLines := splitLines(original)
 	localLines := splitLines(local)
 
 	var builder strings.Builder
-	builder.WriteString("--- original\n")
+	builder.WriteString("--- " + baseLabel + "\n")
 	builder.WriteString("+++ local\n")
 
 	i := 0
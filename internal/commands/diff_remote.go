@@ -0,0 +1,202 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pweiskircher/jira-issue-sync/internal/clock"
+	"github.com/pweiskircher/jira-issue-sync/internal/config"
+	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
+	httpclient "github.com/pweiskircher/jira-issue-sync/internal/http"
+	"github.com/pweiskircher/jira-issue-sync/internal/issue"
+	"github.com/pweiskircher/jira-issue-sync/internal/jira"
+	"github.com/pweiskircher/jira-issue-sync/internal/logging"
+	"github.com/pweiskircher/jira-issue-sync/internal/output"
+	pullsync "github.com/pweiskircher/jira-issue-sync/internal/sync/pull"
+)
+
+// DiffRemoteOptions configures `diff --remote`, which compares working
+// copies against the live Jira issues instead of the original snapshots.
+type DiffRemoteOptions struct {
+	Profile          string
+	State            string
+	Key              string
+	IncludeUnchanged bool
+	Reasons          []string
+	Clock            clock.Clock
+	Environment      config.Environment
+	Adapter          jira.Adapter
+	Logger           logging.Logger
+	RetryBudget      *httpclient.RetryBudget
+	// Insecure skips TLS certificate verification for Jira requests.
+	Insecure bool
+	// JiraBaseURL and JiraEmail override env and config for this run.
+	JiraBaseURL string
+	JiraEmail   string
+}
+
+// RunDiffRemote fetches each matching issue and diffs the working copy
+// against it as pull would render it. Unlike RunDiff it also shows remote
+// edits made since the last pull, which the snapshot cannot reveal.
+func RunDiffRemote(ctx context.Context, workDir string, options DiffRemoteOptions) (output.Report, error) {
+	report := output.Report{CommandName: string(contracts.CommandDiff)}
+
+	filter, err := normalizeFilter(options.State, options.Key, options.Reasons)
+	if err != nil {
+		return report, err
+	}
+
+	cfg, err := resolveWorkspaceConfig(workDir)
+	if err != nil {
+		return report, err
+	}
+
+	environment := options.Environment
+	if environment == (config.Environment{}) {
+		environment = config.EnvironmentFromOS()
+	}
+
+	settings, err := config.Resolve(cfg, config.RuntimeFlags{Profile: options.Profile, JiraBaseURL: options.JiraBaseURL, JiraEmail: options.JiraEmail}, environment, config.ResolveOptions{RequireToken: true})
+	if err != nil {
+		return report, err
+	}
+
+	adapter := options.Adapter
+	if adapter == nil {
+		adapter, err = jira.NewCloudAdapter(withTLSFiles(jira.CloudAdapterOptions{
+			BaseURL:  settings.JiraBaseURL,
+			Email:    settings.JiraEmail,
+			APIToken: settings.JiraAPIToken,
+			Logger:   options.Logger,
+			RetryOptions: httpclient.Options{
+				Budget:   retryBudgetFor(options.RetryBudget, cfg),
+				Clock:    options.Clock,
+				ProxyURL: contracts.ResolveProxyURL(cfg),
+			},
+			RedactionPatterns:  redactionPatternsFor(cfg),
+			InsecureSkipVerify: options.Insecure,
+		}, workDir, cfg))
+		if err != nil {
+			return report, fmt.Errorf("failed to initialize jira adapter: %w", err)
+		}
+	}
+
+	issuesRoot := issuesRootFromConfig(workDir, cfg)
+	issueStore, err := openIssueStore(issuesRoot, cfg)
+	if err != nil {
+		return report, fmt.Errorf("failed to initialize issue store: %w", err)
+	}
+
+	records, err := loadIssueRecords(issuesRoot, filter, settings.Profile.PreserveLabelCase)
+	if err != nil {
+		return report, fmt.Errorf("failed to read local issues: %w", err)
+	}
+
+	pipeline := pullsync.Pipeline{
+		Adapter:            adapter,
+		Store:              issueStore,
+		Converter:          pullsync.NewADFMarkdownConverter(pullsync.ConverterOptions{Flavor: contracts.ResolveMarkdownFlavor(cfg)}),
+		Clock:              options.Clock,
+		Logger:             options.Logger,
+		CustomFieldAliases: settings.Profile.FieldConfig.Aliases,
+		PullFields:         resolvePullFields(settings.Profile.FieldConfig),
+		SkipEnvironment:    settings.Profile.FieldConfig.SkipEnvironment,
+		PreserveLabelCase:  settings.Profile.PreserveLabelCase,
+		IncludeComments:    settings.Profile.FieldConfig.IncludeMetadata,
+	}
+
+	for _, record := range records {
+		if record.Err != nil {
+			filter.addResult(&report, contracts.PerIssueResult{
+				Key:    record.Key,
+				Action: "parse-error",
+				Status: contracts.PerIssueStatusError,
+				Messages: []contracts.IssueMessage{
+					buildTypedDiagnostic("error", record.ReasonCode, record.ErrorCode, record.Err.Error(), record.RelativePath),
+				},
+			})
+			continue
+		}
+
+		result := buildRemoteDiffResult(ctx, pipeline, record)
+		if !options.IncludeUnchanged && result.Action == "unchanged" {
+			continue
+		}
+		filter.addResult(&report, result)
+	}
+
+	return report, nil
+}
+
+func buildRemoteDiffResult(ctx context.Context, pipeline pullsync.Pipeline, record issueRecord) contracts.PerIssueResult {
+	if contracts.IsLocalDraftKey(record.Key) {
+		return contracts.PerIssueResult{
+			Key:    record.Key,
+			Action: "new",
+			Status: contracts.PerIssueStatusSuccess,
+			Messages: []contracts.IssueMessage{{
+				Level: "info",
+				Text:  labeledDiff("remote", "", record.Canonical),
+			}},
+		}
+	}
+
+	remoteCanonical, err := pipeline.FetchCanonical(ctx, record.Key)
+	if err != nil {
+		reason := contracts.ReasonCodeValidationFailed
+		code := "fetch_issue_failed"
+		if typed := asJiraError(err); typed != nil {
+			if typed.ReasonCode != "" {
+				reason = typed.ReasonCode
+			}
+			code = string(typed.Code)
+		}
+		return contracts.PerIssueResult{
+			Key:    record.Key,
+			Action: "fetch-error",
+			Status: contracts.PerIssueStatusError,
+			Messages: []contracts.IssueMessage{
+				buildTypedDiagnostic("error", reason, code, err.Error(), record.RelativePath),
+			},
+		}
+	}
+
+	// The remote side goes through the same parse and render as the local
+	// file, so only content differences remain.
+	remoteDoc, err := parseIssueDocument(record.RelativePath, remoteCanonical, pipeline.PreserveLabelCase)
+	if err == nil {
+		remoteCanonical, err = issue.RenderDocument(remoteDoc)
+	}
+	if err != nil {
+		return contracts.PerIssueResult{
+			Key:    record.Key,
+			Action: "fetch-error",
+			Status: contracts.PerIssueStatusError,
+			Messages: []contracts.IssueMessage{
+				buildTypedDiagnostic("error", contracts.ReasonCodeValidationFailed, "remote_render_failed", err.Error(), record.RelativePath),
+			},
+		}
+	}
+
+	if issue.EqualIgnoringVolatile(remoteCanonical, record.Canonical) {
+		return contracts.PerIssueResult{
+			Key:    record.Key,
+			Action: "unchanged",
+			Status: contracts.PerIssueStatusSuccess,
+			Messages: []contracts.IssueMessage{{
+				Level: "info",
+				Text:  "no differences from remote",
+			}},
+		}
+	}
+
+	return contracts.PerIssueResult{
+		Key:    record.Key,
+		Action: "different",
+		Status: contracts.PerIssueStatusSuccess,
+		Messages: []contracts.IssueMessage{{
+			Level: "info",
+			Text:  structuredDiff("remote", remoteDoc, record.Document, remoteCanonical, record.Canonical),
+		}},
+	}
+}
@@ -2,7 +2,7 @@ package commands
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
@@ -21,22 +21,17 @@ type EditOptions struct {
 func RunEdit(ctx context.Context, workDir string, options EditOptions) (output.Report, error) {
 	report := output.Report{CommandName: string(contracts.CommandEdit)}
 
-	relativePath, err := findIssuePathByKey(workDir, options.Key)
+	issuesRoot, err := resolveIssuesRoot(workDir)
 	if err != nil {
 		return report, err
 	}
 
-	absolutePath := filepath.Join(workDir, contracts.DefaultIssuesRootDir, relativePath)
-	editor := resolveEditor(options.Editor)
-	if editor == "" {
-		return report, fmt.Errorf("no editor configured (set --editor, VISUAL, or EDITOR)")
+	relativePath, err := findIssuePathByKey(issuesRoot, options.Key)
+	if err != nil {
+		return report, err
 	}
 
-	runner := options.RunEditor
-	if runner == nil {
-		runner = runEditor
-	}
-	if err := runner(ctx, editor, absolutePath); err != nil {
+	if err := openInEditor(ctx, options, filepath.Join(issuesRoot, relativePath)); err != nil {
 		return report, err
 	}
 
@@ -53,6 +48,23 @@ func RunEdit(ctx context.Context, workDir string, options EditOptions) (output.R
 	return report, nil
 }
 
+var errNoEditor = errors.New("no editor configured (set --editor, VISUAL, or EDITOR)")
+
+// openInEditor runs the resolved editor on absolutePath, returning
+// errNoEditor when none is configured.
+func openInEditor(ctx context.Context, options EditOptions, absolutePath string) error {
+	editor := resolveEditor(options.Editor)
+	if editor == "" {
+		return errNoEditor
+	}
+
+	runner := options.RunEditor
+	if runner == nil {
+		runner = runEditor
+	}
+	return runner(ctx, editor, absolutePath)
+}
+
 func resolveEditor(editorFlag string) string {
 	if trimmed := strings.TrimSpace(editorFlag); trimmed != "" {
 		return trimmed
@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"sort"
+
+	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
+)
+
+// ExplainDocument enumerates the stable exit codes and reason codes that
+// scripts can rely on.
+type ExplainDocument struct {
+	EnvelopeVersion string            `json:"envelope_version"`
+	ExitCodes       []ExplainedCode   `json:"exit_codes"`
+	ReasonCodes     []ExplainedReason `json:"reason_codes"`
+}
+
+type ExplainedCode struct {
+	Code        int    `json:"code"`
+	Description string `json:"description"`
+}
+
+type ExplainedReason struct {
+	Code        contracts.ReasonCode `json:"code"`
+	Description string               `json:"description"`
+}
+
+func RunExplain() ExplainDocument {
+	document := ExplainDocument{
+		EnvelopeVersion: contracts.JSONEnvelopeVersionV1,
+		ExitCodes:       make([]ExplainedCode, 0, len(contracts.ExitCodeMeaning)),
+		ReasonCodes:     make([]ExplainedReason, 0, len(contracts.StableReasonCodes)),
+	}
+
+	for code, description := range contracts.ExitCodeMeaning {
+		document.ExitCodes = append(document.ExitCodes, ExplainedCode{Code: int(code), Description: description})
+	}
+	sort.Slice(document.ExitCodes, func(i int, j int) bool {
+		return document.ExitCodes[i].Code < document.ExitCodes[j].Code
+	})
+
+	for _, code := range contracts.StableReasonCodes {
+		document.ReasonCodes = append(document.ReasonCodes, ExplainedReason{Code: code, Description: contracts.ReasonCodeMeaning[code]})
+	}
+
+	return document
+}
@@ -9,7 +9,9 @@ import (
 
 	"github.com/pweiskircher/jira-issue-sync/internal/config"
 	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
+	httpclient "github.com/pweiskircher/jira-issue-sync/internal/http"
 	"github.com/pweiskircher/jira-issue-sync/internal/jira"
+	"github.com/pweiskircher/jira-issue-sync/internal/logging"
 	"github.com/pweiskircher/jira-issue-sync/internal/output"
 )
 
@@ -19,6 +21,10 @@ type FieldsOptions struct {
 	Search      string
 	Environment config.Environment
 	Adapter     jira.Adapter
+	Logger      logging.Logger
+	RetryBudget *httpclient.RetryBudget
+	// Insecure skips TLS certificate verification for Jira requests.
+	Insecure bool
 }
 
 func RunFields(ctx context.Context, workDir string, options FieldsOptions) (output.Report, error) {
@@ -41,11 +47,18 @@ func RunFields(ctx context.Context, workDir string, options FieldsOptions) (outp
 
 	adapter := options.Adapter
 	if adapter == nil {
-		adapter, err = jira.NewCloudAdapter(jira.CloudAdapterOptions{
+		adapter, err = jira.NewCloudAdapter(withTLSFiles(jira.CloudAdapterOptions{
 			BaseURL:  settings.JiraBaseURL,
 			Email:    settings.JiraEmail,
 			APIToken: settings.JiraAPIToken,
-		})
+			Logger:   options.Logger,
+			RetryOptions: httpclient.Options{
+				Budget:   retryBudgetFor(options.RetryBudget, cfg),
+				ProxyURL: contracts.ResolveProxyURL(cfg),
+			},
+			RedactionPatterns:  redactionPatternsFor(cfg),
+			InsecureSkipVerify: options.Insecure,
+		}, workDir, cfg))
 		if err != nil {
 			return report, fmt.Errorf("failed to initialize jira adapter: %w", err)
 		}
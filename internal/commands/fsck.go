@@ -0,0 +1,61 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
+	"github.com/pweiskircher/jira-issue-sync/internal/issue"
+	"github.com/pweiskircher/jira-issue-sync/internal/output"
+	"github.com/pweiskircher/jira-issue-sync/internal/store"
+	pullsync "github.com/pweiskircher/jira-issue-sync/internal/sync/pull"
+)
+
+type FsckOptions struct{}
+
+func RunFsck(workDir string, options FsckOptions) (output.Report, error) {
+	report := output.Report{CommandName: string(contracts.CommandFsck)}
+
+	cfg, err := resolveWorkspaceConfig(workDir)
+	if err != nil {
+		return report, err
+	}
+
+	workspaceStore, err := openIssueStore(issuesRootFromConfig(workDir, cfg), cfg)
+	if err != nil {
+		return report, err
+	}
+	workspaceStore.SetParseOptions(issue.ParseOptions{
+		ADFConverter:      pullsync.NewADFMarkdownConverter(pullsync.ConverterOptions{Flavor: contracts.ResolveMarkdownFlavor(cfg)}),
+		PreserveLabelCase: profilePreservesLabelCase(cfg),
+	})
+
+	result, err := workspaceStore.Verify()
+	if err != nil {
+		return report, fmt.Errorf("failed to verify workspace: %w", err)
+	}
+
+	for _, problem := range result.Problems {
+		level := "warning"
+		status := contracts.PerIssueStatusWarning
+		if problem.Code == store.VerifyProblemParseFailed || problem.Code == store.VerifyProblemCacheUnreadable {
+			level = "error"
+			status = contracts.PerIssueStatusError
+		}
+
+		key := problem.Key
+		if key == "" {
+			key = problem.Path
+		}
+
+		addIssueResult(&report, contracts.PerIssueResult{
+			Key:    key,
+			Action: "fsck",
+			Status: status,
+			Messages: []contracts.IssueMessage{
+				buildTypedDiagnostic(level, problem.ReasonCode, string(problem.Code), problem.Message, problem.Path),
+			},
+		})
+	}
+
+	return report, nil
+}
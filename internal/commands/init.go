@@ -1,13 +1,18 @@
 package commands
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/pweiskircher/jira-issue-sync/internal/config"
 	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
+	httpclient "github.com/pweiskircher/jira-issue-sync/internal/http"
+	"github.com/pweiskircher/jira-issue-sync/internal/jira"
+	"github.com/pweiskircher/jira-issue-sync/internal/logging"
 	"github.com/pweiskircher/jira-issue-sync/internal/output"
 	"github.com/pweiskircher/jira-issue-sync/internal/store"
 )
@@ -19,12 +24,22 @@ type InitOptions struct {
 	JiraEmail   string
 	DefaultJQL  string
 	ProfileJQL  string
+	IssuesDir   string
 	Force       bool
 	IssuesRoot  string
 	ConfigPath  string
+	// Discover checks the project key against Jira and writes a starter
+	// field_config plus the fields cache. Without credentials or network
+	// it is skipped with a warning and init proceeds as usual.
+	Discover    bool
+	Environment config.Environment
+	Adapter     jira.Adapter
+	Logger      logging.Logger
+	// Insecure skips TLS certificate verification for Jira requests.
+	Insecure bool
 }
 
-func RunInit(workDir string, options InitOptions) (output.Report, error) {
+func RunInit(ctx context.Context, workDir string, options InitOptions) (output.Report, error) {
 	report := output.Report{CommandName: string(contracts.CommandInit)}
 
 	projectKey := strings.TrimSpace(options.ProjectKey)
@@ -37,9 +52,40 @@ func RunInit(workDir string, options InitOptions) (output.Report, error) {
 		profile = "default"
 	}
 
+	cfg := contracts.Config{
+		ConfigVersion: contracts.ConfigSchemaVersionV1,
+		Jira: contracts.JiraConfig{
+			BaseURL: strings.TrimSpace(options.JiraBaseURL),
+			Email:   strings.TrimSpace(options.JiraEmail),
+		},
+		DefaultProfile: profile,
+		DefaultJQL:     strings.TrimSpace(options.DefaultJQL),
+		IssuesRoot:     strings.TrimSpace(options.IssuesDir),
+		Profiles: map[string]contracts.ProjectProfile{
+			profile: {
+				ProjectKey: strings.ToUpper(projectKey),
+				DefaultJQL: strings.TrimSpace(options.ProfileJQL),
+			},
+		},
+	}
+	if err := contracts.ValidateConfig(cfg); err != nil {
+		return report, err
+	}
+
+	var discovered []jira.FieldDefinition
+	var discoverMessage *contracts.IssueMessage
+	if options.Discover {
+		discovered, discoverMessage = discoverFields(ctx, cfg, options)
+		if discoverMessage == nil {
+			profileConfig := cfg.Profiles[profile]
+			profileConfig.FieldConfig = starterFieldConfig(discovered)
+			cfg.Profiles[profile] = profileConfig
+		}
+	}
+
 	issuesRoot := strings.TrimSpace(options.IssuesRoot)
 	if issuesRoot == "" {
-		issuesRoot = filepath.Join(workDir, contracts.DefaultIssuesRootDir)
+		issuesRoot = issuesRootFromConfig(workDir, cfg)
 	}
 
 	configPath := strings.TrimSpace(options.ConfigPath)
@@ -61,22 +107,6 @@ func RunInit(workDir string, options InitOptions) (output.Report, error) {
 		return report, err
 	}
 
-	cfg := contracts.Config{
-		ConfigVersion: contracts.ConfigSchemaVersionV1,
-		Jira: contracts.JiraConfig{
-			BaseURL: strings.TrimSpace(options.JiraBaseURL),
-			Email:   strings.TrimSpace(options.JiraEmail),
-		},
-		DefaultProfile: profile,
-		DefaultJQL:     strings.TrimSpace(options.DefaultJQL),
-		Profiles: map[string]contracts.ProjectProfile{
-			profile: {
-				ProjectKey: strings.ToUpper(projectKey),
-				DefaultJQL: strings.TrimSpace(options.ProfileJQL),
-			},
-		},
-	}
-
 	if err := config.Write(configPath, cfg); err != nil {
 		return report, err
 	}
@@ -85,7 +115,7 @@ func RunInit(workDir string, options InitOptions) (output.Report, error) {
 	if options.Force {
 		action = "modified"
 	}
-	addIssueResult(&report, contracts.PerIssueResult{
+	result := contracts.PerIssueResult{
 		Key:    "workspace",
 		Action: action,
 		Status: contracts.PerIssueStatusSuccess,
@@ -93,7 +123,120 @@ func RunInit(workDir string, options InitOptions) (output.Report, error) {
 			Level: "info",
 			Text:  "config=" + configPath + " issues_root=" + issuesRoot + " profile=" + profile,
 		}},
-	})
+	}
+	if options.Discover {
+		if discoverMessage == nil {
+			discoverMessage = saveDiscoveredFields(workspaceStore, discovered, len(cfg.Profiles[profile].FieldConfig.Aliases))
+		}
+		if discoverMessage.Level == "warning" {
+			result.Status = contracts.PerIssueStatusWarning
+		}
+		result.Messages = append(result.Messages, *discoverMessage)
+	}
+	addIssueResult(&report, result)
 
 	return report, nil
 }
+
+// discoverFields confirms the project exists and lists Jira fields. Any
+// failure is returned as a warning message instead of an error.
+func discoverFields(ctx context.Context, cfg contracts.Config, options InitOptions) ([]jira.FieldDefinition, *contracts.IssueMessage) {
+	skipped := func(reason string) ([]jira.FieldDefinition, *contracts.IssueMessage) {
+		return nil, &contracts.IssueMessage{Level: "warning", Text: "discovery skipped: " + reason}
+	}
+
+	environment := options.Environment
+	if environment == (config.Environment{}) {
+		environment = config.EnvironmentFromOS()
+	}
+	settings, err := config.Resolve(cfg, config.RuntimeFlags{}, environment, config.ResolveOptions{RequireToken: true})
+	if err != nil {
+		return skipped(err.Error())
+	}
+
+	adapter := options.Adapter
+	if adapter == nil {
+		adapter, err = jira.NewCloudAdapter(jira.CloudAdapterOptions{
+			BaseURL:            settings.JiraBaseURL,
+			Email:              settings.JiraEmail,
+			APIToken:           settings.JiraAPIToken,
+			Logger:             options.Logger,
+			RetryOptions:       httpclient.Options{Budget: retryBudgetFor(nil, cfg), ProxyURL: contracts.ResolveProxyURL(cfg)},
+			InsecureSkipVerify: options.Insecure,
+		})
+		if err != nil {
+			return skipped(err.Error())
+		}
+	}
+
+	if err := validateProjectKey(ctx, adapter, settings.Profile.ProjectKey); err != nil {
+		if typed := asJiraError(err); typed != nil {
+			return skipped("failed to list projects: " + strings.TrimSpace(typed.Error()))
+		}
+		return skipped(err.Error())
+	}
+
+	fields, err := adapter.ListFields(ctx)
+	if err != nil {
+		return skipped("failed to list fields: " + strings.TrimSpace(err.Error()))
+	}
+	return fields, nil
+}
+
+// starterFieldConfig aliases every custom field by its slugged name. Names
+// that slug to nothing or collide fall back to the name plus the field
+// number, so each alias is unique.
+func starterFieldConfig(fields []jira.FieldDefinition) contracts.FieldConfig {
+	custom := make([]jira.FieldDefinition, 0, len(fields))
+	for _, field := range fields {
+		if field.Custom && strings.TrimSpace(field.ID) != "" {
+			custom = append(custom, field)
+		}
+	}
+	sort.Slice(custom, func(i, j int) bool { return custom[i].ID < custom[j].ID })
+
+	aliases := make(map[string]string, len(custom))
+	used := make(map[string]struct{}, len(custom))
+	for _, field := range custom {
+		id := strings.TrimSpace(field.ID)
+		alias := fieldAliasSlug(field.Name)
+		if _, taken := used[alias]; taken || alias == "" {
+			alias = strings.Trim(alias+"_"+strings.TrimPrefix(id, "customfield_"), "_")
+		}
+		used[alias] = struct{}{}
+		aliases[id] = alias
+	}
+
+	if len(aliases) == 0 {
+		return contracts.FieldConfig{}
+	}
+	return contracts.FieldConfig{Aliases: aliases}
+}
+
+func fieldAliasSlug(name string) string {
+	var builder strings.Builder
+	lastUnderscore := true
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			builder.WriteRune(r)
+			lastUnderscore = false
+			continue
+		}
+		if !lastUnderscore {
+			builder.WriteByte('_')
+			lastUnderscore = true
+		}
+	}
+	return strings.Trim(builder.String(), "_")
+}
+
+func saveDiscoveredFields(workspaceStore *store.Store, fields []jira.FieldDefinition, aliasCount int) *contracts.IssueMessage {
+	entries := make([]store.FieldsCacheEntry, 0, len(fields))
+	for _, field := range fields {
+		entries = append(entries, store.FieldsCacheEntry{ID: strings.TrimSpace(field.ID), Name: strings.TrimSpace(field.Name), Custom: field.Custom})
+	}
+	if err := workspaceStore.SaveFieldsCache(store.FieldsCache{Fields: entries}); err != nil {
+		return &contracts.IssueMessage{Level: "warning", Text: "failed to write fields cache: " + strings.TrimSpace(err.Error())}
+	}
+	return &contracts.IssueMessage{Level: "info", Text: fmt.Sprintf("discovered %d fields; aliased %d custom fields in field_config", len(fields), aliasCount)}
+}
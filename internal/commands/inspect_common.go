@@ -5,12 +5,18 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
 
+	"github.com/pweiskircher/jira-issue-sync/internal/config"
 	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
+	httpclient "github.com/pweiskircher/jira-issue-sync/internal/http"
 	"github.com/pweiskircher/jira-issue-sync/internal/issue"
+	"github.com/pweiskircher/jira-issue-sync/internal/jira"
+	"github.com/pweiskircher/jira-issue-sync/internal/logging"
 	"github.com/pweiskircher/jira-issue-sync/internal/output"
+	"github.com/pweiskircher/jira-issue-sync/internal/store"
 )
 
 const (
@@ -31,11 +37,12 @@ type issueRecord struct {
 }
 
 type inspectFilter struct {
-	state string
-	key   string
+	state   string
+	key     string
+	reasons map[contracts.ReasonCode]struct{}
 }
 
-func normalizeFilter(state string, key string) (inspectFilter, error) {
+func normalizeFilter(state string, key string, reasons []string) (inspectFilter, error) {
 	normalizedState := strings.ToLower(strings.TrimSpace(state))
 	if normalizedState == "" {
 		normalizedState = stateFilterAll
@@ -51,23 +58,123 @@ func normalizeFilter(state string, key string) (inspectFilter, error) {
 		return inspectFilter{}, fmt.Errorf("--key must not be only whitespace")
 	}
 
+	var reasonSet map[contracts.ReasonCode]struct{}
+	for _, raw := range reasons {
+		code := contracts.ReasonCode(strings.TrimSpace(raw))
+		if !contracts.IsStableReasonCode(code) {
+			return inspectFilter{}, fmt.Errorf("invalid --reason %q (run `explain` for stable reason codes)", raw)
+		}
+		if reasonSet == nil {
+			reasonSet = make(map[contracts.ReasonCode]struct{}, len(reasons))
+		}
+		reasonSet[code] = struct{}{}
+	}
+
 	return inspectFilter{
-		state: normalizedState,
-		key:   strings.ToLower(trimmedKey),
+		state:   normalizedState,
+		key:     strings.ToLower(trimmedKey),
+		reasons: reasonSet,
 	}, nil
 }
 
-func loadIssueRecords(workDir string, filter inspectFilter) ([]issueRecord, error) {
-	issuesRoot := filepath.Join(workDir, contracts.DefaultIssuesRootDir)
-	dirs := []string{stateFilterOpen, stateFilterClosed}
-	if filter.state == stateFilterOpen {
-		dirs = []string{stateFilterOpen}
-	} else if filter.state == stateFilterClosed {
-		dirs = []string{stateFilterClosed}
+// matchesReason reports whether any message on result carries one of the
+// requested reason codes. No --reason filter matches everything.
+func (filter inspectFilter) matchesReason(result contracts.PerIssueResult) bool {
+	if len(filter.reasons) == 0 {
+		return true
+	}
+	for _, message := range result.Messages {
+		if _, ok := filter.reasons[message.ReasonCode]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// details describes the record's local file for --format templates. Parse
+// failures still have a path and state.
+func (record issueRecord) details() *contracts.IssueDetails {
+	return &contracts.IssueDetails{
+		Summary: record.Document.FrontMatter.Summary,
+		Status:  record.Document.FrontMatter.Status,
+		State:   record.State,
+		Path:    record.RelativePath,
+	}
+}
+
+// addResult records result only when it passes the --reason filter, so
+// counts describe the filtered view.
+func (filter inspectFilter) addResult(report *output.Report, result contracts.PerIssueResult) {
+	if filter.matchesReason(result) {
+		addIssueResult(report, result)
+	}
+}
+
+// resolveIssuesRoot returns the absolute issues root for workDir. Commands
+// that do not otherwise need the config still honor its issues_root; a
+// missing config means the default layout.
+func resolveIssuesRoot(workDir string) (string, error) {
+	cfg, err := resolveWorkspaceConfig(workDir)
+	if err != nil {
+		return "", err
+	}
+	return issuesRootFromConfig(workDir, cfg), nil
+}
+
+// resolveWorkspaceConfig reads the workspace config, treating a missing file
+// as an empty config so defaults apply.
+func resolveWorkspaceConfig(workDir string) (contracts.Config, error) {
+	cfg, err := config.Read(filepath.Join(workDir, contracts.DefaultConfigFilePath))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return contracts.Config{}, nil
+		}
+		return contracts.Config{}, fmt.Errorf("failed to load config: %w", err)
 	}
+	return cfg, nil
+}
+
+func openIssueStore(issuesRoot string, cfg contracts.Config) (*store.Store, error) {
+	issueStore, err := store.New(issuesRoot)
+	if err != nil {
+		return nil, err
+	}
+	issueStore.SetFilenameOptions(issue.FilenameOptions{Style: contracts.ResolveFilenameStyle(cfg), MaxSlugLen: cfg.MaxSlugLen})
+	issueStore.SetFlatLayout(cfg.FlatLayout)
+	issueStore.SetRenderOptions(issue.RenderOptions{
+		LabelStyle: contracts.ResolveLabelRenderStyle(cfg),
+		KeyOrder:   contracts.ResolveFrontMatterOrder(cfg),
+	})
+	return issueStore, nil
+}
+
+// profilePreservesLabelCase reports the default profile's
+// preserve_label_case, for commands that take no --profile.
+func profilePreservesLabelCase(cfg contracts.Config) bool {
+	if len(cfg.Profiles) == 0 {
+		return false
+	}
+	settings, err := config.Resolve(cfg, config.RuntimeFlags{}, config.Environment{}, config.ResolveOptions{})
+	return err == nil && settings.Profile.PreserveLabelCase
+}
 
+// parseIssueDocument parses an issue file or snapshot, keeping label case
+// only for profiles that preserve it.
+func parseIssueDocument(relativePath string, content string, preserveLabelCase bool) (issue.Document, error) {
+	doc, _, err := issue.ParseDocumentWithOptions(relativePath, content, issue.ParseOptions{PreserveLabelCase: preserveLabelCase})
+	return doc, err
+}
+
+func issuesRootFromConfig(workDir string, cfg contracts.Config) string {
+	return filepath.Join(workDir, contracts.ResolveIssuesRootDir(cfg))
+}
+
+// loadIssueRecords reads every issue directory even under a --state filter,
+// so a key present in more than one of them is always caught as a duplicate.
+// Files in the issues root (the flat layout) take their state from status.
+func loadIssueRecords(issuesRoot string, filter inspectFilter, preserveLabelCase bool) ([]issueRecord, error) {
 	records := make([]issueRecord, 0)
-	for _, stateDir := range dirs {
+	for _, stateDir := range store.IssueDirs {
 		files, err := os.ReadDir(filepath.Join(issuesRoot, stateDir))
 		if err != nil {
 			if errors.Is(err, os.ErrNotExist) {
@@ -77,7 +184,7 @@ func loadIssueRecords(workDir string, filter inspectFilter) ([]issueRecord, erro
 		}
 
 		for _, file := range files {
-			if file.IsDir() || strings.ToLower(filepath.Ext(file.Name())) != ".md" {
+			if file.IsDir() || !store.IsIssueFile(stateDir, file.Name()) {
 				continue
 			}
 
@@ -88,7 +195,7 @@ func loadIssueRecords(workDir string, filter inspectFilter) ([]issueRecord, erro
 			}
 
 			record := issueRecord{RelativePath: relativePath, State: stateDir}
-			doc, parseErr := issue.ParseDocument(relativePath, string(content))
+			doc, parseErr := parseIssueDocument(relativePath, string(content), preserveLabelCase)
 			if parseErr != nil {
 				record.Key = keyFromPath(relativePath)
 				record.Err = parseErr
@@ -110,6 +217,9 @@ func loadIssueRecords(workDir string, filter inspectFilter) ([]issueRecord, erro
 					record.Canonical = canonical
 				}
 			}
+			if stateDir == "." {
+				record.State = string(store.IssueStateForStatus(record.Document.FrontMatter.Status))
+			}
 
 			if filter.key != "" && !strings.Contains(strings.ToLower(record.Key), filter.key) {
 				continue
@@ -125,8 +235,68 @@ func loadIssueRecords(workDir string, filter inspectFilter) ([]issueRecord, erro
 		}
 		return records[i].Key < records[j].Key
 	})
+	markDuplicateRecords(issuesRoot, records)
+
+	if filter.state == stateFilterAll || filter.state == "" {
+		return records, nil
+	}
+	filtered := records[:0]
+	for _, record := range records {
+		if record.State == filter.state {
+			filtered = append(filtered, record)
+		}
+	}
+	return filtered, nil
+}
+
+// markDuplicateRecords keeps one file per issue key and turns every other
+// copy into a duplicate_local_issue error, so commands never act on a key
+// twice. The winner is the file the cache last recorded for the key, falling
+// back to the first path in sort order. records must be sorted by key.
+func markDuplicateRecords(issuesRoot string, records []issueRecord) {
+	var cachedPaths map[string]store.CacheEntry
+	for start := 0; start < len(records); {
+		end := start + 1
+		for end < len(records) && records[end].Key == records[start].Key {
+			end++
+		}
+		if end-start > 1 {
+			if cachedPaths == nil {
+				cachedPaths = loadCachedPaths(issuesRoot)
+			}
+			winner := start
+			for i := start; i < end; i++ {
+				if filepath.ToSlash(records[i].RelativePath) == cachedPaths[records[i].Key].Path {
+					winner = i
+					break
+				}
+			}
+			for i := start; i < end; i++ {
+				if i == winner {
+					continue
+				}
+				records[i].Err = fmt.Errorf("issue %s is also stored at %s; ignoring this copy", records[i].Key, records[winner].RelativePath)
+				records[i].ReasonCode = contracts.ReasonCodeDuplicateLocalIssue
+				records[i].ErrorCode = "duplicate_local_issue"
+			}
+		}
+		start = end
+	}
+}
 
-	return records, nil
+// loadCachedPaths returns the cache entries reconciled against the files on
+// disk, or an empty map when the cache cannot be read; duplicate resolution
+// then falls back to path order.
+func loadCachedPaths(issuesRoot string) map[string]store.CacheEntry {
+	issueStore, err := store.New(issuesRoot)
+	if err != nil {
+		return map[string]store.CacheEntry{}
+	}
+	cache, _, err := issueStore.LoadReconciledCache()
+	if err != nil || cache.Issues == nil {
+		return map[string]store.CacheEntry{}
+	}
+	return cache.Issues
 }
 
 func keyFromPath(relativePath string) string {
@@ -179,3 +349,80 @@ func buildTypedDiagnostic(level string, reason contracts.ReasonCode, code string
 		Text:       strings.TrimSpace(text),
 	}
 }
+
+// MaxErrorsExceededError is returned alongside a partial report when a
+// command stops early because its per-issue error count went past
+// --max-errors.
+type MaxErrorsExceededError struct {
+	Limit  int
+	Errors int
+}
+
+func (e *MaxErrorsExceededError) Error() string {
+	return fmt.Sprintf("stopped after %d per-issue errors (--max-errors %d)", e.Errors, e.Limit)
+}
+
+func exceedsMaxErrors(report output.Report, limit int) bool {
+	return limit > 0 && report.Counts.Errors > limit
+}
+
+// retryBudgetFor prefers a budget shared by the caller (sync passes one
+// across both stages) and otherwise starts a fresh one from config.
+func retryBudgetFor(shared *httpclient.RetryBudget, cfg contracts.Config) *httpclient.RetryBudget {
+	if shared != nil {
+		return shared
+	}
+	return httpclient.NewRetryBudget(cfg.RetryBudget)
+}
+
+// recordCalls wraps adapter so every Jira call of the run is written to
+// path, resolved against workDir, with the API token and configured
+// redaction patterns masked. The returned stop func closes the file; a
+// recording that could not be completed is logged but never fails the run.
+func recordCalls(adapter jira.Adapter, workDir string, path string, token string, cfg contracts.Config, logger logging.Logger) (jira.Adapter, func(), error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return adapter, func() {}, nil
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(workDir, path)
+	}
+	recorder, err := jira.NewRecordingAdapter(adapter, path, httpclient.NewRedactor(token).WithPatterns(redactionPatternsFor(cfg)...))
+	if err != nil {
+		return nil, nil, &config.ResolveError{Code: config.ResolveErrorCodeInvalidFlag, Message: "--record could not be opened", Err: err}
+	}
+	return recorder, func() {
+		if err := recorder.Close(); err != nil && logger != nil {
+			logger.Logf(logging.LevelWarn, "recording %s is incomplete: %v", path, err)
+		}
+	}, nil
+}
+
+// withTLSFiles copies the configured CA bundle, client certificate, and TLS
+// policy onto adapter options, resolving relative paths from the project
+// root.
+func withTLSFiles(options jira.CloudAdapterOptions, workDir string, cfg contracts.Config) jira.CloudAdapterOptions {
+	resolve := func(path string) string {
+		path = strings.TrimSpace(path)
+		if path == "" || filepath.IsAbs(path) {
+			return path
+		}
+		return filepath.Join(workDir, path)
+	}
+	options.CABundlePath = resolve(cfg.CABundlePath)
+	options.ClientCertPath = resolve(cfg.ClientCertPath)
+	options.ClientKeyPath = resolve(cfg.ClientKeyPath)
+	options.MinTLSVersion = cfg.MinTLSVersion
+	options.CipherSuites = cfg.TLSCipherSuites
+	return options
+}
+
+// redactionPatternsFor compiles the configured redaction patterns. Config
+// loading already validated them, so a failure only drops the extras.
+func redactionPatternsFor(cfg contracts.Config) []*regexp.Regexp {
+	patterns, err := contracts.CompileRedactionPatterns(cfg)
+	if err != nil {
+		return nil
+	}
+	return patterns
+}
@@ -1,13 +1,18 @@
 package commands
 
 import (
+	"context"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/pweiskircher/jira-issue-sync/internal/config"
 	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
 	"github.com/pweiskircher/jira-issue-sync/internal/issue"
+	"github.com/pweiskircher/jira-issue-sync/internal/jira"
+	"github.com/pweiskircher/jira-issue-sync/internal/output"
 )
 
 func TestRunStatusReportsChangesConflictsAndTypedDiagnostics(t *testing.T) {
@@ -145,6 +150,195 @@ func TestRunStatusReportsChangesConflictsAndTypedDiagnostics(t *testing.T) {
 	}
 }
 
+func TestRunStatusSummarizesDraftsSeparately(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+
+	draft := func(key string) issue.Document {
+		return issue.Document{
+			FrontMatter: issue.FrontMatter{
+				SchemaVersion: contracts.IssueFileSchemaVersionV1,
+				Key:           key,
+				Summary:       "Draft " + key,
+				IssueType:     "Task",
+				Status:        "Open",
+			},
+			CanonicalKey: key,
+			MarkdownBody: "draft",
+		}
+	}
+	writeIssueFile(t, workspace, filepath.Join("open", "L-aaaa1111-first.md"), mustRenderDoc(t, draft("L-aaaa1111")))
+	writeIssueFile(t, workspace, filepath.Join("open", "L-bbbb2222-second.md"), mustRenderDoc(t, draft("L-bbbb2222")))
+
+	original := issue.Document{
+		FrontMatter: issue.FrontMatter{
+			SchemaVersion: contracts.IssueFileSchemaVersionV1,
+			Key:           "PROJ-1",
+			Summary:       "Original",
+			IssueType:     "Task",
+			Status:        "Open",
+		},
+		CanonicalKey: "PROJ-1",
+		MarkdownBody: "body",
+	}
+	local := original
+	local.FrontMatter.Summary = "Edited"
+	writeIssueFile(t, workspace, filepath.Join("open", "PROJ-1-edited.md"), mustRenderDoc(t, local))
+	writeIssueFile(t, workspace, filepath.Join(".sync", "originals", "PROJ-1.md"), mustRenderDoc(t, original))
+
+	writeIssueFile(t, workspace, filepath.Join("open", "PROJ-2-no-snapshot.md"), mustRenderDoc(t, draft("PROJ-2")))
+
+	report, err := RunStatus(workspace, StatusOptions{State: "all"})
+	if err != nil {
+		t.Fatalf("run status failed: %v", err)
+	}
+
+	if report.Counts.Drafts != 2 || report.Counts.Modified != 1 || report.Counts.Conflicts != 1 {
+		t.Fatalf("unexpected summary counts: %#v", report.Counts)
+	}
+}
+
+func TestRunStatusComparesLabelsWithProfileCase(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		preserveLabelCase bool
+		modified          int
+	}{
+		"default lowercases":  {modified: 0},
+		"preserve_label_case": {preserveLabelCase: true, modified: 1},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			workspace := t.TempDir()
+			cfg := contracts.Config{ConfigVersion: contracts.ConfigSchemaVersionV1, Profiles: map[string]contracts.ProjectProfile{"default": {ProjectKey: "PROJ", PreserveLabelCase: tc.preserveLabelCase}}}
+			if err := config.Write(filepath.Join(workspace, contracts.DefaultConfigFilePath), cfg); err != nil {
+				t.Fatalf("write config failed: %v", err)
+			}
+			original := issue.Document{
+				FrontMatter: issue.FrontMatter{
+					SchemaVersion: contracts.IssueFileSchemaVersionV1,
+					Key:           "PROJ-1",
+					Summary:       "Labeled",
+					IssueType:     "Task",
+					Status:        "Open",
+					Labels:        []string{"frontend"},
+				},
+				CanonicalKey: "PROJ-1",
+				MarkdownBody: "body",
+			}
+			writeIssueFile(t, workspace, filepath.Join(".sync", "originals", "PROJ-1.md"), mustRenderDoc(t, original))
+			local := original
+			local.FrontMatter.Labels = []string{"FrontEnd"}
+			writeIssueFile(t, workspace, filepath.Join("open", "PROJ-1-labeled.md"), mustRenderDoc(t, local))
+
+			report, err := RunStatus(workspace, StatusOptions{State: "all"})
+			if err != nil {
+				t.Fatalf("run status failed: %v", err)
+			}
+			if report.Counts.Modified != tc.modified {
+				t.Fatalf("expected %d modified, got %#v issues=%#v", tc.modified, report.Counts, report.Issues)
+			}
+		})
+	}
+}
+
+func TestRunStatusFiltersByReasonCode(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	modified := issue.Document{
+		FrontMatter: issue.FrontMatter{
+			SchemaVersion: contracts.IssueFileSchemaVersionV1,
+			Key:           "PROJ-1",
+			Summary:       "Modified",
+			IssueType:     "Task",
+			Status:        "Open",
+		},
+		CanonicalKey: "PROJ-1",
+		MarkdownBody: "local",
+	}
+	writeIssueFile(t, workspace, filepath.Join("open", "PROJ-1-modified.md"), mustRenderDoc(t, modified))
+	modified.MarkdownBody = "original"
+	writeIssueFile(t, workspace, filepath.Join(".sync", "originals", "PROJ-1.md"), mustRenderDoc(t, modified))
+
+	writeIssueFile(t, workspace, filepath.Join("open", "PROJ-2-no-snapshot.md"), mustRenderDoc(t, issue.Document{
+		FrontMatter: issue.FrontMatter{
+			SchemaVersion: contracts.IssueFileSchemaVersionV1,
+			Key:           "PROJ-2",
+			Summary:       "No snapshot",
+			IssueType:     "Task",
+			Status:        "Open",
+		},
+		CanonicalKey: "PROJ-2",
+		MarkdownBody: "body",
+	}))
+	writeIssueFile(t, workspace, filepath.Join("open", "PROJ-3-broken.md"), "not-front-matter")
+
+	report, err := RunStatus(workspace, StatusOptions{Reasons: []string{string(contracts.ReasonCodeConflictBaseSnapshotMissing)}})
+	if err != nil {
+		t.Fatalf("run status failed: %v", err)
+	}
+	if len(report.Issues) != 1 || report.Issues[0].Key != "PROJ-2" {
+		t.Fatalf("expected only the conflict-coded issue, got %#v", report.Issues)
+	}
+	if report.Counts.Processed != 1 || report.Counts.Conflicts != 1 || report.Counts.Errors != 0 || report.Counts.Updated != 0 {
+		t.Fatalf("expected counts for the filtered view, got %#v", report.Counts)
+	}
+
+	if _, err := RunStatus(workspace, StatusOptions{Reasons: []string{"conflict"}}); err == nil || !strings.Contains(err.Error(), "invalid --reason") {
+		t.Fatalf("expected unknown reason code to be rejected, got %v", err)
+	}
+}
+
+func TestLoadIssueRecordsFlagsKeyPresentInOpenAndClosed(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	content := mustRenderDoc(t, issue.Document{
+		CanonicalKey: "PROJ-7",
+		FrontMatter: issue.FrontMatter{
+			SchemaVersion: contracts.IssueFileSchemaVersionV1,
+			Key:           "PROJ-7",
+			Summary:       "Moved by hand",
+			IssueType:     "Task",
+			Status:        "Done",
+		},
+	})
+	writeIssueFile(t, workspace, filepath.Join("open", "PROJ-7-moved-by-hand.md"), content)
+	writeIssueFile(t, workspace, filepath.Join("closed", "PROJ-7-moved-by-hand.md"), content)
+	writeIssueFile(t, workspace, filepath.Join(".sync", "originals", "PROJ-7.md"), content)
+	writeIssueFile(t, workspace, filepath.Join(".sync", "cache.json"), `{"version":"1","issues":{"PROJ-7":{"path":"closed/PROJ-7-moved-by-hand.md"}}}`)
+
+	report, err := RunStatus(workspace, StatusOptions{State: "all", IncludeUnchanged: true})
+	if err != nil {
+		t.Fatalf("run status failed: %v", err)
+	}
+	if len(report.Issues) != 2 || report.Counts.Errors != 1 {
+		t.Fatalf("expected one kept copy and one duplicate error, got %#v", report)
+	}
+
+	var duplicate contracts.PerIssueResult
+	for _, result := range report.Issues {
+		if result.Status == contracts.PerIssueStatusError {
+			duplicate = result
+		} else if result.Action != "unchanged" {
+			t.Fatalf("expected the cached copy to be compared normally, got %#v", result)
+		}
+	}
+	if len(duplicate.Messages) != 1 || duplicate.Messages[0].ReasonCode != contracts.ReasonCodeDuplicateLocalIssue {
+		t.Fatalf("expected duplicate_local_issue error, got %#v", duplicate)
+	}
+	if !strings.Contains(duplicate.Messages[0].Text, filepath.Join("closed", "PROJ-7-moved-by-hand.md")) {
+		t.Fatalf("expected duplicate to name the kept copy, got %q", duplicate.Messages[0].Text)
+	}
+	if !strings.Contains(duplicate.Messages[0].Text, "[path="+filepath.Join("open", "PROJ-7-moved-by-hand.md")+"]") {
+		t.Fatalf("expected duplicate diagnostic on the open copy, got %q", duplicate.Messages[0].Text)
+	}
+}
+
 func TestRunDiffProducesDeterministicOutput(t *testing.T) {
 	t.Parallel()
 
@@ -205,6 +399,234 @@ func TestRunDiffProducesDeterministicOutput(t *testing.T) {
 	}
 }
 
+func TestViewAndDiffWriteArtifactsToOutputDir(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	local := mustRenderDoc(t, issue.Document{
+		FrontMatter:  issue.FrontMatter{SchemaVersion: contracts.IssueFileSchemaVersionV1, Key: "PROJ-9", Summary: "New Summary", IssueType: "Task", Status: "Open"},
+		CanonicalKey: "PROJ-9",
+		MarkdownBody: "new-body",
+	})
+	original := mustRenderDoc(t, issue.Document{
+		FrontMatter:  issue.FrontMatter{SchemaVersion: contracts.IssueFileSchemaVersionV1, Key: "PROJ-9", Summary: "Old Summary", IssueType: "Task", Status: "Open"},
+		CanonicalKey: "PROJ-9",
+		MarkdownBody: "old-body",
+	})
+	writeIssueFile(t, workspace, filepath.Join("open", "PROJ-9-diff.md"), local)
+	writeIssueFile(t, workspace, filepath.Join(".sync", "originals", "PROJ-9.md"), original)
+
+	outputDir := filepath.Join(workspace, "artifacts")
+	if err := os.MkdirAll(outputDir, 0o755); err != nil {
+		t.Fatalf("mkdir failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, "notes.txt"), []byte("keep me\n"), 0o644); err != nil {
+		t.Fatalf("write unrelated file failed: %v", err)
+	}
+
+	printed, err := RunDiff(workspace, DiffOptions{State: "all"})
+	if err != nil {
+		t.Fatalf("run diff failed: %v", err)
+	}
+	dumped, err := RunDiff(workspace, DiffOptions{State: "all", OutputDir: "artifacts"})
+	if err != nil {
+		t.Fatalf("run diff with output dir failed: %v", err)
+	}
+	diffPath := filepath.Join(outputDir, "PROJ-9.diff")
+	if len(dumped.Issues) != 1 || dumped.Issues[0].Messages[0].Text != "wrote "+diffPath {
+		t.Fatalf("expected diff result to point at the file, got %#v", dumped.Issues)
+	}
+	if content, err := os.ReadFile(diffPath); err != nil || string(content) != printed.Issues[0].Messages[0].Text+"\n" {
+		t.Fatalf("unexpected diff file (err=%v):\n%s", err, content)
+	}
+
+	if _, err := RunView(workspace, ViewOptions{Key: "PROJ-9", OutputDir: "artifacts"}); err != nil {
+		t.Fatalf("run view with output dir failed: %v", err)
+	}
+	if content, err := os.ReadFile(filepath.Join(outputDir, "PROJ-9.md")); err != nil || string(content) != local {
+		t.Fatalf("unexpected view file (err=%v):\n%s", err, content)
+	}
+
+	again, err := RunDiff(workspace, DiffOptions{State: "all", OutputDir: "artifacts"})
+	if err != nil {
+		t.Fatalf("rerun diff failed: %v", err)
+	}
+	if again.Issues[0].Status != contracts.PerIssueStatusError || !strings.Contains(again.Issues[0].Messages[0].Text, "refusing to overwrite") {
+		t.Fatalf("expected rerun to refuse overwriting, got %#v", again.Issues[0])
+	}
+	if content, _ := os.ReadFile(filepath.Join(outputDir, "notes.txt")); string(content) != "keep me\n" {
+		t.Fatalf("unrelated file was modified: %q", content)
+	}
+}
+
+func TestRunDiffReportsLabelAndCustomFieldChangesAsSets(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+
+	local := mustRenderDoc(t, issue.Document{
+		FrontMatter: issue.FrontMatter{
+			SchemaVersion: contracts.IssueFileSchemaVersionV1,
+			Key:           "PROJ-9",
+			Summary:       "Summary",
+			IssueType:     "Task",
+			Status:        "Open",
+			Labels:        []string{"backend", "urgent"},
+			CustomFields: map[string]json.RawMessage{
+				"customer": json.RawMessage(`"Globex"`),
+				"team":     json.RawMessage(`"core"`),
+			},
+		},
+		CanonicalKey: "PROJ-9",
+		MarkdownBody: "body",
+	})
+	original := mustRenderDoc(t, issue.Document{
+		FrontMatter: issue.FrontMatter{
+			SchemaVersion: contracts.IssueFileSchemaVersionV1,
+			Key:           "PROJ-9",
+			Summary:       "Summary",
+			IssueType:     "Task",
+			Status:        "Open",
+			Labels:        []string{"backend", "later"},
+			CustomFields: map[string]json.RawMessage{
+				"customer": json.RawMessage(`"Acme"`),
+				"tier":     json.RawMessage(`"gold"`),
+			},
+		},
+		CanonicalKey: "PROJ-9",
+		MarkdownBody: "body",
+	})
+
+	writeIssueFile(t, workspace, filepath.Join("open", "PROJ-9-diff.md"), local)
+	writeIssueFile(t, workspace, filepath.Join(".sync", "originals", "PROJ-9.md"), original)
+
+	report, err := RunDiff(workspace, DiffOptions{State: "all"})
+	if err != nil {
+		t.Fatalf("run diff failed: %v", err)
+	}
+	if len(report.Issues) != 1 || report.Issues[0].Action != "different" {
+		t.Fatalf("unexpected diff payload: %#v", report)
+	}
+
+	expected := strings.Join([]string{
+		"--- original",
+		"+++ local",
+		"added label urgent",
+		"removed label later",
+		`changed custom field customer: "Acme" -> "Globex"`,
+		`added custom field team: "core"`,
+		"removed custom field tier",
+	}, "\n")
+	if got := report.Issues[0].Messages[0].Text; got != expected {
+		t.Fatalf("unexpected structured diff:\n%s", got)
+	}
+}
+
+func TestRunDiffRemoteComparesWorkingCopyWithLiveIssue(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	writePullConfig(t, workspace)
+	body := `{"version":1,"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"same body"}]}]}`
+	for _, doc := range []issue.Document{
+		{FrontMatter: issue.FrontMatter{Key: "PROJ-1", Summary: "Local title", IssueType: "Task", Status: "Open"}, MarkdownBody: "same body", RawADFJSON: body},
+		{FrontMatter: issue.FrontMatter{Key: "PROJ-2", Summary: "Stable", IssueType: "Task", Status: "Open"}, MarkdownBody: "same body", RawADFJSON: body},
+		{FrontMatter: issue.FrontMatter{Key: "PROJ-3", Summary: "Deleted remotely", IssueType: "Task", Status: "Open"}},
+	} {
+		doc.FrontMatter.SchemaVersion = contracts.IssueFileSchemaVersionV1
+		doc.CanonicalKey = doc.FrontMatter.Key
+		writeIssueFile(t, workspace, filepath.Join("open", doc.FrontMatter.Key+".md"), mustRenderDoc(t, doc))
+	}
+
+	adapter := &pushAdapterStub{issues: map[string]jira.Issue{
+		"PROJ-1": {Key: "PROJ-1", Fields: jira.IssueFields{Summary: "Renamed in Jira", Description: json.RawMessage(body), Status: &jira.StatusRef{Name: "Open"}, IssueType: &jira.NamedRef{Name: "Task"}}},
+		"PROJ-2": {Key: "PROJ-2", Fields: jira.IssueFields{Summary: "Stable", Description: json.RawMessage(body), Status: &jira.StatusRef{Name: "Open"}, IssueType: &jira.NamedRef{Name: "Task"}}},
+	}}
+
+	report, err := RunDiffRemote(context.Background(), workspace, DiffRemoteOptions{
+		Adapter:     adapter,
+		Environment: config.Environment{JiraAPIToken: "token"},
+	})
+	if err != nil {
+		t.Fatalf("run remote diff failed: %v", err)
+	}
+	if adapter.getIssueCalls != 3 {
+		t.Fatalf("expected one fetch per local issue, got %d", adapter.getIssueCalls)
+	}
+	if len(report.Issues) != 2 || report.Counts.Errors != 1 {
+		t.Fatalf("expected unchanged PROJ-2 to be hidden, got %#v", report)
+	}
+
+	changed := report.Issues[0]
+	if changed.Key != "PROJ-1" || changed.Action != "different" {
+		t.Fatalf("expected PROJ-1 to differ from remote, got %#v", changed)
+	}
+	diff := changed.Messages[0].Text
+	for _, want := range []string{"--- remote\n+++ local", `- summary: "Renamed in Jira"`, `+ summary: "Local title"`} {
+		if !strings.Contains(diff, want) {
+			t.Fatalf("expected remote diff to contain %q, got:\n%s", want, diff)
+		}
+	}
+	if strings.Contains(diff, "same body") {
+		t.Fatalf("expected matching body to stay out of the diff, got:\n%s", diff)
+	}
+
+	if missing := report.Issues[1]; missing.Key != "PROJ-3" || missing.Action != "fetch-error" || missing.Status != contracts.PerIssueStatusError {
+		t.Fatalf("expected fetch error for PROJ-3, got %#v", missing)
+	}
+}
+
+func TestSyncedAtOnlyDifferenceIsNotAChange(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	writePushConfig(t, workspace)
+
+	doc := issue.Document{
+		FrontMatter: issue.FrontMatter{
+			SchemaVersion: contracts.IssueFileSchemaVersionV1,
+			Key:           "PROJ-12",
+			Summary:       "Steady",
+			IssueType:     "Task",
+			Status:        "Open",
+			SyncedAt:      "2026-02-20T00:00:00Z",
+		},
+		CanonicalKey: "PROJ-12",
+		MarkdownBody: "body",
+	}
+	original := mustRenderDoc(t, doc)
+	doc.FrontMatter.SyncedAt = "2026-03-01T12:00:00Z"
+	local := mustRenderDoc(t, doc)
+
+	writeIssueFile(t, workspace, filepath.Join("open", "PROJ-12-steady.md"), local)
+	writeIssueFile(t, workspace, filepath.Join(".sync", "originals", "PROJ-12.md"), original)
+
+	statusReport, err := RunStatus(workspace, StatusOptions{State: "all"})
+	if err != nil {
+		t.Fatalf("run status failed: %v", err)
+	}
+	if len(statusReport.Issues) != 0 {
+		t.Fatalf("expected status to treat synced_at-only change as unchanged, got %#v", statusReport.Issues)
+	}
+
+	diffReport, err := RunDiff(workspace, DiffOptions{State: "all", IncludeUnchanged: true})
+	if err != nil {
+		t.Fatalf("run diff failed: %v", err)
+	}
+	if len(diffReport.Issues) != 1 || diffReport.Issues[0].Action != "unchanged" {
+		t.Fatalf("expected diff to report unchanged, got %#v", diffReport.Issues)
+	}
+
+	adapter := &pushAdapterStub{issues: map[string]jira.Issue{}}
+	pushReport, err := RunPush(context.Background(), workspace, PushOptions{Adapter: adapter, Environment: config.Environment{JiraAPIToken: "token"}})
+	if err != nil {
+		t.Fatalf("run push failed: %v", err)
+	}
+	if len(pushReport.Issues) != 0 || adapter.updateCalls != 0 {
+		t.Fatalf("expected push to skip synced_at-only change, got %#v", pushReport.Issues)
+	}
+}
+
 func TestRunListSupportsDeterministicFiltering(t *testing.T) {
 	t.Parallel()
 
@@ -252,6 +674,106 @@ func TestRunListSupportsDeterministicFiltering(t *testing.T) {
 	}
 }
 
+func TestRunListReportsPaginationForTruncatedResults(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	for _, key := range []string{"PROJ-1", "PROJ-2", "PROJ-3"} {
+		writeIssueFile(t, workspace, filepath.Join("open", key+"-issue.md"), mustRenderDoc(t, issue.Document{
+			FrontMatter: issue.FrontMatter{
+				SchemaVersion: contracts.IssueFileSchemaVersionV1,
+				Key:           key,
+				Summary:       "Issue",
+				IssueType:     "Task",
+				Status:        "Open",
+			},
+			CanonicalKey: key,
+		}))
+	}
+
+	report, err := RunList(workspace, ListOptions{State: "all", Limit: 2})
+	if err != nil {
+		t.Fatalf("run list failed: %v", err)
+	}
+	if len(report.Issues) != 2 || report.Issues[0].Key != "PROJ-1" || report.Issues[1].Key != "PROJ-2" {
+		t.Fatalf("expected the first two issues, got %#v", report.Issues)
+	}
+	if report.Counts.Processed != 3 {
+		t.Fatalf("expected counts to cover every match, got %#v", report.Counts)
+	}
+
+	envelope, err := output.BuildEnvelope(report, 0)
+	if err != nil {
+		t.Fatalf("build envelope failed: %v", err)
+	}
+	encoded, err := json.Marshal(envelope)
+	if err != nil {
+		t.Fatalf("marshal envelope failed: %v", err)
+	}
+	if !strings.Contains(string(encoded), `"pagination":{"total":3,"returned":2,"truncated":true}`) {
+		t.Fatalf("expected truncated pagination metadata, got %s", encoded)
+	}
+
+	full, err := RunList(workspace, ListOptions{State: "all"})
+	if err != nil {
+		t.Fatalf("run list failed: %v", err)
+	}
+	if full.Pagination == nil || *full.Pagination != (contracts.PaginationMeta{Total: 3, Returned: 3}) {
+		t.Fatalf("expected untruncated pagination metadata, got %#v", full.Pagination)
+	}
+
+	if _, err := RunList(workspace, ListOptions{State: "all", Limit: -1}); !config.IsResolveErrorCode(err, config.ResolveErrorCodeInvalidFlag) {
+		t.Fatalf("expected negative limit to be rejected as an invalid flag, got %v", err)
+	}
+}
+
+func TestRunFsckReportsTypedWorkspaceProblems(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	doc := issue.Document{
+		FrontMatter: issue.FrontMatter{
+			SchemaVersion: contracts.IssueFileSchemaVersionV1,
+			Key:           "PROJ-1",
+			Summary:       "Current summary",
+			IssueType:     "Task",
+			Status:        "Open",
+		},
+		CanonicalKey: "PROJ-1",
+	}
+	writeIssueFile(t, workspace, filepath.Join("open", "PROJ-1-stale-summary.md"), mustRenderDoc(t, doc))
+	writeIssueFile(t, workspace, filepath.Join(".sync", "originals", "PROJ-1.md"), mustRenderDoc(t, doc))
+	writeIssueFile(t, workspace, filepath.Join(".sync", "originals", "PROJ-5.md"), mustRenderDoc(t, doc))
+	writeIssueFile(t, workspace, filepath.Join("closed", "PROJ-2-broken.md"), "not front matter")
+
+	report, err := RunFsck(workspace, FsckOptions{})
+	if err != nil {
+		t.Fatalf("fsck failed: %v", err)
+	}
+	if report.Counts.Processed != 3 || report.Counts.Errors != 1 || report.Counts.Warnings != 2 {
+		t.Fatalf("unexpected counts: %#v", report.Counts)
+	}
+
+	expected := []struct {
+		key    string
+		status contracts.PerIssueStatus
+		code   string
+	}{
+		{"PROJ-1", contracts.PerIssueStatusWarning, "code=filename_mismatch"},
+		{"PROJ-2", contracts.PerIssueStatusError, "code=parse_failed"},
+		{"PROJ-5", contracts.PerIssueStatusWarning, "code=orphan_snapshot"},
+	}
+	for index, want := range expected {
+		got := report.Issues[index]
+		if got.Key != want.key || got.Status != want.status || got.Action != "fsck" {
+			t.Fatalf("unexpected result at %d: %#v", index, got)
+		}
+		if !strings.HasPrefix(got.Messages[0].Text, want.code) {
+			t.Fatalf("expected %s diagnostic, got %q", want.code, got.Messages[0].Text)
+		}
+	}
+}
+
 func mustRenderDoc(t *testing.T, doc issue.Document) string {
 	t.Helper()
 
@@ -273,3 +795,101 @@ func writeIssueFile(t *testing.T, workspace string, relativePath string, content
 		t.Fatalf("write file failed: %v", err)
 	}
 }
+
+func TestRunConfigLintWarnsAboutUnmatchedAliasesAndUnknownOverrideStatuses(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	cfg := contracts.Config{
+		ConfigVersion: contracts.ConfigSchemaVersionV1,
+		Profiles: map[string]contracts.ProjectProfile{"default": {
+			ProjectKey: "PROJ",
+			FieldConfig: contracts.FieldConfig{Aliases: map[string]string{
+				"points": "customfield_10010",
+				"team":   "customfield_99999",
+			}},
+			TransitionOverrides: map[string]contracts.TransitionOverride{
+				"Done":     {TransitionName: "Close"},
+				"Shipping": {TransitionName: "Ship"},
+			},
+		}},
+	}
+	if err := config.Write(filepath.Join(workspace, contracts.DefaultConfigFilePath), cfg); err != nil {
+		t.Fatalf("write config failed: %v", err)
+	}
+
+	adapter := &initAdapterStub{
+		pullAdapterStub: pullAdapterStub{statuses: []jira.StatusRef{{ID: "1", Name: "To Do"}, {ID: "3", Name: "done"}}},
+		fields:          []jira.FieldDefinition{{ID: "customfield_10010", Name: "Story Points", Custom: true}},
+	}
+	report, err := RunConfigLint(context.Background(), workspace, ConfigLintOptions{Adapter: adapter})
+	if err != nil {
+		t.Fatalf("config lint failed: %v", err)
+	}
+
+	expected := []struct {
+		key  string
+		code string
+	}{
+		{"profiles.default.field_config.aliases.team", "code=alias_unmatched"},
+		{"profiles.default.transition_overrides.Shipping", "code=transition_status_unknown"},
+	}
+	if len(report.Issues) != len(expected) || report.Counts.Warnings != len(expected) {
+		t.Fatalf("unexpected lint results: %#v", report.Issues)
+	}
+	for index, want := range expected {
+		got := report.Issues[index]
+		if got.Key != want.key || got.Status != contracts.PerIssueStatusWarning || !strings.HasPrefix(got.Messages[0].Text, want.code) {
+			t.Fatalf("unexpected result at %d: %#v", index, got)
+		}
+	}
+}
+
+func TestRunConfigLintDoesNotTreatWildcardOverrideAsStatus(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	cfg := contracts.Config{
+		ConfigVersion: contracts.ConfigSchemaVersionV1,
+		Profiles: map[string]contracts.ProjectProfile{"default": {
+			ProjectKey: "PROJ",
+			TransitionOverrides: map[string]contracts.TransitionOverride{
+				contracts.TransitionOverrideWildcard: {Dynamic: &contracts.DynamicTransitionSelector{TargetStatus: "Shipped"}},
+			},
+		}},
+	}
+	if err := config.Write(filepath.Join(workspace, contracts.DefaultConfigFilePath), cfg); err != nil {
+		t.Fatalf("write config failed: %v", err)
+	}
+
+	adapter := &initAdapterStub{
+		pullAdapterStub: pullAdapterStub{statuses: []jira.StatusRef{{ID: "1", Name: "To Do"}}},
+		fields:          []jira.FieldDefinition{{ID: "summary", Name: "Summary"}},
+	}
+	report, err := RunConfigLint(context.Background(), workspace, ConfigLintOptions{Adapter: adapter})
+	if err != nil {
+		t.Fatalf("config lint failed: %v", err)
+	}
+
+	if len(report.Issues) != 1 || report.Issues[0].Key != "profiles.default.transition_overrides.*.dynamic.target_status" {
+		t.Fatalf("expected only the wildcard's dynamic target to be flagged, got %#v", report.Issues)
+	}
+}
+
+func TestRunConfigLintSkipsRemoteChecksWithoutCredentials(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	writePushConfig(t, workspace)
+
+	report, err := RunConfigLint(context.Background(), workspace, ConfigLintOptions{Environment: config.Environment{JiraBaseURL: "https://example.atlassian.net"}})
+	if err != nil {
+		t.Fatalf("config lint failed: %v", err)
+	}
+	if report.Counts.Warnings != 0 || len(report.Issues) != 2 || report.Issues[0].Status != contracts.PerIssueStatusSkipped {
+		t.Fatalf("expected both remote checks to be skipped, got %#v", report.Issues)
+	}
+	if !strings.Contains(report.Issues[1].Messages[0].Text, "transition status check skipped") {
+		t.Fatalf("expected skip reason, got %#v", report.Issues[1])
+	}
+}
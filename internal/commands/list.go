@@ -3,42 +3,56 @@ package commands
 import (
 	"fmt"
 
+	"github.com/pweiskircher/jira-issue-sync/internal/config"
 	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
 	"github.com/pweiskircher/jira-issue-sync/internal/output"
 )
 
 type ListOptions struct {
-	State string
-	Key   string
+	State   string
+	Key     string
+	Reasons []string
+	// Limit caps how many matching issues are returned. Zero returns all.
+	Limit int
 }
 
 func RunList(workDir string, options ListOptions) (output.Report, error) {
 	report := output.Report{CommandName: string(contracts.CommandList)}
 
-	filter, err := normalizeFilter(options.State, options.Key)
+	if options.Limit < 0 {
+		return report, &config.ResolveError{Code: config.ResolveErrorCodeInvalidFlag, Message: fmt.Sprintf("--limit must not be negative, got %d", options.Limit)}
+	}
+	filter, err := normalizeFilter(options.State, options.Key, options.Reasons)
 	if err != nil {
 		return report, err
 	}
 
-	records, err := loadIssueRecords(workDir, filter)
+	cfg, err := resolveWorkspaceConfig(workDir)
+	if err != nil {
+		return report, err
+	}
+	issuesRoot := issuesRootFromConfig(workDir, cfg)
+
+	records, err := loadIssueRecords(issuesRoot, filter, profilePreservesLabelCase(cfg))
 	if err != nil {
 		return report, fmt.Errorf("failed to read local issues: %w", err)
 	}
 
 	for _, record := range records {
 		if record.Err != nil {
-			addIssueResult(&report, contracts.PerIssueResult{
+			filter.addResult(&report, contracts.PerIssueResult{
 				Key:    record.Key,
 				Action: "parse-error",
 				Status: contracts.PerIssueStatusError,
 				Messages: []contracts.IssueMessage{
 					buildTypedDiagnostic("error", record.ReasonCode, record.ErrorCode, record.Err.Error(), record.RelativePath),
 				},
+				Details: record.details(),
 			})
 			continue
 		}
 
-		addIssueResult(&report, contracts.PerIssueResult{
+		filter.addResult(&report, contracts.PerIssueResult{
 			Key:    record.Key,
 			Action: "list",
 			Status: contracts.PerIssueStatusSuccess,
@@ -48,8 +62,19 @@ func RunList(workDir string, options ListOptions) (output.Report, error) {
 					Text:  "path=" + record.RelativePath + " state=" + record.State + " summary=" + record.Document.FrontMatter.Summary,
 				},
 			},
+			Details: record.details(),
 		})
 	}
 
+	// Counts keep describing every match so a truncated list still fails
+	// on parse errors it does not show.
+	pagination := contracts.PaginationMeta{Total: len(report.Issues), Returned: len(report.Issues)}
+	if options.Limit > 0 && len(report.Issues) > options.Limit {
+		report.Issues = report.Issues[:options.Limit]
+		pagination.Returned = options.Limit
+		pagination.Truncated = true
+	}
+	report.Pagination = &pagination
+
 	return report, nil
 }
@@ -1,13 +1,16 @@
 package commands
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/pweiskircher/jira-issue-sync/internal/config"
 	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
 	"github.com/pweiskircher/jira-issue-sync/internal/issue"
 	"github.com/pweiskircher/jira-issue-sync/internal/output"
@@ -23,9 +26,21 @@ type NewOptions struct {
 	Labels     []string
 	Body       string
 	IssuesRoot string
+	// Open launches the editor on the new draft. Editor and RunEditor behave
+	// as in EditOptions.
+	Open      bool
+	Editor    string
+	RunEditor func(ctx context.Context, editor string, absolutePath string) error
+	// Renumber gives the workspace's drafts sequential keys instead of
+	// creating a draft; the other options are ignored.
+	Renumber bool
 }
 
-func RunNew(workDir string, options NewOptions) (output.Report, error) {
+func RunNew(ctx context.Context, workDir string, options NewOptions) (output.Report, error) {
+	if options.Renumber {
+		return runRenumberDrafts(workDir)
+	}
+
 	report := output.Report{CommandName: string(contracts.CommandNew)}
 
 	summary := strings.TrimSpace(options.Summary)
@@ -33,27 +48,33 @@ func RunNew(workDir string, options NewOptions) (output.Report, error) {
 		return report, fmt.Errorf("--summary is required")
 	}
 
-	issueType := strings.TrimSpace(options.IssueType)
-	if issueType == "" {
-		issueType = "Task"
-	}
-
 	status := strings.TrimSpace(options.Status)
 	if status == "" {
 		status = "Open"
 	}
 
+	cfg := contracts.Config{}
 	issuesRoot := strings.TrimSpace(options.IssuesRoot)
 	if issuesRoot == "" {
-		issuesRoot = filepath.Join(workDir, contracts.DefaultIssuesRootDir)
+		resolved, err := resolveWorkspaceConfig(workDir)
+		if err != nil {
+			return report, err
+		}
+		cfg = resolved
+		issuesRoot = issuesRootFromConfig(workDir, cfg)
 	}
 
-	workspaceStore, err := store.New(issuesRoot)
+	workspaceStore, err := openIssueStore(issuesRoot, cfg)
 	if err != nil {
 		return report, err
 	}
 
-	key, err := generateLocalDraftKey(issuesRoot)
+	issueType := strings.TrimSpace(options.IssueType)
+	if issueType == "" {
+		issueType = profileDefaultIssueType(cfg)
+	}
+
+	key, err := generateLocalDraftKey(issuesRoot, contracts.ResolveDraftKeyPrefix(cfg))
 	if err != nil {
 		return report, err
 	}
@@ -68,12 +89,12 @@ func RunNew(workDir string, options NewOptions) (output.Report, error) {
 			Status:        status,
 			Priority:      strings.TrimSpace(options.Priority),
 			Assignee:      strings.TrimSpace(options.Assignee),
-			Labels:        append([]string(nil), options.Labels...),
+			Labels:        contracts.NormalizeLabelsWithCase(options.Labels, profilePreservesLabelCase(cfg)),
 		},
 		MarkdownBody: strings.TrimSpace(options.Body),
 	}
 
-	canonical, err := issue.RenderDocument(doc)
+	canonical, err := workspaceStore.RenderDocument(doc)
 	if err != nil {
 		return report, err
 	}
@@ -83,7 +104,7 @@ func RunNew(workDir string, options NewOptions) (output.Report, error) {
 		return report, err
 	}
 
-	addIssueResult(&report, contracts.PerIssueResult{
+	result := contracts.PerIssueResult{
 		Key:    key,
 		Action: "new",
 		Status: contracts.PerIssueStatusSuccess,
@@ -91,19 +112,54 @@ func RunNew(workDir string, options NewOptions) (output.Report, error) {
 			Level: "info",
 			Text:  "created draft at " + relativePath,
 		}},
-	})
+	}
+	if options.Open {
+		openDraft(ctx, options, filepath.Join(issuesRoot, relativePath), &result)
+	}
+	addIssueResult(&report, result)
 
 	return report, nil
 }
 
-func generateLocalDraftKey(issuesRoot string) (string, error) {
+// profileDefaultIssueType returns the resolved profile's default_issue_type,
+// or Task. Drafts need no credentials or explicit profile, so a config whose
+// profile cannot be resolved just falls back to Task.
+func profileDefaultIssueType(cfg contracts.Config) string {
+	if len(cfg.Profiles) > 0 {
+		settings, err := config.Resolve(cfg, config.RuntimeFlags{}, config.Environment{}, config.ResolveOptions{})
+		if err == nil {
+			if issueType := strings.TrimSpace(settings.Profile.DefaultIssueType); issueType != "" {
+				return issueType
+			}
+		}
+	}
+	return "Task"
+}
+
+// openDraft edits the freshly written draft. The draft already exists, so a
+// missing editor is noted and an editor failure downgrades the result to a
+// warning instead of failing the command.
+func openDraft(ctx context.Context, options NewOptions, absolutePath string, result *contracts.PerIssueResult) {
+	err := openInEditor(ctx, EditOptions{Editor: options.Editor, RunEditor: options.RunEditor}, absolutePath)
+	switch {
+	case err == nil:
+		result.Messages = append(result.Messages, contracts.IssueMessage{Level: "info", Text: "opened draft in editor"})
+	case errors.Is(err, errNoEditor):
+		result.Messages = append(result.Messages, contracts.IssueMessage{Level: "info", Text: "not opened: " + err.Error()})
+	default:
+		result.Status = contracts.PerIssueStatusWarning
+		result.Messages = append(result.Messages, contracts.IssueMessage{Level: "warning", Text: "failed to open editor: " + strings.TrimSpace(err.Error())})
+	}
+}
+
+func generateLocalDraftKey(issuesRoot string, prefix string) (string, error) {
 	for attempt := 0; attempt < 16; attempt++ {
 		random := make([]byte, 3)
 		if _, err := rand.Read(random); err != nil {
 			return "", err
 		}
 
-		key := "L-" + hex.EncodeToString(random)
+		key := prefix + "-" + hex.EncodeToString(random)
 		candidatePrefix := key + "-"
 		if !draftExists(issuesRoot, candidatePrefix) {
 			return key, nil
@@ -114,8 +170,7 @@ func generateLocalDraftKey(issuesRoot string) (string, error) {
 }
 
 func draftExists(issuesRoot string, filenamePrefix string) bool {
-	dirs := []string{"open", "closed"}
-	for _, dir := range dirs {
+	for _, dir := range store.IssueDirs {
 		entries, err := os.ReadDir(filepath.Join(issuesRoot, dir))
 		if err != nil {
 			continue
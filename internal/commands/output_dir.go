@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
+)
+
+// resolveOutputDir makes --output-dir absolute against the workspace and
+// creates it. An empty dir means artifacts go to stdout as usual.
+func resolveOutputDir(workDir string, dir string) (string, error) {
+	dir = strings.TrimSpace(dir)
+	if dir == "" {
+		return "", nil
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(workDir, dir)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create output directory: %w", err)
+	}
+	return dir, nil
+}
+
+// writeArtifact writes content to <dir>/<key><ext>. It never replaces an
+// existing file, so pointing --output-dir at a populated directory cannot
+// clobber anything; rerun into an empty directory instead.
+func writeArtifact(dir string, key string, ext string, content string) (string, error) {
+	key = strings.TrimSpace(key)
+	if key == "" || strings.ContainsAny(key, `/\`) || key == "." || key == ".." {
+		return "", fmt.Errorf("issue key %q cannot be used as a file name", key)
+	}
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+
+	path := filepath.Join(dir, key+ext)
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return "", fmt.Errorf("refusing to overwrite existing file %s", path)
+		}
+		return "", err
+	}
+	if _, err := file.WriteString(content); err != nil {
+		_ = file.Close()
+		return "", err
+	}
+	return path, file.Close()
+}
+
+// artifactResult replaces a result's rendered text with a pointer to the
+// file it was written to, or turns it into an error if the write failed.
+func artifactResult(result contracts.PerIssueResult, dir string, ext string, content string) contracts.PerIssueResult {
+	path, err := writeArtifact(dir, result.Key, ext, content)
+	if err != nil {
+		result.Status = contracts.PerIssueStatusError
+		result.Messages = []contracts.IssueMessage{
+			buildTypedDiagnostic("error", contracts.ReasonCodeValidationFailed, "output_write_failed", err.Error(), ""),
+		}
+		return result
+	}
+	result.Messages = []contracts.IssueMessage{{Level: "info", Text: "wrote " + path}}
+	return result
+}
@@ -0,0 +1,113 @@
+package commands
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/pweiskircher/jira-issue-sync/internal/config"
+	"github.com/pweiskircher/jira-issue-sync/internal/jira"
+	"github.com/pweiskircher/jira-issue-sync/internal/logging"
+)
+
+const maxProjectSuggestions = 5
+
+// validateProjectKey fails with an unknown_project resolve error when the
+// configured key is not among the projects visible to the account. An
+// account that sees no projects at all is not judged, since that usually
+// means missing browse permission rather than a typo.
+func validateProjectKey(ctx context.Context, adapter jira.Adapter, projectKey string) error {
+	key := strings.TrimSpace(projectKey)
+	if key == "" {
+		return nil
+	}
+
+	projects, err := adapter.ListProjects(ctx)
+	if err != nil {
+		return err
+	}
+	if len(projects) == 0 {
+		return nil
+	}
+	for _, project := range projects {
+		if strings.EqualFold(project.Key, key) {
+			return nil
+		}
+	}
+
+	message := "project_key " + key + " does not match any Jira project visible to this account"
+	if suggestions := closeProjectKeys(key, projects); len(suggestions) > 0 {
+		message += "; did you mean " + strings.Join(suggestions, ", ") + "?"
+	}
+	return &config.ResolveError{Code: config.ResolveErrorCodeUnknownProject, Message: message}
+}
+
+// checkProjectKey runs validateProjectKey for pull and push. Failing to list
+// projects is only logged so the command still runs against Jira.
+func checkProjectKey(ctx context.Context, adapter jira.Adapter, projectKey string, logger logging.Logger) error {
+	err := validateProjectKey(ctx, adapter, projectKey)
+	if err == nil {
+		return nil
+	}
+	if typed := asJiraError(err); typed != nil {
+		logging.Debugf(logger, "skipping project key validation: %s", typed.Error())
+		return nil
+	}
+	return err
+}
+
+func closeProjectKeys(key string, projects []jira.Project) []string {
+	type candidate struct {
+		key      string
+		distance int
+	}
+
+	upper := strings.ToUpper(key)
+	candidates := make([]candidate, 0)
+	for _, project := range projects {
+		projectKey := strings.ToUpper(strings.TrimSpace(project.Key))
+		distance := editDistance(upper, projectKey)
+		related := strings.HasPrefix(projectKey, upper) || strings.HasPrefix(upper, projectKey) || strings.EqualFold(strings.TrimSpace(project.Name), key)
+		if distance <= 2 || related {
+			candidates = append(candidates, candidate{key: project.Key, distance: distance})
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].key < candidates[j].key
+	})
+	if len(candidates) > maxProjectSuggestions {
+		candidates = candidates[:maxProjectSuggestions]
+	}
+
+	keys := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		keys = append(keys, candidate.key)
+	}
+	return keys
+}
+
+// editDistance is the Levenshtein distance between two ASCII-ish keys.
+func editDistance(a, b string) int {
+	left, right := []rune(a), []rune(b)
+	previous := make([]int, len(right)+1)
+	current := make([]int, len(right)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(left); i++ {
+		current[0] = i
+		for j := 1; j <= len(right); j++ {
+			cost := 1
+			if left[i-1] == right[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(right)]
+}
@@ -6,13 +6,14 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
-	"time"
 
+	"github.com/pweiskircher/jira-issue-sync/internal/clock"
 	"github.com/pweiskircher/jira-issue-sync/internal/config"
 	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
+	httpclient "github.com/pweiskircher/jira-issue-sync/internal/http"
 	"github.com/pweiskircher/jira-issue-sync/internal/jira"
+	"github.com/pweiskircher/jira-issue-sync/internal/logging"
 	"github.com/pweiskircher/jira-issue-sync/internal/output"
-	"github.com/pweiskircher/jira-issue-sync/internal/store"
 	pullsync "github.com/pweiskircher/jira-issue-sync/internal/sync/pull"
 )
 
@@ -21,13 +22,54 @@ type PullOptions struct {
 	JQL         string
 	PageSize    int
 	Concurrency int
-	Now         func() time.Time
+	DryRun      bool
+	MaxErrors   int
+	Clock       clock.Clock
 	Environment config.Environment
 	Adapter     jira.Adapter
+	Logger      logging.Logger
+	RetryBudget *httpclient.RetryBudget
+	// MaxResultsTotal aborts the pull when the search matches more issues.
+	// Zero falls back to the config's max_pull_issues.
+	MaxResultsTotal int
+	// RepairSnapshots lists issue keys whose original snapshots are rebuilt
+	// from the remote instead of running a JQL pull.
+	RepairSnapshots []string
+	// Insecure skips TLS certificate verification for Jira requests.
+	Insecure bool
+	// JiraBaseURL and JiraEmail override env and config for this run.
+	JiraBaseURL string
+	JiraEmail   string
+	// IncludeUnchanged lists unchanged issues in the report. They are still
+	// not rewritten.
+	IncludeUnchanged bool
+	// Flatten writes issues directly under the issues root for this run, as
+	// if flat_layout were set; pulled files move into place.
+	Flatten bool
+	// JQLFile names a file holding the query, resolved against the
+	// workspace when relative. It cannot be combined with JQL.
+	JQLFile string
+	// RecordPath, when set, records every Jira call of the run to this
+	// file for replay with jira.NewReplayAdapter.
+	RecordPath string
 }
 
 func RunPull(ctx context.Context, workDir string, options PullOptions) (output.Report, error) {
-	report := output.Report{CommandName: string(contracts.CommandPull)}
+	report := output.Report{CommandName: string(contracts.CommandPull), DryRun: options.DryRun}
+
+	if err := config.ValidateMaxErrors(options.MaxErrors); err != nil {
+		return report, err
+	}
+	if err := config.ValidateMaxResultsTotal(options.MaxResultsTotal); err != nil {
+		return report, err
+	}
+	repairKeys, err := config.ParseRepairKeys(options.RepairSnapshots)
+	if err != nil {
+		return report, err
+	}
+	if options.JQL, err = resolveJQLFile(workDir, options.JQL, options.JQLFile); err != nil {
+		return report, err
+	}
 
 	cfg, err := config.Read(filepath.Join(workDir, contracts.DefaultConfigFilePath))
 	if err != nil {
@@ -39,67 +81,100 @@ func RunPull(ctx context.Context, workDir string, options PullOptions) (output.R
 		environment = config.EnvironmentFromOS()
 	}
 
-	settings, err := config.Resolve(cfg, config.RuntimeFlags{Profile: options.Profile, JQL: options.JQL}, environment, config.ResolveOptions{RequireToken: true})
+	settings, err := config.Resolve(cfg, config.RuntimeFlags{Profile: options.Profile, JQL: options.JQL, JiraBaseURL: options.JiraBaseURL, JiraEmail: options.JiraEmail, PageSize: options.PageSize, Concurrency: options.Concurrency}, environment, config.ResolveOptions{RequireToken: true})
 	if err != nil {
 		return report, err
 	}
 
 	jql := strings.TrimSpace(settings.DefaultJQL)
-	if jql == "" {
+	if jql == "" && len(repairKeys) == 0 {
 		return report, fmt.Errorf("failed to resolve runtime settings: no jql provided via --jql or config defaults")
 	}
 
 	adapter := options.Adapter
 	if adapter == nil {
-		adapter, err = jira.NewCloudAdapter(jira.CloudAdapterOptions{
+		adapter, err = jira.NewCloudAdapter(withTLSFiles(jira.CloudAdapterOptions{
 			BaseURL:  settings.JiraBaseURL,
 			Email:    settings.JiraEmail,
 			APIToken: settings.JiraAPIToken,
-		})
+			Logger:   options.Logger,
+			RetryOptions: httpclient.Options{
+				Budget:   retryBudgetFor(options.RetryBudget, cfg),
+				Clock:    options.Clock,
+				ProxyURL: contracts.ResolveProxyURL(cfg),
+			},
+			RedactionPatterns:  redactionPatternsFor(cfg),
+			InsecureSkipVerify: options.Insecure,
+		}, workDir, cfg))
 		if err != nil {
 			return report, fmt.Errorf("failed to initialize jira adapter: %w", err)
 		}
 	}
-
-	issueStore, err := store.New(filepath.Join(workDir, contracts.DefaultIssuesRootDir))
+	adapter, stopRecording, err := recordCalls(adapter, workDir, options.RecordPath, settings.JiraAPIToken, cfg, options.Logger)
 	if err != nil {
-		return report, fmt.Errorf("failed to initialize issue store: %w", err)
+		return report, err
+	}
+	defer stopRecording()
+	if err := checkProjectKey(ctx, adapter, settings.Profile.ProjectKey, options.Logger); err != nil {
+		return report, err
 	}
 
-	now := options.Now
-	if now == nil {
-		now = time.Now
+	if options.Flatten {
+		cfg.FlatLayout = true
+	}
+	issueStore, err := openIssueStore(issuesRootFromConfig(workDir, cfg), cfg)
+	if err != nil {
+		return report, fmt.Errorf("failed to initialize issue store: %w", err)
 	}
 
 	pipeline := pullsync.Pipeline{
 		Adapter:            adapter,
 		Store:              issueStore,
-		Converter:          pullsync.NewADFMarkdownConverter(),
-		PageSize:           options.PageSize,
-		Concurrency:        options.Concurrency,
-		Now:                now,
+		Converter:          pullsync.NewADFMarkdownConverter(pullsync.ConverterOptions{Flavor: contracts.ResolveMarkdownFlavor(cfg)}),
+		PageSize:           settings.PageSize,
+		Concurrency:        settings.Concurrency,
+		Clock:              options.Clock,
+		Logger:             options.Logger,
 		CustomFieldAliases: settings.Profile.FieldConfig.Aliases,
 		PullFields:         resolvePullFields(settings.Profile.FieldConfig),
+		SkipEnvironment:    settings.Profile.FieldConfig.SkipEnvironment,
+		PreserveLabelCase:  settings.Profile.PreserveLabelCase,
+		IncludeComments:    settings.Profile.FieldConfig.IncludeMetadata,
+		DryRun:             options.DryRun,
+		MaxErrors:          options.MaxErrors,
+		MaxIssues:          options.MaxResultsTotal,
+	}
+	if pipeline.MaxIssues == 0 {
+		pipeline.MaxIssues = cfg.MaxPullIssues
 	}
 
-	result, err := pipeline.Execute(ctx, jql)
+	var result pullsync.Result
+	if len(repairKeys) > 0 {
+		result, err = pipeline.RepairSnapshots(ctx, repairKeys)
+	} else {
+		result, err = pipeline.Execute(ctx, jql)
+	}
 	if err != nil {
 		if typed := asJiraError(err); typed != nil {
 			return report, fmt.Errorf("failed to pull issues: %s", typed.Error())
 		}
 		return report, fmt.Errorf("failed to pull issues: %w", err)
 	}
+	report.Timings = result.Timings
 
 	for _, outcome := range result.Outcomes {
 		report.Counts.Processed++
 		if outcome.Updated {
 			report.Counts.Updated++
 		}
-		if outcome.Status == contracts.PerIssueStatusError {
+		switch outcome.Status {
+		case contracts.PerIssueStatusError:
 			report.Counts.Errors++
+		case contracts.PerIssueStatusWarning:
+			report.Counts.Warnings++
 		}
 
-		if !outcome.Updated && outcome.Status == contracts.PerIssueStatusSuccess {
+		if !options.IncludeUnchanged && !outcome.Updated && outcome.Status == contracts.PerIssueStatusSuccess {
 			continue
 		}
 
@@ -111,9 +186,27 @@ func RunPull(ctx context.Context, workDir string, options PullOptions) (output.R
 		})
 	}
 
+	if exceedsMaxErrors(report, options.MaxErrors) {
+		return report, &MaxErrorsExceededError{Limit: options.MaxErrors, Errors: report.Counts.Errors}
+	}
 	return report, nil
 }
 
+// resolveJQLFile returns the query from jqlFile, resolved against workDir
+// when relative, or jql unchanged when no file is given.
+func resolveJQLFile(workDir string, jql string, jqlFile string) (string, error) {
+	if jqlFile == "" {
+		return jql, nil
+	}
+	if jql != "" {
+		return "", &config.ResolveError{Code: config.ResolveErrorCodeInvalidFlag, Message: "--jql and --jql-file cannot be combined"}
+	}
+	if !filepath.IsAbs(jqlFile) {
+		jqlFile = filepath.Join(workDir, jqlFile)
+	}
+	return config.ReadJQLFile(jqlFile)
+}
+
 func asJiraError(err error) *jira.Error {
 	var typed *jira.Error
 	if errors.As(err, &typed) {
@@ -147,6 +240,11 @@ func resolvePullFields(fieldConfig contracts.FieldConfig) []string {
 		seen[trimmed] = struct{}{}
 		result = append(result, trimmed)
 	}
+	// include_metadata adds the read-only comments section, which needs the
+	// comment field even when the chosen wildcard would leave it out.
+	if fieldConfig.IncludeMetadata {
+		fieldConfig.IncludeFields = append(append([]string(nil), fieldConfig.IncludeFields...), "comment")
+	}
 	for _, field := range fieldConfig.IncludeFields {
 		trimmed := strings.TrimSpace(field)
 		if trimmed == "" {
@@ -169,14 +267,34 @@ func resolvePullFields(fieldConfig contracts.FieldConfig) []string {
 	}
 
 	filtered := make([]string, 0, len(result))
+	wildcard := false
 	for _, field := range result {
 		if _, excludedField := excluded[field]; excludedField {
 			continue
 		}
+		wildcard = wildcard || strings.HasPrefix(field, "*")
 		filtered = append(filtered, field)
 	}
 	if len(filtered) == 0 {
-		return []string{"*navigable"}
+		filtered = append(filtered, "*navigable")
+		wildcard = true
+	}
+
+	// Dropping an excluded field from the list is not enough when a
+	// wildcard would still fetch it; Jira's "-field" form removes it from
+	// the wildcard too, so description can be left on the server.
+	if wildcard {
+		for _, field := range fieldConfig.ExcludeFields {
+			trimmed := strings.TrimSpace(field)
+			if trimmed == "" || strings.HasPrefix(trimmed, "*") {
+				continue
+			}
+			if _, ok := seen["-"+trimmed]; ok {
+				continue
+			}
+			seen["-"+trimmed] = struct{}{}
+			filtered = append(filtered, "-"+trimmed)
+		}
 	}
 	return filtered
 }
@@ -3,13 +3,19 @@ package commands
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/pweiskircher/jira-issue-sync/internal/clock"
 	"github.com/pweiskircher/jira-issue-sync/internal/config"
 	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
 	"github.com/pweiskircher/jira-issue-sync/internal/jira"
+	"github.com/pweiskircher/jira-issue-sync/internal/output"
 	"github.com/pweiskircher/jira-issue-sync/internal/store"
 )
 
@@ -22,6 +28,65 @@ func TestResolvePullFieldsUsesConfig(t *testing.T) {
 	if len(fields) != 1 || fields[0] != "customfield_10010" {
 		t.Fatalf("unexpected resolved fields: %#v", fields)
 	}
+
+	fields = resolvePullFields(contracts.FieldConfig{ExcludeFields: []string{"description", "environment"}})
+	if !slices.Equal(fields, []string{"*navigable", "-description", "-environment"}) {
+		t.Fatalf("expected wildcard exclusions, got %#v", fields)
+	}
+}
+
+func TestRunPullExcludedDescriptionIsNeitherRequestedNorWritten(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	cfg := contracts.Config{
+		ConfigVersion: contracts.ConfigSchemaVersionV1,
+		Profiles: map[string]contracts.ProjectProfile{
+			"default": {
+				ProjectKey:  "PROJ",
+				DefaultJQL:  "project = PROJ",
+				FieldConfig: contracts.FieldConfig{ExcludeFields: []string{"description"}},
+			},
+		},
+	}
+	if err := config.Write(filepath.Join(workspace, contracts.DefaultConfigFilePath), cfg); err != nil {
+		t.Fatalf("write config failed: %v", err)
+	}
+
+	// The stub ignores the field list, so the written file proves pull
+	// drops the description itself rather than relying on the server.
+	adapter := &pullAdapterStub{}
+	adapter.search = func(_ context.Context, request jira.SearchIssuesRequest) (jira.SearchIssuesResponse, error) {
+		return jira.SearchIssuesResponse{Total: 1, Issues: []jira.Issue{{
+			Key: "PROJ-1",
+			Fields: jira.IssueFields{
+				Summary:     "Metadata only",
+				Description: json.RawMessage(`{"version":1,"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"heavy"}]}]}`),
+				Status:      &jira.StatusRef{Name: "Open"},
+				IssueType:   &jira.NamedRef{Name: "Task"},
+			},
+		}}}, nil
+	}
+
+	if _, err := RunPull(context.Background(), workspace, PullOptions{Adapter: adapter, Environment: config.Environment{JiraAPIToken: "token"}}); err != nil {
+		t.Fatalf("run pull failed: %v", err)
+	}
+
+	if len(adapter.requests) != 1 {
+		t.Fatalf("expected one search request, got %d", len(adapter.requests))
+	}
+	fields := adapter.requests[0].Fields
+	if slices.Contains(fields, "description") || !slices.Contains(fields, "-description") {
+		t.Fatalf("expected search to exclude description, got %v", fields)
+	}
+
+	content, err := os.ReadFile(filepath.Join(workspace, contracts.DefaultIssuesRootDir, "open", "PROJ-1-metadata-only.md"))
+	if err != nil {
+		t.Fatalf("read pulled issue failed: %v", err)
+	}
+	if strings.Contains(string(content), "```"+contracts.RawADFFenceLanguage) || strings.Contains(string(content), "heavy") {
+		t.Fatalf("expected no description or ADF block, got:\n%s", content)
+	}
 }
 
 func TestRunPullContinuesAfterPerIssueFailures(t *testing.T) {
@@ -150,6 +215,588 @@ func TestRunPullHidesUnchangedIssues(t *testing.T) {
 	}
 }
 
+func TestRunPullFlattenMovesIssuesUnderIssuesRoot(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	writePullConfig(t, workspace)
+
+	adapter := &pullAdapterStub{}
+	adapter.search = func(_ context.Context, request jira.SearchIssuesRequest) (jira.SearchIssuesResponse, error) {
+		if request.StartAt > 0 {
+			return jira.SearchIssuesResponse{StartAt: request.StartAt, Total: 2}, nil
+		}
+		return jira.SearchIssuesResponse{StartAt: 0, Total: 2, Issues: []jira.Issue{
+			{Key: "PROJ-10", Fields: jira.IssueFields{Summary: "Active", Status: &jira.StatusRef{Name: "Open"}, IssueType: &jira.NamedRef{Name: "Task"}}},
+			{Key: "PROJ-11", Fields: jira.IssueFields{Summary: "Finished", Status: &jira.StatusRef{Name: "Done"}, IssueType: &jira.NamedRef{Name: "Task"}}},
+		}}, nil
+	}
+
+	if _, err := RunPull(context.Background(), workspace, PullOptions{
+		Adapter:     adapter,
+		Environment: config.Environment{JiraAPIToken: "token"},
+	}); err != nil {
+		t.Fatalf("split pull failed: %v", err)
+	}
+	issuesRoot := filepath.Join(workspace, ".issues")
+	if _, err := os.Stat(filepath.Join(issuesRoot, "closed", "PROJ-11-finished.md")); err != nil {
+		t.Fatalf("expected split layout before --flatten: %v", err)
+	}
+
+	report, err := RunPull(context.Background(), workspace, PullOptions{
+		Adapter:     adapter,
+		Environment: config.Environment{JiraAPIToken: "token"},
+		Flatten:     true,
+	})
+	if err != nil {
+		t.Fatalf("flatten pull failed: %v", err)
+	}
+	if report.Counts.Updated != 2 || report.Counts.Errors != 0 {
+		t.Fatalf("expected both issues to move, got %#v", report.Counts)
+	}
+	for _, name := range []string{"PROJ-10-active.md", "PROJ-11-finished.md"} {
+		if _, err := os.Stat(filepath.Join(issuesRoot, name)); err != nil {
+			t.Fatalf("expected %s under the issues root: %v", name, err)
+		}
+	}
+	for _, old := range []string{filepath.Join("open", "PROJ-10-active.md"), filepath.Join("closed", "PROJ-11-finished.md")} {
+		if _, err := os.Stat(filepath.Join(issuesRoot, old)); !os.IsNotExist(err) {
+			t.Fatalf("expected %s to be removed after flattening, got %v", old, err)
+		}
+	}
+
+	closedOnly, err := RunList(workspace, ListOptions{State: "closed"})
+	if err != nil {
+		t.Fatalf("list flat workspace failed: %v", err)
+	}
+	if len(closedOnly.Issues) != 1 || closedOnly.Issues[0].Key != "PROJ-11" || closedOnly.Counts.Errors != 0 {
+		t.Fatalf("expected flat Done issue to list as closed, got %#v", closedOnly)
+	}
+	status, err := RunStatus(workspace, StatusOptions{IncludeUnchanged: true})
+	if err != nil {
+		t.Fatalf("status on flat workspace failed: %v", err)
+	}
+	if status.Counts.Processed != 2 || status.Counts.Modified != 0 || status.Counts.Errors != 0 {
+		t.Fatalf("expected flat issues to match their snapshots, got %#v", status.Counts)
+	}
+}
+
+func TestRunPullReadsJQLFromFile(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	writePullConfig(t, workspace)
+	content := "# sprint triage\nproject = PROJ\n  AND labels = \"ops\"  \n\n# newest first\nORDER BY updated DESC\n"
+	if err := os.WriteFile(filepath.Join(workspace, "triage.jql"), []byte(content), 0o644); err != nil {
+		t.Fatalf("write jql file: %v", err)
+	}
+
+	adapter := &pullAdapterStub{}
+	if _, err := RunPull(context.Background(), workspace, PullOptions{
+		JQLFile:     "triage.jql",
+		Adapter:     adapter,
+		Environment: config.Environment{JiraAPIToken: "token"},
+	}); err != nil {
+		t.Fatalf("pull with --jql-file failed: %v", err)
+	}
+	if len(adapter.requests) == 0 {
+		t.Fatalf("expected a search request")
+	}
+	if got, want := adapter.requests[0].JQL, `project = PROJ AND labels = "ops" ORDER BY updated DESC`; got != want {
+		t.Fatalf("expected search JQL %q, got %q", want, got)
+	}
+
+	if err := os.WriteFile(filepath.Join(workspace, "empty.jql"), []byte("# nothing yet\n\n"), 0o644); err != nil {
+		t.Fatalf("write empty jql file: %v", err)
+	}
+	_, err := RunPull(context.Background(), workspace, PullOptions{
+		JQLFile:     "empty.jql",
+		Adapter:     adapter,
+		Environment: config.Environment{JiraAPIToken: "token"},
+	})
+	if !config.IsResolveErrorCode(err, config.ResolveErrorCodeInvalidFlag) {
+		t.Fatalf("expected invalid_flag_value for empty jql file, got %v", err)
+	}
+}
+
+func TestRunPullPicksUpIssueFileMissingFromCache(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	writePullConfig(t, workspace)
+	issuesRoot := filepath.Join(workspace, ".issues")
+	handMade := filepath.Join(issuesRoot, "open", "PROJ-10-copied-by-hand.md")
+	if err := os.MkdirAll(filepath.Dir(handMade), 0o755); err != nil {
+		t.Fatalf("create open dir: %v", err)
+	}
+	if err := os.WriteFile(handMade, []byte("---\nkey: PROJ-10\nsummary: Copied\n---\n"), 0o644); err != nil {
+		t.Fatalf("seed issue file: %v", err)
+	}
+
+	adapter := &pullAdapterStub{}
+	adapter.search = func(_ context.Context, request jira.SearchIssuesRequest) (jira.SearchIssuesResponse, error) {
+		if request.StartAt > 0 {
+			return jira.SearchIssuesResponse{StartAt: request.StartAt, Total: 1}, nil
+		}
+		return jira.SearchIssuesResponse{StartAt: 0, Total: 1, Issues: []jira.Issue{
+			{ID: "10010", Key: "PROJ-10", Fields: jira.IssueFields{Summary: "Active", Status: &jira.StatusRef{Name: "Open"}, IssueType: &jira.NamedRef{Name: "Task"}}},
+		}}, nil
+	}
+
+	report, err := RunPull(context.Background(), workspace, PullOptions{
+		Adapter:     adapter,
+		Environment: config.Environment{JiraAPIToken: "token"},
+	})
+	if err != nil {
+		t.Fatalf("pull failed: %v", err)
+	}
+	if report.Counts.Updated != 1 || report.Counts.Errors != 0 {
+		t.Fatalf("unexpected pull counts: %#v", report.Counts)
+	}
+	if _, err := os.Stat(handMade); !os.IsNotExist(err) {
+		t.Fatalf("expected hand-made copy to be replaced, got %v", err)
+	}
+
+	issueStore, err := store.New(issuesRoot)
+	if err != nil {
+		t.Fatalf("store init failed: %v", err)
+	}
+	cache, err := issueStore.LoadCache()
+	if err != nil {
+		t.Fatalf("load cache failed: %v", err)
+	}
+	if got := cache.Issues["PROJ-10"].Path; got != filepath.Join("open", "PROJ-10-active.md") {
+		t.Fatalf("expected cache to track the pulled file, got %q", got)
+	}
+}
+
+func TestRunPullRendersReadOnlyCommentsThatPushIgnores(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	cfg := contracts.Config{
+		ConfigVersion: contracts.ConfigSchemaVersionV1,
+		Profiles: map[string]contracts.ProjectProfile{
+			"default": {
+				ProjectKey:  "PROJ",
+				DefaultJQL:  "project = PROJ",
+				FieldConfig: contracts.FieldConfig{IncludeMetadata: true},
+			},
+		},
+	}
+	if err := config.Write(filepath.Join(workspace, contracts.DefaultConfigFilePath), cfg); err != nil {
+		t.Fatalf("write config failed: %v", err)
+	}
+
+	adf := func(text string) json.RawMessage {
+		return json.RawMessage(`{"version":1,"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"` + text + `"}]}]}`)
+	}
+	remote := jira.Issue{Key: "PROJ-1", Fields: jira.IssueFields{
+		Summary:     "Commented",
+		Description: adf("body"),
+		Status:      &jira.StatusRef{Name: "Open"},
+		IssueType:   &jira.NamedRef{Name: "Task"},
+		Comments: []jira.Comment{
+			{ID: "2", Author: &jira.AccountRef{DisplayName: "Bob"}, Body: adf("Second thoughts"), CreatedAt: "2026-02-21T09:00:00.000+0000"},
+			{ID: "1", Author: &jira.AccountRef{DisplayName: "Alice"}, Body: adf("First!"), CreatedAt: "2026-02-20T12:00:00.000+0000"},
+		},
+	}}
+	pullAdapter := &pullAdapterStub{}
+	pullAdapter.search = func(_ context.Context, request jira.SearchIssuesRequest) (jira.SearchIssuesResponse, error) {
+		if request.StartAt > 0 {
+			return jira.SearchIssuesResponse{StartAt: request.StartAt, Total: 1}, nil
+		}
+		return jira.SearchIssuesResponse{Total: 1, Issues: []jira.Issue{remote}}, nil
+	}
+
+	if _, err := RunPull(context.Background(), workspace, PullOptions{Adapter: pullAdapter, Environment: config.Environment{JiraAPIToken: "token"}}); err != nil {
+		t.Fatalf("pull failed: %v", err)
+	}
+	if fields := strings.Join(pullAdapter.requests[0].Fields, ","); !strings.Contains(fields, "comment") {
+		t.Fatalf("expected the comment field to be requested, got %s", fields)
+	}
+	issuePath := filepath.Join(workspace, ".issues", "open", "PROJ-1-commented.md")
+	written, err := os.ReadFile(issuePath)
+	if err != nil {
+		t.Fatalf("read pulled issue: %v", err)
+	}
+	wantComments := contracts.CommentsSectionMarker + "\n\n## Comments\n\n" +
+		"_Comments from Jira are read-only here; push ignores edits to this section._\n\n" +
+		"### Alice at 2026-02-20T12:00:00.000+0000\n\nFirst!\n\n" +
+		"### Bob at 2026-02-21T09:00:00.000+0000\n\nSecond thoughts\n"
+	if !strings.HasSuffix(string(written), wantComments) {
+		t.Fatalf("expected comments section at the end, got:\n%s", written)
+	}
+
+	edited := strings.Replace(string(written), "First!", "Edited comment", 1)
+	edited = strings.Replace(edited, "\nbody\n", "\nnew body\n", 1)
+	if err := os.WriteFile(issuePath, []byte(edited), 0o644); err != nil {
+		t.Fatalf("edit issue: %v", err)
+	}
+
+	pushAdapter := &pushAdapterStub{issues: map[string]jira.Issue{"PROJ-1": remote}}
+	report, err := RunPush(context.Background(), workspace, PushOptions{Adapter: pushAdapter, Environment: config.Environment{JiraAPIToken: "token"}})
+	if err != nil {
+		t.Fatalf("push failed: %v", err)
+	}
+	if len(pushAdapter.updateRequests) != 1 || pushAdapter.updateRequests[0].Description == nil {
+		t.Fatalf("expected one description update, got %#v", pushAdapter.updateRequests)
+	}
+	if sent := string(*pushAdapter.updateRequests[0].Description); strings.Contains(sent, "Edited comment") || strings.Contains(sent, "Comments") {
+		t.Fatalf("expected comments to stay out of the description, got %s", sent)
+	}
+	warned := false
+	for _, message := range report.Issues[0].Messages {
+		warned = warned || (message.Level == "warning" && strings.Contains(message.Text, "comments section is read-only"))
+	}
+	if !warned {
+		t.Fatalf("expected a read-only comments warning, got %#v", report.Issues[0].Messages)
+	}
+}
+
+func TestRunPullIncludeUnchangedReportsWithoutRewriting(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	writePullConfig(t, workspace)
+
+	adapter := &pullAdapterStub{}
+	adapter.search = func(_ context.Context, request jira.SearchIssuesRequest) (jira.SearchIssuesResponse, error) {
+		if request.StartAt > 0 {
+			return jira.SearchIssuesResponse{StartAt: request.StartAt, Total: 1}, nil
+		}
+		return jira.SearchIssuesResponse{StartAt: 0, Total: 1, Issues: []jira.Issue{{
+			Key: "PROJ-10",
+			Fields: jira.IssueFields{
+				Summary:   "Stable",
+				Status:    &jira.StatusRef{Name: "Open"},
+				IssueType: &jira.NamedRef{Name: "Task"},
+				UpdatedAt: "2026-02-20T12:00:00Z",
+			},
+		}}}, nil
+	}
+
+	options := PullOptions{Adapter: adapter, Environment: config.Environment{JiraAPIToken: "token"}, IncludeUnchanged: true}
+	if _, err := RunPull(context.Background(), workspace, options); err != nil {
+		t.Fatalf("first pull failed: %v", err)
+	}
+	issuePath := filepath.Join(workspace, ".issues", "open", "PROJ-10-stable.md")
+	past := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(issuePath, past, past); err != nil {
+		t.Fatalf("chtimes failed: %v", err)
+	}
+
+	second, err := RunPull(context.Background(), workspace, options)
+	if err != nil {
+		t.Fatalf("second pull failed: %v", err)
+	}
+	if second.Counts.Updated != 0 || len(second.Issues) != 1 || second.Issues[0].Action != "unchanged" {
+		t.Fatalf("expected one reported unchanged issue, got counts=%#v issues=%#v", second.Counts, second.Issues)
+	}
+	info, err := os.Stat(issuePath)
+	if err != nil {
+		t.Fatalf("stat issue failed: %v", err)
+	}
+	if !info.ModTime().Equal(past) {
+		t.Fatalf("expected unchanged issue file not to be rewritten, mtime=%s", info.ModTime())
+	}
+}
+
+func TestRunPullStampsSyncedAtFromInjectedClock(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	writePullConfig(t, workspace)
+
+	adapter := &pullAdapterStub{}
+	adapter.search = func(_ context.Context, request jira.SearchIssuesRequest) (jira.SearchIssuesResponse, error) {
+		if request.StartAt > 0 {
+			return jira.SearchIssuesResponse{StartAt: request.StartAt, Total: 1}, nil
+		}
+		return jira.SearchIssuesResponse{StartAt: 0, Total: 1, Issues: []jira.Issue{{
+			Key: "PROJ-11",
+			Fields: jira.IssueFields{
+				Summary:   "Clocked",
+				Status:    &jira.StatusRef{Name: "Open"},
+				IssueType: &jira.NamedRef{Name: "Task"},
+				UpdatedAt: "2026-02-20T12:00:00Z",
+			},
+		}}}, nil
+	}
+
+	fake := clock.NewFake(time.Date(2026, time.March, 3, 8, 30, 15, 0, time.FixedZone("CET", 3600)))
+	if _, err := RunPull(context.Background(), workspace, PullOptions{
+		Adapter:     adapter,
+		Environment: config.Environment{JiraAPIToken: "token"},
+		Clock:       fake,
+	}); err != nil {
+		t.Fatalf("pull failed: %v", err)
+	}
+
+	written, err := os.ReadFile(filepath.Join(workspace, ".issues", "open", "PROJ-11-clocked.md"))
+	if err != nil {
+		t.Fatalf("read pulled issue failed: %v", err)
+	}
+	if !strings.Contains(string(written), `synced_at: "2026-03-03T07:30:15Z"`) {
+		t.Fatalf("expected synced_at from injected clock, got:\n%s", written)
+	}
+}
+
+func TestRunPullDryRunReportsWouldBeChangesWithoutWriting(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	writePullConfig(t, workspace)
+
+	issuesRoot := filepath.Join(workspace, ".issues")
+	seedStore, err := store.New(issuesRoot)
+	if err != nil {
+		t.Fatalf("store init failed: %v", err)
+	}
+	seededCache := store.Cache{Issues: map[string]store.CacheEntry{
+		"PROJ-2": {Path: filepath.Join("open", "PROJ-2-old-title.md"), Status: "open", RemoteUpdatedAt: "2026-01-01T00:00:00Z"},
+	}}
+	if err := seedStore.SaveCache(seededCache); err != nil {
+		t.Fatalf("seed cache failed: %v", err)
+	}
+	if err := seedStore.WriteFile(filepath.Join("open", "PROJ-2-old-title.md"), []byte("---\nkey: PROJ-2\n---\n")); err != nil {
+		t.Fatalf("seed issue file failed: %v", err)
+	}
+	cacheBefore, err := os.ReadFile(filepath.Join(issuesRoot, ".sync", "cache.json"))
+	if err != nil {
+		t.Fatalf("read cache failed: %v", err)
+	}
+
+	adapter := &pullAdapterStub{}
+	adapter.search = func(ctx context.Context, request jira.SearchIssuesRequest) (jira.SearchIssuesResponse, error) {
+		issues := []jira.Issue{
+			{Key: "PROJ-1", Fields: jira.IssueFields{Summary: "Fresh", Status: &jira.StatusRef{Name: "Open"}, IssueType: &jira.NamedRef{Name: "Task"}}},
+			{Key: "PROJ-2", Fields: jira.IssueFields{Summary: "Renamed", Status: &jira.StatusRef{Name: "Open"}, IssueType: &jira.NamedRef{Name: "Task"}}},
+		}
+		return jira.SearchIssuesResponse{StartAt: request.StartAt, Total: len(issues), Issues: issues}, nil
+	}
+
+	report, err := RunPull(context.Background(), workspace, PullOptions{
+		DryRun:      true,
+		Adapter:     adapter,
+		Environment: config.Environment{JiraAPIToken: "token"},
+	})
+	if err != nil {
+		t.Fatalf("run pull failed: %v", err)
+	}
+
+	if !report.DryRun || report.Counts.Processed != 2 || report.Counts.Updated != 0 {
+		t.Fatalf("unexpected dry-run report: %#v", report)
+	}
+	if len(report.Issues) != 2 {
+		t.Fatalf("expected both issues listed as would-be changes, got %#v", report.Issues)
+	}
+	expectedActions := map[string]string{"PROJ-1": "would-pull", "PROJ-2": "would-rename"}
+	for _, result := range report.Issues {
+		if result.Action != expectedActions[result.Key] || result.Status != contracts.PerIssueStatusSkipped {
+			t.Fatalf("unexpected dry-run result: %#v", result)
+		}
+		if len(result.Messages) != 1 || result.Messages[0].ReasonCode != contracts.ReasonCodeDryRunNoWrite {
+			t.Fatalf("expected dry_run_no_write message, got %#v", result.Messages)
+		}
+	}
+
+	seededFiles := map[string]int{"open": 1}
+	for _, dir := range []string{"open", "closed", filepath.Join(".sync", "originals")} {
+		entries, err := os.ReadDir(filepath.Join(issuesRoot, dir))
+		if err != nil {
+			t.Fatalf("read %s failed: %v", dir, err)
+		}
+		if len(entries) != seededFiles[dir] {
+			t.Fatalf("expected dry-run to leave %s unchanged, found %d entries", dir, len(entries))
+		}
+	}
+	cacheAfter, err := os.ReadFile(filepath.Join(issuesRoot, ".sync", "cache.json"))
+	if err != nil {
+		t.Fatalf("read cache failed: %v", err)
+	}
+	if string(cacheAfter) != string(cacheBefore) {
+		t.Fatalf("expected cache to be untouched, got %s", cacheAfter)
+	}
+}
+
+func TestRunPullStopsPersistingAfterMaxErrorsExceeded(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	writePullConfig(t, workspace)
+
+	broken := json.RawMessage(`{"version":1,"type":"doc","content":[}`)
+	adapter := &pullAdapterStub{}
+	adapter.search = func(ctx context.Context, request jira.SearchIssuesRequest) (jira.SearchIssuesResponse, error) {
+		issues := []jira.Issue{
+			{Key: "PROJ-1", Fields: jira.IssueFields{Summary: "One", Description: broken, Status: &jira.StatusRef{Name: "Open"}, IssueType: &jira.NamedRef{Name: "Task"}}},
+			{Key: "PROJ-2", Fields: jira.IssueFields{Summary: "Two", Description: broken, Status: &jira.StatusRef{Name: "Open"}, IssueType: &jira.NamedRef{Name: "Task"}}},
+			{Key: "PROJ-3", Fields: jira.IssueFields{Summary: "Three", Description: broken, Status: &jira.StatusRef{Name: "Open"}, IssueType: &jira.NamedRef{Name: "Task"}}},
+			{Key: "PROJ-4", Fields: jira.IssueFields{Summary: "Four", Status: &jira.StatusRef{Name: "Open"}, IssueType: &jira.NamedRef{Name: "Task"}}},
+		}
+		return jira.SearchIssuesResponse{StartAt: request.StartAt, Total: len(issues), Issues: issues}, nil
+	}
+
+	report, err := RunPull(context.Background(), workspace, PullOptions{
+		MaxErrors:   1,
+		Adapter:     adapter,
+		Environment: config.Environment{JiraAPIToken: "token"},
+	})
+	var exceeded *MaxErrorsExceededError
+	if !errors.As(err, &exceeded) {
+		t.Fatalf("expected max-errors stop, got %v", err)
+	}
+	if report.Counts.Processed != 2 || report.Counts.Errors != 2 {
+		t.Fatalf("expected pull to stop after the second failure, got %#v", report.Counts)
+	}
+	if _, statErr := os.Stat(filepath.Join(workspace, ".issues", "open", "PROJ-4-four.md")); !os.IsNotExist(statErr) {
+		t.Fatalf("expected issues after the stop point to stay unwritten, got %v", statErr)
+	}
+}
+
+func TestRunInitAndPullUseConfiguredIssuesRoot(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	if _, err := RunInit(context.Background(), workspace, InitOptions{ProjectKey: "PROJ", DefaultJQL: "project = PROJ", IssuesDir: "docs/issues"}); err != nil {
+		t.Fatalf("run init failed: %v", err)
+	}
+
+	customRoot := filepath.Join(workspace, "docs", "issues")
+	if _, err := os.Stat(filepath.Join(customRoot, ".sync", "originals")); err != nil {
+		t.Fatalf("expected layout under custom root: %v", err)
+	}
+
+	adapter := &pullAdapterStub{}
+	adapter.search = func(_ context.Context, request jira.SearchIssuesRequest) (jira.SearchIssuesResponse, error) {
+		if request.StartAt > 0 {
+			return jira.SearchIssuesResponse{StartAt: request.StartAt, Total: 1}, nil
+		}
+		return jira.SearchIssuesResponse{StartAt: 0, Total: 1, Issues: []jira.Issue{{
+			Key: "PROJ-3",
+			Fields: jira.IssueFields{
+				Summary:   "Custom root",
+				Status:    &jira.StatusRef{Name: "Open"},
+				IssueType: &jira.NamedRef{Name: "Task"},
+			},
+		}}}, nil
+	}
+
+	if _, err := RunPull(context.Background(), workspace, PullOptions{Adapter: adapter, Environment: config.Environment{JiraAPIToken: "token"}}); err != nil {
+		t.Fatalf("run pull failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(customRoot, "open", "PROJ-3-custom-root.md")); err != nil {
+		t.Fatalf("expected pulled file under custom root: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(workspace, contracts.DefaultOpenDir, "PROJ-3-custom-root.md")); !os.IsNotExist(err) {
+		t.Fatalf("did not expect pulled file under default root, err=%v", err)
+	}
+
+	listed, err := RunList(workspace, ListOptions{})
+	if err != nil {
+		t.Fatalf("run list failed: %v", err)
+	}
+	if len(listed.Issues) != 1 || listed.Issues[0].Key != "PROJ-3" {
+		t.Fatalf("expected list to read custom root, got %#v", listed.Issues)
+	}
+}
+
+func TestRunInitRejectsEscapingIssuesRoot(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	if _, err := RunInit(context.Background(), workspace, InitOptions{ProjectKey: "PROJ", IssuesDir: "../outside"}); err == nil {
+		t.Fatalf("expected escaping issues root to be rejected")
+	}
+	if _, err := os.Stat(filepath.Join(workspace, contracts.DefaultConfigFilePath)); !os.IsNotExist(err) {
+		t.Fatalf("expected no config to be written, err=%v", err)
+	}
+}
+
+func TestRunPullEnvelopeIncludesPhaseTimings(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	writePullConfig(t, workspace)
+
+	issues := make([]jira.Issue, 0, 3)
+	for index, summary := range []string{"Alpha", "Beta", "Gamma"} {
+		issues = append(issues, jira.Issue{
+			Key: "PROJ-" + string(rune('1'+index)),
+			Fields: jira.IssueFields{
+				Summary:     summary,
+				Description: json.RawMessage(`{"version":1,"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"body"}]}]}`),
+				Status:      &jira.StatusRef{Name: "Open"},
+				IssueType:   &jira.NamedRef{Name: "Task"},
+			},
+		})
+	}
+
+	adapter := &pullAdapterStub{}
+	adapter.search = func(context.Context, jira.SearchIssuesRequest) (jira.SearchIssuesResponse, error) {
+		time.Sleep(time.Millisecond)
+		return jira.SearchIssuesResponse{Total: len(issues), Issues: issues}, nil
+	}
+
+	report, err := RunPull(context.Background(), workspace, PullOptions{Adapter: adapter, Environment: config.Environment{JiraAPIToken: "token"}})
+	if err != nil {
+		t.Fatalf("run pull failed: %v", err)
+	}
+
+	envelope, err := output.BuildEnvelope(report, time.Second)
+	if err != nil {
+		t.Fatalf("build envelope failed: %v", err)
+	}
+	encoded, err := json.Marshal(envelope)
+	if err != nil {
+		t.Fatalf("marshal envelope failed: %v", err)
+	}
+
+	var decoded struct {
+		Command struct {
+			Timings []struct {
+				Phase      string `json:"phase"`
+				DurationUS int64  `json:"duration_us"`
+			} `json:"timings"`
+		} `json:"command"`
+	}
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("unmarshal envelope failed: %v", err)
+	}
+
+	phases := make([]string, 0, len(decoded.Command.Timings))
+	for _, timing := range decoded.Command.Timings {
+		if timing.DurationUS <= 0 {
+			t.Fatalf("expected non-zero duration for phase %q in %s", timing.Phase, encoded)
+		}
+		phases = append(phases, timing.Phase)
+	}
+	if strings.Join(phases, ",") != "fetch,convert,persist" {
+		t.Fatalf("unexpected phase order: %v", phases)
+	}
+}
+
+func TestRunPullRejectsUnknownProjectKeyWithSuggestions(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	writePullConfig(t, workspace)
+
+	adapter := &pullAdapterStub{projects: []jira.Project{{Key: "PROJX"}, {Key: "PRJ"}, {Key: "WEB"}}}
+	_, err := RunPull(context.Background(), workspace, PullOptions{Adapter: adapter, Environment: config.Environment{JiraAPIToken: "token"}})
+	if !config.IsResolveErrorCode(err, config.ResolveErrorCodeUnknownProject) {
+		t.Fatalf("expected unknown_project error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "did you mean PRJ, PROJX?") {
+		t.Fatalf("expected close matches in error, got %q", err)
+	}
+	if len(adapter.requests) != 0 {
+		t.Fatalf("expected no search after failed validation, got %d", len(adapter.requests))
+	}
+}
+
 func writePullConfig(t *testing.T, workspace string) {
 	t.Helper()
 
@@ -170,6 +817,8 @@ func writePullConfig(t *testing.T, workspace string) {
 
 type pullAdapterStub struct {
 	requests []jira.SearchIssuesRequest
+	projects []jira.Project
+	statuses []jira.StatusRef
 	search   func(context.Context, jira.SearchIssuesRequest) (jira.SearchIssuesResponse, error)
 }
 
@@ -181,9 +830,25 @@ func (s *pullAdapterStub) SearchIssues(ctx context.Context, request jira.SearchI
 	return s.search(ctx, request)
 }
 
+func (s *pullAdapterStub) ValidateJQL(context.Context, string) error {
+	return nil
+}
+
 func (s *pullAdapterStub) ListFields(context.Context) ([]jira.FieldDefinition, error) {
 	panic("unexpected call")
 }
+func (s *pullAdapterStub) ListProjects(context.Context) ([]jira.Project, error) {
+	return s.projects, nil
+}
+func (s *pullAdapterStub) ListStatuses(context.Context) ([]jira.StatusRef, error) {
+	return s.statuses, nil
+}
+func (s *pullAdapterStub) ListSecurityLevels(context.Context, string) ([]jira.NamedRef, error) {
+	return nil, nil
+}
+func (s *pullAdapterStub) GetMyself(context.Context) (jira.AccountRef, error) {
+	return jira.AccountRef{}, nil
+}
 func (s *pullAdapterStub) GetIssue(context.Context, string, []string) (jira.Issue, error) {
 	panic("unexpected call")
 }
@@ -202,3 +867,79 @@ func (s *pullAdapterStub) ApplyTransition(context.Context, string, string) error
 func (s *pullAdapterStub) ResolveTransition(context.Context, string, contracts.TransitionSelection) (jira.TransitionResolution, error) {
 	panic("unexpected call")
 }
+func (s *pullAdapterStub) SearchAssignableUsers(context.Context, string, string) ([]jira.AccountRef, error) {
+	panic("unexpected call")
+}
+
+func TestRunPullRecordsCallsThatReplayIntoTheSameIssueFiles(t *testing.T) {
+	t.Parallel()
+
+	adapter := &pullAdapterStub{}
+	adapter.search = func(_ context.Context, request jira.SearchIssuesRequest) (jira.SearchIssuesResponse, error) {
+		if request.StartAt > 0 {
+			return jira.SearchIssuesResponse{StartAt: request.StartAt, Total: 1}, nil
+		}
+		return jira.SearchIssuesResponse{StartAt: 0, Total: 1, Issues: []jira.Issue{{
+			Key: "PROJ-10",
+			Fields: jira.IssueFields{
+				Summary:     "Recorded",
+				Description: json.RawMessage(`{"version":1,"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"body"}]}]}`),
+				Status:      &jira.StatusRef{Name: "Open"},
+				IssueType:   &jira.NamedRef{Name: "Task"},
+				UpdatedAt:   "2026-02-20T12:00:00Z",
+			},
+		}}}, nil
+	}
+
+	recorded := t.TempDir()
+	writePullConfig(t, recorded)
+	if _, err := RunPull(context.Background(), recorded, PullOptions{
+		Adapter:     adapter,
+		Environment: config.Environment{JiraAPIToken: "secret-token"},
+		RecordPath:  "calls.jsonl",
+	}); err != nil {
+		t.Fatalf("recorded pull failed: %v", err)
+	}
+
+	recordingPath := filepath.Join(recorded, "calls.jsonl")
+	recording, err := os.ReadFile(recordingPath)
+	if err != nil {
+		t.Fatalf("expected recording relative to the workspace: %v", err)
+	}
+	if len(strings.TrimSpace(string(recording))) == 0 {
+		t.Fatalf("expected recorded calls, got an empty file")
+	}
+	if strings.Contains(string(recording), "secret-token") {
+		t.Fatalf("expected token to stay out of the recording: %s", recording)
+	}
+
+	replayer, err := jira.NewReplayAdapter(recordingPath)
+	if err != nil {
+		t.Fatalf("expected replay adapter, got %v", err)
+	}
+	replayed := t.TempDir()
+	writePullConfig(t, replayed)
+	report, err := RunPull(context.Background(), replayed, PullOptions{
+		Adapter:     replayer,
+		Environment: config.Environment{JiraAPIToken: "secret-token"},
+	})
+	if err != nil {
+		t.Fatalf("replayed pull failed: %v", err)
+	}
+	if report.Counts.Updated != 1 || report.Counts.Errors != 0 {
+		t.Fatalf("unexpected replayed pull counts: %#v", report.Counts)
+	}
+
+	relativePath := filepath.Join(".issues", "open", "PROJ-10-recorded.md")
+	want, err := os.ReadFile(filepath.Join(recorded, relativePath))
+	if err != nil {
+		t.Fatalf("read recorded issue: %v", err)
+	}
+	got, err := os.ReadFile(filepath.Join(replayed, relativePath))
+	if err != nil {
+		t.Fatalf("read replayed issue: %v", err)
+	}
+	if string(got) != string(want) {
+		t.Fatalf("replayed issue differs:\n got %s\nwant %s", got, want)
+	}
+}
@@ -2,6 +2,7 @@ package commands
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -9,31 +10,70 @@ import (
 	"strings"
 	"time"
 
+	"github.com/pweiskircher/jira-issue-sync/internal/clock"
 	"github.com/pweiskircher/jira-issue-sync/internal/config"
 	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
 	"github.com/pweiskircher/jira-issue-sync/internal/converter"
+	"github.com/pweiskircher/jira-issue-sync/internal/gitdiff"
+	httpclient "github.com/pweiskircher/jira-issue-sync/internal/http"
 	"github.com/pweiskircher/jira-issue-sync/internal/issue"
 	"github.com/pweiskircher/jira-issue-sync/internal/jira"
+	"github.com/pweiskircher/jira-issue-sync/internal/logging"
 	"github.com/pweiskircher/jira-issue-sync/internal/output"
-	"github.com/pweiskircher/jira-issue-sync/internal/store"
 	publishsync "github.com/pweiskircher/jira-issue-sync/internal/sync/publish"
 	pullsync "github.com/pweiskircher/jira-issue-sync/internal/sync/pull"
 	pushexecute "github.com/pweiskircher/jira-issue-sync/internal/sync/push/execute"
 )
 
-var pushRemoteFields = []string{"summary", "description", "labels", "assignee", "priority", "status", "issuetype", "reporter", "created", "updated"}
+var pushRemoteFields = []string{"summary", "description", "labels", "assignee", "priority", "status", "issuetype", "reporter", "security", "created", "updated", "environment"}
 
 type PushOptions struct {
 	Profile     string
 	DryRun      bool
-	Now         func() time.Time
+	Clock       clock.Clock
 	Environment config.Environment
 	Adapter     jira.Adapter
+	Logger      logging.Logger
+	RetryBudget *httpclient.RetryBudget
+	// Insecure skips TLS certificate verification for Jira requests.
+	Insecure bool
+	// MaxErrors stops the run after the issue that pushes the error count
+	// past it. Zero means unlimited.
+	MaxErrors int
+	// ChangedSince scopes push to issue files that git reports as changed
+	// relative to this ref. Empty means every local issue is considered.
+	ChangedSince     string
+	ListChangedFiles func(ctx context.Context, dir string, ref string) ([]string, error)
+	// Exclude names writable fields to skip this run, e.g. "description".
+	Exclude []string
+	// NoTransition leaves every issue's Jira status alone this run; it is
+	// shorthand for excluding status.
+	NoTransition bool
+	// JiraBaseURL and JiraEmail override env and config for this run.
+	JiraBaseURL string
+	JiraEmail   string
+	// RecordPath, when set, records every Jira call of the run to this
+	// file for replay with jira.NewReplayAdapter.
+	RecordPath string
 }
 
 func RunPush(ctx context.Context, workDir string, options PushOptions) (output.Report, error) {
 	report := output.Report{CommandName: string(contracts.CommandPush), DryRun: options.DryRun}
 
+	if err := config.ValidateMaxErrors(options.MaxErrors); err != nil {
+		return report, err
+	}
+	excluded, err := config.ParsePushExclusions(options.Exclude)
+	if err != nil {
+		return report, err
+	}
+	if options.NoTransition {
+		if excluded == nil {
+			excluded = map[contracts.JiraField]bool{}
+		}
+		excluded[contracts.JiraFieldStatus] = true
+	}
+
 	cfg, err := config.Read(filepath.Join(workDir, contracts.DefaultConfigFilePath))
 	if err != nil {
 		return report, fmt.Errorf("failed to load config: %w", err)
@@ -44,42 +84,78 @@ func RunPush(ctx context.Context, workDir string, options PushOptions) (output.R
 		environment = config.EnvironmentFromOS()
 	}
 
-	settings, err := config.Resolve(cfg, config.RuntimeFlags{Profile: options.Profile}, environment, config.ResolveOptions{RequireToken: true})
+	settings, err := config.Resolve(cfg, config.RuntimeFlags{Profile: options.Profile, JiraBaseURL: options.JiraBaseURL, JiraEmail: options.JiraEmail}, environment, config.ResolveOptions{RequireToken: true})
 	if err != nil {
 		return report, err
 	}
+	if settings.Profile.FieldConfig.SkipEnvironment {
+		if excluded == nil {
+			excluded = map[contracts.JiraField]bool{}
+		}
+		excluded[contracts.JiraFieldEnvironment] = true
+	}
 
 	adapter := options.Adapter
 	if adapter == nil {
-		adapter, err = jira.NewCloudAdapter(jira.CloudAdapterOptions{BaseURL: settings.JiraBaseURL, Email: settings.JiraEmail, APIToken: settings.JiraAPIToken})
+		adapter, err = jira.NewCloudAdapter(withTLSFiles(jira.CloudAdapterOptions{BaseURL: settings.JiraBaseURL, Email: settings.JiraEmail, APIToken: settings.JiraAPIToken, Logger: options.Logger, RetryOptions: httpclient.Options{Budget: retryBudgetFor(options.RetryBudget, cfg), Clock: options.Clock, ProxyURL: contracts.ResolveProxyURL(cfg)}, RedactionPatterns: redactionPatternsFor(cfg), FieldNames: cachedFieldNames(issuesRootFromConfig(workDir, cfg), cfg, options.Logger), InsecureSkipVerify: options.Insecure}, workDir, cfg))
 		if err != nil {
 			return report, fmt.Errorf("failed to initialize jira adapter: %w", err)
 		}
 	}
+	adapter, stopRecording, err := recordCalls(adapter, workDir, options.RecordPath, settings.JiraAPIToken, cfg, options.Logger)
+	if err != nil {
+		return report, err
+	}
+	defer stopRecording()
+	if err := checkProjectKey(ctx, adapter, settings.Profile.ProjectKey, options.Logger); err != nil {
+		return report, err
+	}
 
-	records, err := loadIssueRecords(workDir, inspectFilter{state: stateFilterAll})
+	issuesRoot := issuesRootFromConfig(workDir, cfg)
+	records, err := loadIssueRecords(issuesRoot, inspectFilter{state: stateFilterAll}, settings.Profile.PreserveLabelCase)
 	if err != nil {
 		return report, fmt.Errorf("failed to read local issues: %w", err)
 	}
+	if strings.TrimSpace(options.ChangedSince) != "" {
+		records, err = filterRecordsChangedSince(ctx, issuesRoot, records, options)
+		if err != nil {
+			return report, err
+		}
+	}
 
-	workspaceStore, err := store.New(filepath.Join(workDir, contracts.DefaultIssuesRootDir))
+	workspaceStore, err := openIssueStore(issuesRoot, cfg)
 	if err != nil {
 		return report, fmt.Errorf("failed to initialize issue store: %w", err)
 	}
 
-	now := options.Now
-	if now == nil {
-		now = time.Now
-	}
-
-	pushConverter := pullsync.NewADFMarkdownConverter()
-	for _, record := range records {
+	now := clock.OrSystem(options.Clock).Now
+
+	// Push works issue by issue, so phase timings accumulate across the loop:
+	// fetch is remote reads, plan is local comparison, apply is remote writes
+	// plus snapshot updates.
+	timings := output.NewPhaseTimings("fetch", "plan", "apply")
+
+	pushConverter := pullsync.NewADFMarkdownConverter(pullsync.ConverterOptions{Flavor: contracts.ResolveMarkdownFlavor(cfg)})
+	transitionCache := pushexecute.NewTransitionCache()
+	readonlyFields := contracts.ResolveReadonlyFields(settings.Profile)
+	// publishedKeys maps drafts published earlier in this run to their Jira
+	// keys; later records have their #L-<hex> references rewritten.
+	publishedKeys := make(map[string]string)
+	resolveSelf := selfAccountResolver(ctx, adapter)
+
+	prefetchStarted := time.Now()
+	prefetched := prefetchRemoteIssues(ctx, adapter, pushPrefetchKeys(issuesRoot, records, settings.Profile.PreserveLabelCase), settings.PageSize, options.Logger)
+	timings.Since("fetch", prefetchStarted)
+	for _, record := range orderForPush(records) {
+		if exceedsMaxErrors(report, options.MaxErrors) {
+			break
+		}
 		if record.Err != nil {
 			appendIssue(&report, contracts.PerIssueResult{Key: record.Key, Action: "parse-error", Status: contracts.PerIssueStatusError, Messages: []contracts.IssueMessage{buildTypedDiagnostic("error", record.ReasonCode, record.ErrorCode, record.Err.Error(), record.RelativePath)}})
 			continue
 		}
 
-		if contracts.LocalDraftKeyPattern.MatchString(record.Key) {
+		if contracts.IsLocalDraftKey(record.Key) {
 			if options.DryRun {
 				appendIssue(&report, contracts.PerIssueResult{
 					Key:    record.Key,
@@ -94,16 +170,28 @@ func RunPush(ctx context.Context, workDir string, options PushOptions) (output.R
 				continue
 			}
 
+			record, _ = rewritePublishedReferences(record, publishedKeys)
+			record, _, err = assignSelf(record, resolveSelf)
+			if err != nil {
+				appendIssue(&report, contracts.PerIssueResult{Key: record.Key, Action: "push-error", Status: contracts.PerIssueStatusError, Messages: []contracts.IssueMessage{{Level: "error", ReasonCode: reasonFromPushError(err), Text: strings.TrimSpace(err.Error())}}})
+				continue
+			}
+			applyStarted := time.Now()
 			publishResult, publishErr := publishsync.PublishDraft(ctx, publishsync.Options{
-				Adapter:    adapter,
-				Store:      workspaceStore,
-				Converter:  pushConverter,
-				ProjectKey: settings.Profile.ProjectKey,
+				Adapter:             adapter,
+				Store:               workspaceStore,
+				Converter:           pushConverter,
+				ProjectKey:          settings.Profile.ProjectKey,
+				AssigneeIsAccountID: settings.Profile.AssigneeIsAccountID,
+				PreserveLabelCase:   settings.Profile.PreserveLabelCase,
+				WriteSecurityLevel:  settings.Profile.WriteSecurityLevel,
+				DraftKeyPrefix:      contracts.ResolveDraftKeyPrefix(cfg),
 			}, publishsync.Input{
 				LocalKey:     record.Key,
 				RelativePath: record.RelativePath,
 				Document:     record.Document,
 			})
+			timings.Since("apply", applyStarted)
 			if publishErr != nil {
 				appendIssue(&report, contracts.PerIssueResult{
 					Key:    record.Key,
@@ -118,6 +206,7 @@ func RunPush(ctx context.Context, workDir string, options PushOptions) (output.R
 				continue
 			}
 
+			publishedKeys[record.Key] = publishResult.RemoteKey
 			appendIssue(&report, contracts.PerIssueResult{
 				Key:    publishResult.RemoteKey,
 				Action: "created",
@@ -130,7 +219,45 @@ func RunPush(ctx context.Context, workDir string, options PushOptions) (output.R
 			continue
 		}
 
-		comparison := compareRecordAgainstSnapshot(workDir, record)
+		var renameMessages []contracts.IssueMessage
+		rewritten, referencesRewritten := rewritePublishedReferences(record, publishedKeys)
+		rewritten, assigned, err := assignSelf(rewritten, resolveSelf)
+		if err != nil {
+			appendIssue(&report, contracts.PerIssueResult{Key: record.Key, Action: "push-error", Status: contracts.PerIssueStatusError, Messages: []contracts.IssueMessage{{Level: "error", ReasonCode: reasonFromPushError(err), Text: strings.TrimSpace(err.Error())}}})
+			continue
+		}
+		if referencesRewritten || assigned {
+			if !options.DryRun {
+				rendered, renderErr := workspaceStore.RenderDocument(rewritten.Document)
+				if renderErr == nil {
+					renderErr = workspaceStore.WriteFile(rewritten.RelativePath, []byte(rendered))
+				}
+				if renderErr != nil {
+					appendIssue(&report, contracts.PerIssueResult{Key: record.Key, Action: "push-error", Status: contracts.PerIssueStatusError, Messages: []contracts.IssueMessage{{Level: "error", ReasonCode: contracts.ReasonCodeValidationFailed, Text: "failed to rewrite local issue file: " + strings.TrimSpace(renderErr.Error())}}})
+					continue
+				}
+			}
+			record = rewritten
+			if referencesRewritten {
+				renameMessages = append(renameMessages, contracts.IssueMessage{Level: "info", Text: "rewrote references to drafts published in this run"})
+			}
+			if assigned {
+				renameMessages = append(renameMessages, contracts.IssueMessage{Level: "info", Text: "resolved assignee \"me\" to " + record.Document.FrontMatter.Assignee})
+			}
+		}
+		if !options.DryRun {
+			renamedPath, renamed, renameErr := workspaceStore.ReconcileFilename(record.RelativePath, record.Key, record.Document.FrontMatter.Summary)
+			if renameErr != nil {
+				renameMessages = append(renameMessages, buildTypedDiagnostic("warning", contracts.ReasonCodeValidationFailed, "rename_failed", renameErr.Error(), record.RelativePath))
+			} else if renamed {
+				renameMessages = append(renameMessages, contracts.IssueMessage{Level: "info", Text: "renamed " + record.RelativePath + " to " + renamedPath})
+				record.RelativePath = renamedPath
+			}
+		}
+
+		planStarted := time.Now()
+		comparison := compareRecordAgainstSnapshot(issuesRoot, record, settings.Profile.PreserveLabelCase)
+		timings.Since("plan", planStarted)
 		if comparison.Action == "unchanged" {
 			continue
 		}
@@ -139,7 +266,9 @@ func RunPush(ctx context.Context, workDir string, options PushOptions) (output.R
 			continue
 		}
 
-		originalDoc, err := readOriginalSnapshot(workDir, record.Key)
+		planStarted = time.Now()
+		originalDoc, err := readOriginalSnapshot(issuesRoot, record.Key, settings.Profile.PreserveLabelCase)
+		timings.Since("plan", planStarted)
 		if err != nil {
 			appendIssue(&report, contracts.PerIssueResult{
 				Key:    record.Key,
@@ -154,7 +283,12 @@ func RunPush(ctx context.Context, workDir string, options PushOptions) (output.R
 			continue
 		}
 
-		remoteIssue, err := adapter.GetIssue(ctx, record.Key, pushRemoteFields)
+		remoteIssue, found := prefetched[record.Key]
+		if !found {
+			fetchStarted := time.Now()
+			remoteIssue, err = adapter.GetIssue(ctx, record.Key, pushRemoteFields)
+			timings.Since("fetch", fetchStarted)
+		}
 		if err != nil {
 			appendIssue(&report, contracts.PerIssueResult{
 				Key:    record.Key,
@@ -184,30 +318,153 @@ func RunPush(ctx context.Context, workDir string, options PushOptions) (output.R
 			continue
 		}
 
+		applyStarted := time.Now()
 		outcome := pushexecute.ExecuteIssue(ctx, pushexecute.Options{
-			Adapter:             adapter,
-			Converter:           pushConverter,
-			DryRun:              options.DryRun,
-			TransitionSelection: settings.ResolveTransitionSelection(record.Document.FrontMatter.Status),
+			Adapter:               adapter,
+			Converter:             pushConverter,
+			DryRun:                options.DryRun,
+			TransitionSelection:   settings.ResolveTransitionSelection(record.Document.FrontMatter.Status),
+			Exclude:               excluded,
+			ReadonlyFields:        readonlyFields,
+			DescriptionRiskPolicy: contracts.ResolveDescriptionRiskPolicy(settings.Profile),
+			ProjectKey:            settings.Profile.ProjectKey,
+			AssigneeIsAccountID:   settings.Profile.AssigneeIsAccountID,
+			PreserveLabelCase:     settings.Profile.PreserveLabelCase,
+			TransitionCache:       transitionCache,
 		}, pushexecute.Input{Key: record.Key, Local: record.Document, Original: originalDoc, Remote: remoteDoc})
 
+		timings.Since("apply", applyStarted)
+
+		if len(renameMessages) > 0 {
+			outcome.Result.Messages = append(renameMessages, outcome.Result.Messages...)
+		}
 		appendIssue(&report, outcome.Result)
 		if !options.DryRun && outcome.FullyApplied {
-			canonicalLocal, renderErr := issue.RenderDocument(record.Document)
+			canonicalLocal, renderErr := workspaceStore.RenderDocument(record.Document)
 			if renderErr != nil {
 				appendIssue(&report, contracts.PerIssueResult{Key: record.Key, Action: "snapshot-error", Status: contracts.PerIssueStatusError, Messages: []contracts.IssueMessage{{Level: "error", ReasonCode: contracts.ReasonCodeValidationFailed, Text: "failed to render local snapshot: " + strings.TrimSpace(renderErr.Error())}}})
 				continue
 			}
-			if _, writeErr := workspaceStore.WriteOriginalSnapshot(record.Key, canonicalLocal); writeErr != nil {
+			applyStarted = time.Now()
+			_, writeErr := workspaceStore.WriteOriginalSnapshot(record.Key, canonicalLocal)
+			timings.Since("apply", applyStarted)
+			if writeErr != nil {
 				appendIssue(&report, contracts.PerIssueResult{Key: record.Key, Action: "snapshot-error", Status: contracts.PerIssueStatusError, Messages: []contracts.IssueMessage{{Level: "error", ReasonCode: contracts.ReasonCodeValidationFailed, Text: "failed to update original snapshot: " + strings.TrimSpace(writeErr.Error())}}})
 				continue
 			}
 		}
 	}
 
+	report.Timings = timings.Timings()
+	if exceedsMaxErrors(report, options.MaxErrors) {
+		return report, &MaxErrorsExceededError{Limit: options.MaxErrors, Errors: report.Counts.Errors}
+	}
 	return report, nil
 }
 
+// orderForPush moves drafts ahead of existing issues so their Jira keys are
+// known before any issue that references them is pushed. A draft that
+// references another draft follows it; reference cycles keep load order.
+func orderForPush(records []issueRecord) []issueRecord {
+	drafts := make(map[string]issueRecord)
+	draftOrder := make([]string, 0)
+	rest := make([]issueRecord, 0, len(records))
+	for _, record := range records {
+		if record.Err == nil && contracts.IsLocalDraftKey(record.Key) {
+			if _, duplicate := drafts[record.Key]; !duplicate {
+				drafts[record.Key] = record
+				draftOrder = append(draftOrder, record.Key)
+				continue
+			}
+		}
+		rest = append(rest, record)
+	}
+
+	ordered := make([]issueRecord, 0, len(records))
+	visited := make(map[string]bool, len(drafts))
+	var visit func(key string)
+	visit = func(key string) {
+		if _, seen := visited[key]; seen {
+			return
+		}
+		visited[key] = false
+		for _, referenced := range draftReferences(drafts[key].Document.MarkdownBody) {
+			if _, ok := drafts[referenced]; ok && referenced != key {
+				visit(referenced)
+			}
+		}
+		visited[key] = true
+		ordered = append(ordered, drafts[key])
+	}
+	for _, key := range draftOrder {
+		visit(key)
+	}
+	return append(ordered, rest...)
+}
+
+func draftReferences(markdown string) []string {
+	keys := make([]string, 0)
+	for _, match := range contracts.TempIDBodyReferencePattern.FindAllStringSubmatch(markdown, -1) {
+		if contracts.IsLocalDraftKey(match[1]) {
+			keys = append(keys, match[1])
+		}
+	}
+	return keys
+}
+
+// rewritePublishedReferences replaces #L-<hex> references to drafts that
+// were published earlier in this run and reports whether the body changed.
+func rewritePublishedReferences(record issueRecord, publishedKeys map[string]string) (issueRecord, bool) {
+	if len(publishedKeys) == 0 {
+		return record, false
+	}
+	body := contracts.RewriteTempIDReferences(record.Document.MarkdownBody, publishedKeys)
+	if body == record.Document.MarkdownBody {
+		return record, false
+	}
+	record.Document.MarkdownBody = body
+	return withCanonical(record), true
+}
+
+// selfAccountResolver looks up the authenticated account at most once per
+// run, the first time an issue uses the "me" assignee shorthand.
+func selfAccountResolver(ctx context.Context, adapter jira.Adapter) func() (string, error) {
+	resolved := false
+	accountID := ""
+	var resolveErr error
+	return func() (string, error) {
+		if !resolved {
+			resolved = true
+			account, err := adapter.GetMyself(ctx)
+			accountID, resolveErr = account.AccountID, err
+		}
+		return accountID, resolveErr
+	}
+}
+
+// assignSelf replaces the "me" assignee shorthand with the authenticated
+// account ID and reports whether it did.
+func assignSelf(record issueRecord, resolveSelf func() (string, error)) (issueRecord, bool, error) {
+	if !contracts.IsAssigneeSelf(record.Document.FrontMatter.Assignee) {
+		return record, false, nil
+	}
+	accountID, err := resolveSelf()
+	if err != nil {
+		return record, false, fmt.Errorf("failed to resolve assignee %q to the authenticated account: %w", contracts.AssigneeSelf, err)
+	}
+	record.Document.FrontMatter.Assignee = accountID
+	return withCanonical(record), true, nil
+}
+
+// withCanonical re-renders a record after an in-memory edit so snapshot
+// comparison sees the edited document.
+func withCanonical(record issueRecord) issueRecord {
+	if canonical, err := issue.RenderDocument(record.Document); err == nil {
+		record.Canonical = canonical
+	}
+	return record
+}
+
 func appendIssue(report *output.Report, result contracts.PerIssueResult) {
 	report.Issues = append(report.Issues, result)
 	report.Counts.Processed++
@@ -229,33 +486,51 @@ func appendIssue(report *output.Report, result contracts.PerIssueResult) {
 	}
 }
 
-func readOriginalSnapshot(workDir string, key string) (issue.Document, error) {
+func readOriginalSnapshot(issuesRoot string, key string, preserveLabelCase bool) (issue.Document, error) {
 	snapshotRelativePath := filepath.Join(".sync", "originals", key+".md")
-	content, err := os.ReadFile(filepath.Join(workDir, contracts.DefaultIssuesRootDir, snapshotRelativePath))
+	content, err := os.ReadFile(filepath.Join(issuesRoot, snapshotRelativePath))
 	if err != nil {
 		return issue.Document{}, err
 	}
-	doc, err := issue.ParseDocument(snapshotRelativePath, string(content))
+	doc, err := parseIssueDocument(snapshotRelativePath, string(content), preserveLabelCase)
 	if err != nil {
 		return issue.Document{}, err
 	}
 	return doc, nil
 }
 
-func mapRemoteIssueToDocument(remote jira.Issue, syncedAt time.Time, markdownConverter converter.Adapter) (issue.Document, error) {
-	rawADF := strings.TrimSpace(string(remote.Fields.Description))
-	markdown, err := markdownConverter.ToMarkdown(rawADF)
+// cachedFieldNames reads field names from .sync/fields.json for error
+// messages. A missing or unreadable cache just means raw field IDs.
+func cachedFieldNames(issuesRoot string, cfg contracts.Config, logger logging.Logger) map[string]string {
+	workspaceStore, err := openIssueStore(issuesRoot, cfg)
 	if err != nil {
-		return issue.Document{}, err
+		return nil
+	}
+	cache, err := workspaceStore.LoadFieldsCache()
+	if err != nil {
+		logging.Debugf(logger, "ignoring unreadable fields cache: %v", err)
+		return nil
 	}
 
-	canonicalADF := ""
-	if rawADF != "" {
-		canonicalADF, err = converter.ValidateAndCanonicalizeRawADF(rawADF)
-		if err != nil {
-			return issue.Document{}, err
+	names := make(map[string]string, len(cache.Fields))
+	for _, field := range cache.Fields {
+		id, name := strings.TrimSpace(field.ID), strings.TrimSpace(field.Name)
+		if id != "" && name != "" {
+			names[id] = name
 		}
 	}
+	return names
+}
+
+func mapRemoteIssueToDocument(remote jira.Issue, syncedAt time.Time, markdownConverter converter.Adapter) (issue.Document, error) {
+	markdown, canonicalADF, err := remoteADFToMarkdown(markdownConverter, remote.Fields.Description)
+	if err != nil {
+		return issue.Document{}, err
+	}
+	environmentMarkdown, environmentADF, err := remoteADFToMarkdown(markdownConverter, remote.Fields.Environment)
+	if err != nil {
+		return issue.Document{}, err
+	}
 
 	return issue.Document{
 		CanonicalKey: strings.TrimSpace(remote.Key),
@@ -269,15 +544,34 @@ func mapRemoteIssueToDocument(remote jira.Issue, syncedAt time.Time, markdownCon
 			Assignee:      accountRefValue(remote.Fields.Assignee),
 			Labels:        append([]string(nil), remote.Fields.Labels...),
 			Reporter:      accountRefValue(remote.Fields.Reporter),
+			SecurityLevel: namedRefValue(remote.Fields.Security),
 			CreatedAt:     strings.TrimSpace(remote.Fields.CreatedAt),
 			UpdatedAt:     strings.TrimSpace(remote.Fields.UpdatedAt),
 			SyncedAt:      syncedAt.Format(time.RFC3339Nano),
 		},
-		MarkdownBody: markdown.Markdown,
-		RawADFJSON:   canonicalADF,
+		MarkdownBody:        markdown,
+		RawADFJSON:          canonicalADF,
+		EnvironmentMarkdown: environmentMarkdown,
+		EnvironmentADFJSON:  environmentADF,
 	}, nil
 }
 
+func remoteADFToMarkdown(markdownConverter converter.Adapter, raw json.RawMessage) (string, string, error) {
+	rawADF := strings.TrimSpace(string(raw))
+	result, err := markdownConverter.ToMarkdown(rawADF)
+	if err != nil {
+		return "", "", err
+	}
+	if rawADF == "" {
+		return result.Markdown, "", nil
+	}
+	canonicalADF, err := converter.ValidateAndCanonicalizeRawADF(rawADF)
+	if err != nil {
+		return "", "", err
+	}
+	return result.Markdown, canonicalADF, nil
+}
+
 func reasonFromPushError(err error) contracts.ReasonCode {
 	if typed := asJiraError(err); typed != nil && typed.ReasonCode != "" {
 		return typed.ReasonCode
@@ -322,3 +616,28 @@ func accountRefValue(ref *jira.AccountRef) string {
 	}
 	return strings.TrimSpace(ref.AccountID)
 }
+
+func filterRecordsChangedSince(ctx context.Context, issuesRoot string, records []issueRecord, options PushOptions) ([]issueRecord, error) {
+	listChanged := options.ListChangedFiles
+	if listChanged == nil {
+		listChanged = gitdiff.ChangedFiles
+	}
+
+	changedPaths, err := listChanged(ctx, issuesRoot, options.ChangedSince)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list issues changed since %q: %w", strings.TrimSpace(options.ChangedSince), err)
+	}
+
+	changed := make(map[string]struct{}, len(changedPaths))
+	for _, changedPath := range changedPaths {
+		changed[filepath.ToSlash(filepath.Clean(changedPath))] = struct{}{}
+	}
+
+	filtered := make([]issueRecord, 0, len(records))
+	for _, record := range records {
+		if _, ok := changed[filepath.ToSlash(record.RelativePath)]; ok {
+			filtered = append(filtered, record)
+		}
+	}
+	return filtered, nil
+}
@@ -0,0 +1,98 @@
+package commands
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
+	"github.com/pweiskircher/jira-issue-sync/internal/jira"
+	"github.com/pweiskircher/jira-issue-sync/internal/jql"
+	"github.com/pweiskircher/jira-issue-sync/internal/logging"
+)
+
+// pushPrefetchKeys lists the existing issues push is about to fetch: parsed,
+// non-draft records whose content differs from their snapshot. Records that
+// later turn out to need a fetch anyway simply fall back to GetIssue.
+func pushPrefetchKeys(issuesRoot string, records []issueRecord, preserveLabelCase bool) []string {
+	keys := make([]string, 0, len(records))
+	seen := make(map[string]struct{}, len(records))
+	for _, record := range records {
+		if record.Err != nil || contracts.IsLocalDraftKey(record.Key) {
+			continue
+		}
+		if _, duplicate := seen[record.Key]; duplicate {
+			continue
+		}
+		comparison := compareRecordAgainstSnapshot(issuesRoot, record, preserveLabelCase)
+		if comparison.Action == "unchanged" || comparison.Status == contracts.PerIssueStatusConflict || comparison.Status == contracts.PerIssueStatusError {
+			continue
+		}
+		seen[record.Key] = struct{}{}
+		keys = append(keys, record.Key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// prefetchRemoteIssues hydrates keys with one `key in (...)` search per page
+// instead of a GetIssue round trip each. Any search failure abandons the
+// batch and returns nil so every issue is fetched individually as before.
+func prefetchRemoteIssues(ctx context.Context, adapter jira.Adapter, keys []string, pageSize int, logger logging.Logger) map[string]jira.Issue {
+	if len(keys) < 2 {
+		return nil
+	}
+	if pageSize <= 0 {
+		pageSize = contracts.DefaultPullPageSize
+	}
+
+	fetched := make(map[string]jira.Issue, len(keys))
+	for start := 0; start < len(keys); start += pageSize {
+		end := min(start+pageSize, len(keys))
+		if err := searchIssueKeys(ctx, adapter, keys[start:end], fetched); err != nil {
+			logging.Debugf(logger, "push batch fetch failed, fetching issues one at a time: %v", err)
+			return nil
+		}
+	}
+	return fetched
+}
+
+func searchIssueKeys(ctx context.Context, adapter jira.Adapter, keys []string, fetched map[string]jira.Issue) error {
+	wanted := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		wanted[key] = struct{}{}
+	}
+
+	request := jira.SearchIssuesRequest{
+		JQL:        jql.New().In("key", keys...).OrderBy("key", jql.Ascending).String(),
+		MaxResults: len(keys),
+		Fields:     append([]string(nil), pushRemoteFields...),
+	}
+	for {
+		response, err := adapter.SearchIssues(ctx, request)
+		if err != nil {
+			return err
+		}
+		for _, issue := range response.Issues {
+			key := strings.TrimSpace(issue.Key)
+			if _, ok := wanted[key]; ok {
+				fetched[key] = issue
+			}
+		}
+
+		// Jira may cap maxResults below the chunk size; keep paging until
+		// the response says the result set is exhausted.
+		if len(response.Issues) == 0 || response.IsLast {
+			return nil
+		}
+		if response.NextPageToken != "" {
+			request.NextPageToken = response.NextPageToken
+			continue
+		}
+		next := response.StartAt + len(response.Issues)
+		if response.Total <= 0 || next >= response.Total {
+			return nil
+		}
+		request.StartAt = next
+	}
+}
@@ -5,11 +5,14 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 	"testing"
 
 	"github.com/pweiskircher/jira-issue-sync/internal/config"
 	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
+	"github.com/pweiskircher/jira-issue-sync/internal/gitdiff"
 	"github.com/pweiskircher/jira-issue-sync/internal/issue"
 	"github.com/pweiskircher/jira-issue-sync/internal/jira"
 )
@@ -54,6 +57,65 @@ func TestRunPushDryRunDoesNotMutateRemoteOrLocalState(t *testing.T) {
 	}
 }
 
+func TestRunPullRepairSnapshotsUnblocksPushAfterSnapshotLoss(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	writePushConfig(t, workspace)
+	writePushIssue(t, workspace, "PROJ-1", "Local summary", "Remote summary", "To Do", "To Do")
+	snapshotPath := filepath.Join(workspace, ".issues", ".sync", "originals", "PROJ-1.md")
+	if err := os.Remove(snapshotPath); err != nil {
+		t.Fatalf("remove snapshot failed: %v", err)
+	}
+	adapter := &pushAdapterStub{issues: map[string]jira.Issue{"PROJ-1": testRemoteIssue("PROJ-1", "Remote summary", "To Do")}}
+	environment := config.Environment{JiraAPIToken: "token"}
+
+	blocked, err := RunPush(context.Background(), workspace, PushOptions{Adapter: adapter, Environment: environment})
+	if err != nil {
+		t.Fatalf("push failed: %v", err)
+	}
+	if blocked.Counts.Conflicts != 1 || blocked.Issues[0].Messages[0].ReasonCode != contracts.ReasonCodeConflictBaseSnapshotMissing {
+		t.Fatalf("expected missing snapshot to block push, got %#v", blocked.Issues)
+	}
+	localPaths, err := filepath.Glob(filepath.Join(workspace, ".issues", "open", "PROJ-1*.md"))
+	if err != nil || len(localPaths) != 1 {
+		t.Fatalf("expected one local file, got %v (%v)", localPaths, err)
+	}
+	localBefore, err := os.ReadFile(localPaths[0])
+	if err != nil {
+		t.Fatalf("read local file failed: %v", err)
+	}
+
+	repaired, err := RunPull(context.Background(), workspace, PullOptions{Adapter: adapter, Environment: environment, RepairSnapshots: []string{"proj-1"}})
+	if err != nil {
+		t.Fatalf("repair failed: %v", err)
+	}
+	if repaired.Counts.Updated != 1 || repaired.Issues[0].Action != "repair-snapshot" {
+		t.Fatalf("expected snapshot repair, got %#v", repaired.Issues)
+	}
+	if _, err := os.Stat(snapshotPath); err != nil {
+		t.Fatalf("expected repaired snapshot: %v", err)
+	}
+	localAfter, err := os.ReadFile(localPaths[0])
+	if err != nil {
+		t.Fatalf("read local file failed: %v", err)
+	}
+	if string(localAfter) != string(localBefore) {
+		t.Fatalf("expected working file to stay untouched")
+	}
+
+	pushed, err := RunPush(context.Background(), workspace, PushOptions{Adapter: adapter, Environment: environment})
+	if err != nil {
+		t.Fatalf("push after repair failed: %v", err)
+	}
+	if pushed.Counts.Errors != 0 || pushed.Counts.Conflicts != 0 || adapter.updateCalls != 1 {
+		t.Fatalf("expected push to apply after repair, got %#v (updates=%d)", pushed.Issues, adapter.updateCalls)
+	}
+	if summary := adapter.updateRequests[0].Summary; summary == nil || *summary != "Local summary" {
+		t.Fatalf("expected local summary to be pushed, got %#v", adapter.updateRequests[0])
+	}
+}
+
 func TestRunPushContinuesAfterPerIssueFailures(t *testing.T) {
 	t.Parallel()
 
@@ -84,6 +146,369 @@ func TestRunPushContinuesAfterPerIssueFailures(t *testing.T) {
 	}
 }
 
+func TestRunPushHydratesRemoteIssuesWithOneBatchSearch(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	writePushConfig(t, workspace)
+
+	writePushIssue(t, workspace, "PROJ-1", "Local one", "Remote one", "To Do", "To Do")
+	writePushIssue(t, workspace, "PROJ-2", "Local two", "Remote two", "To Do", "To Do")
+	writePushIssue(t, workspace, "PROJ-3", "Remote three", "Remote three", "To Do", "To Do")
+
+	adapter := &pushAdapterStub{
+		issues: map[string]jira.Issue{
+			"PROJ-1": testRemoteIssue("PROJ-1", "Remote one", "To Do"),
+			"PROJ-2": testRemoteIssue("PROJ-2", "Remote two", "To Do"),
+			"PROJ-3": testRemoteIssue("PROJ-3", "Remote three", "To Do"),
+		},
+	}
+
+	report, runErr := RunPush(context.Background(), workspace, PushOptions{Adapter: adapter, Environment: config.Environment{JiraAPIToken: "token"}})
+	if runErr != nil {
+		t.Fatalf("run push failed: %v", runErr)
+	}
+	if report.Counts.Updated != 2 || report.Counts.Errors != 0 {
+		t.Fatalf("unexpected push counts: %#v", report.Counts)
+	}
+
+	if len(adapter.searchRequests) != 1 {
+		t.Fatalf("expected a single batch search, got %d", len(adapter.searchRequests))
+	}
+	request := adapter.searchRequests[0]
+	if request.JQL != `key in ("PROJ-1", "PROJ-2") ORDER BY key ASC` {
+		t.Fatalf("unexpected batch jql %q", request.JQL)
+	}
+	if !slices.Equal(request.Fields, pushRemoteFields) {
+		t.Fatalf("batch search must request push fields, got %v", request.Fields)
+	}
+	if adapter.getIssueCalls != 0 {
+		t.Fatalf("expected batch search to replace per-issue fetches, got %d GetIssue calls", adapter.getIssueCalls)
+	}
+}
+
+func TestRunPushFallsBackToGetIssueWhenBatchSearchFails(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	writePushConfig(t, workspace)
+
+	writePushIssue(t, workspace, "PROJ-1", "Local one", "Remote one", "To Do", "To Do")
+	writePushIssue(t, workspace, "PROJ-2", "Local two", "Remote two", "To Do", "To Do")
+
+	adapter := &pushAdapterStub{
+		issues: map[string]jira.Issue{
+			"PROJ-1": testRemoteIssue("PROJ-1", "Remote one", "To Do"),
+			"PROJ-2": testRemoteIssue("PROJ-2", "Remote two", "To Do"),
+		},
+		searchErr: errors.New("search unavailable"),
+	}
+
+	report, runErr := RunPush(context.Background(), workspace, PushOptions{Adapter: adapter, Environment: config.Environment{JiraAPIToken: "token"}})
+	if runErr != nil {
+		t.Fatalf("run push failed: %v", runErr)
+	}
+	if report.Counts.Updated != 2 || report.Counts.Errors != 0 {
+		t.Fatalf("unexpected push counts: %#v", report.Counts)
+	}
+	if len(adapter.searchRequests) != 1 || adapter.getIssueCalls != 2 {
+		t.Fatalf("expected failed batch search then two GetIssue calls, got searches=%d gets=%d", len(adapter.searchRequests), adapter.getIssueCalls)
+	}
+}
+
+func TestRunPushStopsAfterMaxErrorsExceeded(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	writePushConfig(t, workspace)
+
+	adapter := &pushAdapterStub{issues: map[string]jira.Issue{}, updateErrByKey: map[string]error{}}
+	for _, key := range []string{"PROJ-1", "PROJ-2", "PROJ-3"} {
+		writePushIssue(t, workspace, key, "Local "+key, "Remote "+key, "To Do", "To Do")
+		adapter.issues[key] = testRemoteIssue(key, "Remote "+key, "To Do")
+		adapter.updateErrByKey[key] = errors.New("boom")
+	}
+
+	report, runErr := RunPush(context.Background(), workspace, PushOptions{Adapter: adapter, Environment: config.Environment{JiraAPIToken: "token"}, MaxErrors: 1})
+	var exceeded *MaxErrorsExceededError
+	if !errors.As(runErr, &exceeded) || exceeded.Limit != 1 || exceeded.Errors != 2 {
+		t.Fatalf("expected max-errors stop, got %v", runErr)
+	}
+	if adapter.updateCalls != 2 {
+		t.Fatalf("expected push to stop after the second failure, got %d update attempts", adapter.updateCalls)
+	}
+	if report.Counts.Errors != 2 || len(report.Issues) != 2 || report.Issues[1].Key != "PROJ-2" {
+		t.Fatalf("expected partial report for PROJ-1 and PROJ-2, got %#v", report.Issues)
+	}
+}
+
+func TestRunPushChangedSinceOnlyProcessesFilesReportedByGit(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	writePushConfig(t, workspace)
+
+	writePushIssue(t, workspace, "PROJ-1", "Local one", "Remote one", "To Do", "To Do")
+	writePushIssue(t, workspace, "PROJ-2", "Local two", "Remote two", "To Do", "To Do")
+
+	adapter := &pushAdapterStub{
+		issues: map[string]jira.Issue{
+			"PROJ-1": testRemoteIssue("PROJ-1", "Remote one", "To Do"),
+			"PROJ-2": testRemoteIssue("PROJ-2", "Remote two", "To Do"),
+		},
+	}
+
+	var gotDir, gotRef string
+	listChanged := func(_ context.Context, dir string, ref string) ([]string, error) {
+		gotDir, gotRef = dir, ref
+		return []string{"open/PROJ-2-local.md", "README.md"}, nil
+	}
+
+	report, runErr := RunPush(context.Background(), workspace, PushOptions{
+		Adapter:          adapter,
+		Environment:      config.Environment{JiraAPIToken: "token"},
+		ChangedSince:     "origin/main",
+		ListChangedFiles: listChanged,
+	})
+	if runErr != nil {
+		t.Fatalf("run push failed: %v", runErr)
+	}
+
+	if gotRef != "origin/main" || gotDir != filepath.Join(workspace, ".issues") {
+		t.Fatalf("unexpected git scope: dir=%q ref=%q", gotDir, gotRef)
+	}
+	if report.Counts.Processed != 1 || report.Counts.Updated != 1 {
+		t.Fatalf("unexpected push counts: %#v", report.Counts)
+	}
+	if len(report.Issues) != 1 || report.Issues[0].Key != "PROJ-2" {
+		t.Fatalf("expected only PROJ-2 to be pushed, got %#v", report.Issues)
+	}
+}
+
+func TestRunPushChangedSinceSurfacesGitErrors(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	writePushConfig(t, workspace)
+
+	listChanged := func(context.Context, string, string) ([]string, error) {
+		return nil, gitdiff.ErrNotRepository
+	}
+
+	_, runErr := RunPush(context.Background(), workspace, PushOptions{
+		Adapter:          &pushAdapterStub{},
+		Environment:      config.Environment{JiraAPIToken: "token"},
+		ChangedSince:     "HEAD~1",
+		ListChangedFiles: listChanged,
+	})
+	if !errors.Is(runErr, gitdiff.ErrNotRepository) {
+		t.Fatalf("expected not-a-repository error, got %v", runErr)
+	}
+}
+
+func TestRunPushExcludeDescriptionSendsRemainingFields(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	writePushConfig(t, workspace)
+
+	local := mustRenderDoc(t, issue.Document{FrontMatter: issue.FrontMatter{SchemaVersion: contracts.IssueFileSchemaVersionV1, Key: "PROJ-1", Summary: "Local summary", IssueType: "Task", Status: "To Do", Labels: []string{"backend"}}, CanonicalKey: "PROJ-1", MarkdownBody: "rewritten body"})
+	original := mustRenderDoc(t, issue.Document{FrontMatter: issue.FrontMatter{SchemaVersion: contracts.IssueFileSchemaVersionV1, Key: "PROJ-1", Summary: "Remote summary", IssueType: "Task", Status: "To Do"}, CanonicalKey: "PROJ-1", MarkdownBody: "body"})
+	writeIssueFile(t, workspace, filepath.Join("open", "PROJ-1-local.md"), local)
+	writeIssueFile(t, workspace, filepath.Join(".sync", "originals", "PROJ-1.md"), original)
+
+	remote := testRemoteIssue("PROJ-1", "Remote summary", "To Do")
+	remote.Fields.Description = []byte(`{"version":1,"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"body"}]}]}`)
+	adapter := &pushAdapterStub{issues: map[string]jira.Issue{"PROJ-1": remote}}
+
+	report, runErr := RunPush(context.Background(), workspace, PushOptions{Adapter: adapter, Environment: config.Environment{JiraAPIToken: "token"}, Exclude: []string{"description"}})
+	if runErr != nil {
+		t.Fatalf("run push failed: %v", runErr)
+	}
+	if report.Counts.Updated != 1 || report.Counts.Warnings != 0 || report.Counts.Conflicts != 0 {
+		t.Fatalf("unexpected push counts: %#v", report.Counts)
+	}
+	if len(adapter.updateRequests) != 1 {
+		t.Fatalf("expected one update request, got %d", len(adapter.updateRequests))
+	}
+	request := adapter.updateRequests[0]
+	if request.Description != nil {
+		t.Fatalf("expected excluded description to stay out of the request, got %s", *request.Description)
+	}
+	if request.Summary == nil || *request.Summary != "Local summary" {
+		t.Fatalf("expected summary update, got %#v", request.Summary)
+	}
+	if request.Labels == nil || len(*request.Labels) != 1 || (*request.Labels)[0] != "backend" {
+		t.Fatalf("expected labels update, got %#v", request.Labels)
+	}
+
+	snapshot, err := os.ReadFile(filepath.Join(workspace, contracts.DefaultIssuesRootDir, ".sync", "originals", "PROJ-1.md"))
+	if err != nil {
+		t.Fatalf("read snapshot failed: %v", err)
+	}
+	if string(snapshot) != original {
+		t.Fatalf("expected snapshot to stay put while description is withheld")
+	}
+}
+
+func TestRunPushNoTransitionLeavesStatusAlone(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	writePushConfig(t, workspace)
+	writePushIssue(t, workspace, "PROJ-1", "Fixed summary", "Remote summary", "Done", "To Do")
+
+	adapter := &pushAdapterStub{
+		issues:          map[string]jira.Issue{"PROJ-1": testRemoteIssue("PROJ-1", "Remote summary", "To Do")},
+		transitionByKey: map[string]jira.TransitionResolution{"PROJ-1": {Kind: jira.TransitionResolutionSelected, Transition: jira.Transition{ID: "31", ToStatusName: "Done"}}},
+	}
+
+	report, runErr := RunPush(context.Background(), workspace, PushOptions{Adapter: adapter, Environment: config.Environment{JiraAPIToken: "token"}, NoTransition: true})
+	if runErr != nil {
+		t.Fatalf("run push failed: %v", runErr)
+	}
+	if report.Counts.Updated != 1 || report.Counts.Warnings != 0 {
+		t.Fatalf("unexpected push counts: %#v", report.Counts)
+	}
+	if adapter.resolveCalls != 0 || adapter.applyCalls != 0 {
+		t.Fatalf("expected no transition calls, got resolve=%d apply=%d", adapter.resolveCalls, adapter.applyCalls)
+	}
+	if len(adapter.updateRequests) != 1 || adapter.updateRequests[0].Summary == nil || *adapter.updateRequests[0].Summary != "Fixed summary" {
+		t.Fatalf("expected the summary fix to be pushed, got %#v", adapter.updateRequests)
+	}
+
+	snapshot, err := os.ReadFile(filepath.Join(workspace, contracts.DefaultIssuesRootDir, ".sync", "originals", "PROJ-1.md"))
+	if err != nil {
+		t.Fatalf("read snapshot failed: %v", err)
+	}
+	if !strings.Contains(string(snapshot), `status: "To Do"`) {
+		t.Fatalf("expected snapshot to keep the old status so a later push transitions, got:\n%s", snapshot)
+	}
+}
+
+func TestRunPushSendsEditedEnvironmentAsADF(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	writePushConfig(t, workspace)
+
+	environmentADF := `{"version":1,"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"Chrome 120"}]}]}`
+	base := issue.Document{FrontMatter: issue.FrontMatter{SchemaVersion: contracts.IssueFileSchemaVersionV1, Key: "PROJ-1", Summary: "Remote summary", IssueType: "Bug", Status: "To Do"}, CanonicalKey: "PROJ-1", MarkdownBody: "body", EnvironmentMarkdown: "Chrome 120", EnvironmentADFJSON: environmentADF}
+	local := base
+	local.EnvironmentMarkdown = "Chrome 121"
+	writeIssueFile(t, workspace, filepath.Join("open", "PROJ-1-local.md"), mustRenderDoc(t, local))
+	writeIssueFile(t, workspace, filepath.Join(".sync", "originals", "PROJ-1.md"), mustRenderDoc(t, base))
+
+	remote := testRemoteIssue("PROJ-1", "Remote summary", "To Do")
+	remote.Fields.IssueType = &jira.NamedRef{Name: "Bug"}
+	remote.Fields.Description = []byte(`{"version":1,"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"body"}]}]}`)
+	remote.Fields.Environment = []byte(environmentADF)
+	adapter := &pushAdapterStub{issues: map[string]jira.Issue{"PROJ-1": remote}}
+
+	report, runErr := RunPush(context.Background(), workspace, PushOptions{Adapter: adapter, Environment: config.Environment{JiraAPIToken: "token"}})
+	if runErr != nil {
+		t.Fatalf("run push failed: %v", runErr)
+	}
+	if report.Counts.Updated != 1 || len(adapter.updateRequests) != 1 {
+		t.Fatalf("expected one update, got counts=%#v issues=%#v", report.Counts, report.Issues)
+	}
+	request := adapter.updateRequests[0]
+	if request.Environment == nil || !strings.Contains(string(*request.Environment), "Chrome 121") {
+		t.Fatalf("expected environment ADF payload, got %#v", request.Environment)
+	}
+	if request.Description != nil || request.Summary != nil {
+		t.Fatalf("expected only environment to be sent, got %#v", request)
+	}
+}
+
+func TestRunPushWarnsThatIssueTypeChangeIsNotSynced(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	writePushConfig(t, workspace)
+
+	base := issue.Document{FrontMatter: issue.FrontMatter{SchemaVersion: contracts.IssueFileSchemaVersionV1, Key: "PROJ-1", Summary: "Remote summary", IssueType: "Task", Status: "To Do"}, CanonicalKey: "PROJ-1", MarkdownBody: "body"}
+	local := base
+	local.FrontMatter.IssueType = "Bug"
+	writeIssueFile(t, workspace, filepath.Join("open", "PROJ-1-remote-summary.md"), mustRenderDoc(t, local))
+	writeIssueFile(t, workspace, filepath.Join(".sync", "originals", "PROJ-1.md"), mustRenderDoc(t, base))
+
+	remote := testRemoteIssue("PROJ-1", "Remote summary", "To Do")
+	remote.Fields.IssueType = &jira.NamedRef{Name: "Task"}
+	remote.Fields.Description = []byte(`{"version":1,"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"body"}]}]}`)
+	adapter := &pushAdapterStub{issues: map[string]jira.Issue{"PROJ-1": remote}}
+
+	report, runErr := RunPush(context.Background(), workspace, PushOptions{Adapter: adapter, Environment: config.Environment{JiraAPIToken: "token"}})
+	if runErr != nil {
+		t.Fatalf("run push failed: %v", runErr)
+	}
+	if len(adapter.updateRequests) != 0 {
+		t.Fatalf("expected no update for a read-only change, got %#v", adapter.updateRequests)
+	}
+	if len(report.Issues) != 1 || len(report.Issues[0].Messages) != 1 {
+		t.Fatalf("expected one message for PROJ-1, got %#v", report.Issues)
+	}
+	message := report.Issues[0].Messages[0]
+	if message.Level != "warning" || message.ReasonCode != contracts.ReasonCodeUnsupportedFieldIgnored || !strings.Contains(message.Text, "issue_type") {
+		t.Fatalf("expected issue_type warning, got %#v", message)
+	}
+}
+
+func TestRunPushRejectsUnknownExcludeField(t *testing.T) {
+	t.Parallel()
+
+	_, runErr := RunPush(context.Background(), t.TempDir(), PushOptions{Exclude: []string{"summary,comments"}})
+	var resolveErr *config.ResolveError
+	if !errors.As(runErr, &resolveErr) || resolveErr.Code != config.ResolveErrorCodeInvalidFlag {
+		t.Fatalf("expected invalid flag error, got %v", runErr)
+	}
+}
+
+func TestRunPushRenamesEditedSummaryToCanonicalFilename(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	writePushConfig(t, workspace)
+
+	local := mustRenderDoc(t, issue.Document{FrontMatter: issue.FrontMatter{SchemaVersion: contracts.IssueFileSchemaVersionV1, Key: "PROJ-1", Summary: "New summary", IssueType: "Task", Status: "Done"}, CanonicalKey: "PROJ-1", MarkdownBody: "body"})
+	original := mustRenderDoc(t, issue.Document{FrontMatter: issue.FrontMatter{SchemaVersion: contracts.IssueFileSchemaVersionV1, Key: "PROJ-1", Summary: "Old summary", IssueType: "Task", Status: "Done"}, CanonicalKey: "PROJ-1", MarkdownBody: "body"})
+	writeIssueFile(t, workspace, filepath.Join("closed", "PROJ-1-old-summary.md"), local)
+	writeIssueFile(t, workspace, filepath.Join(".sync", "originals", "PROJ-1.md"), original)
+	writeIssueFile(t, workspace, filepath.Join(".sync", "cache.json"), `{"version":"1","issues":{"PROJ-1":{"path":"closed/PROJ-1-old-summary.md","status":"closed"}}}`)
+
+	adapter := &pushAdapterStub{issues: map[string]jira.Issue{"PROJ-1": testRemoteIssue("PROJ-1", "Old summary", "Done")}}
+
+	report, runErr := RunPush(context.Background(), workspace, PushOptions{Adapter: adapter, Environment: config.Environment{JiraAPIToken: "token"}})
+	if runErr != nil {
+		t.Fatalf("run push failed: %v", runErr)
+	}
+	if report.Counts.Updated != 1 || report.Counts.Errors != 0 {
+		t.Fatalf("unexpected push counts: %#v", report.Counts)
+	}
+
+	issuesRoot := filepath.Join(workspace, contracts.DefaultIssuesRootDir)
+	if _, err := os.Stat(filepath.Join(issuesRoot, "closed", "PROJ-1-new-summary.md")); err != nil {
+		t.Fatalf("expected canonical filename in closed/: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(issuesRoot, "closed", "PROJ-1-old-summary.md")); !os.IsNotExist(err) {
+		t.Fatalf("expected old filename to be gone, err=%v", err)
+	}
+	if _, err := os.Stat(filepath.Join(issuesRoot, "open", "PROJ-1-new-summary.md")); !os.IsNotExist(err) {
+		t.Fatalf("rename must not move issues across state directories, err=%v", err)
+	}
+
+	cache, err := os.ReadFile(filepath.Join(issuesRoot, ".sync", "cache.json"))
+	if err != nil {
+		t.Fatalf("read cache failed: %v", err)
+	}
+	if !strings.Contains(string(cache), `"path": "closed/PROJ-1-new-summary.md"`) {
+		t.Fatalf("expected cache path to follow rename, got %s", string(cache))
+	}
+	if !strings.Contains(report.Issues[0].Messages[0].Text, "renamed") {
+		t.Fatalf("expected rename message, got %#v", report.Issues[0].Messages)
+	}
+}
+
 func TestRunPushSkipsAmbiguousTransitionAndStillAppliesSafeUpdates(t *testing.T) {
 	t.Parallel()
 
@@ -119,6 +544,41 @@ func TestRunPushSkipsAmbiguousTransitionAndStillAppliesSafeUpdates(t *testing.T)
 	}
 }
 
+func TestRunPushReusesTransitionResolutionForSameStatusIssues(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	writePushConfig(t, workspace)
+
+	selected := jira.TransitionResolution{Kind: jira.TransitionResolutionSelected, Transition: jira.Transition{ID: "31", ToStatusName: "Done"}}
+	adapter := &pushAdapterStub{
+		issues:            map[string]jira.Issue{},
+		transitionByKey:   map[string]jira.TransitionResolution{},
+		applyErrOnceByKey: map[string]error{"PROJ-3": errors.New("transition is not valid for this issue")},
+	}
+	for _, key := range []string{"PROJ-1", "PROJ-2", "PROJ-3", "PROJ-4"} {
+		writePushIssue(t, workspace, key, "Summary", "Summary", "Done", "To Do")
+		adapter.issues[key] = testRemoteIssue(key, "Summary", "To Do")
+		adapter.transitionByKey[key] = selected
+	}
+
+	report, runErr := RunPush(context.Background(), workspace, PushOptions{Adapter: adapter, Environment: config.Environment{JiraAPIToken: "token"}})
+	if runErr != nil {
+		t.Fatalf("run push failed: %v", runErr)
+	}
+	if report.Counts.Updated != 4 || report.Counts.Errors != 0 {
+		t.Fatalf("unexpected counts: %#v", report.Counts)
+	}
+	// PROJ-1 resolves; PROJ-2 reuses it; PROJ-3 fails to apply the cached
+	// transition and resolves again; PROJ-4 reuses the refreshed entry.
+	if adapter.resolveCalls != 2 {
+		t.Fatalf("expected 2 transition resolutions, got %d", adapter.resolveCalls)
+	}
+	if adapter.applyCalls != 5 {
+		t.Fatalf("expected 5 transition applications, got %d", adapter.applyCalls)
+	}
+}
+
 func TestRunPushPublishesLocalDraftAndRewritesScopedReferences(t *testing.T) {
 	t.Parallel()
 
@@ -182,6 +642,121 @@ func TestRunPushPublishesLocalDraftAndRewritesScopedReferences(t *testing.T) {
 	}
 }
 
+func TestRunPushPublishesDraftsBeforeUpdatingIssuesThatReferenceThem(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	writePushConfig(t, workspace)
+
+	// The referencing issue loads first (open/ before closed/), so only the
+	// ordering pass lets its update see the draft's Jira key.
+	original := mustRenderDoc(t, issue.Document{FrontMatter: issue.FrontMatter{SchemaVersion: contracts.IssueFileSchemaVersionV1, Key: "PROJ-1", Summary: "Remote summary", IssueType: "Task", Status: "To Do"}, CanonicalKey: "PROJ-1", MarkdownBody: "body"})
+	local := mustRenderDoc(t, issue.Document{FrontMatter: issue.FrontMatter{SchemaVersion: contracts.IssueFileSchemaVersionV1, Key: "PROJ-1", Summary: "Remote summary", IssueType: "Task", Status: "To Do"}, CanonicalKey: "PROJ-1", MarkdownBody: "Blocked by #L-abc123"})
+	draft := mustRenderDoc(t, issue.Document{FrontMatter: issue.FrontMatter{SchemaVersion: contracts.IssueFileSchemaVersionV1, Key: "L-abc123", Summary: "Dependency", IssueType: "Task", Status: "Done"}, CanonicalKey: "L-abc123", MarkdownBody: "new work"})
+	writeIssueFile(t, workspace, filepath.Join("open", "PROJ-1-remote-summary.md"), local)
+	writeIssueFile(t, workspace, filepath.Join(".sync", "originals", "PROJ-1.md"), original)
+	writeIssueFile(t, workspace, filepath.Join("closed", "L-abc123-dependency.md"), draft)
+
+	remote := testRemoteIssue("PROJ-1", "Remote summary", "To Do")
+	remote.Fields.Description = []byte(`{"version":1,"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"body"}]}]}`)
+	adapter := &pushAdapterStub{issues: map[string]jira.Issue{"PROJ-1": remote}, createdKeyBySummary: map[string]string{"Dependency": "PROJ-500"}}
+
+	report, runErr := RunPush(context.Background(), workspace, PushOptions{Adapter: adapter, Environment: config.Environment{JiraAPIToken: "token"}})
+	if runErr != nil {
+		t.Fatalf("run push failed: %v", runErr)
+	}
+	if report.Counts.Created != 1 || report.Counts.Updated != 1 || report.Counts.Errors != 0 {
+		t.Fatalf("unexpected counts: %#v issues=%#v", report.Counts, report.Issues)
+	}
+	if report.Issues[0].Key != "PROJ-500" || report.Issues[1].Key != "PROJ-1" {
+		t.Fatalf("expected draft publish before update, got %#v", report.Issues)
+	}
+	// The first update drops the draft marker label from PROJ-500.
+	if len(adapter.updateRequests) != 2 || adapter.updateRequests[0].Labels == nil || adapter.updateRequests[1].Description == nil {
+		t.Fatalf("expected a marker label cleanup then one description update, got %#v", adapter.updateRequests)
+	}
+	if description := string(*adapter.updateRequests[1].Description); !strings.Contains(description, "#PROJ-500") || strings.Contains(description, "L-abc123") {
+		t.Fatalf("expected rewritten reference in update, got %s", description)
+	}
+
+	content, err := os.ReadFile(filepath.Join(workspace, contracts.DefaultIssuesRootDir, "open", "PROJ-1-remote-summary.md"))
+	if err != nil {
+		t.Fatalf("read local issue failed: %v", err)
+	}
+	if !strings.Contains(string(content), "Blocked by #PROJ-500") {
+		t.Fatalf("expected local file to carry the published key, got %q", string(content))
+	}
+}
+
+func TestRunPushResolvesAssigneeMeToAuthenticatedAccount(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	writePushConfig(t, workspace)
+
+	adapter := &pushAdapterStub{issues: map[string]jira.Issue{}, myself: jira.AccountRef{AccountID: "acc-me"}}
+	for _, key := range []string{"PROJ-1", "PROJ-2"} {
+		original := issue.Document{FrontMatter: issue.FrontMatter{SchemaVersion: contracts.IssueFileSchemaVersionV1, Key: key, Summary: "Remote summary", IssueType: "Task", Status: "To Do"}, CanonicalKey: key, MarkdownBody: "body"}
+		local := original
+		local.FrontMatter.Assignee = "me"
+		writeIssueFile(t, workspace, filepath.Join("open", key+"-remote-summary.md"), mustRenderDoc(t, local))
+		writeIssueFile(t, workspace, filepath.Join(".sync", "originals", key+".md"), mustRenderDoc(t, original))
+
+		remote := testRemoteIssue(key, "Remote summary", "To Do")
+		remote.Fields.Description = []byte(`{"version":1,"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"body"}]}]}`)
+		adapter.issues[key] = remote
+	}
+
+	report, runErr := RunPush(context.Background(), workspace, PushOptions{Adapter: adapter, Environment: config.Environment{JiraAPIToken: "token"}})
+	if runErr != nil {
+		t.Fatalf("run push failed: %v", runErr)
+	}
+	if report.Counts.Updated != 2 || report.Counts.Errors != 0 {
+		t.Fatalf("unexpected counts: %#v issues=%#v", report.Counts, report.Issues)
+	}
+	if adapter.myselfCalls != 1 {
+		t.Fatalf("expected one myself lookup per run, got %d", adapter.myselfCalls)
+	}
+	for _, request := range adapter.updateRequests {
+		if request.AssigneeAccountID == nil || *request.AssigneeAccountID != "acc-me" {
+			t.Fatalf("expected assignee to resolve to acc-me, got %#v", request.AssigneeAccountID)
+		}
+	}
+
+	content, err := os.ReadFile(filepath.Join(workspace, contracts.DefaultIssuesRootDir, "open", "PROJ-1-remote-summary.md"))
+	if err != nil {
+		t.Fatalf("read local issue failed: %v", err)
+	}
+	if !strings.Contains(string(content), "acc-me") {
+		t.Fatalf("expected local file to carry the resolved account, got %q", string(content))
+	}
+}
+
+func TestRunPushReportsUnresolvableAssigneeMe(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	writePushConfig(t, workspace)
+
+	original := issue.Document{FrontMatter: issue.FrontMatter{SchemaVersion: contracts.IssueFileSchemaVersionV1, Key: "PROJ-1", Summary: "Remote summary", IssueType: "Task", Status: "To Do"}, CanonicalKey: "PROJ-1", MarkdownBody: "body"}
+	local := original
+	local.FrontMatter.Assignee = "me"
+	writeIssueFile(t, workspace, filepath.Join("open", "PROJ-1-remote-summary.md"), mustRenderDoc(t, local))
+	writeIssueFile(t, workspace, filepath.Join(".sync", "originals", "PROJ-1.md"), mustRenderDoc(t, original))
+	adapter := &pushAdapterStub{issues: map[string]jira.Issue{"PROJ-1": testRemoteIssue("PROJ-1", "Remote summary", "To Do")}}
+
+	report, runErr := RunPush(context.Background(), workspace, PushOptions{Adapter: adapter, Environment: config.Environment{JiraAPIToken: "token"}})
+	if runErr != nil {
+		t.Fatalf("run push failed: %v", runErr)
+	}
+	if report.Counts.Errors != 1 || len(adapter.updateRequests) != 0 {
+		t.Fatalf("expected a per-issue error and no update, got %#v", report.Issues)
+	}
+	if text := report.Issues[0].Messages[0].Text; !strings.Contains(text, `assignee "me"`) {
+		t.Fatalf("expected a clear assignee message, got %q", text)
+	}
+}
+
 func TestRunPushRecoversDraftPublishFromMarkerWithoutSecondCreate(t *testing.T) {
 	t.Parallel()
 
@@ -280,6 +855,131 @@ func writePushIssue(t *testing.T, workspace string, key string, localSummary str
 	writeIssueFile(t, workspace, filepath.Join(".sync", "originals", key+".md"), original)
 }
 
+func TestRunPushResolvesAssigneeThroughAssignableUserSearch(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	writePushConfig(t, workspace)
+	adapter := writeAssigneeChange(t, workspace, "Jane Doe")
+	adapter.assignableUsers = []jira.AccountRef{
+		{AccountID: "5b10ac8d82e05b22cc7d4ef5", DisplayName: "Jane Doe", Email: "jane@example.com"},
+		{AccountID: "5b10ac8d82e05b22cc7d4ef6", DisplayName: "Jane Doerr"},
+	}
+
+	report, runErr := RunPush(context.Background(), workspace, PushOptions{Adapter: adapter, Environment: config.Environment{JiraAPIToken: "token"}})
+	if runErr != nil {
+		t.Fatalf("run push failed: %v", runErr)
+	}
+	if report.Counts.Updated != 1 || len(adapter.userSearches) != 1 {
+		t.Fatalf("expected one update after one user search, got %#v searches=%v", report.Issues, adapter.userSearches)
+	}
+	if request := adapter.updateRequests[0]; request.AssigneeAccountID == nil || *request.AssigneeAccountID != "5b10ac8d82e05b22cc7d4ef5" {
+		t.Fatalf("expected the exact display name match, got %#v", request.AssigneeAccountID)
+	}
+}
+
+func TestRunPushSendsPulledAccountIDVerbatimWithoutUserSearch(t *testing.T) {
+	t.Parallel()
+
+	// Pull writes the bare accountId when Jira returns no display name, and
+	// users copy it between files.
+	const accountID = "557058:f58131cb-b67d-43c7-b30d-6b58d40bd077"
+	workspace := t.TempDir()
+	writePushConfig(t, workspace)
+	adapter := writeAssigneeChange(t, workspace, accountID)
+
+	report, runErr := RunPush(context.Background(), workspace, PushOptions{Adapter: adapter, Environment: config.Environment{JiraAPIToken: "token"}})
+	if runErr != nil {
+		t.Fatalf("run push failed: %v", runErr)
+	}
+	if report.Counts.Updated != 1 || len(adapter.userSearches) != 0 {
+		t.Fatalf("expected an update without user search, got %#v searches=%v", report.Issues, adapter.userSearches)
+	}
+	if request := adapter.updateRequests[0]; request.AssigneeAccountID == nil || *request.AssigneeAccountID != accountID {
+		t.Fatalf("expected the accountId verbatim, got %#v", request.AssigneeAccountID)
+	}
+}
+
+func TestRunPushSendsAccountIDPrefixWithoutUserSearch(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	writePushConfig(t, workspace)
+	adapter := writeAssigneeChange(t, workspace, "@accountId:557058:f58131cb-b67d-43c7-b30d-6b58d40bd077")
+
+	report, runErr := RunPush(context.Background(), workspace, PushOptions{Adapter: adapter, Environment: config.Environment{JiraAPIToken: "token"}})
+	if runErr != nil {
+		t.Fatalf("run push failed: %v", runErr)
+	}
+	if report.Counts.Updated != 1 || len(adapter.userSearches) != 0 {
+		t.Fatalf("expected an update without user search, got %#v searches=%v", report.Issues, adapter.userSearches)
+	}
+	if request := adapter.updateRequests[0]; request.AssigneeAccountID == nil || *request.AssigneeAccountID != "557058:f58131cb-b67d-43c7-b30d-6b58d40bd077" {
+		t.Fatalf("expected the prefix to be stripped, got %#v", request.AssigneeAccountID)
+	}
+}
+
+func TestRunPushAssigneeIsAccountIDSkipsUserSearch(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		assignee string
+		updated  int
+		warnings int
+	}{
+		"plausible":   {assignee: "5b10ac8d82e05b22cc7d4ef5", updated: 1},
+		"implausible": {assignee: "jane@example.com", warnings: 1},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			workspace := t.TempDir()
+			cfg := contracts.Config{ConfigVersion: contracts.ConfigSchemaVersionV1, Profiles: map[string]contracts.ProjectProfile{"default": {ProjectKey: "PROJ", DefaultJQL: "project = PROJ", AssigneeIsAccountID: true}}}
+			if err := config.Write(filepath.Join(workspace, contracts.DefaultConfigFilePath), cfg); err != nil {
+				t.Fatalf("write config failed: %v", err)
+			}
+			adapter := writeAssigneeChange(t, workspace, tc.assignee)
+			adapter.assignableUsers = []jira.AccountRef{{AccountID: "5b10ac8d82e05b22cc7d4ef5", DisplayName: "Jane Doe", Email: "jane@example.com"}}
+
+			report, runErr := RunPush(context.Background(), workspace, PushOptions{Adapter: adapter, Environment: config.Environment{JiraAPIToken: "token"}})
+			if runErr != nil {
+				t.Fatalf("run push failed: %v", runErr)
+			}
+			if len(adapter.userSearches) != 0 {
+				t.Fatalf("expected no user search, got %v", adapter.userSearches)
+			}
+			if report.Counts.Updated != tc.updated || report.Counts.Warnings != tc.warnings {
+				t.Fatalf("unexpected counts: %#v issues=%#v", report.Counts, report.Issues)
+			}
+			if tc.updated == 0 {
+				if len(adapter.updateRequests) != 0 {
+					t.Fatalf("expected no update for an implausible accountId, got %#v", adapter.updateRequests)
+				}
+				return
+			}
+			if request := adapter.updateRequests[0]; request.AssigneeAccountID == nil || *request.AssigneeAccountID != tc.assignee {
+				t.Fatalf("expected the assignee verbatim, got %#v", request.AssigneeAccountID)
+			}
+		})
+	}
+}
+
+// writeAssigneeChange writes PROJ-1 with assignee changed locally to
+// assignee and returns a stub serving its unchanged remote.
+func writeAssigneeChange(t *testing.T, workspace string, assignee string) *pushAdapterStub {
+	t.Helper()
+
+	original := issue.Document{FrontMatter: issue.FrontMatter{SchemaVersion: contracts.IssueFileSchemaVersionV1, Key: "PROJ-1", Summary: "Remote summary", IssueType: "Task", Status: "To Do"}, CanonicalKey: "PROJ-1", MarkdownBody: "body"}
+	local := original
+	local.FrontMatter.Assignee = assignee
+	writeIssueFile(t, workspace, filepath.Join("open", "PROJ-1-remote-summary.md"), mustRenderDoc(t, local))
+	writeIssueFile(t, workspace, filepath.Join(".sync", "originals", "PROJ-1.md"), mustRenderDoc(t, original))
+
+	remote := testRemoteIssue("PROJ-1", "Remote summary", "To Do")
+	remote.Fields.Description = []byte(`{"version":1,"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"body"}]}]}`)
+	return &pushAdapterStub{issues: map[string]jira.Issue{"PROJ-1": remote}}
+}
+
 func writePushConfig(t *testing.T, workspace string) {
 	t.Helper()
 
@@ -297,19 +997,68 @@ type pushAdapterStub struct {
 	issues              map[string]jira.Issue
 	updateErrByKey      map[string]error
 	transitionByKey     map[string]jira.TransitionResolution
+	applyErrOnceByKey   map[string]error
 	createdKeyBySummary map[string]string
+	projects            []jira.Project
+	myself              jira.AccountRef
+	myselfCalls         int
 	updateCalls         int
+	updateRequests      []jira.UpdateIssueRequest
 	applyCalls          int
+	resolveCalls        int
 	createCalls         int
+	assignableUsers     []jira.AccountRef
+	userSearches        []string
+	searchable          bool
+	searchErr           error
+	searchRequests      []jira.SearchIssuesRequest
+	getIssueCalls       int
 }
 
-func (s *pushAdapterStub) SearchIssues(context.Context, jira.SearchIssuesRequest) (jira.SearchIssuesResponse, error) {
+// SearchIssues serves the issues whose quoted keys appear in the JQL, which
+// covers the push batch prefetch; draft marker probes match nothing.
+func (s *pushAdapterStub) SearchIssues(_ context.Context, request jira.SearchIssuesRequest) (jira.SearchIssuesResponse, error) {
+	s.searchRequests = append(s.searchRequests, request)
+	if s.searchErr != nil {
+		return jira.SearchIssuesResponse{}, s.searchErr
+	}
+	keys := make([]string, 0, len(s.issues))
+	for key := range s.issues {
+		if strings.Contains(request.JQL, `"`+key+`"`) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	issues := make([]jira.Issue, 0, len(keys))
+	for _, key := range keys {
+		issues = append(issues, s.issues[key])
+	}
+	return jira.SearchIssuesResponse{Total: len(issues), MaxResults: request.MaxResults, Issues: issues, IsLast: true}, nil
+}
+func (s *pushAdapterStub) ValidateJQL(context.Context, string) error {
 	panic("unexpected call")
 }
 func (s *pushAdapterStub) ListFields(context.Context) ([]jira.FieldDefinition, error) {
 	panic("unexpected call")
 }
+func (s *pushAdapterStub) ListProjects(context.Context) ([]jira.Project, error) {
+	return s.projects, nil
+}
+func (s *pushAdapterStub) ListStatuses(context.Context) ([]jira.StatusRef, error) {
+	return nil, nil
+}
+func (s *pushAdapterStub) ListSecurityLevels(context.Context, string) ([]jira.NamedRef, error) {
+	return nil, nil
+}
+func (s *pushAdapterStub) GetMyself(context.Context) (jira.AccountRef, error) {
+	s.myselfCalls++
+	if s.myself.AccountID == "" {
+		return jira.AccountRef{}, errors.New("myself is not configured")
+	}
+	return s.myself, nil
+}
 func (s *pushAdapterStub) GetIssue(_ context.Context, issueKey string, _ []string) (jira.Issue, error) {
+	s.getIssueCalls++
 	if issue, ok := s.issues[issueKey]; ok {
 		return issue, nil
 	}
@@ -322,8 +1071,9 @@ func (s *pushAdapterStub) CreateIssue(_ context.Context, request jira.CreateIssu
 	}
 	return jira.CreatedIssue{Key: "PROJ-999"}, nil
 }
-func (s *pushAdapterStub) UpdateIssue(_ context.Context, issueKey string, _ jira.UpdateIssueRequest) error {
+func (s *pushAdapterStub) UpdateIssue(_ context.Context, issueKey string, request jira.UpdateIssueRequest) error {
 	s.updateCalls++
+	s.updateRequests = append(s.updateRequests, request)
 	if err, ok := s.updateErrByKey[issueKey]; ok {
 		return err
 	}
@@ -332,13 +1082,28 @@ func (s *pushAdapterStub) UpdateIssue(_ context.Context, issueKey string, _ jira
 func (s *pushAdapterStub) ListTransitions(context.Context, string) ([]jira.Transition, error) {
 	panic("unexpected call")
 }
-func (s *pushAdapterStub) ApplyTransition(context.Context, string, string) error {
+func (s *pushAdapterStub) ApplyTransition(_ context.Context, issueKey string, _ string) error {
 	s.applyCalls++
+	if err, ok := s.applyErrOnceByKey[issueKey]; ok {
+		delete(s.applyErrOnceByKey, issueKey)
+		return err
+	}
 	return nil
 }
 func (s *pushAdapterStub) ResolveTransition(_ context.Context, issueKey string, _ contracts.TransitionSelection) (jira.TransitionResolution, error) {
+	s.resolveCalls++
 	if resolution, ok := s.transitionByKey[issueKey]; ok {
 		return resolution, nil
 	}
 	return jira.TransitionResolution{Kind: jira.TransitionResolutionUnavailable, ReasonCode: contracts.ReasonCodeTransitionUnavailable}, nil
 }
+func (s *pushAdapterStub) SearchAssignableUsers(_ context.Context, _ string, query string) ([]jira.AccountRef, error) {
+	s.userSearches = append(s.userSearches, query)
+	users := make([]jira.AccountRef, 0)
+	for _, user := range s.assignableUsers {
+		if strings.Contains(strings.ToLower(user.DisplayName+" "+user.Email), strings.ToLower(query)) {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
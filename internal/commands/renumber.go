@@ -0,0 +1,171 @@
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
+	"github.com/pweiskircher/jira-issue-sync/internal/output"
+	"github.com/pweiskircher/jira-issue-sync/internal/store"
+	publishsync "github.com/pweiskircher/jira-issue-sync/internal/sync/publish"
+)
+
+// renumberTempSuffix marks rewritten drafts before they replace the
+// originals. It is not .md, so readers never pick the files up.
+const renumberTempSuffix = ".renumber.tmp"
+
+type renumberedDraft struct {
+	record    issueRecord
+	newKey    string
+	newPath   string
+	canonical string
+}
+
+// runRenumberDrafts gives local drafts sequential keys (<prefix>-1,
+// <prefix>-2, ... zero-padded to the same width) in order of their current
+// keys, and rewrites #<draft> references between them. Drafts a push has
+// started publishing keep their key: Jira may already carry a marker label
+// naming it. Issues with Jira keys are never read for rewriting or touched.
+//
+// Every rewritten draft is staged next to its target before any original
+// is removed, so a failure while staging leaves the workspace unchanged.
+func runRenumberDrafts(workDir string) (output.Report, error) {
+	report := output.Report{CommandName: string(contracts.CommandNew)}
+
+	cfg, err := resolveWorkspaceConfig(workDir)
+	if err != nil {
+		return report, err
+	}
+	issuesRoot := issuesRootFromConfig(workDir, cfg)
+	workspaceStore, err := openIssueStore(issuesRoot, cfg)
+	if err != nil {
+		return report, err
+	}
+
+	records, err := loadIssueRecords(issuesRoot, inspectFilter{state: stateFilterAll}, profilePreservesLabelCase(cfg))
+	if err != nil {
+		return report, fmt.Errorf("failed to read local issues: %w", err)
+	}
+
+	drafts := make([]issueRecord, 0)
+	reserved := make(map[string]bool)
+	for _, record := range records {
+		if !contracts.IsLocalDraftKey(record.Key) {
+			continue
+		}
+		if record.Err != nil {
+			return report, fmt.Errorf("cannot renumber drafts while %s fails to parse: %w", record.RelativePath, record.Err)
+		}
+		if publishStarted(workspaceStore, record.Key) {
+			reserved[record.Key] = true
+			addIssueResult(&report, contracts.PerIssueResult{
+				Key:    record.Key,
+				Action: "renumber-skipped",
+				Status: contracts.PerIssueStatusWarning,
+				Messages: []contracts.IssueMessage{{
+					Level: "warning",
+					Text:  "publish is in progress; finish it with push before renumbering this draft",
+				}},
+			})
+			continue
+		}
+		drafts = append(drafts, record)
+	}
+	sort.Slice(drafts, func(i int, j int) bool {
+		return drafts[i].Key < drafts[j].Key
+	})
+
+	prefix := contracts.ResolveDraftKeyPrefix(cfg)
+	width := len(strconv.Itoa(len(drafts) + len(reserved)))
+	replacements := make(map[string]string, len(drafts))
+	next := 1
+	for _, draft := range drafts {
+		key := fmt.Sprintf("%s-%0*d", prefix, width, next)
+		for reserved[key] {
+			next++
+			key = fmt.Sprintf("%s-%0*d", prefix, width, next)
+		}
+		next++
+		if key != draft.Key {
+			replacements[draft.Key] = key
+		}
+	}
+	if len(replacements) == 0 {
+		return report, nil
+	}
+
+	planned := make([]renumberedDraft, 0, len(drafts))
+	for _, draft := range drafts {
+		newKey := draft.Key
+		if replacement, ok := replacements[draft.Key]; ok {
+			newKey = replacement
+		}
+		doc := draft.Document
+		doc.CanonicalKey = newKey
+		doc.FrontMatter.Key = newKey
+		doc.MarkdownBody = contracts.RewriteTempIDReferences(doc.MarkdownBody, replacements)
+		doc.EnvironmentMarkdown = contracts.RewriteTempIDReferences(doc.EnvironmentMarkdown, replacements)
+		canonical, err := workspaceStore.RenderDocument(doc)
+		if err != nil {
+			return report, fmt.Errorf("failed to render %s: %w", draft.RelativePath, err)
+		}
+		if newKey == draft.Key && canonical == draft.Canonical {
+			continue
+		}
+		filename, err := workspaceStore.IssueFilename(newKey, doc.FrontMatter.Summary)
+		if err != nil {
+			return report, err
+		}
+		planned = append(planned, renumberedDraft{
+			record:    draft,
+			newKey:    newKey,
+			newPath:   filepath.Join(filepath.Dir(draft.RelativePath), filename),
+			canonical: canonical,
+		})
+	}
+
+	for index, draft := range planned {
+		if err := workspaceStore.WriteFile(draft.newPath+renumberTempSuffix, []byte(draft.canonical)); err != nil {
+			for _, staged := range planned[:index] {
+				_ = workspaceStore.Remove(staged.newPath + renumberTempSuffix)
+			}
+			return report, fmt.Errorf("failed to stage renumbered draft %s: %w", draft.newPath, err)
+		}
+	}
+	for _, draft := range planned {
+		if err := workspaceStore.Remove(draft.record.RelativePath); err != nil {
+			return report, fmt.Errorf("failed to remove %s; rewritten drafts are staged as *%s: %w", draft.record.RelativePath, renumberTempSuffix, err)
+		}
+	}
+	for _, draft := range planned {
+		if err := workspaceStore.Rename(draft.newPath+renumberTempSuffix, draft.newPath); err != nil {
+			return report, fmt.Errorf("failed to move staged draft into %s: %w", draft.newPath, err)
+		}
+		text := "rewrote draft references in " + draft.newPath
+		if draft.newKey != draft.record.Key {
+			text = "renumbered to " + draft.newKey + " at " + draft.newPath
+		}
+		addIssueResult(&report, contracts.PerIssueResult{
+			Key:      draft.record.Key,
+			Action:   "renumber",
+			Status:   contracts.PerIssueStatusSuccess,
+			Messages: []contracts.IssueMessage{{Level: "info", Text: text}},
+		})
+	}
+	report.Counts.Updated = len(planned)
+
+	return report, nil
+}
+
+// publishStarted reports whether push left a publish marker or snapshot for
+// the draft, which means the issue may already exist in Jira.
+func publishStarted(workspaceStore *store.Store, key string) bool {
+	for _, path := range []string{publishsync.MarkerPath(key), filepath.Join(".sync", "originals", key+".md")} {
+		if _, err := workspaceStore.ReadFile(path); err == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package commands
+
+import (
+	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
+)
+
+// IssueFileSchema is a JSON Schema for issue file front matter, plus an
+// x-body extension describing how the Markdown body embeds raw ADF. It is
+// built from the contracts constants so it tracks the parser.
+type IssueFileSchema struct {
+	Schema               string                                      `json:"$schema"`
+	Title                string                                      `json:"title"`
+	Type                 string                                      `json:"type"`
+	Required             []contracts.FrontMatterKey                  `json:"required"`
+	Properties           map[contracts.FrontMatterKey]SchemaProperty `json:"properties"`
+	AdditionalProperties bool                                        `json:"additionalProperties"`
+	Body                 IssueBodySchema                             `json:"x-body"`
+}
+
+type SchemaProperty struct {
+	Type                 string           `json:"type,omitempty"`
+	Description          string           `json:"description,omitempty"`
+	Const                string           `json:"const,omitempty"`
+	Pattern              string           `json:"pattern,omitempty"`
+	Format               string           `json:"format,omitempty"`
+	AnyOf                []SchemaProperty `json:"anyOf,omitempty"`
+	Items                *SchemaProperty  `json:"items,omitempty"`
+	AdditionalProperties *SchemaProperty  `json:"additionalProperties,omitempty"`
+}
+
+// IssueBodySchema describes the layout after the closing front matter
+// delimiter. It has no JSON Schema equivalent.
+type IssueBodySchema struct {
+	FrontMatterDelimiter     string `json:"front_matter_delimiter"`
+	DescriptionFence         string `json:"description_fence"`
+	EnvironmentSectionMarker string `json:"environment_section_marker"`
+	EnvironmentFence         string `json:"environment_fence"`
+	CommentsSectionMarker    string `json:"comments_section_marker"`
+	ADFDocType               string `json:"adf_doc_type"`
+	ADFDocVersion            int    `json:"adf_doc_version"`
+}
+
+var frontMatterPropertySchemas = map[contracts.FrontMatterKey]SchemaProperty{
+	contracts.FrontMatterKeySchemaVersion: {Type: "string", Const: contracts.IssueFileSchemaVersionV1},
+	contracts.FrontMatterKeyKey: {
+		Type:        "string",
+		Description: "Jira issue key, or local draft key before publish",
+		AnyOf: []SchemaProperty{
+			{Pattern: contracts.JiraIssueKeyPattern.String()},
+			{Pattern: contracts.LocalDraftKeyPattern.String()},
+		},
+	},
+	contracts.FrontMatterKeySummary:   {Type: "string"},
+	contracts.FrontMatterKeyIssueType: {Type: "string"},
+	contracts.FrontMatterKeyStatus:    {Type: "string"},
+	contracts.FrontMatterKeyPriority:  {Type: "string"},
+	contracts.FrontMatterKeyAssignee:  {Type: "string", Description: "account ID, or \"me\" to assign the authenticated account on push"},
+	contracts.FrontMatterKeyLabels:    {Type: "array", Items: &SchemaProperty{Type: "string"}},
+	contracts.FrontMatterKeyReporter:  {Type: "string"},
+	contracts.FrontMatterKeySecurityLevel: {
+		Type:        "string",
+		Description: "Jira issue security level name; read-only except on draft publish with write_security_level",
+	},
+	contracts.FrontMatterKeyCreatedAt: {Type: "string", Format: "date-time"},
+	contracts.FrontMatterKeyUpdatedAt: {Type: "string", Format: "date-time"},
+	contracts.FrontMatterKeySyncedAt:  {Type: "string", Format: "date-time", Description: "volatile; ignored when deciding whether an issue changed"},
+	contracts.FrontMatterKeyCustomFields: {
+		Type:        "object",
+		Description: "inline JSON object keyed by profile field alias",
+	},
+	contracts.FrontMatterKeyCustomFieldNames: {
+		Type:                 "object",
+		Description:          "inline JSON object from customfield_<number> to field name",
+		AdditionalProperties: &SchemaProperty{Type: "string"},
+	},
+}
+
+func RunSchema() IssueFileSchema {
+	schema := IssueFileSchema{
+		Schema:     "https://json-schema.org/draft/2020-12/schema",
+		Title:      "jira-issue-sync issue file front matter (schema_version " + contracts.IssueFileSchemaVersionV1 + ")",
+		Type:       "object",
+		Required:   append([]contracts.FrontMatterKey(nil), contracts.RequiredFrontMatterKeys...),
+		Properties: make(map[contracts.FrontMatterKey]SchemaProperty, len(contracts.AllFrontMatterKeys())),
+		Body: IssueBodySchema{
+			FrontMatterDelimiter:     contracts.FrontMatterDelimiter,
+			DescriptionFence:         "```" + contracts.RawADFFenceLanguage,
+			EnvironmentSectionMarker: contracts.EnvironmentSectionMarker,
+			EnvironmentFence:         "```" + contracts.RawADFFenceLanguage + " " + contracts.RawADFEnvironmentLabel,
+			CommentsSectionMarker:    contracts.CommentsSectionMarker,
+			ADFDocType:               contracts.RawADFDocType,
+			ADFDocVersion:            contracts.RawADFDocVersion,
+		},
+	}
+	for _, key := range contracts.AllFrontMatterKeys() {
+		schema.Properties[key] = frontMatterPropertySchemas[key]
+	}
+	return schema
+}
@@ -15,51 +15,76 @@ type StatusOptions struct {
 	State            string
 	Key              string
 	IncludeUnchanged bool
+	Reasons          []string
 }
 
 func RunStatus(workDir string, options StatusOptions) (output.Report, error) {
 	report := output.Report{CommandName: string(contracts.CommandStatus)}
 
-	filter, err := normalizeFilter(options.State, options.Key)
+	filter, err := normalizeFilter(options.State, options.Key, options.Reasons)
 	if err != nil {
 		return report, err
 	}
 
-	records, err := loadIssueRecords(workDir, filter)
+	cfg, err := resolveWorkspaceConfig(workDir)
+	if err != nil {
+		return report, err
+	}
+	issuesRoot := issuesRootFromConfig(workDir, cfg)
+	preserveLabelCase := profilePreservesLabelCase(cfg)
+
+	records, err := loadIssueRecords(issuesRoot, filter, preserveLabelCase)
 	if err != nil {
 		return report, fmt.Errorf("failed to read local issues: %w", err)
 	}
 
 	for _, record := range records {
 		if record.Err != nil {
-			addIssueResult(&report, contracts.PerIssueResult{
+			filter.addResult(&report, contracts.PerIssueResult{
 				Key:    record.Key,
 				Action: "parse-error",
 				Status: contracts.PerIssueStatusError,
 				Messages: []contracts.IssueMessage{
 					buildTypedDiagnostic("error", record.ReasonCode, record.ErrorCode, record.Err.Error(), record.RelativePath),
 				},
+				Details: record.details(),
 			})
 			continue
 		}
 
-		result := compareRecordAgainstSnapshot(workDir, record)
+		result := compareRecordAgainstSnapshot(issuesRoot, record, preserveLabelCase)
+		result.Details = record.details()
 		if !options.IncludeUnchanged && result.Action == "unchanged" {
 			continue
 		}
-		addIssueResult(&report, result)
+		filter.addResult(&report, result)
 	}
 
+	summarizeStatus(&report)
 	return report, nil
 }
 
-func compareRecordAgainstSnapshot(workDir string, record issueRecord) contracts.PerIssueResult {
+// summarizeStatus tallies the reported issues into the status-only counts.
+// Drafts are counted by key so a draft that fails to parse still shows up as
+// pending publish.
+func summarizeStatus(report *output.Report) {
+	for _, result := range report.Issues {
+		if contracts.IsLocalDraftKey(result.Key) {
+			report.Counts.Drafts++
+		}
+		if result.Action == "modified" {
+			report.Counts.Modified++
+		}
+	}
+}
+
+func compareRecordAgainstSnapshot(issuesRoot string, record issueRecord, preserveLabelCase bool) contracts.PerIssueResult {
 	snapshotRelativePath := filepath.Join(".sync", "originals", record.Key+".md")
-	snapshotAbsolutePath := filepath.Join(workDir, contracts.DefaultIssuesRootDir, snapshotRelativePath)
+	snapshotAbsolutePath := filepath.Join(issuesRoot, snapshotRelativePath)
 	snapshotContent, err := os.ReadFile(snapshotAbsolutePath)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
-			if contracts.LocalDraftKeyPattern.MatchString(record.Key) {
+			if contracts.IsLocalDraftKey(record.Key) {
 				return contracts.PerIssueResult{
 					Key:    record.Key,
 					Action: "new",
@@ -96,7 +121,7 @@ func compareRecordAgainstSnapshot(workDir string, record issueRecord) contracts.
 		}
 	}
 
-	snapshotDoc, parseErr := issue.ParseDocument(snapshotRelativePath, string(snapshotContent))
+	snapshotDoc, parseErr := parseIssueDocument(snapshotRelativePath, string(snapshotContent), preserveLabelCase)
 	if parseErr != nil {
 		reason := contracts.ReasonCodeValidationFailed
 		code := "snapshot_parse_failed"
@@ -127,7 +152,7 @@ func compareRecordAgainstSnapshot(workDir string, record issueRecord) contracts.
 		}
 	}
 
-	if snapshotCanonical == record.Canonical {
+	if issue.EqualIgnoringVolatile(snapshotCanonical, record.Canonical) {
 		return contracts.PerIssueResult{
 			Key:    record.Key,
 			Action: "unchanged",
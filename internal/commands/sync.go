@@ -2,11 +2,14 @@ package commands
 
 import (
 	"context"
-	"time"
+	"path/filepath"
 
+	"github.com/pweiskircher/jira-issue-sync/internal/clock"
 	"github.com/pweiskircher/jira-issue-sync/internal/config"
 	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
+	httpclient "github.com/pweiskircher/jira-issue-sync/internal/http"
 	"github.com/pweiskircher/jira-issue-sync/internal/jira"
+	"github.com/pweiskircher/jira-issue-sync/internal/logging"
 	"github.com/pweiskircher/jira-issue-sync/internal/output"
 	"github.com/pweiskircher/jira-issue-sync/internal/sync/orchestrator"
 )
@@ -17,9 +20,21 @@ type SyncOptions struct {
 	PageSize    int
 	Concurrency int
 	DryRun      bool
-	Now         func() time.Time
+	Clock       clock.Clock
 	Environment config.Environment
 	Adapter     jira.Adapter
+	Logger      logging.Logger
+	RetryBudget *httpclient.RetryBudget
+	// StopOnConflict skips the pull stage when push reports a conflict.
+	StopOnConflict bool
+	// Insecure skips TLS certificate verification for Jira requests.
+	Insecure bool
+	// JiraBaseURL and JiraEmail override env and config for both stages.
+	JiraBaseURL string
+	JiraEmail   string
+	// JQLFile names a file holding the pull-stage query, resolved against
+	// the workspace when relative. It cannot be combined with JQL.
+	JQLFile string
 }
 
 var runPushCommand = RunPush
@@ -28,30 +43,60 @@ var runPullCommand = RunPull
 func RunSync(ctx context.Context, workDir string, options SyncOptions) (output.Report, error) {
 	report := output.Report{CommandName: string(contracts.CommandSync), DryRun: options.DryRun}
 
+	// Reject bad pull tuning before the push stage mutates anything.
+	if err := config.ValidatePullTuning(options.PageSize, options.Concurrency); err != nil {
+		return report, err
+	}
+	jql, err := resolveJQLFile(workDir, options.JQL, options.JQLFile)
+	if err != nil {
+		return report, err
+	}
+
+	// Both stages draw from one retry budget so retry_budget bounds the
+	// whole sync run rather than each stage separately.
+	budget := options.RetryBudget
+	if budget == nil {
+		if cfg, cfgErr := config.Read(filepath.Join(workDir, contracts.DefaultConfigFilePath)); cfgErr == nil {
+			budget = httpclient.NewRetryBudget(cfg.RetryBudget)
+		}
+	}
+
 	combined, err := orchestrator.Execute(ctx, orchestrator.Plan{
 		Push: func(stageCtx context.Context) (output.Report, error) {
 			return runPushCommand(stageCtx, workDir, PushOptions{
 				Profile:     options.Profile,
 				DryRun:      options.DryRun,
-				Now:         options.Now,
+				Clock:       options.Clock,
 				Environment: options.Environment,
 				Adapter:     options.Adapter,
+				Logger:      options.Logger,
+				RetryBudget: budget,
+				Insecure:    options.Insecure,
+				JiraBaseURL: options.JiraBaseURL,
+				JiraEmail:   options.JiraEmail,
 			})
 		},
 		Pull: func(stageCtx context.Context) (output.Report, error) {
 			return runPullCommand(stageCtx, workDir, PullOptions{
 				Profile:     options.Profile,
-				JQL:         options.JQL,
+				JQL:         jql,
 				PageSize:    options.PageSize,
 				Concurrency: options.Concurrency,
-				Now:         options.Now,
+				Clock:       options.Clock,
 				Environment: options.Environment,
 				Adapter:     options.Adapter,
+				Logger:      options.Logger,
+				RetryBudget: budget,
+				Insecure:    options.Insecure,
+				JiraBaseURL: options.JiraBaseURL,
+				JiraEmail:   options.JiraEmail,
 			})
 		},
+		StopOnPushConflict: options.StopOnConflict,
 	})
 
 	report.Counts = combined.Counts
 	report.Issues = combined.Issues
+	report.Timings = combined.Timings
 	return report, err
 }
@@ -2,17 +2,22 @@ package commands
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/pweiskircher/jira-issue-sync/internal/clock"
 	"github.com/pweiskircher/jira-issue-sync/internal/config"
 	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
 	"github.com/pweiskircher/jira-issue-sync/internal/output"
 )
 
 func TestRunSyncAggregatesPushThenPullReports(t *testing.T) {
-	now := time.Date(2026, time.February, 20, 12, 0, 0, 0, time.UTC)
+	clk := clock.NewFake(time.Date(2026, time.February, 20, 12, 0, 0, 0, time.UTC))
 	env := config.Environment{JiraAPIToken: "token"}
 	sequence := make([]string, 0, 2)
 
@@ -34,8 +39,8 @@ func TestRunSyncAggregatesPushThenPullReports(t *testing.T) {
 		if options.Profile != "staging" {
 			t.Fatalf("unexpected profile: %q", options.Profile)
 		}
-		if options.Now == nil || !options.Now().Equal(now) {
-			t.Fatalf("expected now function to propagate")
+		if options.Clock != clk {
+			t.Fatalf("expected clock to propagate")
 		}
 		if options.Environment != env {
 			t.Fatalf("expected environment to propagate")
@@ -59,8 +64,8 @@ func TestRunSyncAggregatesPushThenPullReports(t *testing.T) {
 		if options.PageSize != 50 || options.Concurrency != 3 {
 			t.Fatalf("unexpected pull pagination options: %#v", options)
 		}
-		if options.Now == nil || !options.Now().Equal(now) {
-			t.Fatalf("expected now function to propagate")
+		if options.Clock != clk {
+			t.Fatalf("expected clock to propagate")
 		}
 		if options.Environment != env {
 			t.Fatalf("expected environment to propagate")
@@ -78,7 +83,7 @@ func TestRunSyncAggregatesPushThenPullReports(t *testing.T) {
 		PageSize:    50,
 		Concurrency: 3,
 		DryRun:      true,
-		Now:         func() time.Time { return now },
+		Clock:       clk,
 		Environment: env,
 	})
 	if err != nil {
@@ -101,6 +106,47 @@ func TestRunSyncAggregatesPushThenPullReports(t *testing.T) {
 	}
 }
 
+func TestRunSyncReadsPullJQLFromFile(t *testing.T) {
+	originalPush := runPushCommand
+	originalPull := runPullCommand
+	t.Cleanup(func() {
+		runPushCommand = originalPush
+		runPullCommand = originalPull
+	})
+
+	workspace := t.TempDir()
+	if err := os.WriteFile(filepath.Join(workspace, "triage.jql"), []byte("# sprint triage\nproject = PROJ\nORDER BY updated DESC\n"), 0o644); err != nil {
+		t.Fatalf("write jql file: %v", err)
+	}
+
+	pushCalled := false
+	runPushCommand = func(context.Context, string, PushOptions) (output.Report, error) {
+		pushCalled = true
+		return output.Report{}, nil
+	}
+	var pulledJQL string
+	runPullCommand = func(_ context.Context, _ string, options PullOptions) (output.Report, error) {
+		pulledJQL = options.JQL
+		return output.Report{}, nil
+	}
+
+	if _, err := RunSync(context.Background(), workspace, SyncOptions{JQLFile: "triage.jql"}); err != nil {
+		t.Fatalf("sync with --jql-file failed: %v", err)
+	}
+	if want := "project = PROJ ORDER BY updated DESC"; pulledJQL != want {
+		t.Fatalf("expected pull JQL %q, got %q", want, pulledJQL)
+	}
+
+	pushCalled = false
+	_, err := RunSync(context.Background(), workspace, SyncOptions{JQL: "project = OTHER", JQLFile: "triage.jql"})
+	if !config.IsResolveErrorCode(err, config.ResolveErrorCodeInvalidFlag) {
+		t.Fatalf("expected invalid_flag_value when combining --jql and --jql-file, got %v", err)
+	}
+	if pushCalled {
+		t.Fatalf("push must not run when the pull JQL is invalid")
+	}
+}
+
 func TestRunSyncStopsOnPushFatalError(t *testing.T) {
 	originalPush := runPushCommand
 	originalPull := runPullCommand
@@ -153,3 +199,99 @@ func TestRunSyncReturnsMergedReportOnPullFatalError(t *testing.T) {
 		t.Fatalf("unexpected merged counts on pull fatal error: %#v", report.Counts)
 	}
 }
+
+func TestRunSyncStopOnConflictSkipsPullAfterPushConflict(t *testing.T) {
+	originalPush := runPushCommand
+	originalPull := runPullCommand
+	t.Cleanup(func() {
+		runPushCommand = originalPush
+		runPullCommand = originalPull
+	})
+
+	pullCalls := 0
+	runPushCommand = func(context.Context, string, PushOptions) (output.Report, error) {
+		return output.Report{
+			Counts: contracts.AggregateCounts{Processed: 1, Conflicts: 1},
+			Issues: []contracts.PerIssueResult{{Key: "PROJ-1", Action: "conflict", Status: contracts.PerIssueStatusConflict}},
+		}, nil
+	}
+	runPullCommand = func(context.Context, string, PullOptions) (output.Report, error) {
+		pullCalls++
+		return output.Report{Counts: contracts.AggregateCounts{Processed: 1, Updated: 1}}, nil
+	}
+
+	report, err := RunSync(context.Background(), "/tmp/workspace", SyncOptions{StopOnConflict: true})
+	if err != nil {
+		t.Fatalf("expected non-fatal sync result, got %v", err)
+	}
+	if pullCalls != 0 {
+		t.Fatalf("pull must not execute when push conflicts and stop-on-conflict is set")
+	}
+	if report.Counts.Processed != 1 || report.Counts.Conflicts != 1 || len(report.Issues) != 1 {
+		t.Fatalf("expected push-only report, got %#v", report)
+	}
+	if code := output.ResolveExitCode(report, err); code != contracts.ExitCodePartial {
+		t.Fatalf("expected partial exit code, got %d", code)
+	}
+
+	if _, err := RunSync(context.Background(), "/tmp/workspace", SyncOptions{}); err != nil {
+		t.Fatalf("default sync failed: %v", err)
+	}
+	if pullCalls != 1 {
+		t.Fatalf("expected default sync to run pull despite push conflicts, got %d pull calls", pullCalls)
+	}
+}
+
+func TestRunSyncTagsIssueResultsWithTheirStage(t *testing.T) {
+	originalPush := runPushCommand
+	originalPull := runPullCommand
+	t.Cleanup(func() {
+		runPushCommand = originalPush
+		runPullCommand = originalPull
+	})
+
+	runPushCommand = func(context.Context, string, PushOptions) (output.Report, error) {
+		return output.Report{
+			CommandName: "push",
+			Counts:      contracts.AggregateCounts{Processed: 1, Updated: 1},
+			Issues:      []contracts.PerIssueResult{{Key: "PROJ-1", Action: "updated", Status: contracts.PerIssueStatusSuccess}},
+		}, nil
+	}
+	runPullCommand = func(context.Context, string, PullOptions) (output.Report, error) {
+		return output.Report{
+			CommandName: "pull",
+			Counts:      contracts.AggregateCounts{Processed: 2, Updated: 2},
+			Issues: []contracts.PerIssueResult{
+				{Key: "PROJ-1", Action: "pull", Status: contracts.PerIssueStatusSuccess},
+				{Key: "PROJ-2", Action: "pull", Status: contracts.PerIssueStatusSuccess},
+			},
+		}, nil
+	}
+
+	report, err := RunSync(context.Background(), t.TempDir(), SyncOptions{})
+	if err != nil {
+		t.Fatalf("run sync failed: %v", err)
+	}
+
+	expected := []string{"push", "pull", "pull"}
+	if len(report.Issues) != len(expected) {
+		t.Fatalf("unexpected issues: %#v", report.Issues)
+	}
+	for index, phase := range expected {
+		if report.Issues[index].Phase != phase {
+			t.Fatalf("expected issue %d (%s) to carry phase %q, got %q", index, report.Issues[index].Key, phase, report.Issues[index].Phase)
+		}
+	}
+
+	envelope, err := output.BuildEnvelope(report, 0)
+	if err != nil {
+		t.Fatalf("build envelope failed: %v", err)
+	}
+	encoded, err := json.Marshal(envelope)
+	if err != nil {
+		t.Fatalf("marshal envelope failed: %v", err)
+	}
+	if !strings.Contains(string(encoded), `"key":"PROJ-1","action":"updated","status":"success","phase":"push"`) {
+		t.Fatalf("expected push phase in JSON envelope, got %s", encoded)
+	}
+}
@@ -13,22 +13,31 @@ import (
 
 type ViewOptions struct {
 	Key string
+	// OutputDir, when set, receives the rendered issue as <KEY>.md instead
+	// of printing it.
+	OutputDir string
 }
 
 func RunView(workDir string, options ViewOptions) (output.Report, error) {
 	report := output.Report{CommandName: string(contracts.CommandView)}
 
-	relativePath, err := findIssuePathByKey(workDir, options.Key)
+	cfg, err := resolveWorkspaceConfig(workDir)
 	if err != nil {
 		return report, err
 	}
+	issuesRoot := issuesRootFromConfig(workDir, cfg)
 
-	content, err := os.ReadFile(filepath.Join(workDir, contracts.DefaultIssuesRootDir, relativePath))
+	relativePath, err := findIssuePathByKey(issuesRoot, options.Key)
 	if err != nil {
 		return report, err
 	}
 
-	doc, err := issue.ParseDocument(relativePath, string(content))
+	content, err := os.ReadFile(filepath.Join(issuesRoot, relativePath))
+	if err != nil {
+		return report, err
+	}
+
+	doc, err := parseIssueDocument(relativePath, string(content), profilePreservesLabelCase(cfg))
 	if err != nil {
 		addIssueResult(&report, contracts.PerIssueResult{
 			Key:    strings.TrimSpace(options.Key),
@@ -48,7 +57,7 @@ func RunView(workDir string, options ViewOptions) (output.Report, error) {
 		return report, fmt.Errorf("failed to render document: %w", err)
 	}
 
-	addIssueResult(&report, contracts.PerIssueResult{
+	result := contracts.PerIssueResult{
 		Key:    doc.CanonicalKey,
 		Action: "view",
 		Status: contracts.PerIssueStatusSuccess,
@@ -56,7 +65,15 @@ func RunView(workDir string, options ViewOptions) (output.Report, error) {
 			{Level: "info", Text: "path=" + relativePath},
 			{Level: "info", Text: canonical},
 		},
-	})
+	}
+	outputDir, err := resolveOutputDir(workDir, options.OutputDir)
+	if err != nil {
+		return report, err
+	}
+	if outputDir != "" {
+		result = artifactResult(result, outputDir, ".md", canonical)
+	}
+	addIssueResult(&report, result)
 
 	return report, nil
 }
@@ -0,0 +1,109 @@
+// pattern: Imperative Shell
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// EnvironmentFromFile loads credentials from a dotenv-style file and layers
+// them below the process environment: a non-blank OS variable always wins.
+// Errors never include file contents so secrets cannot leak into output.
+func EnvironmentFromFile(path string) (Environment, error) {
+	return environmentFromFile(path, os.LookupEnv)
+}
+
+func environmentFromFile(path string, lookup func(string) (string, bool)) (Environment, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Environment{}, &Error{Code: ErrorCodeEnvFileReadFailed, Path: path, Err: err}
+	}
+
+	values, err := ParseEnvFile(data)
+	if err != nil {
+		return Environment{}, &Error{Code: ErrorCodeEnvFileParseFailed, Path: path, Err: err}
+	}
+
+	return EnvironmentFromLookup(layeredLookup(lookup, values)), nil
+}
+
+// ParseEnvFile parses KEY=VALUE lines. Blank lines and lines starting with
+// '#' are skipped, an optional "export " prefix is accepted, and values may
+// be wrapped in single or double quotes. Unquoted values end at " #".
+func ParseEnvFile(data []byte) (map[string]string, error) {
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if lineNumber == 1 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+
+		key, rawValue, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.ContainsAny(key, " \t\"'") {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", lineNumber)
+		}
+
+		value, err := parseEnvValue(strings.TrimSpace(rawValue))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		values[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+func parseEnvValue(raw string) (string, error) {
+	if raw == "" {
+		return "", nil
+	}
+
+	quote := raw[0]
+	if quote != '"' && quote != '\'' {
+		if index := strings.Index(raw, " #"); index >= 0 {
+			raw = raw[:index]
+		}
+		return strings.TrimSpace(raw), nil
+	}
+
+	closing := strings.IndexByte(raw[1:], quote)
+	if closing < 0 {
+		return "", fmt.Errorf("unterminated quoted value")
+	}
+	rest := strings.TrimSpace(raw[closing+2:])
+	if rest != "" && !strings.HasPrefix(rest, "#") {
+		return "", fmt.Errorf("unexpected characters after quoted value")
+	}
+
+	value := raw[1 : closing+1]
+	if quote == '"' {
+		value = strings.NewReplacer(`\n`, "\n", `\t`, "\t").Replace(value)
+	}
+	return value, nil
+}
+
+func layeredLookup(primary func(string) (string, bool), fallback map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		if primary != nil {
+			if value, ok := primary(key); ok && strings.TrimSpace(value) != "" {
+				return value, true
+			}
+		}
+		value, ok := fallback[key]
+		return value, ok
+	}
+}
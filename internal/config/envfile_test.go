@@ -0,0 +1,98 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseEnvFileHandlesCommentsAndQuotes(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(strings.Join([]string{
+		"# credentials for local runs",
+		"",
+		"   # indented comment",
+		`JIRA_API_TOKEN="tok#en with spaces"`,
+		"export JIRA_EMAIL='me@example.com' # trailing comment",
+		"JIRA_BASE_URL=https://example.atlassian.net # inline comment",
+		"EMPTY=",
+	}, "\n"))
+
+	values, err := ParseEnvFile(data)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	expected := map[string]string{
+		EnvJiraAPIToken: "tok#en with spaces",
+		EnvJiraEmail:    "me@example.com",
+		EnvJiraBaseURL:  "https://example.atlassian.net",
+		"EMPTY":         "",
+	}
+	if len(values) != len(expected) {
+		t.Fatalf("unexpected values: %#v", values)
+	}
+	for key, want := range expected {
+		if values[key] != want {
+			t.Fatalf("expected %s=%q, got %q", key, want, values[key])
+		}
+	}
+}
+
+func TestParseEnvFileRejectsMalformedLinesWithoutEchoingContent(t *testing.T) {
+	t.Parallel()
+
+	for _, line := range []string{`JIRA_API_TOKEN="secret-value`, "secret-value-without-key"} {
+		_, err := ParseEnvFile([]byte("# header\n" + line))
+		if err == nil {
+			t.Fatalf("expected parse error for %q", line)
+		}
+		if !strings.Contains(err.Error(), "line 2") || strings.Contains(err.Error(), "secret-value") {
+			t.Fatalf("unexpected error text: %v", err)
+		}
+	}
+}
+
+func TestEnvironmentFromFileLetsProcessEnvironmentWin(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), ".env")
+	content := "JIRA_API_TOKEN=file-token\nJIRA_EMAIL=file@example.com\nJIRA_BASE_URL=https://file.example\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write env file failed: %v", err)
+	}
+
+	osEnv := map[string]string{
+		EnvJiraAPIToken: "os-token",
+		EnvJiraEmail:    "   ",
+	}
+	lookup := func(key string) (string, bool) {
+		value, ok := osEnv[key]
+		return value, ok
+	}
+
+	environment, err := environmentFromFile(path, lookup)
+	if err != nil {
+		t.Fatalf("load env file failed: %v", err)
+	}
+	if environment.JiraAPIToken != "os-token" {
+		t.Fatalf("expected OS token to win, got %q", environment.JiraAPIToken)
+	}
+	if environment.JiraEmail != "file@example.com" {
+		t.Fatalf("expected file email when OS value is blank, got %q", environment.JiraEmail)
+	}
+	if environment.JiraBaseURL != "https://file.example" {
+		t.Fatalf("expected file base URL, got %q", environment.JiraBaseURL)
+	}
+}
+
+func TestEnvironmentFromFileReportsMissingFile(t *testing.T) {
+	t.Parallel()
+
+	_, err := EnvironmentFromFile(filepath.Join(t.TempDir(), "missing.env"))
+	if !IsErrorCode(err, ErrorCodeEnvFileReadFailed) {
+		t.Fatalf("expected %s, got %v", ErrorCodeEnvFileReadFailed, err)
+	}
+}
@@ -12,6 +12,9 @@ const (
 	ErrorCodeParseFailed      ErrorCode = "config_parse_failed"
 	ErrorCodeValidationFailed ErrorCode = "config_validation_failed"
 	ErrorCodeWriteFailed      ErrorCode = "config_write_failed"
+
+	ErrorCodeEnvFileReadFailed  ErrorCode = "env_file_read_failed"
+	ErrorCodeEnvFileParseFailed ErrorCode = "env_file_parse_failed"
 )
 
 type Error struct {
@@ -35,6 +38,10 @@ func (err *Error) Error() string {
 		prefix = "invalid configuration"
 	case ErrorCodeWriteFailed:
 		prefix = "failed to write config"
+	case ErrorCodeEnvFileReadFailed:
+		prefix = "failed to read env file"
+	case ErrorCodeEnvFileParseFailed:
+		prefix = "failed to parse env file"
 	default:
 		prefix = "config error"
 	}
@@ -68,9 +75,11 @@ type ResolveErrorCode string
 const (
 	ResolveErrorCodeInvalidConfig  ResolveErrorCode = "invalid_config"
 	ResolveErrorCodeInvalidFlag    ResolveErrorCode = "invalid_flag_value"
+	ResolveErrorCodeInvalidEnv     ResolveErrorCode = "invalid_env_value"
 	ResolveErrorCodeMissingProfile ResolveErrorCode = "missing_profile"
 	ResolveErrorCodeUnknownProfile ResolveErrorCode = "unknown_profile"
 	ResolveErrorCodeMissingToken   ResolveErrorCode = "missing_api_token"
+	ResolveErrorCodeUnknownProject ResolveErrorCode = "unknown_project"
 )
 
 type ResolveError struct {
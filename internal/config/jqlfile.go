@@ -0,0 +1,36 @@
+// pattern: Imperative Shell
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ReadJQLFile loads a --jql-file query. Lines are trimmed, blank lines and
+// lines starting with '#' are dropped, and the rest are joined with single
+// spaces so long queries can be split across lines.
+func ReadJQLFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", &ResolveError{Code: ResolveErrorCodeInvalidFlag, Message: "--jql-file could not be read", Err: err}
+	}
+	jql := ParseJQLFile(string(data))
+	if jql == "" {
+		return "", &ResolveError{Code: ResolveErrorCodeInvalidFlag, Message: fmt.Sprintf("--jql-file %s contains no JQL", path)}
+	}
+	return jql, nil
+}
+
+// ParseJQLFile strips comments and blank lines from JQL file content.
+func ParseJQLFile(content string) string {
+	parts := make([]string, 0)
+	for _, line := range strings.Split(strings.TrimPrefix(content, "\ufeff"), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts = append(parts, line)
+	}
+	return strings.Join(parts, " ")
+}
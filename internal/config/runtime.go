@@ -2,8 +2,10 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
@@ -13,6 +15,10 @@ const (
 	EnvJiraAPIToken = "JIRA_API_TOKEN"
 	EnvJiraBaseURL  = "JIRA_BASE_URL"
 	EnvJiraEmail    = "JIRA_EMAIL"
+	// EnvConcurrency and EnvPageSize tune pull for runs where editing
+	// flags or config is awkward, such as containers. Flags win.
+	EnvConcurrency = "JIRA_SYNC_CONCURRENCY"
+	EnvPageSize    = "JIRA_SYNC_PAGE_SIZE"
 )
 
 type RuntimeFlags struct {
@@ -20,12 +26,18 @@ type RuntimeFlags struct {
 	JQL         string
 	JiraBaseURL string
 	JiraEmail   string
+	PageSize    int
+	Concurrency int
 }
 
 type Environment struct {
 	JiraAPIToken string
 	JiraBaseURL  string
 	JiraEmail    string
+	// Concurrency and PageSize hold the raw tuning variables; Resolve
+	// parses and range-checks them.
+	Concurrency string
+	PageSize    string
 }
 
 type ResolveOptions struct {
@@ -49,6 +61,10 @@ type RuntimeSettings struct {
 	DefaultJQL          string
 	DefaultJQLSource    JQLSource
 	TransitionOverrides map[string]contracts.TransitionOverride
+	// PageSize and Concurrency come from the flags, else the environment;
+	// zero means the built-in default.
+	PageSize    int
+	Concurrency int
 }
 
 func (settings RuntimeSettings) ResolveTransitionSelection(targetStatus string) contracts.TransitionSelection {
@@ -77,6 +93,18 @@ func Resolve(config contracts.Config, flags RuntimeFlags, env Environment, optio
 		}
 	}
 
+	if err := ValidatePullTuning(flags.PageSize, flags.Concurrency); err != nil {
+		return RuntimeSettings{}, err
+	}
+	envPageSize, err := parseTuningEnv(EnvPageSize, env.PageSize, contracts.MaxPullPageSize)
+	if err != nil {
+		return RuntimeSettings{}, err
+	}
+	envConcurrency, err := parseTuningEnv(EnvConcurrency, env.Concurrency, contracts.MaxPullConcurrency)
+	if err != nil {
+		return RuntimeSettings{}, err
+	}
+
 	token := strings.TrimSpace(env.JiraAPIToken)
 	if options.RequireToken && token == "" {
 		return RuntimeSettings{}, &ResolveError{
@@ -92,6 +120,8 @@ func Resolve(config contracts.Config, flags RuntimeFlags, env Environment, optio
 		JiraAPIToken:        token,
 		JiraBaseURL:         firstNonEmpty(strings.TrimSpace(flags.JiraBaseURL), strings.TrimSpace(env.JiraBaseURL), strings.TrimSpace(config.Jira.BaseURL)),
 		JiraEmail:           firstNonEmpty(strings.TrimSpace(flags.JiraEmail), strings.TrimSpace(env.JiraEmail), strings.TrimSpace(config.Jira.Email)),
+		PageSize:            firstPositive(flags.PageSize, envPageSize),
+		Concurrency:         firstPositive(flags.Concurrency, envConcurrency),
 	}
 
 	if flagJQL != "" {
@@ -122,6 +152,8 @@ func EnvironmentFromLookup(lookup func(string) (string, bool)) Environment {
 		JiraAPIToken: lookupTrimmed(lookup, EnvJiraAPIToken),
 		JiraBaseURL:  lookupTrimmed(lookup, EnvJiraBaseURL),
 		JiraEmail:    lookupTrimmed(lookup, EnvJiraEmail),
+		Concurrency:  lookupTrimmed(lookup, EnvConcurrency),
+		PageSize:     lookupTrimmed(lookup, EnvPageSize),
 	}
 }
 
@@ -170,6 +202,15 @@ func resolveProfile(config contracts.Config, profileFlag string) (string, contra
 	}
 }
 
+func firstPositive(values ...int) int {
+	for _, value := range values {
+		if value > 0 {
+			return value
+		}
+	}
+	return 0
+}
+
 func firstNonEmpty(values ...string) string {
 	for _, value := range values {
 		if strings.TrimSpace(value) != "" {
@@ -200,6 +241,8 @@ func cloneProfile(profile contracts.ProjectProfile) contracts.ProjectProfile {
 	cloned := profile
 	cloned.TransitionOverrides = cloneTransitionOverrides(profile.TransitionOverrides)
 	cloned.FieldConfig = cloneFieldConfig(profile.FieldConfig)
+	cloned.WritableFields = append([]string(nil), profile.WritableFields...)
+	cloned.ReadonlyFields = append([]string(nil), profile.ReadonlyFields...)
 	return cloned
 }
 
@@ -234,3 +277,114 @@ func cloneTransitionOverrides(overrides map[string]contracts.TransitionOverride)
 
 	return cloned
 }
+
+// ValidatePullTuning checks --page-size and --concurrency against the
+// supported pull envelope. Zero means "use the default".
+func ValidatePullTuning(pageSize int, concurrency int) error {
+	if err := validateBoundedFlag("--page-size", pageSize, contracts.MaxPullPageSize); err != nil {
+		return err
+	}
+	return validateBoundedFlag("--concurrency", concurrency, contracts.MaxPullConcurrency)
+}
+
+// parseTuningEnv reads a tuning variable. Unset is zero; a set value must
+// be a whole number in the same range as the matching flag.
+func parseTuningEnv(name string, raw string, max int) (int, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 0, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 1 || value > max {
+		return 0, &ResolveError{
+			Code:    ResolveErrorCodeInvalidEnv,
+			Message: fmt.Sprintf("%s must be a whole number between 1 and %d, got %q", name, max, raw),
+		}
+	}
+	return value, nil
+}
+
+// ValidateMaxErrors checks --max-errors. Zero means unlimited.
+func ValidateMaxErrors(maxErrors int) error {
+	if maxErrors >= 0 {
+		return nil
+	}
+	return &ResolveError{
+		Code:    ResolveErrorCodeInvalidFlag,
+		Message: fmt.Sprintf("--max-errors must not be negative, got %d", maxErrors),
+	}
+}
+
+// ValidateMaxResultsTotal checks --max-results-total. Zero defers to
+// max_pull_issues.
+func ValidateMaxResultsTotal(maxResultsTotal int) error {
+	if maxResultsTotal >= 0 {
+		return nil
+	}
+	return &ResolveError{
+		Code:    ResolveErrorCodeInvalidFlag,
+		Message: fmt.Sprintf("--max-results-total must not be negative, got %d", maxResultsTotal),
+	}
+}
+
+// ParsePushExclusions resolves --exclude values, which may be repeated or
+// comma-separated, into the set of writable fields push must skip.
+func ParsePushExclusions(values []string) (map[contracts.JiraField]bool, error) {
+	excluded := map[contracts.JiraField]bool{}
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			name := strings.ToLower(strings.TrimSpace(part))
+			if name == "" {
+				continue
+			}
+			field := contracts.JiraField(name)
+			if !contracts.SupportedWritableField(field) {
+				return nil, &ResolveError{
+					Code:    ResolveErrorCodeInvalidFlag,
+					Message: fmt.Sprintf("--exclude does not accept %q; expected one of summary, description, labels, assignee, priority, status, environment", part),
+				}
+			}
+			excluded[field] = true
+		}
+	}
+	if len(excluded) == 0 {
+		return nil, nil
+	}
+	return excluded, nil
+}
+
+// ParseRepairKeys parses --repair-snapshots values (repeatable or
+// comma-separated) into sorted, de-duplicated Jira issue keys.
+func ParseRepairKeys(values []string) ([]string, error) {
+	seen := map[string]bool{}
+	keys := make([]string, 0)
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			key := strings.ToUpper(strings.TrimSpace(part))
+			if key == "" || seen[key] {
+				continue
+			}
+			if !contracts.JiraIssueKeyPattern.MatchString(key) {
+				return nil, &ResolveError{
+					Code:    ResolveErrorCodeInvalidFlag,
+					Message: fmt.Sprintf("--repair-snapshots expects Jira issue keys such as PROJ-123, got %q", strings.TrimSpace(part)),
+				}
+			}
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// validateBoundedFlag accepts zero (use the default) or a value in 1..max.
+func validateBoundedFlag(name string, value int, max int) error {
+	if value == 0 || (value >= 1 && value <= max) {
+		return nil
+	}
+	return &ResolveError{
+		Code:    ResolveErrorCodeInvalidFlag,
+		Message: fmt.Sprintf("%s must be between 1 and %d, got %d", name, max, value),
+	}
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"reflect"
+	"strconv"
 	"testing"
 
 	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
@@ -136,6 +137,69 @@ func TestResolveTokenRequirementIsEnvOnly(t *testing.T) {
 	}
 }
 
+func TestResolveRejectsOutOfRangePullTuning(t *testing.T) {
+	cases := []RuntimeFlags{
+		{PageSize: -1},
+		{PageSize: contracts.MaxPullPageSize + 1},
+		{Concurrency: -4},
+		{Concurrency: contracts.MaxPullConcurrency + 1},
+	}
+	for _, flags := range cases {
+		_, err := Resolve(baseConfig(), flags, Environment{}, ResolveOptions{})
+		if !IsResolveErrorCode(err, ResolveErrorCodeInvalidFlag) {
+			t.Fatalf("expected invalid flag error for %#v, got %v", flags, err)
+		}
+	}
+
+	if _, err := Resolve(baseConfig(), RuntimeFlags{PageSize: contracts.MaxPullPageSize, Concurrency: 1}, Environment{}, ResolveOptions{}); err != nil {
+		t.Fatalf("expected in-range tuning to resolve, got %v", err)
+	}
+}
+
+func TestResolvePullTuningFallsBackToEnvironment(t *testing.T) {
+	env := EnvironmentFromLookup(func(key string) (string, bool) {
+		values := map[string]string{
+			EnvConcurrency: " 8 ",
+			EnvPageSize:    "50",
+		}
+		value, ok := values[key]
+		return value, ok
+	})
+
+	settings, err := Resolve(baseConfig(), RuntimeFlags{}, env, ResolveOptions{})
+	if err != nil {
+		t.Fatalf("resolve failed: %v", err)
+	}
+	if settings.Concurrency != 8 || settings.PageSize != 50 {
+		t.Fatalf("expected env tuning, got concurrency=%d page size=%d", settings.Concurrency, settings.PageSize)
+	}
+
+	settings, err = Resolve(baseConfig(), RuntimeFlags{Concurrency: 2}, env, ResolveOptions{})
+	if err != nil {
+		t.Fatalf("resolve failed: %v", err)
+	}
+	if settings.Concurrency != 2 || settings.PageSize != 50 {
+		t.Fatalf("expected flag to override env concurrency only, got concurrency=%d page size=%d", settings.Concurrency, settings.PageSize)
+	}
+
+	settings, err = Resolve(baseConfig(), RuntimeFlags{}, Environment{}, ResolveOptions{})
+	if err != nil || settings.Concurrency != 0 || settings.PageSize != 0 {
+		t.Fatalf("expected built-in defaults without env, got %#v (%v)", settings, err)
+	}
+
+	for _, env := range []Environment{
+		{Concurrency: "0"},
+		{Concurrency: "many"},
+		{PageSize: "1.5"},
+		{PageSize: strconv.Itoa(contracts.MaxPullPageSize + 1)},
+	} {
+		_, err := Resolve(baseConfig(), RuntimeFlags{}, env, ResolveOptions{})
+		if !IsResolveErrorCode(err, ResolveErrorCodeInvalidEnv) {
+			t.Fatalf("expected invalid env error for %#v, got %v", env, err)
+		}
+	}
+}
+
 func TestEnvironmentFromLookupTrimsValues(t *testing.T) {
 	env := EnvironmentFromLookup(func(key string) (string, bool) {
 		values := map[string]string{
@@ -47,12 +47,31 @@ type CommandEnvelope struct {
 	Command         CommandMeta      `json:"command"`
 	Counts          AggregateCounts  `json:"counts"`
 	Issues          []PerIssueResult `json:"issues,omitempty"`
+	// Pagination is set by commands that can return a subset of their
+	// matches, currently `list`.
+	Pagination *PaginationMeta `json:"pagination,omitempty"`
+}
+
+// PaginationMeta says how many issues matched and how many the envelope
+// carries, so a UI can show "showing 50 of 1200".
+type PaginationMeta struct {
+	Total     int  `json:"total"`
+	Returned  int  `json:"returned"`
+	Truncated bool `json:"truncated"`
 }
 
 type CommandMeta struct {
-	Name       string `json:"name"`
-	DurationMS int64  `json:"duration_ms"`
-	DryRun     bool   `json:"dry_run"`
+	Name       string        `json:"name"`
+	DurationMS int64         `json:"duration_ms"`
+	DryRun     bool          `json:"dry_run"`
+	Timings    []PhaseTiming `json:"timings,omitempty"`
+}
+
+// PhaseTiming is the wall time spent in one named phase of a command,
+// measured with the monotonic clock.
+type PhaseTiming struct {
+	Phase      string `json:"phase"`
+	DurationUS int64  `json:"duration_us"`
 }
 
 type AggregateCounts struct {
@@ -62,6 +81,9 @@ type AggregateCounts struct {
 	Conflicts int `json:"conflicts"`
 	Warnings  int `json:"warnings"`
 	Errors    int `json:"errors"`
+	// Drafts and Modified are filled only by status and omitted elsewhere.
+	Drafts   int `json:"drafts,omitempty"`
+	Modified int `json:"modified,omitempty"`
 }
 
 type PerIssueStatus string
@@ -79,6 +101,20 @@ type PerIssueResult struct {
 	Action   string         `json:"action"`
 	Status   PerIssueStatus `json:"status"`
 	Messages []IssueMessage `json:"messages,omitempty"`
+	// Phase names the sync stage ("push" or "pull") that produced the
+	// result. Single-stage commands leave it empty.
+	Phase string `json:"phase,omitempty"`
+	// Details carries local file data for human --format templates and is
+	// never part of the JSON envelope.
+	Details *IssueDetails `json:"-"`
+}
+
+// IssueDetails is what inspection commands know about an issue's local file.
+type IssueDetails struct {
+	Summary string
+	Status  string
+	State   string
+	Path    string
 }
 
 type IssueMessage struct {
@@ -2,7 +2,11 @@
 package contracts
 
 import (
+	"crypto/tls"
 	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
 )
@@ -20,13 +24,73 @@ var SupportedConfigSchemaVersions = []string{ConfigSchemaVersionV1}
 
 // Config models .issues/.sync/config.json.
 type Config struct {
-	ConfigVersion  string                    `json:"config_version"`
-	Jira           JiraConfig                `json:"jira"`
-	DefaultProfile string                    `json:"default_profile,omitempty"`
-	DefaultJQL     string                    `json:"default_jql,omitempty"`
-	Profiles       map[string]ProjectProfile `json:"profiles"`
+	ConfigVersion    string     `json:"config_version"`
+	Jira             JiraConfig `json:"jira"`
+	DefaultProfile   string     `json:"default_profile,omitempty"`
+	DefaultJQL       string     `json:"default_jql,omitempty"`
+	IssuesRoot       string     `json:"issues_root,omitempty"`
+	RetryBudget      int        `json:"retry_budget,omitempty"`
+	FilenameStyle    string     `json:"filename_style,omitempty"`
+	MaxSlugLen       int        `json:"max_slug_len,omitempty"`
+	LabelRenderStyle string     `json:"label_render_style,omitempty"`
+	DraftKeyPrefix   string     `json:"draft_key_prefix,omitempty"`
+	MarkdownFlavor   string     `json:"markdown_flavor,omitempty"`
+	// FlatLayout stores every issue file directly under the issues root
+	// instead of splitting them into open/ and closed/ by status.
+	FlatLayout bool `json:"flat_layout,omitempty"`
+	// FrontMatterOrder lists front matter keys to render first, in order.
+	// Keys left out follow in the default order.
+	FrontMatterOrder []string `json:"front_matter_order,omitempty"`
+	// MaxPullIssues aborts a pull whose search matches more issues, so a
+	// too-broad JQL cannot fill the disk. Zero means unlimited.
+	MaxPullIssues int `json:"max_pull_issues,omitempty"`
+	// RedactionPatterns are extra regular expressions whose matches are
+	// replaced in error messages and logs, next to the built-in secrets.
+	RedactionPatterns []string `json:"redaction_patterns,omitempty"`
+	// ProxyURL routes Jira requests through a fixed proxy instead of the
+	// HTTPS_PROXY/HTTP_PROXY environment variables.
+	ProxyURL string `json:"proxy_url,omitempty"`
+	// CABundlePath, ClientCertPath, and ClientKeyPath name PEM files for
+	// self-hosted Jira behind a private CA or mutual TLS. Relative paths
+	// resolve from the project root.
+	CABundlePath   string `json:"ca_bundle_path,omitempty"`
+	ClientCertPath string `json:"client_cert_path,omitempty"`
+	ClientKeyPath  string `json:"client_key_path,omitempty"`
+	// MinTLSVersion ("1.2" or "1.3") and TLSCipherSuites tighten the TLS
+	// policy for Jira requests. The suite allowlist only affects TLS 1.2.
+	MinTLSVersion   string                    `json:"min_tls_version,omitempty"`
+	TLSCipherSuites []string                  `json:"tls_cipher_suites,omitempty"`
+	Profiles        map[string]ProjectProfile `json:"profiles"`
 }
 
+// Filename styles select how issue files are named on disk.
+const (
+	FilenameStyleKeySummary = "key-summary"
+	FilenameStyleKeyOnly    = "key-only"
+)
+
+// Label render styles select how front matter labels are written. The parser
+// accepts both forms regardless of the configured style.
+const (
+	LabelRenderStyleBlock  = "block"
+	LabelRenderStyleInline = "inline"
+)
+
+// Markdown flavors select the dialect descriptions are rendered in. GFM adds
+// strikethrough, task lists, and pipe tables; CommonMark leaves them as text.
+const (
+	MarkdownFlavorCommonMark = "commonmark"
+	MarkdownFlavorGFM        = "gfm"
+)
+
+// Description risk policies decide what push does with a description or
+// environment edit whose conversion back to ADF may lose content.
+const (
+	DescriptionRiskPolicyBlock = "block"
+	DescriptionRiskPolicyWarn  = "warn"
+	DescriptionRiskPolicyAllow = "allow"
+)
+
 // JiraConfig contains non-secret Jira defaults; token is env-only by contract.
 type JiraConfig struct {
 	BaseURL string `json:"base_url,omitempty"`
@@ -37,8 +101,25 @@ type JiraConfig struct {
 type ProjectProfile struct {
 	ProjectKey          string                        `json:"project_key"`
 	DefaultJQL          string                        `json:"default_jql,omitempty"`
+	DefaultIssueType    string                        `json:"default_issue_type,omitempty"`
 	TransitionOverrides map[string]TransitionOverride `json:"transition_overrides,omitempty"`
 	FieldConfig         FieldConfig                   `json:"field_config,omitempty"`
+	// WritableFields, when set, limits push to these writable fields.
+	// ReadonlyFields removes fields from whatever set applies, so
+	// ["status"] stops push from ever transitioning.
+	WritableFields []string `json:"writable_fields,omitempty"`
+	ReadonlyFields []string `json:"readonly_fields,omitempty"`
+	// DescriptionRiskPolicy is block (the default), warn, or allow.
+	DescriptionRiskPolicy string `json:"description_risk_policy,omitempty"`
+	// AssigneeIsAccountID makes push treat every assignee value as an
+	// accountId and send it without a user lookup.
+	AssigneeIsAccountID bool `json:"assignee_is_account_id,omitempty"`
+	// PreserveLabelCase keeps label case instead of lowercasing, for Jira
+	// instances with case-sensitive labels.
+	PreserveLabelCase bool `json:"preserve_label_case,omitempty"`
+	// WriteSecurityLevel lets draft publish set a draft's security_level.
+	// Existing issues never have their security level pushed.
+	WriteSecurityLevel bool `json:"write_security_level,omitempty"`
 }
 
 // FieldConfig controls pull field selection and custom-field labeling.
@@ -48,6 +129,9 @@ type FieldConfig struct {
 	ExcludeFields   []string          `json:"exclude_fields,omitempty"`
 	Aliases         map[string]string `json:"aliases,omitempty"`
 	IncludeMetadata bool              `json:"include_metadata,omitempty"`
+	// SkipEnvironment turns off pulling and pushing the Jira environment
+	// field for teams that do not use it.
+	SkipEnvironment bool `json:"skip_environment,omitempty"`
 }
 
 // TransitionOverride defines transition disambiguation selectors.
@@ -176,6 +260,74 @@ func ValidateConfig(config Config) error {
 		issues = appendIssue(issues, "default_jql", ConfigValidationCodeInvalidValue, "must not be only whitespace")
 	}
 
+	if config.IssuesRoot != "" {
+		if message, ok := validateIssuesRoot(config.IssuesRoot); !ok {
+			issues = appendIssue(issues, "issues_root", ConfigValidationCodeInvalidValue, message)
+		}
+	}
+
+	if config.RetryBudget < 0 {
+		issues = appendIssue(issues, "retry_budget", ConfigValidationCodeInvalidValue, "must not be negative")
+	}
+
+	if config.MaxPullIssues < 0 {
+		issues = appendIssue(issues, "max_pull_issues", ConfigValidationCodeInvalidValue, "must not be negative")
+	}
+
+	for index, pattern := range config.RedactionPatterns {
+		if _, err := compileRedactionPattern(pattern); err != nil {
+			issues = appendIssue(issues, fmt.Sprintf("redaction_patterns[%d]", index), ConfigValidationCodeInvalidValue, err.Error())
+		}
+	}
+
+	if config.ProxyURL != "" {
+		if _, err := parseProxyURL(config.ProxyURL); err != nil {
+			issues = appendIssue(issues, "proxy_url", ConfigValidationCodeInvalidValue, err.Error())
+		}
+	}
+
+	if (strings.TrimSpace(config.ClientCertPath) == "") != (strings.TrimSpace(config.ClientKeyPath) == "") {
+		issues = appendIssue(issues, "client_cert_path", ConfigValidationCodeInvalidValue, "client_cert_path and client_key_path must be set together")
+	}
+
+	minTLSVersion, err := ParseMinTLSVersion(config.MinTLSVersion)
+	if err != nil {
+		issues = appendIssue(issues, "min_tls_version", ConfigValidationCodeInvalidValue, err.Error())
+	}
+	if _, err := ParseCipherSuites(config.TLSCipherSuites); err != nil {
+		issues = appendIssue(issues, "tls_cipher_suites", ConfigValidationCodeInvalidValue, err.Error())
+	} else if len(config.TLSCipherSuites) > 0 && minTLSVersion == tls.VersionTLS13 {
+		issues = appendIssue(issues, "tls_cipher_suites", ConfigValidationCodeInvalidValue, "cipher suites cannot be restricted when min_tls_version is 1.3")
+	}
+
+	switch strings.TrimSpace(config.FilenameStyle) {
+	case "", FilenameStyleKeySummary, FilenameStyleKeyOnly:
+	default:
+		issues = appendIssue(issues, "filename_style", ConfigValidationCodeInvalidValue, "must be one of: key-summary, key-only")
+	}
+
+	switch strings.TrimSpace(config.LabelRenderStyle) {
+	case "", LabelRenderStyleBlock, LabelRenderStyleInline:
+	default:
+		issues = appendIssue(issues, "label_render_style", ConfigValidationCodeInvalidValue, "must be one of: block, inline")
+	}
+
+	issues = validateFrontMatterOrder(issues, config.FrontMatterOrder)
+
+	switch strings.TrimSpace(config.MarkdownFlavor) {
+	case "", MarkdownFlavorCommonMark, MarkdownFlavorGFM:
+	default:
+		issues = appendIssue(issues, "markdown_flavor", ConfigValidationCodeInvalidValue, "must be one of: commonmark, gfm")
+	}
+
+	if config.DraftKeyPrefix != "" && !ValidDraftKeyPrefix(strings.TrimSpace(config.DraftKeyPrefix)) {
+		issues = appendIssue(issues, "draft_key_prefix", ConfigValidationCodeInvalidValue, "must be letters and digits starting with a letter, and must not look like a Jira project key")
+	}
+
+	if config.MaxSlugLen < 0 {
+		issues = appendIssue(issues, "max_slug_len", ConfigValidationCodeInvalidValue, "must not be negative")
+	}
+
 	if len(config.Profiles) == 0 {
 		issues = appendIssue(issues, "profiles", ConfigValidationCodeRequired, "must include at least one profile")
 	}
@@ -202,6 +354,10 @@ func ValidateConfig(config Config) error {
 			issues = appendIssue(issues, profilePath+".default_jql", ConfigValidationCodeInvalidValue, "must not be only whitespace")
 		}
 
+		if profile.DefaultIssueType != "" && strings.TrimSpace(profile.DefaultIssueType) == "" {
+			issues = appendIssue(issues, profilePath+".default_issue_type", ConfigValidationCodeInvalidValue, "must not be only whitespace")
+		}
+
 		for _, targetStatus := range sortedKeys(profile.TransitionOverrides) {
 			override := profile.TransitionOverrides[targetStatus]
 			overridePath := profilePath + ".transition_overrides." + targetStatus
@@ -209,6 +365,14 @@ func ValidateConfig(config Config) error {
 		}
 
 		issues = append(issues, validateFieldConfig(profilePath+".field_config", profile.FieldConfig)...)
+		issues = append(issues, validateWritableFieldNames(profilePath+".writable_fields", profile.WritableFields)...)
+		issues = append(issues, validateWritableFieldNames(profilePath+".readonly_fields", profile.ReadonlyFields)...)
+
+		switch strings.TrimSpace(profile.DescriptionRiskPolicy) {
+		case "", DescriptionRiskPolicyBlock, DescriptionRiskPolicyWarn, DescriptionRiskPolicyAllow:
+		default:
+			issues = appendIssue(issues, profilePath+".description_risk_policy", ConfigValidationCodeInvalidValue, "must be one of: block, warn, allow")
+		}
 	}
 
 	if len(issues) == 0 {
@@ -219,6 +383,156 @@ func ValidateConfig(config Config) error {
 	return ConfigValidationError{Issues: issues}
 }
 
+// ResolveIssuesRootDir returns the workspace-relative issues root, falling
+// back to DefaultIssuesRootDir when the config does not override it.
+func ResolveIssuesRootDir(config Config) string {
+	trimmed := strings.TrimSpace(config.IssuesRoot)
+	if trimmed == "" {
+		return DefaultIssuesRootDir
+	}
+	return filepath.Clean(trimmed)
+}
+
+// ResolveFilenameStyle returns the configured filename style, defaulting to
+// key-summary.
+func ResolveFilenameStyle(config Config) string {
+	if style := strings.TrimSpace(config.FilenameStyle); style != "" {
+		return style
+	}
+	return FilenameStyleKeySummary
+}
+
+// ResolveLabelRenderStyle returns the configured label style, defaulting to
+// block.
+func ResolveLabelRenderStyle(config Config) string {
+	if style := strings.TrimSpace(config.LabelRenderStyle); style != "" {
+		return style
+	}
+	return LabelRenderStyleBlock
+}
+
+// ResolveFrontMatterOrder returns the configured leading front matter keys,
+// or nil to render in the default order.
+func ResolveFrontMatterOrder(config Config) []FrontMatterKey {
+	if len(config.FrontMatterOrder) == 0 {
+		return nil
+	}
+	keys := make([]FrontMatterKey, 0, len(config.FrontMatterOrder))
+	for _, key := range config.FrontMatterOrder {
+		keys = append(keys, FrontMatterKey(strings.TrimSpace(key)))
+	}
+	return keys
+}
+
+func validateFrontMatterOrder(issues []ConfigValidationIssue, order []string) []ConfigValidationIssue {
+	supported := make(map[FrontMatterKey]struct{})
+	for _, key := range AllFrontMatterKeys() {
+		supported[key] = struct{}{}
+	}
+	seen := make(map[FrontMatterKey]struct{}, len(order))
+	for index, raw := range order {
+		path := fmt.Sprintf("front_matter_order[%d]", index)
+		key := FrontMatterKey(strings.TrimSpace(raw))
+		if _, ok := supported[key]; !ok {
+			issues = appendIssue(issues, path, ConfigValidationCodeInvalidValue, fmt.Sprintf("%q is not a supported front matter key", raw))
+			continue
+		}
+		if _, duplicate := seen[key]; duplicate {
+			issues = appendIssue(issues, path, ConfigValidationCodeInvalidValue, fmt.Sprintf("%q is listed more than once", raw))
+			continue
+		}
+		seen[key] = struct{}{}
+	}
+	return issues
+}
+
+// CompileRedactionPatterns compiles the configured redaction patterns. Read
+// already rejects invalid ones, so an error here means the config was built
+// in memory without validation.
+func CompileRedactionPatterns(config Config) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(config.RedactionPatterns))
+	for index, pattern := range config.RedactionPatterns {
+		expression, err := compileRedactionPattern(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("redaction_patterns[%d]: %w", index, err)
+		}
+		compiled = append(compiled, expression)
+	}
+	return compiled, nil
+}
+
+// compileRedactionPattern rejects patterns that can match the empty string,
+// since those would splice the placeholder between every character.
+func compileRedactionPattern(pattern string) (*regexp.Regexp, error) {
+	if strings.TrimSpace(pattern) == "" {
+		return nil, fmt.Errorf("must not be empty")
+	}
+	expression, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("must be a valid regular expression: %v", err)
+	}
+	if expression.MatchString("") {
+		return nil, fmt.Errorf("must not match the empty string")
+	}
+	return expression, nil
+}
+
+// ResolveProxyURL returns the configured proxy, or nil when requests should
+// follow the proxy environment variables. Config loading already validated it.
+func ResolveProxyURL(config Config) *url.URL {
+	if strings.TrimSpace(config.ProxyURL) == "" {
+		return nil
+	}
+	proxy, err := parseProxyURL(config.ProxyURL)
+	if err != nil {
+		return nil
+	}
+	return proxy
+}
+
+func parseProxyURL(raw string) (*url.URL, error) {
+	proxy, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return nil, fmt.Errorf("must be a valid URL: %v", err)
+	}
+	switch proxy.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("must use the http, https, or socks5 scheme")
+	}
+	if proxy.Host == "" {
+		return nil, fmt.Errorf("must include a host")
+	}
+	return proxy, nil
+}
+
+// ResolveMarkdownFlavor returns the configured markdown flavor, defaulting
+// to CommonMark.
+func ResolveMarkdownFlavor(config Config) string {
+	if flavor := strings.TrimSpace(config.MarkdownFlavor); flavor != "" {
+		return flavor
+	}
+	return MarkdownFlavorCommonMark
+}
+
+// ResolveDescriptionRiskPolicy returns the profile's description risk
+// policy, defaulting to block.
+func ResolveDescriptionRiskPolicy(profile ProjectProfile) string {
+	if policy := strings.TrimSpace(profile.DescriptionRiskPolicy); policy != "" {
+		return policy
+	}
+	return DescriptionRiskPolicyBlock
+}
+
+// ResolveDraftKeyPrefix returns the configured draft key prefix, defaulting
+// to DefaultDraftKeyPrefix.
+func ResolveDraftKeyPrefix(config Config) string {
+	if prefix := strings.TrimSpace(config.DraftKeyPrefix); prefix != "" {
+		return prefix
+	}
+	return DefaultDraftKeyPrefix
+}
+
 // ResolveDefaultJQL returns default JQL using profile-over-global precedence.
 func ResolveDefaultJQL(config Config, profileName string) (string, JQLSource, bool) {
 	if profileName != "" {
@@ -236,8 +550,13 @@ func ResolveDefaultJQL(config Config, profileName string) (string, JQLSource, bo
 	return "", "", false
 }
 
+// TransitionOverrideWildcard keys the override used for any target status
+// without its own entry.
+const TransitionOverrideWildcard = "*"
+
 // ResolveTransitionSelectionForStatus resolves an override by target status key.
-// Lookup is case-insensitive, then precedence is applied.
+// Lookup is case-insensitive and falls back to the "*" override, then
+// precedence is applied.
 func ResolveTransitionSelectionForStatus(profile ProjectProfile, targetStatus string) TransitionSelection {
 	if override, ok := findTransitionOverride(profile.TransitionOverrides, targetStatus); ok {
 		return ResolveTransitionSelection(override, targetStatus)
@@ -278,6 +597,25 @@ func ResolveTransitionSelection(override TransitionOverride, targetStatus string
 	}
 }
 
+func validateIssuesRoot(root string) (string, bool) {
+	trimmed := strings.TrimSpace(root)
+	if trimmed == "" {
+		return "must not be only whitespace", false
+	}
+	if filepath.IsAbs(trimmed) || strings.HasPrefix(trimmed, "/") {
+		return "must be a path relative to the workspace", false
+	}
+
+	cleaned := filepath.ToSlash(filepath.Clean(trimmed))
+	if cleaned == "." {
+		return "must name a directory below the workspace", false
+	}
+	if cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "must not escape the workspace", false
+	}
+	return "", true
+}
+
 func validateFieldConfig(path string, fieldConfig FieldConfig) []ConfigValidationIssue {
 	issues := make([]ConfigValidationIssue, 0)
 
@@ -316,6 +654,51 @@ func validateFieldConfig(path string, fieldConfig FieldConfig) []ConfigValidatio
 	return issues
 }
 
+func validateWritableFieldNames(path string, names []string) []ConfigValidationIssue {
+	issues := make([]ConfigValidationIssue, 0)
+	for i, name := range names {
+		if !SupportedWritableField(JiraField(strings.ToLower(strings.TrimSpace(name)))) {
+			issues = appendIssue(issues, fmt.Sprintf("%s[%d]", path, i), ConfigValidationCodeInvalidValue, "must be one of: "+writableFieldNames())
+		}
+	}
+	return issues
+}
+
+func writableFieldNames() string {
+	names := make([]string, 0, len(WritableFieldContracts))
+	for _, contract := range WritableFieldContracts {
+		names = append(names, string(contract.Field))
+	}
+	return strings.Join(names, ", ")
+}
+
+// ResolveReadonlyFields returns the writable fields a profile denies to
+// push, or nil when the profile keeps the default contract.
+func ResolveReadonlyFields(profile ProjectProfile) map[JiraField]bool {
+	if len(profile.WritableFields) == 0 && len(profile.ReadonlyFields) == 0 {
+		return nil
+	}
+
+	allowed := make(map[JiraField]bool, len(WritableFieldContracts))
+	for _, contract := range WritableFieldContracts {
+		allowed[contract.Field] = len(profile.WritableFields) == 0
+	}
+	for _, name := range profile.WritableFields {
+		allowed[JiraField(strings.ToLower(strings.TrimSpace(name)))] = true
+	}
+	for _, name := range profile.ReadonlyFields {
+		allowed[JiraField(strings.ToLower(strings.TrimSpace(name)))] = false
+	}
+
+	denied := make(map[JiraField]bool)
+	for _, contract := range WritableFieldContracts {
+		if !allowed[contract.Field] {
+			denied[contract.Field] = true
+		}
+	}
+	return denied
+}
+
 func validateTransitionOverride(path string, targetStatus string, override TransitionOverride) []ConfigValidationIssue {
 	issues := make([]ConfigValidationIssue, 0)
 
@@ -394,6 +777,10 @@ func findTransitionOverride(overrides map[string]TransitionOverride, targetStatu
 		}
 	}
 
+	if override, ok := overrides[TransitionOverrideWildcard]; ok {
+		return override, true
+	}
+
 	return TransitionOverride{}, false
 }
 
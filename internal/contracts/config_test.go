@@ -192,6 +192,44 @@ func TestResolveTransitionSelectionForStatusCaseInsensitiveLookup(t *testing.T)
 	}
 }
 
+func TestResolveTransitionSelectionForStatusFallsBackToWildcard(t *testing.T) {
+	profile := ProjectProfile{
+		ProjectKey: "CORE",
+		TransitionOverrides: map[string]TransitionOverride{
+			"*":    {TransitionName: "Resolve"},
+			"done": {TransitionID: "31"},
+		},
+	}
+
+	selection := ResolveTransitionSelectionForStatus(profile, "Won't Do")
+	if selection.Kind != TransitionSelectionByName || selection.TransitionName != "Resolve" {
+		t.Fatalf("expected wildcard name selection, got %#v", selection)
+	}
+
+	selection = ResolveTransitionSelectionForStatus(profile, "Done")
+	if selection.Kind != TransitionSelectionByID || selection.TransitionID != "31" {
+		t.Fatalf("expected specific override to win over wildcard, got %#v", selection)
+	}
+}
+
+func TestValidateConfigRequiresSelectorOnWildcardOverride(t *testing.T) {
+	config := Config{
+		ConfigVersion: "1",
+		Profiles: map[string]ProjectProfile{
+			"core": {ProjectKey: "CORE", TransitionOverrides: map[string]TransitionOverride{"*": {}}},
+		},
+	}
+
+	err := ValidateConfig(config)
+	var validationErr ConfigValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if len(validationErr.Issues) != 1 || validationErr.Issues[0].Path != "profiles.core.transition_overrides.*" || validationErr.Issues[0].Code != ConfigValidationCodeRequired {
+		t.Fatalf("unexpected issues: %#v", validationErr.Issues)
+	}
+}
+
 func TestResolveTransitionSelectionForStatusFallsBackToDynamicTarget(t *testing.T) {
 	profile := ProjectProfile{ProjectKey: "CORE"}
 
@@ -203,3 +241,210 @@ func TestResolveTransitionSelectionForStatusFallsBackToDynamicTarget(t *testing.
 		t.Fatalf("unexpected candidates: %#v", selection.DynamicStatusCandidates)
 	}
 }
+
+func TestValidateConfigRejectsDraftKeyPrefixShapedLikeJiraKey(t *testing.T) {
+	config := Config{
+		ConfigVersion:  "1",
+		DraftKeyPrefix: "PROJ",
+		Profiles:       map[string]ProjectProfile{"core": {ProjectKey: "CORE"}},
+	}
+
+	err := ValidateConfig(config)
+	var validationErr ConfigValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if validationErr.Issues[0].Path != "draft_key_prefix" || validationErr.Issues[0].Code != ConfigValidationCodeInvalidValue {
+		t.Fatalf("unexpected issue: %#v", validationErr.Issues)
+	}
+
+	config.DraftKeyPrefix = "draft"
+	if err := ValidateConfig(config); err != nil {
+		t.Fatalf("expected draft prefix to be valid, got %v", err)
+	}
+	if got := ResolveDraftKeyPrefix(config); got != "draft" {
+		t.Fatalf("unexpected resolved prefix: %q", got)
+	}
+	if got := ResolveDraftKeyPrefix(Config{}); got != DefaultDraftKeyPrefix {
+		t.Fatalf("expected default prefix, got %q", got)
+	}
+}
+
+func TestValidateConfigRejectsUnsafeRedactionPatterns(t *testing.T) {
+	config := Config{
+		ConfigVersion:     "1",
+		RedactionPatterns: []string{`internal\.example\.com`, `(unclosed`, `x*`},
+		Profiles:          map[string]ProjectProfile{"core": {ProjectKey: "CORE"}},
+	}
+
+	err := ValidateConfig(config)
+	var validationErr ConfigValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if len(validationErr.Issues) != 2 || validationErr.Issues[0].Path != "redaction_patterns[1]" || validationErr.Issues[1].Path != "redaction_patterns[2]" {
+		t.Fatalf("unexpected issues: %#v", validationErr.Issues)
+	}
+
+	config.RedactionPatterns = config.RedactionPatterns[:1]
+	patterns, err := CompileRedactionPatterns(config)
+	if err != nil || len(patterns) != 1 {
+		t.Fatalf("expected one compiled pattern, got %v (%v)", patterns, err)
+	}
+}
+
+func TestValidateConfigChecksFrontMatterOrder(t *testing.T) {
+	config := Config{
+		ConfigVersion:    "1",
+		FrontMatterOrder: []string{"summary", "title", "key", "summary"},
+		Profiles:         map[string]ProjectProfile{"core": {ProjectKey: "CORE"}},
+	}
+
+	err := ValidateConfig(config)
+	var validationErr ConfigValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if len(validationErr.Issues) != 2 || validationErr.Issues[0].Path != "front_matter_order[1]" || validationErr.Issues[1].Path != "front_matter_order[3]" {
+		t.Fatalf("unexpected issues: %#v", validationErr.Issues)
+	}
+
+	config.FrontMatterOrder = []string{"summary", "key"}
+	if err := ValidateConfig(config); err != nil {
+		t.Fatalf("expected partial order to validate, got %v", err)
+	}
+	if order := ResolveFrontMatterOrder(config); len(order) != 2 || order[0] != FrontMatterKeySummary || order[1] != FrontMatterKeyKey {
+		t.Fatalf("unexpected resolved order: %#v", order)
+	}
+}
+
+func TestValidateConfigChecksProxyURL(t *testing.T) {
+	config := Config{
+		ConfigVersion: "1",
+		ProxyURL:      "ftp://proxy.corp.test",
+		Profiles:      map[string]ProjectProfile{"core": {ProjectKey: "CORE"}},
+	}
+
+	err := ValidateConfig(config)
+	var validationErr ConfigValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if len(validationErr.Issues) != 1 || validationErr.Issues[0].Path != "proxy_url" {
+		t.Fatalf("unexpected issues: %#v", validationErr.Issues)
+	}
+
+	config.ProxyURL = "http://proxy.corp.test:3128"
+	if err := ValidateConfig(config); err != nil {
+		t.Fatalf("expected valid proxy url, got %v", err)
+	}
+	if got := ResolveProxyURL(config); got == nil || got.Host != "proxy.corp.test:3128" {
+		t.Fatalf("unexpected resolved proxy: %v", got)
+	}
+	if got := ResolveProxyURL(Config{}); got != nil {
+		t.Fatalf("expected no proxy by default, got %v", got)
+	}
+}
+
+func TestValidateConfigChecksTLSPolicy(t *testing.T) {
+	config := Config{
+		ConfigVersion:   "1",
+		MinTLSVersion:   "1.0",
+		TLSCipherSuites: []string{"TLS_RSA_WITH_RC4_128_SHA"},
+		Profiles:        map[string]ProjectProfile{"core": {ProjectKey: "CORE"}},
+	}
+
+	err := ValidateConfig(config)
+	var validationErr ConfigValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if len(validationErr.Issues) != 2 || validationErr.Issues[0].Path != "min_tls_version" || validationErr.Issues[1].Path != "tls_cipher_suites" {
+		t.Fatalf("unexpected issues: %#v", validationErr.Issues)
+	}
+
+	config.MinTLSVersion = "1.2"
+	config.TLSCipherSuites = []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"}
+	if err := ValidateConfig(config); err != nil {
+		t.Fatalf("expected valid TLS policy, got %v", err)
+	}
+
+	config.MinTLSVersion = "1.3"
+	if err := ValidateConfig(config); err == nil {
+		t.Fatalf("expected cipher suites with a TLS 1.3 minimum to be rejected")
+	}
+}
+
+func TestValidateConfigChecksDescriptionRiskPolicy(t *testing.T) {
+	config := Config{
+		ConfigVersion: "1",
+		Profiles:      map[string]ProjectProfile{"core": {ProjectKey: "CORE", DescriptionRiskPolicy: "ignore"}},
+	}
+
+	err := ValidateConfig(config)
+	var validationErr ConfigValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if len(validationErr.Issues) != 1 || validationErr.Issues[0].Path != "profiles.core.description_risk_policy" {
+		t.Fatalf("unexpected issues: %#v", validationErr.Issues)
+	}
+
+	if got := ResolveDescriptionRiskPolicy(ProjectProfile{}); got != DescriptionRiskPolicyBlock {
+		t.Fatalf("expected block by default, got %q", got)
+	}
+}
+
+func TestValidateConfigRejectsUnknownWritableFieldNames(t *testing.T) {
+	config := Config{
+		ConfigVersion: "1",
+		Profiles: map[string]ProjectProfile{"core": {
+			ProjectKey:     "CORE",
+			WritableFields: []string{"summary", "status"},
+			ReadonlyFields: []string{"status", "reporter"},
+		}},
+	}
+
+	err := ValidateConfig(config)
+	var validationErr ConfigValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if len(validationErr.Issues) != 1 || validationErr.Issues[0].Path != "profiles.core.readonly_fields[1]" {
+		t.Fatalf("unexpected issues: %#v", validationErr.Issues)
+	}
+}
+
+func TestValidateConfigRejectsEscapingIssuesRoot(t *testing.T) {
+	for _, root := range []string{"/abs/issues", "../outside", "docs/../../outside", ".", "   "} {
+		config := Config{
+			ConfigVersion: "1",
+			IssuesRoot:    root,
+			Profiles:      map[string]ProjectProfile{"core": {ProjectKey: "CORE"}},
+		}
+
+		err := ValidateConfig(config)
+		var validationErr ConfigValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatalf("expected validation error for %q, got %v", root, err)
+		}
+		if validationErr.Issues[0].Path != "issues_root" || validationErr.Issues[0].Code != ConfigValidationCodeInvalidValue {
+			t.Fatalf("unexpected issue for %q: %#v", root, validationErr.Issues)
+		}
+	}
+
+	valid := Config{
+		ConfigVersion: "1",
+		IssuesRoot:    "docs/issues/",
+		Profiles:      map[string]ProjectProfile{"core": {ProjectKey: "CORE"}},
+	}
+	if err := ValidateConfig(valid); err != nil {
+		t.Fatalf("expected nested relative root to be valid, got %v", err)
+	}
+	if got := ResolveIssuesRootDir(valid); got != "docs/issues" {
+		t.Fatalf("unexpected resolved issues root: %q", got)
+	}
+	if got := ResolveIssuesRootDir(Config{}); got != DefaultIssuesRootDir {
+		t.Fatalf("expected default issues root, got %q", got)
+	}
+}
@@ -30,6 +30,25 @@ func TestFileFormatFrontMatterContract(t *testing.T) {
 	}
 }
 
+func TestDraftKeyRecognitionHonorsPrefixes(t *testing.T) {
+	for _, key := range []string{"L-1a2b3c", "draft-00ff", "D-12", "imp2-abc"} {
+		if !IsLocalDraftKey(key) {
+			t.Fatalf("expected %q to be a local draft key", key)
+		}
+	}
+	for _, key := range []string{"PROJ-12", "AB-1a2b", "L-XYZ", "-abc", "L1a2b"} {
+		if IsLocalDraftKey(key) {
+			t.Fatalf("expected %q not to be a local draft key", key)
+		}
+	}
+
+	for _, prefix := range []string{"PROJ", "AB", "1x", "dr-aft", ""} {
+		if ValidDraftKeyPrefix(prefix) {
+			t.Fatalf("expected prefix %q to be rejected", prefix)
+		}
+	}
+}
+
 func TestRawADFContract(t *testing.T) {
 	markdown := "# Title\n\n```jira-adf\n{\"version\":1,\"type\":\"doc\",\"content\":[]}\n```\n"
 
@@ -120,6 +139,14 @@ func TestFieldMappingAndNormalization(t *testing.T) {
 	if !reflect.DeepEqual(labels, expected) {
 		t.Fatalf("label normalization mismatch: got=%v want=%v", labels, expected)
 	}
+
+	preserved := NormalizeLabelsPreservingCase([]string{"FrontEnd", "  frontend", "FrontEnd ", "Bug", ""})
+	if want := []string{"Bug", "FrontEnd", "frontend"}; !reflect.DeepEqual(preserved, want) {
+		t.Fatalf("case-preserving label normalization mismatch: got=%v want=%v", preserved, want)
+	}
+	if got := NormalizeLabelsWithCase([]string{"FrontEnd"}, false); !reflect.DeepEqual(got, []string{"frontend"}) {
+		t.Fatalf("expected default mode to lowercase, got %v", got)
+	}
 }
 
 func TestReasonCodesStableAndUnique(t *testing.T) {
@@ -133,6 +160,12 @@ func TestReasonCodesStableAndUnique(t *testing.T) {
 			t.Fatalf("duplicate reason code: %s", code)
 		}
 		seen[code] = struct{}{}
+		if ReasonCodeMeaning[code] == "" {
+			t.Fatalf("reason code %s has no documented meaning", code)
+		}
+	}
+	if len(ReasonCodeMeaning) != len(StableReasonCodes) {
+		t.Fatalf("reason-code meanings must cover exactly the stable taxonomy")
 	}
 
 	if !IsStableReasonCode(ReasonCodeUnsupportedFieldIgnored) {
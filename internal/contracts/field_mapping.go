@@ -1,8 +1,10 @@
 package contracts
 
 import (
+	"regexp"
 	"sort"
 	"strings"
+	"time"
 )
 
 type JiraField string
@@ -14,16 +16,59 @@ const (
 	JiraFieldAssignee    JiraField = "assignee"
 	JiraFieldPriority    JiraField = "priority"
 	JiraFieldStatus      JiraField = "status"
-
-	JiraFieldKey          JiraField = "key"
-	JiraFieldIssueType    JiraField = "issue_type"
-	JiraFieldReporter     JiraField = "reporter"
-	JiraFieldCreatedAt    JiraField = "created_at"
-	JiraFieldUpdatedAt    JiraField = "updated_at"
-	JiraFieldSyncedAt     JiraField = "synced_at"
-	JiraFieldCustomFields JiraField = "custom_fields"
+	JiraFieldEnvironment JiraField = "environment"
+
+	JiraFieldKey           JiraField = "key"
+	JiraFieldIssueType     JiraField = "issue_type"
+	JiraFieldReporter      JiraField = "reporter"
+	JiraFieldSecurityLevel JiraField = "security_level"
+	JiraFieldCreatedAt     JiraField = "created_at"
+	JiraFieldUpdatedAt     JiraField = "updated_at"
+	JiraFieldSyncedAt      JiraField = "synced_at"
+	JiraFieldCustomFields  JiraField = "custom_fields"
+	JiraFieldComments      JiraField = "comments"
 )
 
+// AssigneeSelf is the assignee shorthand that push resolves to the account
+// the run authenticates as.
+const AssigneeSelf = "me"
+
+// IsAssigneeSelf reports whether an assignee value is the "me" shorthand.
+func IsAssigneeSelf(value string) bool {
+	return strings.EqualFold(strings.TrimSpace(value), AssigneeSelf)
+}
+
+// AssigneeAccountIDPrefix marks an assignee value as a literal accountId,
+// such as "@accountId:557058:f58131cb", that push sends without a user
+// lookup.
+const AssigneeAccountIDPrefix = "@accountId:"
+
+// accountIDPattern is deliberately loose: Jira accountIds come in several
+// shapes, but none contain whitespace, "@", or ".", so emails and display
+// names are still caught.
+var accountIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9:_-]{0,127}$`)
+
+// ParseAssigneeAccountID returns the accountId an assignee value names
+// literally, and whether it names one. A value does when it carries
+// AssigneeAccountIDPrefix or when valuesAreAccountIDs is set, which is the
+// profile's assignee_is_account_id setting. Empty values and the "me"
+// shorthand are never literal accountIds.
+func ParseAssigneeAccountID(value string, valuesAreAccountIDs bool) (string, bool) {
+	trimmed := strings.TrimSpace(value)
+	if rest, ok := strings.CutPrefix(trimmed, AssigneeAccountIDPrefix); ok {
+		return strings.TrimSpace(rest), true
+	}
+	if trimmed == "" || IsAssigneeSelf(trimmed) || !valuesAreAccountIDs {
+		return "", false
+	}
+	return trimmed, true
+}
+
+// IsPlausibleAccountID reports whether value could be a Jira accountId.
+func IsPlausibleAccountID(value string) bool {
+	return accountIDPattern.MatchString(value)
+}
+
 type SyncDirection string
 
 const (
@@ -40,6 +85,7 @@ const (
 	NormalizationLabelsCanonicalSet   NormalizationRule = "labels_canonical_set"
 	NormalizationTrimEmptyToNull      NormalizationRule = "trim_empty_to_null"
 	NormalizationTrimAndTitleCase     NormalizationRule = "trim_and_title_case"
+	NormalizationTimestampUTC         NormalizationRule = "timestamp_utc"
 )
 
 type UnsupportedFieldPolicy string
@@ -73,15 +119,17 @@ var WritableFieldContracts = []FieldContract{
 	{Field: JiraFieldAssignee, Direction: SyncDirectionBidirectional, Normalization: NormalizationTrimEmptyToNull, UnsupportedPolicy: UnsupportedFieldPolicyWarnAndIgnore},
 	{Field: JiraFieldPriority, Direction: SyncDirectionBidirectional, Normalization: NormalizationTrimAndTitleCase, UnsupportedPolicy: UnsupportedFieldPolicyWarnAndIgnore},
 	{Field: JiraFieldStatus, Direction: SyncDirectionBidirectional, Normalization: NormalizationTrimOuterWhitespace, UnsupportedPolicy: UnsupportedFieldPolicyWarnAndIgnore},
+	{Field: JiraFieldEnvironment, Direction: SyncDirectionBidirectional, Normalization: NormalizationNormalizeLineEndings, UnsupportedPolicy: UnsupportedFieldPolicyWarnAndIgnore},
 }
 
 var ReadOnlyFieldContracts = []FieldContract{
 	{Field: JiraFieldKey, Direction: SyncDirectionReadOnly, Normalization: NormalizationTrimOuterWhitespace, UnsupportedPolicy: UnsupportedFieldPolicyWarnAndIgnore},
 	{Field: JiraFieldIssueType, Direction: SyncDirectionReadOnly, Normalization: NormalizationTrimOuterWhitespace, UnsupportedPolicy: UnsupportedFieldPolicyWarnAndIgnore},
 	{Field: JiraFieldReporter, Direction: SyncDirectionReadOnly, Normalization: NormalizationTrimOuterWhitespace, UnsupportedPolicy: UnsupportedFieldPolicyWarnAndIgnore},
-	{Field: JiraFieldCreatedAt, Direction: SyncDirectionReadOnly, Normalization: NormalizationIdentity, UnsupportedPolicy: UnsupportedFieldPolicyWarnAndIgnore},
-	{Field: JiraFieldUpdatedAt, Direction: SyncDirectionReadOnly, Normalization: NormalizationIdentity, UnsupportedPolicy: UnsupportedFieldPolicyWarnAndIgnore},
-	{Field: JiraFieldSyncedAt, Direction: SyncDirectionReadOnly, Normalization: NormalizationIdentity, UnsupportedPolicy: UnsupportedFieldPolicyWarnAndIgnore},
+	{Field: JiraFieldSecurityLevel, Direction: SyncDirectionReadOnly, Normalization: NormalizationTrimOuterWhitespace, UnsupportedPolicy: UnsupportedFieldPolicyWarnAndIgnore},
+	{Field: JiraFieldCreatedAt, Direction: SyncDirectionReadOnly, Normalization: NormalizationTimestampUTC, UnsupportedPolicy: UnsupportedFieldPolicyWarnAndIgnore},
+	{Field: JiraFieldUpdatedAt, Direction: SyncDirectionReadOnly, Normalization: NormalizationTimestampUTC, UnsupportedPolicy: UnsupportedFieldPolicyWarnAndIgnore},
+	{Field: JiraFieldSyncedAt, Direction: SyncDirectionReadOnly, Normalization: NormalizationTimestampUTC, UnsupportedPolicy: UnsupportedFieldPolicyWarnAndIgnore},
 	{Field: JiraFieldCustomFields, Direction: SyncDirectionReadOnly, Normalization: NormalizationIdentity, UnsupportedPolicy: UnsupportedFieldPolicyWarnAndIgnore},
 }
 
@@ -123,16 +171,62 @@ func NormalizeSingleValue(rule NormalizationRule, value string) string {
 			return ""
 		}
 		return strings.ToUpper(trimmed[:1]) + trimmed[1:]
+	case NormalizationTimestampUTC:
+		return normalizeTimestamp(value)
 	default:
 		return value
 	}
 }
 
+// timestampLayouts covers RFC3339 plus Jira's "+0000" offset form with and
+// without milliseconds.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999-0700",
+	"2006-01-02T15:04:05-0700",
+}
+
+// normalizeTimestamp renders parseable timestamps as RFC3339 UTC with
+// second precision. Anything else is kept verbatim (trimmed) so unexpected
+// remote formats are never lost.
+func normalizeTimestamp(value string) string {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		return ""
+	}
+	for _, layout := range timestampLayouts {
+		if parsed, err := time.Parse(layout, trimmed); err == nil {
+			return parsed.UTC().Format(time.RFC3339)
+		}
+	}
+	return trimmed
+}
+
+// NormalizeLabels trims, lowercases, dedupes, and sorts labels.
 func NormalizeLabels(values []string) []string {
+	return normalizeLabels(values, true)
+}
+
+// NormalizeLabelsPreservingCase is NormalizeLabels without lowercasing, so
+// "FrontEnd" and "frontend" stay distinct as Jira may treat them.
+func NormalizeLabelsPreservingCase(values []string) []string {
+	return normalizeLabels(values, false)
+}
+
+// NormalizeLabelsWithCase picks between the two by a profile's
+// preserve_label_case setting.
+func NormalizeLabelsWithCase(values []string, preserveCase bool) []string {
+	return normalizeLabels(values, !preserveCase)
+}
+
+func normalizeLabels(values []string, lowercase bool) []string {
 	canonical := make([]string, 0, len(values))
 	seen := make(map[string]struct{})
 	for _, value := range values {
-		label := strings.ToLower(strings.TrimSpace(value))
+		label := strings.TrimSpace(value)
+		if lowercase {
+			label = strings.ToLower(label)
+		}
 		if label == "" {
 			continue
 		}
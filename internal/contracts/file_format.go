@@ -12,19 +12,66 @@ const (
 	FrontMatterDelimiter = "---"
 
 	RawADFFenceLanguage = "jira-adf"
-	RawADFDocType       = "doc"
-	RawADFDocVersion    = 1
+	// RawADFEnvironmentLabel tags the environment block as
+	// "```jira-adf environment" so it never matches the description fence.
+	RawADFEnvironmentLabel = "environment"
+	// EnvironmentSectionMarker starts the environment section; everything
+	// after it, up to the comments section, belongs to the Jira environment
+	// field.
+	EnvironmentSectionMarker = "<!-- jira:environment -->"
+	// CommentsSectionMarker starts the read-only comments section pull
+	// writes last when include_metadata is set. push never sends it.
+	CommentsSectionMarker = "<!-- jira:comments (read-only) -->"
+	RawADFDocType         = "doc"
+	RawADFDocVersion      = 1
+
+	// DefaultDraftKeyPrefix is used for new drafts unless draft_key_prefix
+	// is configured.
+	DefaultDraftKeyPrefix = "L"
+
+	// DraftMarkerLabelPrefix tags an issue created from a draft until the
+	// publish completes, so a retried publish can find it by label.
+	DraftMarkerLabelPrefix = "jira-issue-sync-draft-"
 )
 
-// Contracted key formats.
+// Contracted key formats. Local draft keys are <prefix>-<hex>; use
+// IsLocalDraftKey rather than LocalDraftKeyPattern alone, since the pattern
+// also matches Jira keys.
 var (
-	JiraIssueKeyPattern  = regexp.MustCompile(`^[A-Z][A-Z0-9]+-[0-9]+$`)
-	LocalDraftKeyPattern = regexp.MustCompile(`^L-[0-9a-f]+$`)
+	JiraIssueKeyPattern   = regexp.MustCompile(`^[A-Z][A-Z0-9]+-[0-9]+$`)
+	LocalDraftKeyPattern  = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9]*)-[0-9a-f]+$`)
+	DraftKeyPrefixPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*$`)
+
+	jiraProjectKeyPattern = regexp.MustCompile(`^[A-Z][A-Z0-9]+$`)
 )
 
+// ValidDraftKeyPrefix reports whether prefix can be used for draft keys. A
+// prefix shaped like a Jira project key (two or more uppercase letters and
+// digits) is rejected so draft keys can never collide with Jira keys.
+func ValidDraftKeyPrefix(prefix string) bool {
+	return DraftKeyPrefixPattern.MatchString(prefix) && !jiraProjectKeyPattern.MatchString(prefix)
+}
+
+// IsLocalDraftKey reports whether key is a local draft key under any valid
+// prefix, so drafts keep being recognized after draft_key_prefix changes.
+func IsLocalDraftKey(key string) bool {
+	match := LocalDraftKeyPattern.FindStringSubmatch(key)
+	return len(match) == 2 && ValidDraftKeyPrefix(match[1])
+}
+
+// DraftMarkerLabel is the Jira label that identifies the issue created from
+// the local draft localKey. Draft keys repeat across workspaces and after
+// renumbering, so nonce, drawn once per publish, keeps the label unique.
+func DraftMarkerLabel(localKey string, nonce string) string {
+	return DraftMarkerLabelPrefix + localKey + "-" + nonce
+}
+
 // RawADFFencedBlockPattern matches exactly one embedded raw ADF fenced block payload.
 var RawADFFencedBlockPattern = regexp.MustCompile("(?s)```jira-adf[ \\t]*\\n(\\{.*?\\})\\n```")
 
+// RawADFEnvironmentBlockPattern matches the labeled environment raw ADF block.
+var RawADFEnvironmentBlockPattern = regexp.MustCompile("(?s)```jira-adf[ \\t]+environment[ \\t]*\\n(\\{.*?\\})\\n```")
+
 type FrontMatterKey string
 
 const (
@@ -37,6 +84,7 @@ const (
 	FrontMatterKeyAssignee         FrontMatterKey = "assignee"
 	FrontMatterKeyLabels           FrontMatterKey = "labels"
 	FrontMatterKeyReporter         FrontMatterKey = "reporter"
+	FrontMatterKeySecurityLevel    FrontMatterKey = "security_level"
 	FrontMatterKeyCreatedAt        FrontMatterKey = "created_at"
 	FrontMatterKeyUpdatedAt        FrontMatterKey = "updated_at"
 	FrontMatterKeySyncedAt         FrontMatterKey = "synced_at"
@@ -59,6 +107,7 @@ var OptionalFrontMatterKeys = []FrontMatterKey{
 	FrontMatterKeyAssignee,
 	FrontMatterKeyLabels,
 	FrontMatterKeyReporter,
+	FrontMatterKeySecurityLevel,
 	FrontMatterKeyCreatedAt,
 	FrontMatterKeyUpdatedAt,
 	FrontMatterKeySyncedAt,
@@ -19,6 +19,13 @@ const (
 	ReasonCodeLockStaleRecovered           ReasonCode = "lock_stale_recovered"
 	ReasonCodeDryRunNoWrite                ReasonCode = "dry_run_no_write"
 	ReasonCodeTempIDRewriteOutOfScope      ReasonCode = "temp_id_rewrite_out_of_scope"
+	ReasonCodePullDuplicateDropped         ReasonCode = "pull_duplicate_dropped"
+	ReasonCodeRateLimited                  ReasonCode = "rate_limited"
+	ReasonCodeDuplicateLocalIssue          ReasonCode = "duplicate_local_issue"
+	ReasonCodeFieldReadonlySkipped         ReasonCode = "field_readonly_skipped"
+	ReasonCodeSummaryTooLong               ReasonCode = "summary_too_long"
+	ReasonCodeADFUnknownNode               ReasonCode = "adf_unknown_node"
+	ReasonCodeDescriptionADFBlockStale     ReasonCode = "description_adf_block_stale"
 )
 
 // StableReasonCodes freezes the contract taxonomy and ordering.
@@ -38,6 +45,39 @@ var StableReasonCodes = []ReasonCode{
 	ReasonCodeLockStaleRecovered,
 	ReasonCodeDryRunNoWrite,
 	ReasonCodeTempIDRewriteOutOfScope,
+	ReasonCodePullDuplicateDropped,
+	ReasonCodeRateLimited,
+	ReasonCodeDuplicateLocalIssue,
+	ReasonCodeFieldReadonlySkipped,
+	ReasonCodeSummaryTooLong,
+	ReasonCodeADFUnknownNode,
+	ReasonCodeDescriptionADFBlockStale,
+}
+
+// ReasonCodeMeaning documents each stable reason code for `explain`.
+var ReasonCodeMeaning = map[ReasonCode]string{
+	ReasonCodeConflictFieldChangedBoth:     "a field changed both locally and in Jira since the last sync",
+	ReasonCodeConflictBaseSnapshotMissing:  "no original snapshot exists to compute a three-way merge",
+	ReasonCodeDescriptionRiskyBlocked:      "description push was blocked because conversion could lose content",
+	ReasonCodeDescriptionADFBlockMissing:   "the raw ADF block is missing from the issue file",
+	ReasonCodeDescriptionADFBlockMalformed: "the raw ADF block is not valid ADF JSON",
+	ReasonCodeTransitionAmbiguous:          "more than one Jira transition matches the target status",
+	ReasonCodeTransitionUnavailable:        "no Jira transition reaches the target status",
+	ReasonCodeUnsupportedFieldIgnored:      "a field is not supported for push and was ignored",
+	ReasonCodeValidationFailed:             "local or remote data failed validation",
+	ReasonCodeAuthFailed:                   "Jira rejected the credentials",
+	ReasonCodeTransportError:               "the Jira request failed at the network or HTTP level",
+	ReasonCodeLockAcquireFailed:            "the workspace lock could not be acquired",
+	ReasonCodeLockStaleRecovered:           "a stale workspace lock was recovered",
+	ReasonCodeDryRunNoWrite:                "dry-run mode skipped a write",
+	ReasonCodeTempIDRewriteOutOfScope:      "a temporary draft ID reference was outside the rewrite scope",
+	ReasonCodePullDuplicateDropped:         "the same issue was returned on more than one search page",
+	ReasonCodeRateLimited:                  "Jira kept rate-limiting the request after retries were exhausted",
+	ReasonCodeDuplicateLocalIssue:          "another local file holds the same issue key; this copy was ignored",
+	ReasonCodeFieldReadonlySkipped:         "a local edit to a field the profile makes read-only was not pushed",
+	ReasonCodeSummaryTooLong:               "the local summary is longer than Jira accepts and was not pushed",
+	ReasonCodeADFUnknownNode:               "Jira ADF uses a node type this tool does not know; the markdown may omit it but the raw ADF keeps it",
+	ReasonCodeDescriptionADFBlockStale:     "the raw ADF block no longer matches the markdown beside it; push uses the markdown",
 }
 
 func IsStableReasonCode(code ReasonCode) bool {
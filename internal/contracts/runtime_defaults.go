@@ -22,6 +22,19 @@ const (
 	DefaultRetryBaseBackoff = 500 * time.Millisecond
 )
 
+// Upper bounds for user-supplied pull tuning, matching the perf harness envelope.
+const (
+	MaxPullPageSize    = 200
+	MaxPullConcurrency = 16
+)
+
+// MaxSummaryLength is the longest summary, in characters, Jira accepts.
+const MaxSummaryLength = 255
+
+// MaxSearchPageSize caps maxResults on a single search request; larger
+// requests are sent with this value instead.
+const MaxSearchPageSize = MaxPullPageSize
+
 const (
 	DefaultLockStaleAfter     = 15 * time.Minute
 	DefaultLockAcquireTimeout = 30 * time.Second
@@ -31,17 +44,21 @@ const (
 type CommandName string
 
 const (
-	CommandInit   CommandName = "init"
-	CommandPull   CommandName = "pull"
-	CommandPush   CommandName = "push"
-	CommandSync   CommandName = "sync"
-	CommandStatus CommandName = "status"
-	CommandList   CommandName = "list"
-	CommandNew    CommandName = "new"
-	CommandEdit   CommandName = "edit"
-	CommandView   CommandName = "view"
-	CommandDiff   CommandName = "diff"
-	CommandFields CommandName = "fields"
+	CommandInit    CommandName = "init"
+	CommandPull    CommandName = "pull"
+	CommandPush    CommandName = "push"
+	CommandSync    CommandName = "sync"
+	CommandStatus  CommandName = "status"
+	CommandList    CommandName = "list"
+	CommandNew     CommandName = "new"
+	CommandEdit    CommandName = "edit"
+	CommandView    CommandName = "view"
+	CommandDiff    CommandName = "diff"
+	CommandFields  CommandName = "fields"
+	CommandFsck    CommandName = "fsck"
+	CommandExplain CommandName = "explain"
+	CommandConfig  CommandName = "config"
+	CommandSchema  CommandName = "schema"
 )
 
 type LockRequirement string
@@ -53,17 +70,21 @@ const (
 
 // CommandLockPolicy freezes lock requirements for each MVP command.
 var CommandLockPolicy = map[CommandName]LockRequirement{
-	CommandInit:   LockRequirementExclusive,
-	CommandPull:   LockRequirementExclusive,
-	CommandPush:   LockRequirementExclusive,
-	CommandSync:   LockRequirementExclusive,
-	CommandNew:    LockRequirementExclusive,
-	CommandEdit:   LockRequirementExclusive,
-	CommandStatus: LockRequirementNone,
-	CommandList:   LockRequirementNone,
-	CommandView:   LockRequirementNone,
-	CommandDiff:   LockRequirementNone,
-	CommandFields: LockRequirementNone,
+	CommandInit:    LockRequirementExclusive,
+	CommandPull:    LockRequirementExclusive,
+	CommandPush:    LockRequirementExclusive,
+	CommandSync:    LockRequirementExclusive,
+	CommandNew:     LockRequirementExclusive,
+	CommandEdit:    LockRequirementExclusive,
+	CommandStatus:  LockRequirementNone,
+	CommandList:    LockRequirementNone,
+	CommandView:    LockRequirementNone,
+	CommandDiff:    LockRequirementNone,
+	CommandFields:  LockRequirementNone,
+	CommandFsck:    LockRequirementNone,
+	CommandExplain: LockRequirementNone,
+	CommandConfig:  LockRequirementNone,
+	CommandSchema:  LockRequirementNone,
 }
 
 func RequiresLock(command CommandName) bool {
@@ -6,9 +6,10 @@ import (
 )
 
 // TempIDBodyReferencePattern matches markdown-local temp issue references that are eligible for rewrite.
-var TempIDBodyReferencePattern = regexp.MustCompile(`#(L-[0-9a-f]+)\b`)
+var TempIDBodyReferencePattern = regexp.MustCompile(`#([A-Za-z][A-Za-z0-9]*-[0-9a-f]+)\b`)
 
-// RewriteTempIDReferences rewrites markdown-local #L-<hex> references to canonical Jira keys.
+// RewriteTempIDReferences rewrites markdown-local #<prefix>-<hex> draft references to canonical Jira keys,
+// or to other draft keys when drafts are renumbered.
 //
 // Only reference-style tokens outside embedded raw ADF fenced blocks are rewritten.
 func RewriteTempIDReferences(markdown string, replacements map[string]string) string {
@@ -47,13 +48,16 @@ func rewriteTempIDSegment(segment string, replacements map[string]string) string
 
 	return TempIDBodyReferencePattern.ReplaceAllStringFunc(segment, func(match string) string {
 		localKey := strings.TrimPrefix(match, "#")
+		if !IsLocalDraftKey(localKey) {
+			return match
+		}
 		replacement, ok := replacements[localKey]
 		if !ok {
 			return match
 		}
 
 		replacement = strings.TrimSpace(replacement)
-		if !JiraIssueKeyPattern.MatchString(replacement) {
+		if !JiraIssueKeyPattern.MatchString(replacement) && !IsLocalDraftKey(replacement) {
 			return match
 		}
 
@@ -0,0 +1,54 @@
+package contracts
+
+import (
+	"crypto/tls"
+	"fmt"
+	"strings"
+)
+
+// DefaultMinTLSVersion is the lowest TLS version used for Jira requests when
+// min_tls_version is not set.
+const DefaultMinTLSVersion = "1.2"
+
+var supportedTLSVersions = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// ParseMinTLSVersion maps a configured minimum such as "1.3" to its
+// crypto/tls constant. Empty means DefaultMinTLSVersion. Versions below 1.2
+// are rejected rather than silently raised.
+func ParseMinTLSVersion(raw string) (uint16, error) {
+	name := strings.TrimSpace(raw)
+	if name == "" {
+		name = DefaultMinTLSVersion
+	}
+	version, ok := supportedTLSVersions[name]
+	if !ok {
+		return 0, fmt.Errorf("unsupported TLS version %q; must be one of: 1.2, 1.3", raw)
+	}
+	return version, nil
+}
+
+// ParseCipherSuites maps IANA cipher suite names, such as
+// TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, to their IDs. Only suites Go
+// considers secure are accepted. The list only restricts TLS 1.2; TLS 1.3
+// suites are not configurable.
+func ParseCipherSuites(names []string) ([]uint16, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	known := make(map[string]uint16)
+	for _, suite := range tls.CipherSuites() {
+		known[suite.Name] = suite.ID
+	}
+	ids := make([]uint16, 0, len(names))
+	for _, name := range names {
+		id, ok := known[strings.TrimSpace(name)]
+		if !ok {
+			return nil, fmt.Errorf("unsupported or insecure cipher suite %q", name)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -12,6 +12,7 @@ type ErrorCode string
 const (
 	ErrorCodeMalformedADFJSON   ErrorCode = "malformed_adf_json"
 	ErrorCodeInvalidADFEnvelope ErrorCode = "invalid_adf_envelope"
+	ErrorCodeInvalidADFNode     ErrorCode = "invalid_adf_node"
 )
 
 // Error is a typed conversion error with stable reason-code mapping.
@@ -3,6 +3,7 @@ package converter
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"strings"
 
 	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
@@ -43,3 +44,105 @@ func ValidateAndCanonicalizeRawADF(payload string) (string, error) {
 
 	return compact.String(), nil
 }
+
+// Severity separates ADF problems that stop a conversion from ones that
+// only deserve a warning.
+type Severity string
+
+const (
+	// SeverityError is invalid JSON or a broken node tree.
+	SeverityError Severity = "error"
+	// SeverityWarning is well-formed ADF using a node type this tool does
+	// not know, usually one Jira added after this release.
+	SeverityWarning Severity = "warning"
+)
+
+// knownADFNodeTypes lists the ADF node types of the published schema.
+var knownADFNodeTypes = map[string]bool{
+	"doc": true, "paragraph": true, "text": true, "heading": true, "hardBreak": true, "rule": true,
+	"bulletList": true, "orderedList": true, "listItem": true, "taskList": true, "taskItem": true,
+	"decisionList": true, "decisionItem": true, "blockquote": true, "codeBlock": true, "panel": true,
+	"table": true, "tableRow": true, "tableHeader": true, "tableCell": true,
+	"media": true, "mediaSingle": true, "mediaGroup": true, "mediaInline": true,
+	"mention": true, "emoji": true, "date": true, "status": true, "placeholder": true,
+	"inlineCard": true, "blockCard": true, "embedCard": true, "expand": true, "nestedExpand": true,
+	"layoutSection": true, "layoutColumn": true,
+	"extension": true, "inlineExtension": true, "bodiedExtension": true, "multiBodiedExtension": true, "extensionFrame": true,
+}
+
+// ValidateRawADFWithSeverity runs ValidateAndCanonicalizeRawADF and then
+// walks the node tree. A node that is not an object, has no type, or has
+// non-array content is a SeverityError returned as *Error. Unknown node
+// types are SeverityWarning: they come back as risk signals, one per type
+// in first-seen order, and the canonical payload is still returned.
+func ValidateRawADFWithSeverity(payload string) (string, []RiskSignal, error) {
+	canonical, err := ValidateAndCanonicalizeRawADF(payload)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var root any
+	if err := json.Unmarshal([]byte(canonical), &root); err != nil {
+		return "", nil, &Error{
+			Code:       ErrorCodeMalformedADFJSON,
+			ReasonCode: contracts.ReasonCodeDescriptionADFBlockMalformed,
+			Message:    "failed to decode ADF node tree",
+			Err:        err,
+		}
+	}
+
+	unknown := make([]string, 0)
+	seen := make(map[string]bool)
+	if err := walkADFNode(root, "doc", func(nodeType string) {
+		if !knownADFNodeTypes[nodeType] && !seen[nodeType] {
+			seen[nodeType] = true
+			unknown = append(unknown, nodeType)
+		}
+	}); err != nil {
+		return "", nil, err
+	}
+
+	var risks []RiskSignal
+	for _, nodeType := range unknown {
+		risks = append(risks, RiskSignal{
+			ReasonCode: contracts.ReasonCodeADFUnknownNode,
+			Message:    fmt.Sprintf("unknown ADF node type %q; its content may be missing from the markdown, the raw ADF block keeps it", nodeType),
+		})
+	}
+	return canonical, risks, nil
+}
+
+func walkADFNode(raw any, path string, visit func(string)) error {
+	node, ok := raw.(map[string]any)
+	if !ok {
+		return invalidADFNode(path, "is not an object")
+	}
+	nodeType, ok := node["type"].(string)
+	if !ok || strings.TrimSpace(nodeType) == "" {
+		return invalidADFNode(path, "has no type")
+	}
+	visit(nodeType)
+
+	content, present := node["content"]
+	if !present {
+		return nil
+	}
+	children, ok := content.([]any)
+	if !ok {
+		return invalidADFNode(path, "has content that is not an array")
+	}
+	for index, child := range children {
+		if err := walkADFNode(child, fmt.Sprintf("%s.content[%d]", path, index), visit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func invalidADFNode(path string, problem string) error {
+	return &Error{
+		Code:       ErrorCodeInvalidADFNode,
+		ReasonCode: contracts.ReasonCodeDescriptionADFBlockMalformed,
+		Message:    fmt.Sprintf("ADF node at %s %s", path, problem),
+	}
+}
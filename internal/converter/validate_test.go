@@ -2,6 +2,7 @@ package converter
 
 import (
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
@@ -49,3 +50,31 @@ func TestValidateAndCanonicalizeRawADFReturnsTypedErrorForInvalidEnvelope(t *tes
 		t.Fatalf("expected invalid ADF envelope error, got: %v", err)
 	}
 }
+
+func TestValidateRawADFWithSeverityWarnsOnUnknownNodeType(t *testing.T) {
+	payload := `{"version":1,"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"hi"}]},{"type":"futureWidget","attrs":{"id":1}},{"type":"futureWidget"}]}`
+
+	canonical, risks, err := ValidateRawADFWithSeverity(payload)
+	if err != nil {
+		t.Fatalf("expected unknown node type to be a warning, got error: %v", err)
+	}
+	if canonical != payload {
+		t.Fatalf("unexpected canonical payload: %s", canonical)
+	}
+	if len(risks) != 1 || risks[0].ReasonCode != contracts.ReasonCodeADFUnknownNode || !strings.Contains(risks[0].Message, `"futureWidget"`) {
+		t.Fatalf("expected one unknown-node warning, got %#v", risks)
+	}
+}
+
+func TestValidateRawADFWithSeverityRejectsBrokenNodeTree(t *testing.T) {
+	for _, payload := range []string{
+		`{"version":1,"type":"doc","content":["text"]}`,
+		`{"version":1,"type":"doc","content":[{"content":[]}]}`,
+		`{"version":1,"type":"doc","content":[{"type":"paragraph","content":{"type":"text"}}]}`,
+	} {
+		_, _, err := ValidateRawADFWithSeverity(payload)
+		if !IsErrorCode(err, ErrorCodeInvalidADFNode) {
+			t.Fatalf("expected invalid node error for %s, got %v", payload, err)
+		}
+	}
+}
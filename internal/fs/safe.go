@@ -14,6 +14,13 @@ var (
 	ErrPathEscapes = errors.New("path escapes root")
 )
 
+// writeTempContents writes data into the temp file backing an atomic write.
+// It is a variable so tests can simulate a failure partway through a write.
+var writeTempContents = func(file *os.File, data []byte) error {
+	_, err := file.Write(data)
+	return err
+}
+
 // SafeFS constrains all file operations to a single root directory.
 type SafeFS struct {
 	root string
@@ -81,6 +88,8 @@ func (s *SafeFS) EnsureDir(path string, perm os.FileMode) error {
 	return os.MkdirAll(resolved, perm)
 }
 
+// WriteFileAtomic writes data to a temp file in the target directory, syncs
+// it, and renames it into place so readers never observe a partial file.
 func (s *SafeFS) WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
 	resolved, err := s.Resolve(path)
 	if err != nil {
@@ -98,7 +107,7 @@ func (s *SafeFS) WriteFileAtomic(path string, data []byte, perm os.FileMode) err
 	}
 	defer func() { _ = os.Remove(temp.Name()) }()
 
-	if _, err := temp.Write(data); err != nil {
+	if err := writeTempContents(temp, data); err != nil {
 		_ = temp.Close()
 		return err
 	}
@@ -106,11 +115,30 @@ func (s *SafeFS) WriteFileAtomic(path string, data []byte, perm os.FileMode) err
 		_ = temp.Close()
 		return err
 	}
+	if err := temp.Sync(); err != nil {
+		_ = temp.Close()
+		return err
+	}
 	if err := temp.Close(); err != nil {
 		return err
 	}
 
-	return os.Rename(temp.Name(), resolved)
+	if err := os.Rename(temp.Name(), resolved); err != nil {
+		return err
+	}
+	syncDir(dir)
+	return nil
+}
+
+// syncDir flushes directory metadata so a completed rename survives a crash.
+// Not every platform supports syncing directories, so failures are ignored.
+func syncDir(dir string) {
+	handle, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	_ = handle.Sync()
+	_ = handle.Close()
 }
 
 func (s *SafeFS) Rename(fromPath, toPath string) error {
@@ -138,6 +166,14 @@ func (s *SafeFS) ReadFile(path string) ([]byte, error) {
 	return os.ReadFile(resolved)
 }
 
+func (s *SafeFS) ReadDir(path string) ([]os.DirEntry, error) {
+	resolved, err := s.Resolve(path)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadDir(resolved)
+}
+
 func (s *SafeFS) Remove(path string) error {
 	resolved, err := s.Resolve(path)
 	if err != nil {
@@ -51,3 +51,51 @@ func TestSafeFSWriteAndRenameInsideRoot(t *testing.T) {
 		t.Fatalf("unexpected content: %q", string(data))
 	}
 }
+
+func TestSafeFSWriteFailureLeavesNoPartialFile(t *testing.T) {
+	root := filepath.Join(t.TempDir(), ".issues")
+	safe, err := NewSafeFS(root)
+	if err != nil {
+		t.Fatalf("expected safe fs, got error: %v", err)
+	}
+
+	target := filepath.Join("open", "PROJ-1.md")
+	if err := safe.WriteFileAtomic(target, []byte("original\n"), 0o644); err != nil {
+		t.Fatalf("initial write failed: %v", err)
+	}
+
+	injected := errors.New("disk full")
+	previous := writeTempContents
+	writeTempContents = func(file *os.File, data []byte) error {
+		if _, err := file.Write(data[:len(data)/2]); err != nil {
+			return err
+		}
+		return injected
+	}
+	t.Cleanup(func() { writeTempContents = previous })
+
+	err = safe.WriteFileAtomic(target, []byte("replacement content\n"), 0o644)
+	if !errors.Is(err, injected) {
+		t.Fatalf("expected injected write failure, got: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(root, target))
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if string(data) != "original\n" {
+		t.Fatalf("expected original content to survive failed write, got %q", string(data))
+	}
+
+	entries, err := os.ReadDir(filepath.Join(root, "open"))
+	if err != nil {
+		t.Fatalf("read dir failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "PROJ-1.md" {
+		names := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+		t.Fatalf("expected only the target file to remain, got %v", names)
+	}
+}
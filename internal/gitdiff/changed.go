@@ -0,0 +1,85 @@
+package gitdiff
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+var (
+	ErrGitNotInstalled = errors.New("git executable not found in PATH")
+	ErrNotRepository   = errors.New("not inside a git repository")
+	ErrUnknownRef      = errors.New("unknown git ref")
+)
+
+// ChangedFiles lists files under dir that differ from ref, including
+// uncommitted working-tree edits and untracked files that are not ignored,
+// such as new drafts. Paths are slash-separated and relative to dir.
+func ChangedFiles(ctx context.Context, dir string, ref string) ([]string, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return nil, fmt.Errorf("git ref must not be empty")
+	}
+	if strings.HasPrefix(ref, "-") {
+		return nil, fmt.Errorf("invalid git ref %q", ref)
+	}
+
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, ErrGitNotInstalled
+	}
+
+	// Outside a repository git diff silently switches to --no-index, so
+	// check for one first.
+	if _, err := runGit(ctx, dir, "rev-parse", "--is-inside-work-tree"); err != nil {
+		return nil, err
+	}
+	changed, err := runGit(ctx, dir, "diff", "--name-only", "--relative", ref, "--", ".")
+	if err != nil {
+		return nil, fmt.Errorf("git diff against %q failed: %w", ref, err)
+	}
+	untracked, err := runGit(ctx, dir, "ls-files", "--others", "--exclude-standard", "--", ".")
+	if err != nil {
+		return nil, fmt.Errorf("git ls-files failed: %w", err)
+	}
+
+	return append(ParseNameOnly(changed), ParseNameOnly(untracked)...), nil
+}
+
+// runGit runs git in dir and returns its stdout. Failures that callers act
+// on are mapped to ErrNotRepository and ErrUnknownRef.
+func runGit(ctx context.Context, dir string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	command := exec.CommandContext(ctx, "git", append([]string{"-c", "core.quotePath=false", "-C", dir}, args...)...)
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+	if err := command.Run(); err != nil {
+		detail := strings.TrimSpace(stderr.String())
+		lowered := strings.ToLower(detail)
+		switch {
+		case strings.Contains(lowered, "not a git repository"):
+			return "", ErrNotRepository
+		case strings.Contains(lowered, "unknown revision"), strings.Contains(lowered, "bad revision"), strings.Contains(lowered, "bad object"):
+			return "", fmt.Errorf("%w: %s", ErrUnknownRef, detail)
+		}
+		if detail == "" {
+			detail = err.Error()
+		}
+		return "", errors.New(detail)
+	}
+	return stdout.String(), nil
+}
+
+// ParseNameOnly splits `git diff --name-only` or `git ls-files` output into
+// non-empty paths.
+func ParseNameOnly(raw string) []string {
+	paths := make([]string, 0)
+	for _, line := range strings.Split(raw, "\n") {
+		if trimmed := strings.TrimSpace(line); trimmed != "" {
+			paths = append(paths, trimmed)
+		}
+	}
+	return paths
+}
@@ -0,0 +1,105 @@
+package gitdiff
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestParseNameOnlySkipsBlankLines(t *testing.T) {
+	t.Parallel()
+
+	got := ParseNameOnly("open/PROJ-1-a.md\n\n  closed/PROJ-2-b.md  \n")
+	want := []string{"open/PROJ-1-a.md", "closed/PROJ-2-b.md"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected paths: %#v", got)
+	}
+	if got := ParseNameOnly(""); len(got) != 0 {
+		t.Fatalf("expected no paths, got %#v", got)
+	}
+}
+
+func TestChangedFilesIncludesEditsAndUntrackedFiles(t *testing.T) {
+	t.Parallel()
+
+	repo := newTestRepo(t)
+	issuesRoot := filepath.Join(repo, ".issues")
+	writeTestFile(t, filepath.Join(issuesRoot, "open", "PROJ-1-edited.md"), "before")
+	writeTestFile(t, filepath.Join(issuesRoot, "open", "PROJ-2-same.md"), "same")
+	writeTestFile(t, filepath.Join(issuesRoot, ".gitignore"), "*.tmp\n")
+	runTestGit(t, repo, "add", "-A")
+	runTestGit(t, repo, "commit", "-qm", "baseline")
+
+	writeTestFile(t, filepath.Join(issuesRoot, "open", "PROJ-1-edited.md"), "after")
+	writeTestFile(t, filepath.Join(issuesRoot, "open", "L-abc123-draft.md"), "new draft")
+	writeTestFile(t, filepath.Join(issuesRoot, "open", "scratch.tmp"), "ignored")
+	writeTestFile(t, filepath.Join(repo, "outside.md"), "not under the issues root")
+
+	got, err := ChangedFiles(context.Background(), issuesRoot, "HEAD")
+	if err != nil {
+		t.Fatalf("changed files failed: %v", err)
+	}
+	sort.Strings(got)
+	want := []string{"open/L-abc123-draft.md", "open/PROJ-1-edited.md"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected changed files: %#v", got)
+	}
+}
+
+func TestChangedFilesMapsGitFailures(t *testing.T) {
+	t.Parallel()
+
+	repo := newTestRepo(t)
+	writeTestFile(t, filepath.Join(repo, "README.md"), "readme")
+	runTestGit(t, repo, "add", "-A")
+	runTestGit(t, repo, "commit", "-qm", "baseline")
+
+	if _, err := ChangedFiles(context.Background(), repo, "no-such-branch"); !errors.Is(err, ErrUnknownRef) {
+		t.Fatalf("expected unknown ref error, got %v", err)
+	}
+	if _, err := ChangedFiles(context.Background(), t.TempDir(), "HEAD"); !errors.Is(err, ErrNotRepository) {
+		t.Fatalf("expected not-a-repository error, got %v", err)
+	}
+	if _, err := ChangedFiles(context.Background(), repo, "--output=x"); err == nil || errors.Is(err, ErrUnknownRef) {
+		t.Fatalf("expected option-like ref to be rejected before running git, got %v", err)
+	}
+}
+
+func newTestRepo(t *testing.T) string {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	repo := t.TempDir()
+	runTestGit(t, repo, "init", "-q")
+	runTestGit(t, repo, "config", "user.email", "test@example.com")
+	runTestGit(t, repo, "config", "user.name", "Test")
+	return repo
+}
+
+func runTestGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+
+	command := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	command.Env = append(os.Environ(), "GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_NOSYSTEM=1")
+	if output, err := command.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, output)
+	}
+}
+
+func writeTestFile(t *testing.T, path string, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir failed: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+}
@@ -1,12 +1,16 @@
 package httpclient
 
-import "strings"
+import (
+	"regexp"
+	"strings"
+)
 
 const RedactedPlaceholder = "[REDACTED]"
 
 // Redactor removes sensitive values from error messages.
 type Redactor struct {
-	secrets []string
+	secrets  []string
+	patterns []*regexp.Regexp
 }
 
 func NewRedactor(secrets ...string) Redactor {
@@ -31,8 +35,21 @@ func NewRedactor(secrets ...string) Redactor {
 	return Redactor{secrets: unique}
 }
 
+// WithPatterns returns a copy of r that also replaces every match of the
+// given expressions. Patterns run after the literal secrets.
+func (r Redactor) WithPatterns(patterns ...*regexp.Regexp) Redactor {
+	combined := append([]*regexp.Regexp(nil), r.patterns...)
+	for _, pattern := range patterns {
+		if pattern != nil {
+			combined = append(combined, pattern)
+		}
+	}
+	r.patterns = combined
+	return r
+}
+
 func (r Redactor) Redact(value string) string {
-	if value == "" || len(r.secrets) == 0 {
+	if value == "" || (len(r.secrets) == 0 && len(r.patterns) == 0) {
 		return value
 	}
 
@@ -40,5 +57,8 @@ func (r Redactor) Redact(value string) string {
 	for _, secret := range r.secrets {
 		redacted = strings.ReplaceAll(redacted, secret, RedactedPlaceholder)
 	}
+	for _, pattern := range r.patterns {
+		redacted = pattern.ReplaceAllLiteralString(redacted, RedactedPlaceholder)
+	}
 	return redacted
 }
@@ -1,6 +1,9 @@
 package httpclient
 
-import "testing"
+import (
+	"regexp"
+	"testing"
+)
 
 func TestRedactorRedactsConfiguredSecrets(t *testing.T) {
 	t.Parallel()
@@ -24,3 +27,15 @@ func TestRedactorIgnoresBlankAndDuplicateSecrets(t *testing.T) {
 		t.Fatalf("expected deterministic redaction for duplicates, got %q", got)
 	}
 }
+
+func TestRedactorAppliesPatternsAfterSecrets(t *testing.T) {
+	t.Parallel()
+
+	redactor := NewRedactor("token-123").WithPatterns(regexp.MustCompile(`[a-z0-9-]+\.corp\.example\.com`))
+	got := redactor.Redact("dial jira.corp.example.com with token-123 failed")
+
+	want := "dial [REDACTED] with [REDACTED] failed"
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
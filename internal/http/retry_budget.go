@@ -0,0 +1,44 @@
+package httpclient
+
+import "sync/atomic"
+
+// RetryBudget is a pool of retries shared by every request in one command
+// run. Once it is spent, retryable failures are returned immediately so a
+// flaky endpoint cannot stretch runtime across hundreds of issues.
+type RetryBudget struct {
+	remaining atomic.Int64
+}
+
+// NewRetryBudget returns a budget allowing max retries in total. A max of
+// zero or less means unlimited and yields nil.
+func NewRetryBudget(max int) *RetryBudget {
+	if max <= 0 {
+		return nil
+	}
+	budget := &RetryBudget{}
+	budget.remaining.Store(int64(max))
+	return budget
+}
+
+// Remaining reports how many retries are left; -1 means unlimited.
+func (b *RetryBudget) Remaining() int {
+	if b == nil {
+		return -1
+	}
+	return int(b.remaining.Load())
+}
+
+func (b *RetryBudget) take() bool {
+	if b == nil {
+		return true
+	}
+	for {
+		current := b.remaining.Load()
+		if current <= 0 {
+			return false
+		}
+		if b.remaining.CompareAndSwap(current, current-1) {
+			return true
+		}
+	}
+}
@@ -3,15 +3,19 @@ package httpclient
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"errors"
 	"io"
 	"net"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/pweiskircher/jira-issue-sync/internal/clock"
 	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
+	"github.com/pweiskircher/jira-issue-sync/internal/logging"
 )
 
 type Doer interface {
@@ -23,6 +27,31 @@ type Options struct {
 	MaxAttempts  int
 	BaseBackoff  time.Duration
 	RetryOnCodes map[int]struct{}
+	Logger       logging.Logger
+	// Clock drives backoff sleeps and Retry-After dates; nil is the wall
+	// clock.
+	Clock clock.Clock
+	// Budget caps retries across every client sharing it; nil is unlimited.
+	Budget *RetryBudget
+	// ProxyURL routes the default client through a fixed proxy; nil falls
+	// back to the proxy environment variables. Ignored when a Doer is given.
+	ProxyURL *url.URL
+	// TLSConfig replaces the default client's TLS settings, for private CAs
+	// and client certificates. Ignored when a Doer is given.
+	TLSConfig *tls.Config
+}
+
+// IdempotencyKeyHeader marks a request the server deduplicates, which makes
+// resending it after a timeout safe whatever its method.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+type idempotentRetryKey struct{}
+
+// WithIdempotentRetry marks requests made with ctx as safe to resend after a
+// transport timeout. It is for POST endpoints that only read, such as JQL
+// parsing.
+func WithIdempotentRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, idempotentRetryKey{}, true)
 }
 
 type Sleeper interface {
@@ -36,12 +65,15 @@ type RetryClient struct {
 	baseBackoff time.Duration
 	retryCodes  map[int]struct{}
 	sleeper     Sleeper
+	now         func() time.Time
+	logger      logging.Logger
+	budget      *RetryBudget
 }
 
 func NewRetryClient(doer Doer, options Options) *RetryClient {
 	resolved := resolveOptions(options)
 	if doer == nil {
-		doer = &http.Client{Timeout: resolved.Timeout}
+		doer = newDefaultClient(resolved.Timeout, options.ProxyURL, options.TLSConfig)
 	}
 
 	return &RetryClient{
@@ -50,7 +82,10 @@ func NewRetryClient(doer Doer, options Options) *RetryClient {
 		maxAttempts: resolved.MaxAttempts,
 		baseBackoff: resolved.BaseBackoff,
 		retryCodes:  resolved.RetryOnCodes,
-		sleeper:     timeSleeper{},
+		sleeper:     clock.OrSystem(options.Clock),
+		now:         clock.OrSystem(options.Clock).Now,
+		logger:      logging.OrNop(options.Logger),
+		budget:      options.Budget,
 	}
 }
 
@@ -84,17 +119,27 @@ func (c *RetryClient) Do(req *http.Request) (*http.Response, error) {
 		attemptReq := cloneRequest(req, body)
 		attemptReq, cancel := withRequestTimeout(attemptReq, c.timeout)
 
+		started := time.Now()
 		resp, err := c.doer.Do(attemptReq)
 		if err != nil {
 			cancel()
+			logging.Debugf(c.logger, "http %s %s attempt %d/%d failed after %s: %v", req.Method, req.URL.Path, attempt, c.maxAttempts, time.Since(started).Round(time.Millisecond), err)
 			if !shouldRetryError(err) || attempt == c.maxAttempts {
 				return nil, err
 			}
+			if mayHaveReachedServer(err) && !retrySafeAfterTimeout(req) {
+				logging.Debugf(c.logger, "http %s %s not retried: the server may have processed it", req.Method, req.URL.Path)
+				return nil, err
+			}
+			if !c.budget.take() {
+				return nil, err
+			}
 			c.sleep(backoffForAttempt(c.baseBackoff, attempt))
 			continue
 		}
 
-		if !c.shouldRetryStatus(resp.StatusCode) || attempt == c.maxAttempts {
+		logging.Debugf(c.logger, "http %s %s attempt %d/%d status %d in %s", req.Method, req.URL.Path, attempt, c.maxAttempts, resp.StatusCode, time.Since(started).Round(time.Millisecond))
+		if !c.shouldRetryStatus(resp.StatusCode) || attempt == c.maxAttempts || !c.budget.take() {
 			if resp.Body != nil {
 				resp.Body = &cancelOnCloseReadCloser{ReadCloser: resp.Body, cancel: cancel}
 			} else {
@@ -104,7 +149,7 @@ func (c *RetryClient) Do(req *http.Request) (*http.Response, error) {
 		}
 
 		backoff := backoffForAttempt(c.baseBackoff, attempt)
-		if retryAfter := parseRetryAfter(resp.Header.Get("Retry-After")); retryAfter > backoff {
+		if retryAfter := ParseRetryAfter(resp.Header.Get("Retry-After"), c.currentTime()); retryAfter > backoff {
 			backoff = retryAfter
 		}
 
@@ -126,6 +171,13 @@ func (c *RetryClient) sleep(duration time.Duration) {
 	c.sleeper.Sleep(duration)
 }
 
+func (c *RetryClient) currentTime() time.Time {
+	if c.now == nil {
+		return time.Now()
+	}
+	return c.now()
+}
+
 func (c *RetryClient) shouldRetryStatus(statusCode int) bool {
 	_, ok := c.retryCodes[statusCode]
 	return ok
@@ -203,6 +255,31 @@ func shouldRetryError(err error) bool {
 	return false
 }
 
+// retrySafeAfterTimeout reports whether req can be sent again when an earlier
+// attempt may have been applied. POST and PATCH are not, unless the request
+// carries an idempotency key or its context was marked retry-safe.
+func retrySafeAfterTimeout(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	if strings.TrimSpace(req.Header.Get(IdempotencyKeyHeader)) != "" {
+		return true
+	}
+	safe, _ := req.Context().Value(idempotentRetryKey{}).(bool)
+	return safe
+}
+
+// mayHaveReachedServer is false only for errors raised while dialing, when
+// no byte of the request can have been sent.
+func mayHaveReachedServer(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return false
+	}
+	return true
+}
+
 func backoffForAttempt(base time.Duration, attempt int) time.Duration {
 	if base <= 0 || attempt <= 0 {
 		return 0
@@ -211,7 +288,9 @@ func backoffForAttempt(base time.Duration, attempt int) time.Duration {
 	return time.Duration(factor) * base
 }
 
-func parseRetryAfter(value string) time.Duration {
+// ParseRetryAfter reads a Retry-After header given as delay-seconds or an
+// HTTP date relative to now. Missing, past, or malformed values yield zero.
+func ParseRetryAfter(value string, now time.Time) time.Duration {
 	trimmed := strings.TrimSpace(value)
 	if trimmed == "" {
 		return 0
@@ -222,7 +301,7 @@ func parseRetryAfter(value string) time.Duration {
 	}
 
 	if when, err := http.ParseTime(trimmed); err == nil {
-		delta := time.Until(when)
+		delta := when.Sub(now)
 		if delta > 0 {
 			return delta
 		}
@@ -239,12 +318,6 @@ func drainAndClose(body io.ReadCloser) {
 	_ = body.Close()
 }
 
-type timeSleeper struct{}
-
-func (timeSleeper) Sleep(d time.Duration) {
-	time.Sleep(d)
-}
-
 type cancelOnCloseReadCloser struct {
 	io.ReadCloser
 	cancel context.CancelFunc
@@ -112,6 +112,45 @@ func TestRetryClientDoesNotRetryNonRetryableStatus(t *testing.T) {
 	}
 }
 
+func TestRetryClientStopsRetryingOnceSharedBudgetIsSpent(t *testing.T) {
+	t.Parallel()
+
+	budget := NewRetryBudget(1)
+	attempts := 0
+	doer := doerFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		return responseWithStatus(http.StatusServiceUnavailable, "retry"), nil
+	})
+	options := Options{MaxAttempts: 3, BaseBackoff: time.Millisecond, Budget: budget}
+
+	first := NewRetryClient(doer, options).WithSleeper(&recordingSleeper{})
+	second := NewRetryClient(doer, options).WithSleeper(&recordingSleeper{})
+
+	for index, client := range []*RetryClient{first, second} {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "https://example.test", nil)
+		if err != nil {
+			t.Fatalf("expected request creation success, got %v", err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("request %d failed: %v", index, err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusServiceUnavailable {
+			t.Fatalf("expected retryable status to surface, got %d", resp.StatusCode)
+		}
+	}
+
+	// The first request spends the single retry (2 attempts); the second
+	// fails fast on its first 503.
+	if attempts != 3 {
+		t.Fatalf("expected 3 total attempts with a budget of 1, got %d", attempts)
+	}
+	if budget.Remaining() != 0 {
+		t.Fatalf("expected budget to be spent, got %d", budget.Remaining())
+	}
+}
+
 func TestRetryClientRetriesTransientErrors(t *testing.T) {
 	t.Parallel()
 
@@ -146,6 +185,49 @@ func TestRetryClientRetriesTransientErrors(t *testing.T) {
 	}
 }
 
+func TestRetryClientDoesNotRetryPOSTAfterTransportTimeoutByDefault(t *testing.T) {
+	t.Parallel()
+
+	attempts := 0
+	client := NewRetryClient(doerFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		return nil, context.DeadlineExceeded
+	}), Options{MaxAttempts: 3}).WithSleeper(&recordingSleeper{})
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, "https://example.test/rest/api/3/issue", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatalf("expected request creation success, got %v", err)
+	}
+	if _, err := client.Do(req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if attempts != 1 {
+		t.Fatalf("expected a timed-out POST to be sent once, got %d attempts", attempts)
+	}
+
+	for name, marked := range map[string]func(*http.Request) *http.Request{
+		"idempotency key": func(req *http.Request) *http.Request {
+			req.Header.Set(IdempotencyKeyHeader, "create-1")
+			return req
+		},
+		"retry-safe context": func(req *http.Request) *http.Request {
+			return req.WithContext(WithIdempotentRetry(req.Context()))
+		},
+	} {
+		attempts = 0
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, "https://example.test/rest/api/3/issue", strings.NewReader(`{}`))
+		if err != nil {
+			t.Fatalf("expected request creation success, got %v", err)
+		}
+		if _, err := client.Do(marked(req)); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("%s: expected deadline exceeded, got %v", name, err)
+		}
+		if attempts != 3 {
+			t.Fatalf("%s: expected POST to be retried, got %d attempts", name, attempts)
+		}
+	}
+}
+
 func TestRetryClientAppliesPerAttemptTimeout(t *testing.T) {
 	t.Parallel()
 
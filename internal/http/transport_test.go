@@ -0,0 +1,38 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestRetryClientDefaultTransportDialsConfiguredProxy(t *testing.T) {
+	dialed := make([]string, 0)
+	original := dialContext
+	dialContext = func(_ context.Context, _ string, address string) (net.Conn, error) {
+		dialed = append(dialed, address)
+		return nil, errors.New("dial refused by test")
+	}
+	t.Cleanup(func() { dialContext = original })
+
+	proxy, err := url.Parse("http://proxy.corp.test:3128")
+	if err != nil {
+		t.Fatalf("parse proxy url: %v", err)
+	}
+	client := NewRetryClient(nil, Options{MaxAttempts: 1, ProxyURL: proxy})
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "http://jira.example.test/rest/api/3/myself", nil)
+	if err != nil {
+		t.Fatalf("expected request creation success, got %v", err)
+	}
+	if _, err := client.Do(req); err == nil {
+		t.Fatalf("expected the refused dial to fail the request")
+	}
+
+	if len(dialed) != 1 || dialed[0] != "proxy.corp.test:3128" {
+		t.Fatalf("expected a single dial to the proxy, got %v", dialed)
+	}
+}
@@ -0,0 +1,72 @@
+package issue
+
+import (
+	"strings"
+
+	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
+	"github.com/pweiskircher/jira-issue-sync/internal/converter"
+)
+
+// ParseOptions enables optional checks that need more than the file itself.
+// The zero value parses exactly like ParseDocument.
+type ParseOptions struct {
+	// ADFConverter, when set, renders each embedded raw ADF block back to
+	// markdown and warns when the result no longer matches the markdown
+	// next to it.
+	ADFConverter converter.Adapter
+	// PreserveLabelCase keeps label case, for profiles with
+	// preserve_label_case.
+	PreserveLabelCase bool
+}
+
+// ParseDocumentWithOptions parses like ParseDocument and returns any
+// warnings from the checks enabled in options. Warnings never fail a parse.
+func ParseDocumentWithOptions(path, content string, options ParseOptions) (Document, []converter.RiskSignal, error) {
+	doc, err := parseDocument(path, content, options.PreserveLabelCase)
+	if err != nil {
+		return Document{}, nil, err
+	}
+	if options.ADFConverter == nil {
+		return doc, nil, nil
+	}
+
+	var warnings []converter.RiskSignal
+	if warning, stale := detectStaleRawADF(options.ADFConverter, "description", doc.MarkdownBody, doc.RawADFJSON); stale {
+		warnings = append(warnings, warning)
+	}
+	if warning, stale := detectStaleRawADF(options.ADFConverter, "environment", doc.EnvironmentMarkdown, doc.EnvironmentADFJSON); stale {
+		warnings = append(warnings, warning)
+	}
+	return doc, warnings, nil
+}
+
+// detectStaleRawADF reports when the raw ADF block describes different text
+// than the markdown beside it, which usually means the markdown was edited
+// by hand. Push converts the markdown, so the stale block is discarded.
+func detectStaleRawADF(adfConverter converter.Adapter, section string, markdown string, rawADF string) (converter.RiskSignal, bool) {
+	if strings.TrimSpace(rawADF) == "" {
+		return converter.RiskSignal{}, false
+	}
+	rendered, err := adfConverter.ToMarkdown(rawADF)
+	if err != nil {
+		// Parse already validated the block; a converter failure here is
+		// not evidence of drift.
+		return converter.RiskSignal{}, false
+	}
+	if comparableMarkdown(rendered.Markdown) == comparableMarkdown(markdown) {
+		return converter.RiskSignal{}, false
+	}
+	return converter.RiskSignal{
+		ReasonCode: contracts.ReasonCodeDescriptionADFBlockStale,
+		Message:    section + " markdown differs from its raw ADF block; push will use the markdown and replace the block",
+	}, true
+}
+
+func comparableMarkdown(markdown string) string {
+	normalized := contracts.NormalizeSingleValue(contracts.NormalizationNormalizeLineEndings, markdown)
+	lines := strings.Split(strings.TrimSpace(normalized), "\n")
+	for index, line := range lines {
+		lines[index] = strings.TrimRight(line, " \t")
+	}
+	return strings.Join(lines, "\n")
+}
@@ -15,7 +15,13 @@ import (
 var customFieldKeyPattern = regexp.MustCompile(`^customfield_[0-9]+$`)
 
 // ParseDocument parses a markdown issue file into a deterministic model.
+// Labels are lowercased, as they are for profiles without
+// preserve_label_case; see ParseOptions to keep their case.
 func ParseDocument(path, content string) (Document, error) {
+	return parseDocument(path, content, false)
+}
+
+func parseDocument(path, content string, preserveLabelCase bool) (Document, error) {
 	normalized := contracts.NormalizeSingleValue(contracts.NormalizationNormalizeLineEndings, content)
 	frontMatterLines, body, err := splitFrontMatter(normalized)
 	if err != nil {
@@ -42,7 +48,7 @@ func ParseDocument(path, content string) (Document, error) {
 			Message:    "issue key is required in front matter or filename",
 		}
 	}
-	if !contracts.JiraIssueKeyPattern.MatchString(canonicalKey) && !contracts.LocalDraftKeyPattern.MatchString(canonicalKey) {
+	if !contracts.JiraIssueKeyPattern.MatchString(canonicalKey) && !contracts.IsLocalDraftKey(canonicalKey) {
 		return Document{}, &ParseError{
 			Code:       ParseErrorCodeInvalidIssueKey,
 			ReasonCode: contracts.ReasonCodeValidationFailed,
@@ -51,22 +57,75 @@ func ParseDocument(path, content string) (Document, error) {
 		}
 	}
 	frontMatter.Key = canonicalKey
+	if !preserveLabelCase {
+		frontMatter.Labels = contracts.NormalizeLabels(frontMatter.Labels)
+	}
 
-	markdownBody, rawADFJSON, err := extractAndValidateRawADF(body)
+	body, comments := splitCommentsSection(body)
+	descriptionBody, environmentBody := splitEnvironmentSection(body)
+	markdownBody, rawADFJSON, err := extractAndValidateRawADF(descriptionBody)
+	if err != nil {
+		return Document{}, err
+	}
+	environmentMarkdown, environmentADFJSON, err := extractEnvironmentRawADF(environmentBody)
 	if err != nil {
 		return Document{}, err
 	}
 
 	return Document{
-		CanonicalKey: canonicalKey,
-		FrontMatter:  frontMatter,
-		MarkdownBody: markdownBody,
-		RawADFJSON:   rawADFJSON,
+		CanonicalKey:        canonicalKey,
+		FrontMatter:         frontMatter,
+		MarkdownBody:        markdownBody,
+		RawADFJSON:          rawADFJSON,
+		EnvironmentMarkdown: environmentMarkdown,
+		EnvironmentADFJSON:  environmentADFJSON,
+		Comments:            strings.TrimSpace(comments),
 	}, nil
 }
 
+// RenderOptions selects presentation choices that do not change the parsed
+// document. The zero value renders labels as a block list.
+type RenderOptions struct {
+	LabelStyle string
+	// KeyOrder lists front matter keys to write first; the remaining keys
+	// follow in CanonicalFrontMatterOrder.
+	KeyOrder []contracts.FrontMatterKey
+}
+
 // RenderDocument renders the deterministic canonical markdown issue format.
 func RenderDocument(doc Document) (string, error) {
+	return RenderDocumentWithOptions(doc, RenderOptions{})
+}
+
+// frontMatterRenderOrder puts the known keys of leading first, then every
+// other canonical key in its usual place. Unknown and repeated keys are
+// dropped, so the result is always a permutation of the canonical order.
+func frontMatterRenderOrder(leading []contracts.FrontMatterKey) []contracts.FrontMatterKey {
+	if len(leading) == 0 {
+		return CanonicalFrontMatterOrder
+	}
+	remaining := make(map[contracts.FrontMatterKey]bool, len(CanonicalFrontMatterOrder))
+	for _, key := range CanonicalFrontMatterOrder {
+		remaining[key] = true
+	}
+	order := make([]contracts.FrontMatterKey, 0, len(CanonicalFrontMatterOrder))
+	for _, key := range leading {
+		if remaining[key] {
+			order = append(order, key)
+			remaining[key] = false
+		}
+	}
+	for _, key := range CanonicalFrontMatterOrder {
+		if remaining[key] {
+			order = append(order, key)
+		}
+	}
+	return order
+}
+
+// RenderDocumentWithOptions renders the canonical format with the given
+// presentation options applied.
+func RenderDocumentWithOptions(doc Document, options RenderOptions) (string, error) {
 	canonical, err := canonicalizeDocument(doc)
 	if err != nil {
 		return "", err
@@ -76,8 +135,8 @@ func RenderDocument(doc Document) (string, error) {
 	builder.WriteString(contracts.FrontMatterDelimiter)
 	builder.WriteString("\n")
 
-	for _, key := range CanonicalFrontMatterOrder {
-		if line, ok := renderFrontMatterLine(canonical.FrontMatter, key); ok {
+	for _, key := range frontMatterRenderOrder(options.KeyOrder) {
+		if line, ok := renderFrontMatterLine(canonical.FrontMatter, key, options); ok {
 			builder.WriteString(line)
 			builder.WriteString("\n")
 		}
@@ -106,6 +165,35 @@ func RenderDocument(doc Document) (string, error) {
 		builder.WriteString("\n")
 	}
 
+	if canonical.EnvironmentMarkdown != "" || canonical.EnvironmentADFJSON != "" {
+		builder.WriteString("\n")
+		builder.WriteString(contracts.EnvironmentSectionMarker)
+		builder.WriteString("\n")
+		if canonical.EnvironmentMarkdown != "" {
+			builder.WriteString("\n")
+			builder.WriteString(canonical.EnvironmentMarkdown)
+			builder.WriteString("\n")
+		}
+		if canonical.EnvironmentADFJSON != "" {
+			builder.WriteString("\n```")
+			builder.WriteString(contracts.RawADFFenceLanguage)
+			builder.WriteString(" ")
+			builder.WriteString(contracts.RawADFEnvironmentLabel)
+			builder.WriteString("\n")
+			builder.WriteString(canonical.EnvironmentADFJSON)
+			builder.WriteString("\n```")
+			builder.WriteString("\n")
+		}
+	}
+
+	if canonical.Comments != "" {
+		builder.WriteString("\n")
+		builder.WriteString(contracts.CommentsSectionMarker)
+		builder.WriteString("\n\n")
+		builder.WriteString(canonical.Comments)
+		builder.WriteString("\n")
+	}
+
 	return builder.String(), nil
 }
 
@@ -119,7 +207,7 @@ func canonicalizeDocument(doc Document) (Document, error) {
 			Message:    "issue key is required",
 		}
 	}
-	if !contracts.JiraIssueKeyPattern.MatchString(key) && !contracts.LocalDraftKeyPattern.MatchString(key) {
+	if !contracts.JiraIssueKeyPattern.MatchString(key) && !contracts.IsLocalDraftKey(key) {
 		return Document{}, &ParseError{
 			Code:       ParseErrorCodeInvalidIssueKey,
 			ReasonCode: contracts.ReasonCodeValidationFailed,
@@ -151,11 +239,28 @@ func canonicalizeDocument(doc Document) (Document, error) {
 		canonicalRawADF = validated
 	}
 
+	normalizedEnvironment := strings.TrimSpace(
+		contracts.NormalizeSingleValue(contracts.NormalizationNormalizeLineEndings, doc.EnvironmentMarkdown),
+	)
+	canonicalEnvironmentADF := ""
+	if strings.TrimSpace(doc.EnvironmentADFJSON) != "" {
+		validated, validateErr := converter.ValidateAndCanonicalizeRawADF(doc.EnvironmentADFJSON)
+		if validateErr != nil {
+			return Document{}, mapRawADFError(validateErr)
+		}
+		canonicalEnvironmentADF = validated
+	}
+
 	return Document{
-		CanonicalKey: key,
-		FrontMatter:  normalizedFrontMatter,
-		MarkdownBody: normalizedMarkdown,
-		RawADFJSON:   canonicalRawADF,
+		CanonicalKey:        key,
+		FrontMatter:         normalizedFrontMatter,
+		MarkdownBody:        normalizedMarkdown,
+		RawADFJSON:          canonicalRawADF,
+		EnvironmentMarkdown: normalizedEnvironment,
+		EnvironmentADFJSON:  canonicalEnvironmentADF,
+		Comments: strings.TrimSpace(
+			contracts.NormalizeSingleValue(contracts.NormalizationNormalizeLineEndings, doc.Comments),
+		),
 	}, nil
 }
 
@@ -288,6 +393,7 @@ func buildFrontMatter(values map[contracts.FrontMatterKey]interface{}) (FrontMat
 		Assignee:         toString(values[contracts.FrontMatterKeyAssignee]),
 		Labels:           toStringSlice(values[contracts.FrontMatterKeyLabels]),
 		Reporter:         toString(values[contracts.FrontMatterKeyReporter]),
+		SecurityLevel:    toString(values[contracts.FrontMatterKeySecurityLevel]),
 		CreatedAt:        toString(values[contracts.FrontMatterKeyCreatedAt]),
 		UpdatedAt:        toString(values[contracts.FrontMatterKeyUpdatedAt]),
 		SyncedAt:         toString(values[contracts.FrontMatterKeySyncedAt]),
@@ -352,10 +458,13 @@ func normalizeFrontMatter(frontMatter FrontMatter) (FrontMatter, error) {
 	frontMatter.Priority = contracts.NormalizeSingleValue(contracts.NormalizationTrimAndTitleCase, frontMatter.Priority)
 	frontMatter.Assignee = contracts.NormalizeSingleValue(contracts.NormalizationTrimEmptyToNull, frontMatter.Assignee)
 	frontMatter.Reporter = contracts.NormalizeSingleValue(contracts.NormalizationTrimEmptyToNull, frontMatter.Reporter)
-	frontMatter.CreatedAt = strings.TrimSpace(frontMatter.CreatedAt)
-	frontMatter.UpdatedAt = strings.TrimSpace(frontMatter.UpdatedAt)
-	frontMatter.SyncedAt = strings.TrimSpace(frontMatter.SyncedAt)
-	frontMatter.Labels = contracts.NormalizeLabels(frontMatter.Labels)
+	frontMatter.SecurityLevel = contracts.NormalizeSingleValue(contracts.NormalizationTrimEmptyToNull, frontMatter.SecurityLevel)
+	frontMatter.CreatedAt = contracts.NormalizeSingleValue(contracts.NormalizationTimestampUTC, frontMatter.CreatedAt)
+	frontMatter.UpdatedAt = contracts.NormalizeSingleValue(contracts.NormalizationTimestampUTC, frontMatter.UpdatedAt)
+	frontMatter.SyncedAt = contracts.NormalizeSingleValue(contracts.NormalizationTimestampUTC, frontMatter.SyncedAt)
+	// Rendering keeps label case; parsing lowercases unless the profile
+	// preserves it.
+	frontMatter.Labels = contracts.NormalizeLabelsPreservingCase(frontMatter.Labels)
 
 	normalizedCustomFields, err := normalizeCustomFields(frontMatter.CustomFields)
 	if err != nil {
@@ -367,7 +476,7 @@ func normalizeFrontMatter(frontMatter FrontMatter) (FrontMatter, error) {
 	if err != nil {
 		return FrontMatter{}, err
 	}
-	frontMatter.CustomFieldNames = normalizedCustomFieldNames
+	frontMatter.CustomFieldNames = pruneOrphanCustomFieldNames(normalizedCustomFieldNames, frontMatter.CustomFields)
 
 	return frontMatter, nil
 }
@@ -417,6 +526,59 @@ func extractAndValidateRawADF(body string) (string, string, error) {
 	return markdown, canonicalRawADF, nil
 }
 
+// splitEnvironmentSection cuts the body at the environment marker line. The
+// marker must sit on its own line so prose mentioning it is left alone.
+// splitCommentsSection cuts the read-only comments section off the end of
+// the body. It is split first because it follows the environment section.
+func splitCommentsSection(body string) (string, string) {
+	lines := strings.Split(body, "\n")
+	for index, line := range lines {
+		if strings.TrimSpace(line) == contracts.CommentsSectionMarker {
+			return strings.Join(lines[:index], "\n"), strings.Join(lines[index+1:], "\n")
+		}
+	}
+	return body, ""
+}
+
+func splitEnvironmentSection(body string) (string, string) {
+	lines := strings.Split(body, "\n")
+	for index, line := range lines {
+		if strings.TrimSpace(line) == contracts.EnvironmentSectionMarker {
+			return strings.Join(lines[:index], "\n"), strings.Join(lines[index+1:], "\n")
+		}
+	}
+	return body, ""
+}
+
+func extractEnvironmentRawADF(section string) (string, string, error) {
+	if strings.Count(section, "```"+contracts.RawADFFenceLanguage) > 1 {
+		return "", "", &ParseError{
+			Code:       ParseErrorCodeMalformedRawADF,
+			ReasonCode: contracts.ReasonCodeDescriptionADFBlockMalformed,
+			Message:    "multiple embedded environment raw ADF blocks are not supported",
+		}
+	}
+
+	match := contracts.RawADFEnvironmentBlockPattern.FindStringSubmatch(section)
+	if match == nil {
+		if strings.Contains(section, "```"+contracts.RawADFFenceLanguage) {
+			return "", "", &ParseError{
+				Code:       ParseErrorCodeMalformedRawADF,
+				ReasonCode: contracts.ReasonCodeDescriptionADFBlockMalformed,
+				Message:    "embedded environment raw ADF block is malformed",
+			}
+		}
+		return strings.TrimSpace(section), "", nil
+	}
+
+	canonicalRawADF, err := converter.ValidateAndCanonicalizeRawADF(match[1])
+	if err != nil {
+		return "", "", mapRawADFError(err)
+	}
+	markdown := contracts.RawADFEnvironmentBlockPattern.ReplaceAllString(section, "")
+	return strings.TrimSpace(markdown), canonicalRawADF, nil
+}
+
 func mapRawADFError(err error) error {
 	if err == nil {
 		return nil
@@ -429,7 +591,7 @@ func mapRawADFError(err error) error {
 	}
 }
 
-func renderFrontMatterLine(frontMatter FrontMatter, key contracts.FrontMatterKey) (string, bool) {
+func renderFrontMatterLine(frontMatter FrontMatter, key contracts.FrontMatterKey, options RenderOptions) (string, bool) {
 	switch key {
 	case contracts.FrontMatterKeySchemaVersion:
 		return string(key) + ": " + quote(frontMatter.SchemaVersion), true
@@ -458,6 +620,16 @@ func renderFrontMatterLine(frontMatter FrontMatter, key contracts.FrontMatterKey
 		var builder strings.Builder
 		builder.WriteString(string(key))
 		builder.WriteString(":")
+		if options.LabelStyle == contracts.LabelRenderStyleInline {
+			quoted := make([]string, 0, len(frontMatter.Labels))
+			for _, label := range frontMatter.Labels {
+				quoted = append(quoted, quote(label))
+			}
+			builder.WriteString(" [")
+			builder.WriteString(strings.Join(quoted, ", "))
+			builder.WriteString("]")
+			return builder.String(), true
+		}
 		for _, label := range frontMatter.Labels {
 			builder.WriteString("\n- ")
 			builder.WriteString(quote(label))
@@ -468,6 +640,11 @@ func renderFrontMatterLine(frontMatter FrontMatter, key contracts.FrontMatterKey
 			return "", false
 		}
 		return string(key) + ": " + quote(frontMatter.Reporter), true
+	case contracts.FrontMatterKeySecurityLevel:
+		if frontMatter.SecurityLevel == "" {
+			return "", false
+		}
+		return string(key) + ": " + quote(frontMatter.SecurityLevel), true
 	case contracts.FrontMatterKeyCreatedAt:
 		if frontMatter.CreatedAt == "" {
 			return "", false
@@ -669,6 +846,28 @@ func normalizeCustomFieldNames(customFieldNames map[string]string) (map[string]s
 	return normalized, nil
 }
 
+// pruneOrphanCustomFieldNames drops names whose field no longer appears in
+// custom_fields, keyed either by field ID or by the name itself (pull writes
+// alias keys), so stale entries do not pile up after a field is removed.
+func pruneOrphanCustomFieldNames(customFieldNames map[string]string, customFields map[string]json.RawMessage) map[string]string {
+	if len(customFieldNames) == 0 {
+		return nil
+	}
+
+	pruned := make(map[string]string, len(customFieldNames))
+	for fieldID, name := range customFieldNames {
+		_, byID := customFields[fieldID]
+		_, byName := customFields[name]
+		if byID || byName {
+			pruned[fieldID] = name
+		}
+	}
+	if len(pruned) == 0 {
+		return nil
+	}
+	return pruned
+}
+
 func toCustomFields(value interface{}) map[string]json.RawMessage {
 	if value == nil {
 		return nil
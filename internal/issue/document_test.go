@@ -2,10 +2,13 @@ package issue
 
 import (
 	"encoding/json"
+	"errors"
+	"reflect"
 	"strings"
 	"testing"
 
 	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
+	"github.com/pweiskircher/jira-issue-sync/internal/converter"
 )
 
 func TestParseRenderRoundTripIsDeterministic(t *testing.T) {
@@ -60,6 +63,203 @@ User-facing markdown description.
 	}
 }
 
+func TestEnvironmentSectionRoundTripsAlongsideDescription(t *testing.T) {
+	doc := Document{
+		CanonicalKey:        "PROJ-7",
+		FrontMatter:         FrontMatter{Key: "PROJ-7", Summary: "Crash on login", IssueType: "Bug", Status: "Open"},
+		MarkdownBody:        "Steps to reproduce.",
+		RawADFJSON:          `{"version":1,"type":"doc","content":[]}`,
+		EnvironmentMarkdown: "macOS 15\r\nSafari 18",
+		EnvironmentADFJSON:  `{ "version": 1, "type": "doc", "content": [] }`,
+	}
+
+	rendered, err := RenderDocument(doc)
+	if err != nil {
+		t.Fatalf("expected render success, got: %v", err)
+	}
+	if !strings.Contains(rendered, contracts.EnvironmentSectionMarker+"\n\nmacOS 15\nSafari 18\n\n```jira-adf environment\n") {
+		t.Fatalf("expected labeled environment section, got:\n%s", rendered)
+	}
+
+	parsed, err := ParseDocument("/tmp/PROJ-7-crash-on-login.md", rendered)
+	if err != nil {
+		t.Fatalf("expected parse success, got: %v", err)
+	}
+	if parsed.MarkdownBody != "Steps to reproduce." || parsed.RawADFJSON != `{"version":1,"type":"doc","content":[]}` {
+		t.Fatalf("expected description to stay separate, got %q / %q", parsed.MarkdownBody, parsed.RawADFJSON)
+	}
+	if parsed.EnvironmentMarkdown != "macOS 15\nSafari 18" || parsed.EnvironmentADFJSON != `{"version":1,"type":"doc","content":[]}` {
+		t.Fatalf("unexpected environment: %q / %q", parsed.EnvironmentMarkdown, parsed.EnvironmentADFJSON)
+	}
+
+	rerendered, err := RenderDocument(parsed)
+	if err != nil {
+		t.Fatalf("expected rerender success, got: %v", err)
+	}
+	if rendered != rerendered {
+		t.Fatalf("expected deterministic round-trip render\nfirst:\n%s\nsecond:\n%s", rendered, rerendered)
+	}
+}
+
+func TestParseDocumentRejectsMalformedEnvironmentBlock(t *testing.T) {
+	input := "---\nschema_version: \"1\"\nkey: \"PROJ-8\"\nsummary: \"x\"\nissue_type: \"Bug\"\nstatus: \"Open\"\n---\n\n" +
+		contracts.EnvironmentSectionMarker + "\n\n```jira-adf environment\n{\"type\":\"paragraph\"}\n```\n"
+
+	_, err := ParseDocument("/tmp/PROJ-8-x.md", input)
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.Code != ParseErrorCodeMalformedRawADF {
+		t.Fatalf("expected malformed raw ADF error, got %v", err)
+	}
+}
+
+func TestRenderLabelsHonorsLabelStyle(t *testing.T) {
+	doc := Document{
+		CanonicalKey: "PROJ-9",
+		FrontMatter:  FrontMatter{Key: "PROJ-9", Summary: "Labels", IssueType: "Task", Status: "Open", Labels: []string{"p1", "backend"}},
+	}
+
+	block, err := RenderDocument(doc)
+	if err != nil {
+		t.Fatalf("expected block render success, got: %v", err)
+	}
+	if !strings.Contains(block, "labels:\n- \"backend\"\n- \"p1\"\n") {
+		t.Fatalf("expected default block labels, got:\n%s", block)
+	}
+
+	inline, err := RenderDocumentWithOptions(doc, RenderOptions{LabelStyle: contracts.LabelRenderStyleInline})
+	if err != nil {
+		t.Fatalf("expected inline render success, got: %v", err)
+	}
+	if !strings.Contains(inline, "labels: [\"backend\", \"p1\"]\n") {
+		t.Fatalf("expected inline labels, got:\n%s", inline)
+	}
+}
+
+func TestRenderFrontMatterHonorsCustomKeyOrder(t *testing.T) {
+	doc := Document{
+		CanonicalKey: "PROJ-9",
+		FrontMatter:  FrontMatter{Key: "PROJ-9", Summary: "Ordered", IssueType: "Task", Status: "Open", Priority: "High"},
+	}
+
+	rendered, err := RenderDocumentWithOptions(doc, RenderOptions{KeyOrder: []contracts.FrontMatterKey{
+		contracts.FrontMatterKeySummary,
+		contracts.FrontMatterKeyPriority,
+		contracts.FrontMatterKeyKey,
+	}})
+	if err != nil {
+		t.Fatalf("expected render success, got: %v", err)
+	}
+	want := "---\nsummary: \"Ordered\"\npriority: \"High\"\nkey: \"PROJ-9\"\nschema_version: \"1\"\nissue_type: \"Task\"\nstatus: \"Open\"\n---\n"
+	if rendered != want {
+		t.Fatalf("unexpected custom order render\ngot:\n%s\nwant:\n%s", rendered, want)
+	}
+
+	parsed, err := ParseDocument("/tmp/PROJ-9-ordered.md", rendered)
+	if err != nil {
+		t.Fatalf("expected custom order to parse, got: %v", err)
+	}
+	canonical, err := RenderDocument(parsed)
+	if err != nil {
+		t.Fatalf("rerender failed: %v", err)
+	}
+	if want, _ := RenderDocument(doc); canonical != want {
+		t.Fatalf("expected custom order to parse to the same document\ngot:\n%s\nwant:\n%s", canonical, want)
+	}
+}
+
+func TestParseAcceptsBothLabelStyles(t *testing.T) {
+	doc := Document{
+		CanonicalKey: "PROJ-9",
+		FrontMatter:  FrontMatter{Key: "PROJ-9", Summary: "Labels", IssueType: "Task", Status: "Open", Labels: []string{"backend", "p1"}},
+	}
+
+	for _, style := range []string{contracts.LabelRenderStyleBlock, contracts.LabelRenderStyleInline} {
+		rendered, err := RenderDocumentWithOptions(doc, RenderOptions{LabelStyle: style})
+		if err != nil {
+			t.Fatalf("render %s failed: %v", style, err)
+		}
+		parsed, err := ParseDocument("/tmp/PROJ-9-labels.md", rendered)
+		if err != nil {
+			t.Fatalf("parse %s failed: %v", style, err)
+		}
+		if strings.Join(parsed.FrontMatter.Labels, ",") != "backend,p1" {
+			t.Fatalf("unexpected labels for %s style: %#v", style, parsed.FrontMatter.Labels)
+		}
+
+		canonical, err := RenderDocument(parsed)
+		if err != nil {
+			t.Fatalf("rerender %s failed: %v", style, err)
+		}
+		if want, _ := RenderDocument(doc); canonical != want {
+			t.Fatalf("expected %s style to parse to the same document\ngot:\n%s\nwant:\n%s", style, canonical, want)
+		}
+	}
+}
+
+func TestParseDocumentNormalizesTimestampsToUTC(t *testing.T) {
+	input := `---
+schema_version: "1"
+key: "PROJ-5"
+summary: "Timestamps"
+issue_type: "Task"
+status: "Open"
+created_at: "2026-02-20T09:30:00.000+0100"
+updated_at: "2026-02-20T00:00:00.000+0000"
+synced_at: "2026-02-21T10:11:12.123456789Z"
+---
+`
+
+	doc, err := ParseDocument("/tmp/PROJ-5-timestamps.md", input)
+	if err != nil {
+		t.Fatalf("expected parse success, got: %v", err)
+	}
+	if doc.FrontMatter.CreatedAt != "2026-02-20T08:30:00Z" {
+		t.Fatalf("unexpected created_at: %q", doc.FrontMatter.CreatedAt)
+	}
+	if doc.FrontMatter.UpdatedAt != "2026-02-20T00:00:00Z" {
+		t.Fatalf("unexpected updated_at: %q", doc.FrontMatter.UpdatedAt)
+	}
+	if doc.FrontMatter.SyncedAt != "2026-02-21T10:11:12Z" {
+		t.Fatalf("unexpected synced_at: %q", doc.FrontMatter.SyncedAt)
+	}
+
+	rendered, err := RenderDocument(doc)
+	if err != nil {
+		t.Fatalf("expected render success, got: %v", err)
+	}
+	reparsed, err := ParseDocument("/tmp/PROJ-5-timestamps.md", rendered)
+	if err != nil {
+		t.Fatalf("expected reparse success, got: %v", err)
+	}
+	rerendered, err := RenderDocument(reparsed)
+	if err != nil {
+		t.Fatalf("expected rerender success, got: %v", err)
+	}
+	if rendered != rerendered {
+		t.Fatalf("expected stable timestamp round-trip\nfirst:\n%s\nsecond:\n%s", rendered, rerendered)
+	}
+}
+
+func TestParseDocumentKeepsUnparseableTimestampsVerbatim(t *testing.T) {
+	input := `---
+schema_version: "1"
+key: "PROJ-6"
+summary: "Odd timestamp"
+issue_type: "Task"
+status: "Open"
+created_at: "  last tuesday  "
+---
+`
+
+	doc, err := ParseDocument("/tmp/PROJ-6-odd-timestamp.md", input)
+	if err != nil {
+		t.Fatalf("expected parse success, got: %v", err)
+	}
+	if doc.FrontMatter.CreatedAt != "last tuesday" {
+		t.Fatalf("expected unparseable timestamp to be preserved, got %q", doc.FrontMatter.CreatedAt)
+	}
+}
+
 func TestRenderDocumentUsesCanonicalFieldOrder(t *testing.T) {
 	doc := Document{
 		CanonicalKey: "PROJ-42",
@@ -184,6 +384,32 @@ custom_fields: {"customer":"Enterprise"}
 	}
 }
 
+func TestParseDocumentPrunesOrphanCustomFieldNames(t *testing.T) {
+	input := `---
+schema_version: "1"
+key: "PROJ-1"
+summary: "Summary"
+issue_type: "Task"
+status: "Open"
+custom_fields: {"customer":"Enterprise","customfield_10011":"Gold"}
+custom_field_names: {"customfield_10010":"customer","customfield_10011":"Tier","customfield_10012":"Removed"}
+---
+`
+
+	doc, err := ParseDocument("/tmp/PROJ-1.md", input)
+	if err != nil {
+		t.Fatalf("expected parse success, got: %v", err)
+	}
+
+	expected := map[string]string{
+		"customfield_10010": "customer",
+		"customfield_10011": "Tier",
+	}
+	if !reflect.DeepEqual(doc.FrontMatter.CustomFieldNames, expected) {
+		t.Fatalf("unexpected custom field names: %#v", doc.FrontMatter.CustomFieldNames)
+	}
+}
+
 func TestParseDocumentReturnsTypedErrorForMalformedRawADF(t *testing.T) {
 	input := `---
 schema_version: "1"
@@ -208,3 +434,75 @@ Body
 		t.Fatalf("expected malformed raw ADF parse error, got: %v", err)
 	}
 }
+
+func TestStripVolatileFrontMatterOnlyTouchesFrontMatter(t *testing.T) {
+	left := "---\r\nkey: \"PROJ-1\"\r\nsynced_at: \"2026-02-20T00:00:00Z\"\r\n---\r\n\nsynced_at: kept in body\n"
+	right := "---\nkey: \"PROJ-1\"\nsynced_at: \"2026-03-01T00:00:00Z\"\n---\n\nsynced_at: kept in body\n"
+
+	if !EqualIgnoringVolatile(left, right) {
+		t.Fatalf("expected synced_at-only difference to be ignored")
+	}
+	if stripped := StripVolatileFrontMatter(right); stripped != "---\nkey: \"PROJ-1\"\n---\n\nsynced_at: kept in body\n" {
+		t.Fatalf("unexpected stripped text: %q", stripped)
+	}
+	if EqualIgnoringVolatile(right, strings.Replace(right, "kept in body", "edited body", 1)) {
+		t.Fatalf("expected body edits to remain visible")
+	}
+}
+
+type fixedMarkdownConverter struct {
+	markdown string
+}
+
+func (c fixedMarkdownConverter) ToMarkdown(string) (converter.MarkdownResult, error) {
+	return converter.MarkdownResult{Markdown: c.markdown}, nil
+}
+
+func (c fixedMarkdownConverter) ToADF(string) (converter.ADFResult, error) {
+	return converter.ADFResult{}, nil
+}
+
+func TestParseDocumentWithOptionsWarnsWhenRawADFIsStale(t *testing.T) {
+	input := `---
+schema_version: "1"
+key: "PROJ-1"
+summary: "Summary"
+issue_type: "Task"
+status: "Open"
+---
+
+Edited by hand.
+
+` + "```jira-adf" + `
+{"version":1,"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"Pulled from Jira."}]}]}
+` + "```" + `
+`
+
+	doc, warnings, err := ParseDocumentWithOptions("/tmp/PROJ-1.md", input, ParseOptions{
+		ADFConverter: fixedMarkdownConverter{markdown: "Pulled from Jira."},
+	})
+	if err != nil {
+		t.Fatalf("expected parse success, got: %v", err)
+	}
+	if doc.MarkdownBody != "Edited by hand." {
+		t.Fatalf("unexpected markdown body: %q", doc.MarkdownBody)
+	}
+	if len(warnings) != 1 || warnings[0].ReasonCode != contracts.ReasonCodeDescriptionADFBlockStale || !strings.HasPrefix(warnings[0].Message, "description ") {
+		t.Fatalf("expected one stale description warning, got %#v", warnings)
+	}
+
+	_, warnings, err = ParseDocumentWithOptions("/tmp/PROJ-1.md", input, ParseOptions{
+		ADFConverter: fixedMarkdownConverter{markdown: "Edited by hand.  \n"},
+	})
+	if err != nil {
+		t.Fatalf("expected parse success, got: %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Fatalf("expected matching markdown to produce no warnings, got %#v", warnings)
+	}
+
+	_, warnings, err = ParseDocumentWithOptions("/tmp/PROJ-1.md", input, ParseOptions{})
+	if err != nil || len(warnings) != 0 {
+		t.Fatalf("expected the check to be off without a converter, got %#v, %v", warnings, err)
+	}
+}
@@ -14,10 +14,23 @@ const (
 	maxSlugLen   = 64
 )
 
-var keyPrefixInFilenamePattern = regexp.MustCompile(`^([A-Z][A-Z0-9]+-[0-9]+|L-[0-9a-f]+)(?:-.+)?\.md$`)
+// keyPrefixInFilenamePattern accepts draft keys under any prefix;
+// ParseFilenameKey still checks the prefix with contracts.IsLocalDraftKey.
+var keyPrefixInFilenamePattern = regexp.MustCompile(`^([A-Z][A-Z0-9]+-[0-9]+|[A-Za-z][A-Za-z0-9]*-[0-9a-f]+)(?:-.+)?\.md$`)
+
+// FilenameOptions selects the naming style used by BuildFilenameWithOptions.
+// The zero value matches BuildFilename: key plus a slug of at most 64 bytes.
+type FilenameOptions struct {
+	Style      string
+	MaxSlugLen int
+}
 
 // StableSlug renders deterministic lowercase slugs for filenames.
 func StableSlug(summary string) string {
+	return truncatedSlug(summary, maxSlugLen)
+}
+
+func truncatedSlug(summary string, limit int) string {
 	lower := strings.ToLower(strings.TrimSpace(summary))
 	if lower == "" {
 		return fallbackSlug
@@ -41,8 +54,8 @@ func StableSlug(summary string) string {
 	if slug == "" {
 		return fallbackSlug
 	}
-	if len(slug) > maxSlugLen {
-		slug = strings.Trim(slug[:maxSlugLen], "-")
+	if len(slug) > limit {
+		slug = strings.Trim(slug[:limit], "-")
 		if slug == "" {
 			return fallbackSlug
 		}
@@ -52,7 +65,14 @@ func StableSlug(summary string) string {
 
 // BuildFilename renders stable issue filenames from key+summary.
 func BuildFilename(key, summary string) (string, error) {
-	if !contracts.JiraIssueKeyPattern.MatchString(key) && !contracts.LocalDraftKeyPattern.MatchString(key) {
+	return BuildFilenameWithOptions(key, summary, FilenameOptions{})
+}
+
+// BuildFilenameWithOptions renders issue filenames in the configured style:
+// "KEY.md" for key-only, otherwise "KEY-<slug>.md" with the slug capped at
+// MaxSlugLen bytes (64 when unset).
+func BuildFilenameWithOptions(key, summary string, options FilenameOptions) (string, error) {
+	if !contracts.JiraIssueKeyPattern.MatchString(key) && !contracts.IsLocalDraftKey(key) {
 		return "", &ParseError{
 			Code:       ParseErrorCodeInvalidIssueKey,
 			ReasonCode: contracts.ReasonCodeValidationFailed,
@@ -60,7 +80,15 @@ func BuildFilename(key, summary string) (string, error) {
 			Message:    "issue key does not match supported key formats",
 		}
 	}
-	return fmt.Sprintf("%s-%s.md", key, StableSlug(summary)), nil
+	if options.Style == contracts.FilenameStyleKeyOnly {
+		return key + ".md", nil
+	}
+
+	limit := options.MaxSlugLen
+	if limit <= 0 {
+		limit = maxSlugLen
+	}
+	return fmt.Sprintf("%s-%s.md", key, truncatedSlug(summary, limit)), nil
 }
 
 // ParseFilenameKey extracts a key prefix from a canonical issue filename.
@@ -70,5 +98,8 @@ func ParseFilenameKey(path string) (string, bool) {
 	if len(match) != 2 {
 		return "", false
 	}
+	if !contracts.JiraIssueKeyPattern.MatchString(match[1]) && !contracts.IsLocalDraftKey(match[1]) {
+		return "", false
+	}
 	return match[1], true
 }
@@ -1,6 +1,10 @@
 package issue
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
+)
 
 func TestBuildFilenameUsesStableSlug(t *testing.T) {
 	filename, err := BuildFilename("PROJ-123", " Fix Login: Flow! ")
@@ -31,4 +35,66 @@ func TestParseFilenameKey(t *testing.T) {
 	if key != "L-1a2b3c" {
 		t.Fatalf("unexpected key: %s", key)
 	}
+
+	key, ok = ParseFilenameKey("/tmp/D-1082f0-hello-draft.md")
+	if !ok || key != "D-1082f0" {
+		t.Fatalf("expected custom-prefix draft key, got %q (ok=%v)", key, ok)
+	}
+	if key, ok := ParseFilenameKey("/tmp/PROJ-1a2b-notes.md"); ok {
+		t.Fatalf("expected Jira-shaped draft prefix to be rejected, got %q", key)
+	}
+}
+
+func TestBuildFilenameWithOptionsStyles(t *testing.T) {
+	cases := []struct {
+		name    string
+		options FilenameOptions
+		want    string
+	}{
+		{name: "default", options: FilenameOptions{}, want: "PROJ-7-fix-login-flow.md"},
+		{name: "key-summary", options: FilenameOptions{Style: contracts.FilenameStyleKeySummary}, want: "PROJ-7-fix-login-flow.md"},
+		{name: "key-only", options: FilenameOptions{Style: contracts.FilenameStyleKeyOnly, MaxSlugLen: 5}, want: "PROJ-7.md"},
+		{name: "max-slug-len", options: FilenameOptions{Style: contracts.FilenameStyleKeySummary, MaxSlugLen: 9}, want: "PROJ-7-fix-login.md"},
+	}
+
+	for _, tc := range cases {
+		filename, err := BuildFilenameWithOptions("PROJ-7", "Fix Login Flow", tc.options)
+		if err != nil {
+			t.Fatalf("%s: build failed: %v", tc.name, err)
+		}
+		if filename != tc.want {
+			t.Fatalf("%s: expected %q, got %q", tc.name, tc.want, filename)
+		}
+		if key, ok := ParseFilenameKey(filename); !ok || key != "PROJ-7" {
+			t.Fatalf("%s: expected filename key to round-trip, got %q", tc.name, key)
+		}
+	}
+}
+
+func TestBuildFilenameWithOptionsTruncationIsDeterministic(t *testing.T) {
+	options := FilenameOptions{MaxSlugLen: 10}
+	summary := "Refactor the --- sync pipeline for speed"
+
+	first, err := BuildFilenameWithOptions("PROJ-8", summary, options)
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		again, err := BuildFilenameWithOptions("PROJ-8", summary, options)
+		if err != nil || again != first {
+			t.Fatalf("expected stable filename %q, got %q (%v)", first, again, err)
+		}
+	}
+	if first != "PROJ-8-refactor-t.md" {
+		t.Fatalf("unexpected truncated filename: %s", first)
+	}
+
+	// A cut landing on a separator must not leave a trailing hyphen.
+	trimmed, err := BuildFilenameWithOptions("PROJ-8", summary, FilenameOptions{MaxSlugLen: 9})
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	if trimmed != "PROJ-8-refactor.md" {
+		t.Fatalf("unexpected truncated filename: %s", trimmed)
+	}
 }
@@ -17,6 +17,7 @@ type FrontMatter struct {
 	Assignee         string
 	Labels           []string
 	Reporter         string
+	SecurityLevel    string
 	CreatedAt        string
 	UpdatedAt        string
 	SyncedAt         string
@@ -30,6 +31,13 @@ type Document struct {
 	FrontMatter  FrontMatter
 	MarkdownBody string
 	RawADFJSON   string
+	// EnvironmentMarkdown and EnvironmentADFJSON hold the Jira environment
+	// field, rendered after the description in its own labeled section.
+	EnvironmentMarkdown string
+	EnvironmentADFJSON  string
+	// Comments is the read-only comments section rendered last. It is kept
+	// verbatim and never pushed.
+	Comments string
 }
 
 // CanonicalFrontMatterOrder is the deterministic render order.
@@ -43,6 +51,7 @@ var CanonicalFrontMatterOrder = []contracts.FrontMatterKey{
 	contracts.FrontMatterKeyAssignee,
 	contracts.FrontMatterKeyLabels,
 	contracts.FrontMatterKeyReporter,
+	contracts.FrontMatterKeySecurityLevel,
 	contracts.FrontMatterKeyCreatedAt,
 	contracts.FrontMatterKeyUpdatedAt,
 	contracts.FrontMatterKeySyncedAt,
@@ -0,0 +1,59 @@
+package issue
+
+import (
+	"strings"
+
+	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
+)
+
+// VolatileFrontMatterKeys are rewritten by every sync without reflecting a
+// local or remote edit. Change detection in pull, push, status, and diff
+// ignores them.
+var VolatileFrontMatterKeys = []contracts.FrontMatterKey{
+	contracts.FrontMatterKeySyncedAt,
+}
+
+// StripVolatileFrontMatter normalizes line endings and drops volatile keys
+// from the front matter block of an issue document. The body is untouched,
+// so a body line that happens to start with "synced_at:" still counts.
+func StripVolatileFrontMatter(text string) string {
+	normalized := contracts.NormalizeSingleValue(contracts.NormalizationNormalizeLineEndings, text)
+	lines := strings.Split(normalized, "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != contracts.FrontMatterDelimiter {
+		return normalized
+	}
+
+	filtered := make([]string, 0, len(lines))
+	filtered = append(filtered, lines[0])
+	inFrontMatter := true
+	for _, line := range lines[1:] {
+		if inFrontMatter {
+			if strings.TrimSpace(line) == contracts.FrontMatterDelimiter {
+				inFrontMatter = false
+			} else if isVolatileFrontMatterLine(line) {
+				continue
+			}
+		}
+		filtered = append(filtered, line)
+	}
+	return strings.Join(filtered, "\n")
+}
+
+// EqualIgnoringVolatile reports whether two rendered documents match once
+// volatile front matter is removed.
+func EqualIgnoringVolatile(left string, right string) bool {
+	return StripVolatileFrontMatter(left) == StripVolatileFrontMatter(right)
+}
+
+func isVolatileFrontMatterLine(line string) bool {
+	key, _, ok := strings.Cut(strings.TrimSpace(line), ":")
+	if !ok {
+		return false
+	}
+	for _, volatile := range VolatileFrontMatterKeys {
+		if strings.TrimSpace(key) == string(volatile) {
+			return true
+		}
+	}
+	return false
+}
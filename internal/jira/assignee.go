@@ -0,0 +1,63 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
+)
+
+// ResolveAssignee returns the accountId push sends for a front-matter
+// assignee value; empty unassigns. Literal accountIds (see
+// contracts.ParseAssigneeAccountID) are checked for plausibility and sent
+// without a lookup, and so is any bare value shaped like an accountId, such
+// as the one pull writes. Only a value that cannot be an accountId, like an
+// email or a display name, is looked up: it must match exactly one user
+// assignable in projectKey by email or display name, ignoring case.
+func ResolveAssignee(ctx context.Context, adapter Adapter, projectKey string, value string, valuesAreAccountIDs bool) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", nil
+	}
+	if accountID, literal := contracts.ParseAssigneeAccountID(value, valuesAreAccountIDs); literal {
+		if !contracts.IsPlausibleAccountID(accountID) {
+			return "", &Error{
+				Code:       ErrorCodeInvalidInput,
+				ReasonCode: contracts.ReasonCodeValidationFailed,
+				Message:    fmt.Sprintf("assignee %q is not a valid Jira accountId", value),
+			}
+		}
+		return accountID, nil
+	}
+	if contracts.IsPlausibleAccountID(value) {
+		return value, nil
+	}
+
+	users, err := adapter.SearchAssignableUsers(ctx, projectKey, value)
+	if err != nil {
+		return "", fmt.Errorf("failed to look up assignee %q: %w", value, err)
+	}
+	matches := make([]string, 0, 1)
+	for _, user := range users {
+		if strings.EqualFold(user.Email, value) || strings.EqualFold(user.DisplayName, value) {
+			matches = append(matches, user.AccountID)
+		}
+	}
+	switch len(matches) {
+	case 1:
+		return matches[0], nil
+	case 0:
+		return "", &Error{
+			Code:       ErrorCodeInvalidInput,
+			ReasonCode: contracts.ReasonCodeValidationFailed,
+			Message:    fmt.Sprintf("no user assignable in %s matches assignee %q", projectKey, value),
+		}
+	default:
+		return "", &Error{
+			Code:       ErrorCodeInvalidInput,
+			ReasonCode: contracts.ReasonCodeValidationFailed,
+			Message:    fmt.Sprintf("assignee %q matches %d users assignable in %s; use %s<id>", value, len(matches), projectKey, contracts.AssigneeAccountIDPrefix),
+		}
+	}
+}
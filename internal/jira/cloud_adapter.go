@@ -9,22 +9,63 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"regexp"
+	"runtime/debug"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/pweiskircher/jira-issue-sync/internal/clock"
 	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
 	httpclient "github.com/pweiskircher/jira-issue-sync/internal/http"
+	"github.com/pweiskircher/jira-issue-sync/internal/logging"
 )
 
 const maxResponseBodyBytes = 10 << 20
 
+// Version names this build in the default User-Agent. Release builds set it
+// with -ldflags "-X github.com/pweiskircher/jira-issue-sync/internal/jira.Version=v1.2.3";
+// otherwise the module version from `go install` is used, then "dev".
+var Version = ""
+
 type CloudAdapterOptions struct {
 	BaseURL      string
 	Email        string
 	APIToken     string
 	HTTPDoer     httpclient.Doer
 	RetryOptions httpclient.Options
+	Logger       logging.Logger
+	// RedactionPatterns add user-configured expressions to the built-in
+	// secret redaction.
+	RedactionPatterns []*regexp.Regexp
+	// FieldNames maps field IDs to display names. Keys of Jira's "errors"
+	// object found here are shown by name in error messages.
+	FieldNames map[string]string
+	// UserAgent replaces the default "jira-issue-sync/<version>" header.
+	UserAgent string
+	// Headers are sent with every request, for gateways that need their own
+	// tokens. A name that matches a built-in header such as Authorization
+	// replaces it. Values of credential headers (see isCredentialHeader) and
+	// of headers named in SensitiveHeaders are redacted; other values, such
+	// as X-Atlassian-Token: no-check, are left readable.
+	Headers          map[string]string
+	SensitiveHeaders []string
+	// CABundlePath adds PEM certificates to the system roots, for Jira
+	// servers signed by a private CA.
+	CABundlePath string
+	// ClientCertPath and ClientKeyPath name a PEM client certificate and key
+	// for servers that require mutual TLS. Both must be set together.
+	ClientCertPath string
+	ClientKeyPath  string
+	// InsecureSkipVerify disables server certificate checks. It exists for
+	// dev servers with self-signed certificates and excludes CABundlePath.
+	InsecureSkipVerify bool
+	// MinTLSVersion is the lowest TLS version negotiated, "1.2" (the
+	// default) or "1.3"; anything else fails construction. CipherSuites
+	// restricts TLS 1.2 to the named suites and requires a 1.2 minimum.
+	MinTLSVersion string
+	CipherSuites  []string
 }
 
 type CloudAdapter struct {
@@ -32,10 +73,15 @@ type CloudAdapter struct {
 	authHeader string
 	client     *httpclient.RetryClient
 	redactor   httpclient.Redactor
+	now        func() time.Time
+	fieldNames map[string]string
+	logger     logging.Logger
+	userAgent  string
+	headers    http.Header
 }
 
 func NewCloudAdapter(options CloudAdapterOptions) (*CloudAdapter, error) {
-	baseURL, err := normalizeBaseURL(options.BaseURL)
+	baseURL, urlSecrets, err := normalizeBaseURL(options.BaseURL)
 	if err != nil {
 		return nil, err
 	}
@@ -58,18 +104,93 @@ func NewCloudAdapter(options CloudAdapterOptions) (*CloudAdapter, error) {
 		}
 	}
 
+	sensitive := make(map[string]bool, len(options.SensitiveHeaders))
+	for _, name := range options.SensitiveHeaders {
+		sensitive[http.CanonicalHeaderKey(strings.TrimSpace(name))] = true
+	}
+	headers := make(http.Header, len(options.Headers))
+	headerSecrets := make([]string, 0, len(options.Headers))
+	for name, value := range options.Headers {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, &Error{
+				Code:       ErrorCodeInvalidInput,
+				ReasonCode: contracts.ReasonCodeValidationFailed,
+				Message:    "invalid jira adapter options: header names must not be empty",
+			}
+		}
+		headers.Set(name, value)
+		if sensitive[http.CanonicalHeaderKey(name)] || isCredentialHeader(name) {
+			headerSecrets = append(headerSecrets, value)
+		}
+	}
+
 	authSecret := email + ":" + token
 	authHeader := "Basic " + base64.StdEncoding.EncodeToString([]byte(authSecret))
-	redactor := httpclient.NewRedactor(token, authSecret, authHeader)
+	secrets := append([]string{token, authSecret, authHeader}, urlSecrets...)
+	redactor := httpclient.NewRedactor(append(secrets, headerSecrets...)...).WithPatterns(options.RedactionPatterns...)
+
+	retryOptions := options.RetryOptions
+	tlsConfig, err := tlsConfigFor(options)
+	if err != nil {
+		return nil, err
+	}
+	retryOptions.TLSConfig = tlsConfig
+	if options.Logger != nil {
+		retryOptions.Logger = options.Logger
+	}
+	retryOptions.Logger = logging.WithRedaction(retryOptions.Logger, redactor.Redact)
+
+	userAgent := strings.TrimSpace(options.UserAgent)
+	if userAgent == "" {
+		userAgent = DefaultUserAgent()
+	}
 
 	return &CloudAdapter{
 		baseURL:    baseURL,
 		authHeader: authHeader,
-		client:     httpclient.NewRetryClient(options.HTTPDoer, options.RetryOptions),
+		client:     httpclient.NewRetryClient(options.HTTPDoer, retryOptions),
 		redactor:   redactor,
+		now:        clock.OrSystem(options.RetryOptions.Clock).Now,
+		fieldNames: options.FieldNames,
+		logger:     retryOptions.Logger,
+		userAgent:  userAgent,
+		headers:    headers,
 	}, nil
 }
 
+// credentialHeaderWords mark header names that carry credentials.
+var credentialHeaderWords = []string{"auth", "token", "secret", "password", "session", "cookie", "api-key", "apikey"}
+
+// isCredentialHeader reports whether a custom header likely carries a
+// credential. X-Atlassian-Token only switches off Jira's XSRF check.
+func isCredentialHeader(name string) bool {
+	lowered := strings.ToLower(name)
+	if lowered == "x-atlassian-token" {
+		return false
+	}
+	for _, word := range credentialHeaderWords {
+		if strings.Contains(lowered, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultUserAgent identifies this tool and its version to Jira.
+func DefaultUserAgent() string {
+	version := strings.TrimSpace(Version)
+	if version == "" {
+		if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+			version = info.Main.Version
+		}
+	}
+	if version == "" {
+		version = "dev"
+	}
+	return "jira-issue-sync/" + version
+}
+
 func (a *CloudAdapter) SearchIssues(ctx context.Context, request SearchIssuesRequest) (SearchIssuesResponse, error) {
 	if a == nil {
 		return SearchIssuesResponse{}, &Error{Code: ErrorCodeInvalidInput, Message: "jira adapter is nil"}
@@ -77,8 +198,12 @@ func (a *CloudAdapter) SearchIssues(ctx context.Context, request SearchIssuesReq
 
 	query := url.Values{}
 	query.Set("jql", request.JQL)
-	if request.MaxResults > 0 {
-		query.Set("maxResults", strconv.Itoa(request.MaxResults))
+	if maxResults := request.MaxResults; maxResults > 0 {
+		if maxResults > contracts.MaxSearchPageSize {
+			logging.Warnf(a.logger, "search page size %d exceeds the maximum of %d; requesting %d", maxResults, contracts.MaxSearchPageSize, contracts.MaxSearchPageSize)
+			maxResults = contracts.MaxSearchPageSize
+		}
+		query.Set("maxResults", strconv.Itoa(maxResults))
 	}
 	if fields := normalizeStringSlice(request.Fields); len(fields) > 0 {
 		query.Set("fields", strings.Join(fields, ","))
@@ -132,6 +257,147 @@ func (a *CloudAdapter) ListFields(ctx context.Context) ([]FieldDefinition, error
 	return fields, nil
 }
 
+// listProjectsPageSize is the page size requested from project/search;
+// Jira caps it at 50 regardless.
+const listProjectsPageSize = 50
+
+// ListProjects returns every project visible to the configured account,
+// following project/search pagination until Jira reports the last page.
+func (a *CloudAdapter) ListProjects(ctx context.Context) ([]Project, error) {
+	if a == nil {
+		return nil, &Error{Code: ErrorCodeInvalidInput, Message: "jira adapter is nil"}
+	}
+
+	projects := make([]Project, 0)
+	startAt := 0
+	for {
+		query := url.Values{}
+		query.Set("startAt", strconv.Itoa(startAt))
+		query.Set("maxResults", strconv.Itoa(listProjectsPageSize))
+
+		var response projectSearchAPIResponse
+		if err := a.doJSON(ctx, http.MethodGet, "/rest/api/3/project/search", query, nil, []int{http.StatusOK}, &response); err != nil {
+			return nil, err
+		}
+
+		for _, item := range response.Values {
+			key := strings.TrimSpace(item.Key)
+			if key == "" {
+				continue
+			}
+			projects = append(projects, Project{
+				ID:   strings.TrimSpace(item.ID),
+				Key:  key,
+				Name: strings.TrimSpace(item.Name),
+			})
+		}
+
+		if response.IsLast || len(response.Values) == 0 {
+			return projects, nil
+		}
+		startAt += len(response.Values)
+		if response.Total > 0 && startAt >= response.Total {
+			return projects, nil
+		}
+	}
+}
+
+// GetMyself returns the account the adapter authenticates as.
+// ValidateJQL asks Jira to parse jql with strict validation. Syntax errors
+// and unknown fields or values come back as an ErrorCodeInvalidJQL error
+// carrying Jira's messages.
+func (a *CloudAdapter) ValidateJQL(ctx context.Context, jql string) error {
+	if a == nil {
+		return &Error{Code: ErrorCodeInvalidInput, Message: "jira adapter is nil"}
+	}
+
+	query := url.Values{}
+	query.Set("validation", "strict")
+	payload := map[string][]string{"queries": {jql}}
+
+	var response jqlParseAPIResponse
+	// Parsing only reads, so a timed-out attempt can be resent safely.
+	if err := a.doJSON(httpclient.WithIdempotentRetry(ctx), http.MethodPost, "/rest/api/3/jql/parse", query, payload, []int{http.StatusOK}, &response); err != nil {
+		return err
+	}
+	for _, parsed := range response.Queries {
+		if len(parsed.Errors) > 0 {
+			return &Error{
+				Code:       ErrorCodeInvalidJQL,
+				ReasonCode: contracts.ReasonCodeValidationFailed,
+				Message:    "invalid JQL: " + strings.Join(parsed.Errors, "; "),
+				redactor:   a.redactor,
+			}
+		}
+	}
+	return nil
+}
+
+func (a *CloudAdapter) GetMyself(ctx context.Context) (AccountRef, error) {
+	if a == nil {
+		return AccountRef{}, &Error{Code: ErrorCodeInvalidInput, Message: "jira adapter is nil"}
+	}
+
+	var response accountAPIRef
+	if err := a.doJSON(ctx, http.MethodGet, "/rest/api/3/myself", nil, nil, []int{http.StatusOK}, &response); err != nil {
+		return AccountRef{}, err
+	}
+	account := mapAccountRef(&response)
+	if account.AccountID == "" {
+		return AccountRef{}, &Error{Code: ErrorCodeResponseDecode, ReasonCode: contracts.ReasonCodeValidationFailed, Message: "jira myself response has no accountId"}
+	}
+	return *account, nil
+}
+
+// ListStatuses returns every workflow status the account can see.
+func (a *CloudAdapter) ListStatuses(ctx context.Context) ([]StatusRef, error) {
+	if a == nil {
+		return nil, &Error{Code: ErrorCodeInvalidInput, Message: "jira adapter is nil"}
+	}
+
+	var response []namedAPIRef
+	if err := a.doJSON(ctx, http.MethodGet, "/rest/api/3/status", nil, nil, []int{http.StatusOK}, &response); err != nil {
+		return nil, err
+	}
+	statuses := make([]StatusRef, 0, len(response))
+	for index := range response {
+		if status := mapStatusRef(&response[index]); status.Name != "" {
+			statuses = append(statuses, *status)
+		}
+	}
+	return statuses, nil
+}
+
+// ListSecurityLevels returns the issue security levels of projectKey that
+// the account can see.
+func (a *CloudAdapter) ListSecurityLevels(ctx context.Context, projectKey string) ([]NamedRef, error) {
+	if a == nil {
+		return nil, &Error{Code: ErrorCodeInvalidInput, Message: "jira adapter is nil"}
+	}
+
+	projectKey = strings.TrimSpace(projectKey)
+	if projectKey == "" {
+		return nil, &Error{
+			Code:       ErrorCodeInvalidInput,
+			ReasonCode: contracts.ReasonCodeValidationFailed,
+			Message:    "invalid security level request: project key must be set",
+		}
+	}
+
+	var response securityLevelsAPIResponse
+	resourcePath := "/rest/api/3/project/" + url.PathEscape(projectKey) + "/securitylevel"
+	if err := a.doJSON(ctx, http.MethodGet, resourcePath, nil, nil, []int{http.StatusOK}, &response); err != nil {
+		return nil, err
+	}
+	levels := make([]NamedRef, 0, len(response.Levels))
+	for index := range response.Levels {
+		if level := mapNamedRef(&response.Levels[index]); level.ID != "" && level.Name != "" {
+			levels = append(levels, *level)
+		}
+	}
+	return levels, nil
+}
+
 func (a *CloudAdapter) GetIssue(ctx context.Context, issueKey string, fields []string) (Issue, error) {
 	if a == nil {
 		return Issue{}, &Error{Code: ErrorCodeInvalidInput, Message: "jira adapter is nil"}
@@ -205,6 +471,16 @@ func (a *CloudAdapter) CreateIssue(ctx context.Context, request CreateIssueReque
 	if priority := strings.TrimSpace(request.PriorityName); priority != "" {
 		fields["priority"] = map[string]string{"name": priority}
 	}
+	if level := request.SecurityLevel; level != nil {
+		if id := strings.TrimSpace(level.ID); id != "" {
+			fields["security"] = map[string]string{"id": id}
+		} else if name := strings.TrimSpace(level.Name); name != "" {
+			fields["security"] = map[string]string{"name": name}
+		}
+	}
+	if err := addCustomFields(fields, request.CustomFields, "create"); err != nil {
+		return CreatedIssue{}, err
+	}
 
 	payload := map[string]any{"fields": fields}
 	var response createdIssueAPIResponse
@@ -236,6 +512,13 @@ func (a *CloudAdapter) UpdateIssue(ctx context.Context, issueKey string, request
 			fields["description"] = json.RawMessage(*request.Description)
 		}
 	}
+	if request.Environment != nil {
+		if len(*request.Environment) == 0 {
+			fields["environment"] = nil
+		} else {
+			fields["environment"] = json.RawMessage(*request.Environment)
+		}
+	}
 	if request.Labels != nil {
 		fields["labels"] = normalizeStringSlice(*request.Labels)
 	}
@@ -255,6 +538,9 @@ func (a *CloudAdapter) UpdateIssue(ctx context.Context, issueKey string, request
 			fields["priority"] = map[string]string{"name": priority}
 		}
 	}
+	if err := addCustomFields(fields, request.CustomFields, "update"); err != nil {
+		return err
+	}
 
 	if len(fields) == 0 {
 		return nil
@@ -329,6 +615,82 @@ func (a *CloudAdapter) ResolveTransition(ctx context.Context, issueKey string, s
 	return resolveTransitionSelection(transitions, selection), nil
 }
 
+// maxAssignableUsers bounds one assignable user search. Assignee
+// resolution needs a single exact match, so more candidates than this only
+// means the query is too vague.
+const maxAssignableUsers = 50
+
+// SearchAssignableUsers returns the users that can be assigned issues in
+// projectKey whose display name or email matches query.
+func (a *CloudAdapter) SearchAssignableUsers(ctx context.Context, projectKey string, query string) ([]AccountRef, error) {
+	if a == nil {
+		return nil, &Error{Code: ErrorCodeInvalidInput, Message: "jira adapter is nil"}
+	}
+
+	projectKey = strings.TrimSpace(projectKey)
+	query = strings.TrimSpace(query)
+	if projectKey == "" || query == "" {
+		return nil, &Error{
+			Code:       ErrorCodeInvalidInput,
+			ReasonCode: contracts.ReasonCodeValidationFailed,
+			Message:    "invalid assignable user search: project key and query must be set",
+		}
+	}
+
+	values := url.Values{}
+	values.Set("project", projectKey)
+	values.Set("query", query)
+	values.Set("maxResults", strconv.Itoa(maxAssignableUsers))
+
+	var response []accountAPIRef
+	if err := a.doJSON(ctx, http.MethodGet, "/rest/api/3/user/assignable/search", values, nil, []int{http.StatusOK}, &response); err != nil {
+		return nil, err
+	}
+	users := make([]AccountRef, 0, len(response))
+	for index := range response {
+		if user := mapAccountRef(&response[index]); user != nil && user.AccountID != "" {
+			users = append(users, *user)
+		}
+	}
+	return users, nil
+}
+
+// addCustomFields merges custom field values into a fields payload. Each
+// value is re-encoded so nested object keys come out sorted, like the map
+// keys around them: the same request always serializes to the same bytes.
+func addCustomFields(fields map[string]any, custom map[string]json.RawMessage, operation string) error {
+	for id, raw := range custom {
+		id = strings.TrimSpace(id)
+		if _, builtIn := fields[id]; builtIn || id == "" {
+			return &Error{
+				Code:       ErrorCodeInvalidInput,
+				ReasonCode: contracts.ReasonCodeValidationFailed,
+				Message:    fmt.Sprintf("invalid %s issue request: custom field id %q is empty or collides with a built-in field", operation, id),
+			}
+		}
+
+		// UseNumber keeps large numbers, such as 64-bit ids, digit for
+		// digit instead of rounding them through float64.
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		decoder.UseNumber()
+		var value any
+		err := decoder.Decode(&value)
+		if err == nil && decoder.More() {
+			err = fmt.Errorf("unexpected data after the JSON value")
+		}
+		if err != nil {
+			return &Error{
+				Code:       ErrorCodeInvalidInput,
+				ReasonCode: contracts.ReasonCodeValidationFailed,
+				Message:    fmt.Sprintf("invalid %s issue request: custom field %s is not valid JSON", operation, id),
+				Err:        err,
+			}
+		}
+		fields[id] = value
+	}
+	return nil
+}
+
 func (a *CloudAdapter) doJSON(ctx context.Context, method string, resourcePath string, query url.Values, payload any, expectedStatusCodes []int, out any) error {
 	if len(expectedStatusCodes) == 0 {
 		expectedStatusCodes = []int{http.StatusOK}
@@ -376,6 +738,10 @@ func (a *CloudAdapter) doJSON(ctx context.Context, method string, resourcePath s
 		req.Header.Set("Content-Type", "application/json")
 	}
 	req.Header.Set("Authorization", a.authHeader)
+	req.Header.Set("User-Agent", a.userAgent)
+	for name, values := range a.headers {
+		req.Header[name] = append([]string(nil), values...)
+	}
 
 	resp, err := a.client.Do(req)
 	if err != nil {
@@ -411,7 +777,7 @@ func (a *CloudAdapter) doJSON(ctx context.Context, method string, resourcePath s
 	}
 
 	if !containsStatus(expectedStatusCodes, resp.StatusCode) {
-		return a.statusError(resp.StatusCode, responseBody)
+		return a.statusError(resp.StatusCode, resp.Header, responseBody)
 	}
 
 	if out == nil || len(responseBody) == 0 {
@@ -432,11 +798,15 @@ func (a *CloudAdapter) doJSON(ctx context.Context, method string, resourcePath s
 	return nil
 }
 
-func (a *CloudAdapter) statusError(statusCode int, body []byte) error {
-	detail := extractAPIErrorMessage(body)
+func (a *CloudAdapter) statusError(statusCode int, header http.Header, body []byte) error {
+	detail := extractAPIErrorMessage(body, a.fieldNames)
 	if detail == "" {
 		detail = strings.ToLower(http.StatusText(statusCode))
 	}
+	requestID := responseRequestID(header)
+	if requestID != "" {
+		detail += " (jira request id " + requestID + ")"
+	}
 
 	if statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden {
 		return &Error{
@@ -445,6 +815,26 @@ func (a *CloudAdapter) statusError(statusCode int, body []byte) error {
 			StatusCode: statusCode,
 			Message:    fmt.Sprintf("jira authentication failed with status %d: %s", statusCode, detail),
 			redactor:   a.redactor,
+			requestID:  requestID,
+		}
+	}
+
+	// A 429 only reaches here once the retry client has given up, so surface
+	// it as its own outcome with Jira's suggested wait.
+	if statusCode == http.StatusTooManyRequests {
+		retryAfter := httpclient.ParseRetryAfter(header.Get("Retry-After"), a.now())
+		message := fmt.Sprintf("jira rate limit persisted after retries (status %d): %s", statusCode, detail)
+		if retryAfter > 0 {
+			message += fmt.Sprintf("; retry after %s", retryAfter)
+		}
+		return &Error{
+			Code:       ErrorCodeRateLimited,
+			ReasonCode: contracts.ReasonCodeRateLimited,
+			StatusCode: statusCode,
+			Message:    message,
+			RetryAfter: retryAfter,
+			redactor:   a.redactor,
+			requestID:  requestID,
 		}
 	}
 
@@ -454,7 +844,28 @@ func (a *CloudAdapter) statusError(statusCode int, body []byte) error {
 		StatusCode: statusCode,
 		Message:    fmt.Sprintf("jira request failed with status %d: %s", statusCode, detail),
 		redactor:   a.redactor,
+		requestID:  requestID,
+	}
+}
+
+// maxRequestIDLength bounds the X-ARequestId value kept on errors; Jira's
+// IDs are far shorter.
+const maxRequestIDLength = 128
+
+// responseRequestID returns Jira's X-ARequestId header when it looks like an
+// ID. Anything else, such as a proxy echoing request data, is dropped so it
+// cannot smuggle text into error messages.
+func responseRequestID(header http.Header) string {
+	requestID := strings.TrimSpace(header.Get("X-ARequestId"))
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return ""
+	}
+	for _, r := range requestID {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.') {
+			return ""
+		}
 	}
+	return requestID
 }
 
 func (a *CloudAdapter) endpointFor(resourcePath string, query url.Values) (string, error) {
@@ -464,17 +875,25 @@ func (a *CloudAdapter) endpointFor(resourcePath string, query url.Values) (strin
 		return "", err
 	}
 
+	// Join on both the decoded and escaped forms so a context path such as
+	// "/jira" (or one with escaped characters) survives unchanged.
+	escapedBase := strings.TrimRight(parsedBase.EscapedPath(), "/")
 	parsedBase.Path = strings.TrimRight(parsedBase.Path, "/") + trimmedPath
+	parsedBase.RawPath = escapedBase + trimmedPath
 	if len(query) > 0 {
 		parsedBase.RawQuery = query.Encode()
 	}
 	return parsedBase.String(), nil
 }
 
-func normalizeBaseURL(baseURL string) (string, error) {
+// normalizeBaseURL canonicalizes the Jira base URL. Any userinfo embedded in
+// it is stripped (auth always goes through the Authorization header); a
+// password and its user:password pair are returned so the caller can
+// register them with the redactor.
+func normalizeBaseURL(baseURL string) (string, []string, error) {
 	trimmed := strings.TrimSpace(baseURL)
 	if trimmed == "" {
-		return "", &Error{
+		return "", nil, &Error{
 			Code:       ErrorCodeInvalidInput,
 			ReasonCode: contracts.ReasonCodeValidationFailed,
 			Message:    "invalid jira adapter options: base URL must be set",
@@ -483,7 +902,7 @@ func normalizeBaseURL(baseURL string) (string, error) {
 
 	parsed, err := url.Parse(trimmed)
 	if err != nil {
-		return "", &Error{
+		return "", nil, &Error{
 			Code:       ErrorCodeInvalidInput,
 			ReasonCode: contracts.ReasonCodeValidationFailed,
 			Message:    "invalid jira adapter options: base URL is malformed",
@@ -492,17 +911,28 @@ func normalizeBaseURL(baseURL string) (string, error) {
 	}
 
 	if parsed.Scheme == "" || parsed.Host == "" {
-		return "", &Error{
+		return "", nil, &Error{
 			Code:       ErrorCodeInvalidInput,
 			ReasonCode: contracts.ReasonCodeValidationFailed,
 			Message:    "invalid jira adapter options: base URL must include scheme and host",
 		}
 	}
 
+	var strippedSecrets []string
+	if parsed.User != nil {
+		// A bare username is not secret, and redacting it would mangle
+		// every message that mentions it; only a password is registered.
+		if password, ok := parsed.User.Password(); ok && password != "" {
+			strippedSecrets = append(strippedSecrets, parsed.User.String(), password)
+		}
+		parsed.User = nil
+	}
+
+	parsed.RawPath = strings.TrimRight(parsed.EscapedPath(), "/")
 	parsed.Path = strings.TrimRight(parsed.Path, "/")
 	parsed.RawQuery = ""
 	parsed.Fragment = ""
-	return parsed.String(), nil
+	return parsed.String(), strippedSecrets, nil
 }
 
 func validateIssueKey(issueKey string) (string, error) {
@@ -542,7 +972,9 @@ func normalizeStringSlice(values []string) []string {
 	return normalized
 }
 
-func extractAPIErrorMessage(body []byte) string {
+// extractAPIErrorMessage flattens a Jira error body. Field keys in the
+// "errors" object are replaced by their names from fieldNames when known.
+func extractAPIErrorMessage(body []byte, fieldNames map[string]string) string {
 	trimmed := strings.TrimSpace(string(body))
 	if trimmed == "" {
 		return ""
@@ -580,7 +1012,11 @@ func extractAPIErrorMessage(body []byte) string {
 			if value == "" {
 				continue
 			}
-			parts = append(parts, fmt.Sprintf("%s: %s", key, value))
+			label := key
+			if name := strings.TrimSpace(fieldNames[key]); name != "" {
+				label = name
+			}
+			parts = append(parts, fmt.Sprintf("%s: %s", label, value))
 		}
 	}
 
@@ -599,6 +1035,26 @@ type searchIssuesAPIResponse struct {
 	IsLast        bool               `json:"isLast"`
 }
 
+type jqlParseAPIResponse struct {
+	Queries []struct {
+		Query  string   `json:"query"`
+		Errors []string `json:"errors"`
+	} `json:"queries"`
+}
+
+type projectSearchAPIResponse struct {
+	StartAt int                  `json:"startAt"`
+	Total   int                  `json:"total"`
+	IsLast  bool                 `json:"isLast"`
+	Values  []projectAPIResponse `json:"values"`
+}
+
+type projectAPIResponse struct {
+	ID   string `json:"id"`
+	Key  string `json:"key"`
+	Name string `json:"name"`
+}
+
 type issueAPIResponse struct {
 	ID     string             `json:"id"`
 	Key    string             `json:"key"`
@@ -608,17 +1064,32 @@ type issueAPIResponse struct {
 type issueFieldsAPIData struct {
 	Summary      string                     `json:"summary"`
 	Description  json.RawMessage            `json:"description"`
+	Environment  json.RawMessage            `json:"environment"`
 	Labels       []string                   `json:"labels"`
 	Assignee     *accountAPIRef             `json:"assignee"`
 	Priority     *namedAPIRef               `json:"priority"`
 	Status       *namedAPIRef               `json:"status"`
 	IssueType    *namedAPIRef               `json:"issuetype"`
 	Reporter     *accountAPIRef             `json:"reporter"`
+	Security     *namedAPIRef               `json:"security"`
 	CreatedAt    string                     `json:"created"`
 	UpdatedAt    string                     `json:"updated"`
+	Comment      *commentPageAPIData        `json:"comment"`
 	CustomFields map[string]json.RawMessage `json:"-"`
 }
 
+type commentPageAPIData struct {
+	Comments []commentAPIData `json:"comments"`
+}
+
+type commentAPIData struct {
+	ID        string          `json:"id"`
+	Author    *accountAPIRef  `json:"author"`
+	Body      json.RawMessage `json:"body"`
+	CreatedAt string          `json:"created"`
+	UpdatedAt string          `json:"updated"`
+}
+
 func (f *issueFieldsAPIData) UnmarshalJSON(data []byte) error {
 	type issueFieldsAlias issueFieldsAPIData
 	var alias issueFieldsAlias
@@ -669,6 +1140,10 @@ type namedAPIRef struct {
 	Name string `json:"name"`
 }
 
+type securityLevelsAPIResponse struct {
+	Levels []namedAPIRef `json:"levels"`
+}
+
 type createdIssueAPIResponse struct {
 	ID   string `json:"id"`
 	Key  string `json:"key"`
@@ -698,19 +1173,39 @@ func mapAPIIssue(raw issueAPIResponse) Issue {
 		Fields: IssueFields{
 			Summary:      strings.TrimSpace(raw.Fields.Summary),
 			Description:  cloneRawJSON(raw.Fields.Description),
+			Environment:  cloneNonNullRawJSON(raw.Fields.Environment),
 			Labels:       normalizeStringSlice(raw.Fields.Labels),
 			Assignee:     mapAccountRef(raw.Fields.Assignee),
 			Priority:     mapNamedRef(raw.Fields.Priority),
 			Status:       mapStatusRef(raw.Fields.Status),
 			IssueType:    mapNamedRef(raw.Fields.IssueType),
 			Reporter:     mapAccountRef(raw.Fields.Reporter),
+			Security:     mapNamedRef(raw.Fields.Security),
 			CreatedAt:    strings.TrimSpace(raw.Fields.CreatedAt),
 			UpdatedAt:    strings.TrimSpace(raw.Fields.UpdatedAt),
 			CustomFields: cloneRawJSONMap(raw.Fields.CustomFields),
+			Comments:     mapComments(raw.Fields.Comment),
 		},
 	}
 }
 
+func mapComments(raw *commentPageAPIData) []Comment {
+	if raw == nil || len(raw.Comments) == 0 {
+		return nil
+	}
+	comments := make([]Comment, 0, len(raw.Comments))
+	for _, comment := range raw.Comments {
+		comments = append(comments, Comment{
+			ID:        strings.TrimSpace(comment.ID),
+			Author:    mapAccountRef(comment.Author),
+			Body:      cloneNonNullRawJSON(comment.Body),
+			CreatedAt: strings.TrimSpace(comment.CreatedAt),
+			UpdatedAt: strings.TrimSpace(comment.UpdatedAt),
+		})
+	}
+	return comments
+}
+
 func mapAccountRef(raw *accountAPIRef) *AccountRef {
 	if raw == nil {
 		return nil
@@ -743,6 +1238,15 @@ func cloneRawJSON(value json.RawMessage) json.RawMessage {
 	return append(json.RawMessage(nil), value...)
 }
 
+// cloneNonNullRawJSON treats a JSON null as absent; Jira reports an unset
+// environment as null.
+func cloneNonNullRawJSON(value json.RawMessage) json.RawMessage {
+	if strings.TrimSpace(string(value)) == "null" {
+		return nil
+	}
+	return cloneRawJSON(value)
+}
+
 func cloneRawJSONMap(values map[string]json.RawMessage) map[string]json.RawMessage {
 	if len(values) == 0 {
 		return nil
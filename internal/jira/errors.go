@@ -3,6 +3,7 @@ package jira
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
 	httpclient "github.com/pweiskircher/jira-issue-sync/internal/http"
@@ -16,8 +17,12 @@ const (
 	ErrorCodeRequestBuild     ErrorCode = "request_build_failed"
 	ErrorCodeTransport        ErrorCode = "transport_error"
 	ErrorCodeAuthFailed       ErrorCode = "auth_failed"
+	ErrorCodeRateLimited      ErrorCode = "rate_limited"
 	ErrorCodeUnexpectedStatus ErrorCode = "unexpected_status"
 	ErrorCodeResponseDecode   ErrorCode = "response_decode_failed"
+	// ErrorCodeInvalidJQL means Jira parsed the query and rejected it;
+	// other errors from ValidateJQL say nothing about the query itself.
+	ErrorCodeInvalidJQL ErrorCode = "invalid_jql"
 )
 
 type Error struct {
@@ -25,8 +30,12 @@ type Error struct {
 	ReasonCode contracts.ReasonCode
 	StatusCode int
 	Message    string
+	// RetryAfter is Jira's suggested wait from the last rate-limited
+	// response; zero when the header was absent.
+	RetryAfter time.Duration
 	Err        error
 	redactor   httpclient.Redactor
+	requestID  string
 }
 
 func (err *Error) Error() string {
@@ -44,6 +53,16 @@ func (err *Error) Error() string {
 	return err.redactor.Redact(fmt.Sprintf("%s: %v", base, err.Err))
 }
 
+// RequestID returns Jira's X-ARequestId for the failed response, which
+// Atlassian support can use to find the request in their logs. It is empty
+// when the error did not come from a Jira response.
+func (err *Error) RequestID() string {
+	if err == nil {
+		return ""
+	}
+	return err.redactor.Redact(err.requestID)
+}
+
 func (err *Error) Unwrap() error {
 	if err == nil {
 		return nil
@@ -0,0 +1,396 @@
+package jira
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
+	httpclient "github.com/pweiskircher/jira-issue-sync/internal/http"
+)
+
+// ErrorCodeReplayMiss means a replay adapter was asked for a call that is
+// not in its recording, or that has already been served.
+const ErrorCodeReplayMiss ErrorCode = "replay_miss"
+
+// recordedCall is one line of a recording file. Request holds the call's
+// arguments; exactly one of Response and Error is set.
+type recordedCall struct {
+	Method   string          `json:"method"`
+	Request  json.RawMessage `json:"request"`
+	Response json.RawMessage `json:"response,omitempty"`
+	Error    *recordedError  `json:"error,omitempty"`
+}
+
+type recordedError struct {
+	Code       ErrorCode            `json:"code,omitempty"`
+	ReasonCode contracts.ReasonCode `json:"reason_code,omitempty"`
+	StatusCode int                  `json:"status_code,omitempty"`
+	RequestID  string               `json:"request_id,omitempty"`
+	Message    string               `json:"message"`
+}
+
+type issueRequest struct {
+	IssueKey string   `json:"issue_key"`
+	Fields   []string `json:"fields,omitempty"`
+}
+
+type updateRequest struct {
+	IssueKey string             `json:"issue_key"`
+	Update   UpdateIssueRequest `json:"update"`
+}
+
+type transitionRequest struct {
+	IssueKey     string `json:"issue_key"`
+	TransitionID string `json:"transition_id"`
+}
+
+type resolveTransitionRequest struct {
+	IssueKey  string                        `json:"issue_key"`
+	Selection contracts.TransitionSelection `json:"selection"`
+}
+
+type assignableUsersRequest struct {
+	ProjectKey string `json:"project_key"`
+	Query      string `json:"query"`
+}
+
+type projectRequest struct {
+	ProjectKey string `json:"project_key"`
+}
+
+type jqlRequest struct {
+	JQL string `json:"jql"`
+}
+
+// RecordingAdapter forwards every call to an inner adapter and appends the
+// call, its result and any error to a JSON Lines file. Each line is passed
+// through the redactor before it is written, so the file can be shared.
+type RecordingAdapter struct {
+	inner    Adapter
+	redactor httpclient.Redactor
+
+	mu   sync.Mutex
+	file *os.File
+	err  error
+}
+
+// NewRecordingAdapter truncates path and records into it. Callers must
+// Close the adapter to flush the file.
+func NewRecordingAdapter(inner Adapter, path string, redactor httpclient.Redactor) (*RecordingAdapter, error) {
+	if inner == nil {
+		return nil, errors.New("recording adapter requires an inner adapter")
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recording file: %w", err)
+	}
+	return &RecordingAdapter{inner: inner, redactor: redactor, file: file}, nil
+}
+
+// Close closes the recording file and reports the first write failure, if
+// any. Recording failures never fail the wrapped calls themselves.
+func (a *RecordingAdapter) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.file == nil {
+		return a.err
+	}
+	closeErr := a.file.Close()
+	a.file = nil
+	if a.err != nil {
+		return a.err
+	}
+	return closeErr
+}
+
+func (a *RecordingAdapter) write(method string, request any, response any, callErr error) {
+	entry := recordedCall{Method: method}
+	var err error
+	if entry.Request, err = json.Marshal(request); err == nil {
+		if callErr != nil {
+			entry.Error = newRecordedError(callErr)
+		} else {
+			entry.Response, err = json.Marshal(response)
+		}
+	}
+	var line []byte
+	if err == nil {
+		line, err = json.Marshal(entry)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.err != nil {
+		return
+	}
+	if err != nil {
+		a.err = fmt.Errorf("failed to encode recorded %s call: %w", method, err)
+		return
+	}
+	if a.file == nil {
+		a.err = errors.New("recording adapter used after Close")
+		return
+	}
+	if _, err := a.file.WriteString(a.redactor.Redact(string(line)) + "\n"); err != nil {
+		a.err = fmt.Errorf("failed to write recording: %w", err)
+	}
+}
+
+func newRecordedError(err error) *recordedError {
+	var jiraErr *Error
+	if errors.As(err, &jiraErr) {
+		return &recordedError{
+			Code:       jiraErr.Code,
+			ReasonCode: jiraErr.ReasonCode,
+			StatusCode: jiraErr.StatusCode,
+			RequestID:  jiraErr.RequestID(),
+			Message:    jiraErr.Error(),
+		}
+	}
+	return &recordedError{Message: err.Error()}
+}
+
+func (a *RecordingAdapter) SearchIssues(ctx context.Context, request SearchIssuesRequest) (SearchIssuesResponse, error) {
+	response, err := a.inner.SearchIssues(ctx, request)
+	a.write("SearchIssues", request, response, err)
+	return response, err
+}
+
+func (a *RecordingAdapter) ValidateJQL(ctx context.Context, jql string) error {
+	err := a.inner.ValidateJQL(ctx, jql)
+	a.write("ValidateJQL", jqlRequest{JQL: jql}, nil, err)
+	return err
+}
+
+func (a *RecordingAdapter) ListFields(ctx context.Context) ([]FieldDefinition, error) {
+	fields, err := a.inner.ListFields(ctx)
+	a.write("ListFields", nil, fields, err)
+	return fields, err
+}
+
+func (a *RecordingAdapter) ListProjects(ctx context.Context) ([]Project, error) {
+	projects, err := a.inner.ListProjects(ctx)
+	a.write("ListProjects", nil, projects, err)
+	return projects, err
+}
+
+func (a *RecordingAdapter) GetMyself(ctx context.Context) (AccountRef, error) {
+	account, err := a.inner.GetMyself(ctx)
+	a.write("GetMyself", nil, account, err)
+	return account, err
+}
+
+func (a *RecordingAdapter) ListStatuses(ctx context.Context) ([]StatusRef, error) {
+	statuses, err := a.inner.ListStatuses(ctx)
+	a.write("ListStatuses", nil, statuses, err)
+	return statuses, err
+}
+
+func (a *RecordingAdapter) ListSecurityLevels(ctx context.Context, projectKey string) ([]NamedRef, error) {
+	levels, err := a.inner.ListSecurityLevels(ctx, projectKey)
+	a.write("ListSecurityLevels", projectRequest{ProjectKey: projectKey}, levels, err)
+	return levels, err
+}
+
+func (a *RecordingAdapter) GetIssue(ctx context.Context, issueKey string, fields []string) (Issue, error) {
+	issue, err := a.inner.GetIssue(ctx, issueKey, fields)
+	a.write("GetIssue", issueRequest{IssueKey: issueKey, Fields: fields}, issue, err)
+	return issue, err
+}
+
+func (a *RecordingAdapter) CreateIssue(ctx context.Context, request CreateIssueRequest) (CreatedIssue, error) {
+	created, err := a.inner.CreateIssue(ctx, request)
+	a.write("CreateIssue", request, created, err)
+	return created, err
+}
+
+func (a *RecordingAdapter) UpdateIssue(ctx context.Context, issueKey string, request UpdateIssueRequest) error {
+	err := a.inner.UpdateIssue(ctx, issueKey, request)
+	a.write("UpdateIssue", updateRequest{IssueKey: issueKey, Update: request}, nil, err)
+	return err
+}
+
+func (a *RecordingAdapter) ListTransitions(ctx context.Context, issueKey string) ([]Transition, error) {
+	transitions, err := a.inner.ListTransitions(ctx, issueKey)
+	a.write("ListTransitions", issueRequest{IssueKey: issueKey}, transitions, err)
+	return transitions, err
+}
+
+func (a *RecordingAdapter) ApplyTransition(ctx context.Context, issueKey string, transitionID string) error {
+	err := a.inner.ApplyTransition(ctx, issueKey, transitionID)
+	a.write("ApplyTransition", transitionRequest{IssueKey: issueKey, TransitionID: transitionID}, nil, err)
+	return err
+}
+
+func (a *RecordingAdapter) ResolveTransition(ctx context.Context, issueKey string, selection contracts.TransitionSelection) (TransitionResolution, error) {
+	resolution, err := a.inner.ResolveTransition(ctx, issueKey, selection)
+	a.write("ResolveTransition", resolveTransitionRequest{IssueKey: issueKey, Selection: selection}, resolution, err)
+	return resolution, err
+}
+
+func (a *RecordingAdapter) SearchAssignableUsers(ctx context.Context, projectKey string, query string) ([]AccountRef, error) {
+	users, err := a.inner.SearchAssignableUsers(ctx, projectKey, query)
+	a.write("SearchAssignableUsers", assignableUsersRequest{ProjectKey: projectKey, Query: query}, users, err)
+	return users, err
+}
+
+// ReplayAdapter serves calls from a file written by RecordingAdapter. A call
+// matches a recorded line with the same method and arguments; repeated
+// calls are served in recorded order, so a paged search replays page by
+// page. Recorded values that were redacted replay as the placeholder.
+type ReplayAdapter struct {
+	mu      sync.Mutex
+	pending map[string][]recordedCall
+}
+
+// NewReplayAdapter loads a recording file.
+func NewReplayAdapter(path string) (*ReplayAdapter, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording file: %w", err)
+	}
+	defer file.Close()
+
+	adapter := &ReplayAdapter{pending: map[string][]recordedCall{}}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry recordedCall
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("recording line %d is not valid: %w", line, err)
+		}
+		key := replayKey(entry.Method, entry.Request)
+		adapter.pending[key] = append(adapter.pending[key], entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read recording file: %w", err)
+	}
+	return adapter, nil
+}
+
+func replayKey(method string, request json.RawMessage) string {
+	return method + " " + string(request)
+}
+
+func replay[T any](a *ReplayAdapter, method string, request any) (T, error) {
+	var zero T
+	encoded, err := json.Marshal(request)
+	if err != nil {
+		return zero, &Error{Code: ErrorCodeRequestEncode, Message: "failed to encode " + method + " arguments for replay", Err: err}
+	}
+
+	key := replayKey(method, encoded)
+	a.mu.Lock()
+	queue := a.pending[key]
+	if len(queue) == 0 {
+		a.mu.Unlock()
+		return zero, &Error{Code: ErrorCodeReplayMiss, Message: fmt.Sprintf("no recorded %s call for %s", method, encoded)}
+	}
+	entry := queue[0]
+	a.pending[key] = queue[1:]
+	a.mu.Unlock()
+
+	if entry.Error != nil {
+		return zero, &Error{
+			Code:       entry.Error.Code,
+			ReasonCode: entry.Error.ReasonCode,
+			StatusCode: entry.Error.StatusCode,
+			Message:    entry.Error.Message,
+			requestID:  entry.Error.RequestID,
+		}
+	}
+	var response T
+	if len(entry.Response) == 0 {
+		return response, nil
+	}
+	if err := json.Unmarshal(entry.Response, &response); err != nil {
+		return zero, &Error{Code: ErrorCodeResponseDecode, Message: "failed to decode recorded " + method + " response", Err: err}
+	}
+	return response, nil
+}
+
+func (a *ReplayAdapter) SearchIssues(_ context.Context, request SearchIssuesRequest) (SearchIssuesResponse, error) {
+	response, err := replay[SearchIssuesResponse](a, "SearchIssues", request)
+	for index := range response.Issues {
+		clearNullRawFields(&response.Issues[index])
+	}
+	return response, err
+}
+
+func (a *ReplayAdapter) ValidateJQL(_ context.Context, jql string) error {
+	_, err := replay[struct{}](a, "ValidateJQL", jqlRequest{JQL: jql})
+	return err
+}
+
+func (a *ReplayAdapter) ListFields(context.Context) ([]FieldDefinition, error) {
+	return replay[[]FieldDefinition](a, "ListFields", nil)
+}
+
+func (a *ReplayAdapter) ListProjects(context.Context) ([]Project, error) {
+	return replay[[]Project](a, "ListProjects", nil)
+}
+
+func (a *ReplayAdapter) GetMyself(context.Context) (AccountRef, error) {
+	return replay[AccountRef](a, "GetMyself", nil)
+}
+
+func (a *ReplayAdapter) ListStatuses(context.Context) ([]StatusRef, error) {
+	return replay[[]StatusRef](a, "ListStatuses", nil)
+}
+
+func (a *ReplayAdapter) ListSecurityLevels(_ context.Context, projectKey string) ([]NamedRef, error) {
+	return replay[[]NamedRef](a, "ListSecurityLevels", projectRequest{ProjectKey: projectKey})
+}
+
+func (a *ReplayAdapter) GetIssue(_ context.Context, issueKey string, fields []string) (Issue, error) {
+	issue, err := replay[Issue](a, "GetIssue", issueRequest{IssueKey: issueKey, Fields: fields})
+	clearNullRawFields(&issue)
+	return issue, err
+}
+
+// clearNullRawFields undoes the JSON round trip of absent ADF fields, which
+// encode as null and would otherwise decode as a non-empty raw message.
+func clearNullRawFields(issue *Issue) {
+	if string(issue.Fields.Description) == "null" {
+		issue.Fields.Description = nil
+	}
+	if string(issue.Fields.Environment) == "null" {
+		issue.Fields.Environment = nil
+	}
+}
+
+func (a *ReplayAdapter) CreateIssue(_ context.Context, request CreateIssueRequest) (CreatedIssue, error) {
+	return replay[CreatedIssue](a, "CreateIssue", request)
+}
+
+func (a *ReplayAdapter) UpdateIssue(_ context.Context, issueKey string, request UpdateIssueRequest) error {
+	_, err := replay[struct{}](a, "UpdateIssue", updateRequest{IssueKey: issueKey, Update: request})
+	return err
+}
+
+func (a *ReplayAdapter) ListTransitions(_ context.Context, issueKey string) ([]Transition, error) {
+	return replay[[]Transition](a, "ListTransitions", issueRequest{IssueKey: issueKey})
+}
+
+func (a *ReplayAdapter) ApplyTransition(_ context.Context, issueKey string, transitionID string) error {
+	_, err := replay[struct{}](a, "ApplyTransition", transitionRequest{IssueKey: issueKey, TransitionID: transitionID})
+	return err
+}
+
+func (a *ReplayAdapter) ResolveTransition(_ context.Context, issueKey string, selection contracts.TransitionSelection) (TransitionResolution, error) {
+	return replay[TransitionResolution](a, "ResolveTransition", resolveTransitionRequest{IssueKey: issueKey, Selection: selection})
+}
+
+func (a *ReplayAdapter) SearchAssignableUsers(_ context.Context, projectKey string, query string) ([]AccountRef, error) {
+	return replay[[]AccountRef](a, "SearchAssignableUsers", assignableUsersRequest{ProjectKey: projectKey, Query: query})
+}
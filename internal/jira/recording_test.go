@@ -0,0 +1,103 @@
+package jira
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	httpclient "github.com/pweiskircher/jira-issue-sync/internal/http"
+)
+
+func TestReplayAdapterServesRecordedSearchAndGet(t *testing.T) {
+	t.Parallel()
+
+	const token = "token-secret-123"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case req.URL.Path == "/rest/api/3/search/jql":
+			_, _ = w.Write([]byte(`{"issues":[{"id":"10001","key":"PROJ-1","fields":{"summary":"Rotate ` + token + `","labels":["ops"]}}],"isLast":true}`))
+		case req.URL.Path == "/rest/api/3/issue/PROJ-1":
+			_, _ = w.Write([]byte(`{"id":"10001","key":"PROJ-1","fields":{"summary":"First","status":{"id":"3","name":"In Progress"}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errorMessages":["Issue does not exist"]}`))
+		}
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "calls.jsonl")
+	recorder, err := NewRecordingAdapter(mustNewCloudAdapter(t, CloudAdapterOptions{
+		BaseURL:  server.URL,
+		Email:    "bot@example.com",
+		APIToken: token,
+	}), path, httpclient.NewRedactor(token))
+	if err != nil {
+		t.Fatalf("expected recorder, got %v", err)
+	}
+
+	ctx := context.Background()
+	searchRequest := SearchIssuesRequest{JQL: "project = PROJ", MaxResults: 50, Fields: []string{"summary", "labels"}}
+	recordedSearch, err := recorder.SearchIssues(ctx, searchRequest)
+	if err != nil {
+		t.Fatalf("expected search success, got %v", err)
+	}
+	recordedIssue, err := recorder.GetIssue(ctx, "PROJ-1", []string{"summary", "status"})
+	if err != nil {
+		t.Fatalf("expected get success, got %v", err)
+	}
+	if _, err := recorder.GetIssue(ctx, "PROJ-404", nil); err == nil {
+		t.Fatalf("expected get of missing issue to fail")
+	}
+	if err := recorder.Close(); err != nil {
+		t.Fatalf("expected recording to close cleanly, got %v", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read recording: %v", err)
+	}
+	if strings.Contains(string(content), token) {
+		t.Fatalf("expected token to be redacted from recording: %s", content)
+	}
+	if lines := strings.Count(string(content), "\n"); lines != 3 {
+		t.Fatalf("expected one line per call, got %d:\n%s", lines, content)
+	}
+
+	replayer, err := NewReplayAdapter(path)
+	if err != nil {
+		t.Fatalf("expected replay adapter, got %v", err)
+	}
+	var _ Adapter = replayer
+
+	replayedSearch, err := replayer.SearchIssues(ctx, searchRequest)
+	if err != nil {
+		t.Fatalf("expected replayed search, got %v", err)
+	}
+	recordedSearch.Issues[0].Fields.Summary = "Rotate " + httpclient.RedactedPlaceholder
+	if !reflect.DeepEqual(replayedSearch, recordedSearch) {
+		t.Fatalf("replayed search differs:\n got %#v\nwant %#v", replayedSearch, recordedSearch)
+	}
+	replayedIssue, err := replayer.GetIssue(ctx, "PROJ-1", []string{"summary", "status"})
+	if err != nil {
+		t.Fatalf("expected replayed get, got %v", err)
+	}
+	if !reflect.DeepEqual(replayedIssue, recordedIssue) {
+		t.Fatalf("replayed issue differs:\n got %#v\nwant %#v", replayedIssue, recordedIssue)
+	}
+	if _, err := replayer.GetIssue(ctx, "PROJ-404", nil); !IsErrorCode(err, ErrorCodeUnexpectedStatus) {
+		t.Fatalf("expected recorded unexpected_status error, got %v", err)
+	}
+
+	if _, err := replayer.GetIssue(ctx, "PROJ-1", []string{"summary", "status"}); !IsErrorCode(err, ErrorCodeReplayMiss) {
+		t.Fatalf("expected second get to miss the recording, got %v", err)
+	}
+	if _, err := replayer.GetIssue(ctx, "PROJ-1", []string{"summary"}); !IsErrorCode(err, ErrorCodeReplayMiss) {
+		t.Fatalf("expected get with different fields to miss the recording, got %v", err)
+	}
+}
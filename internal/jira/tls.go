@@ -0,0 +1,75 @@
+package jira
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
+)
+
+// tlsConfigFor builds the transport's TLS settings: the version floor and
+// cipher allowlist, plus the CA bundle and client certificate named in
+// options. Roots stay the system defaults unless a bundle is given.
+func tlsConfigFor(options CloudAdapterOptions) (*tls.Config, error) {
+	caPath := strings.TrimSpace(options.CABundlePath)
+	certPath := strings.TrimSpace(options.ClientCertPath)
+	keyPath := strings.TrimSpace(options.ClientKeyPath)
+	if options.InsecureSkipVerify && caPath != "" {
+		return nil, invalidTLSOption("insecure skip-verify cannot be combined with a ca bundle")
+	}
+
+	minVersion, err := contracts.ParseMinTLSVersion(options.MinTLSVersion)
+	if err != nil {
+		return nil, invalidTLSOption(err.Error())
+	}
+	cipherSuites, err := contracts.ParseCipherSuites(options.CipherSuites)
+	if err != nil {
+		return nil, invalidTLSOption(err.Error())
+	}
+	if len(cipherSuites) > 0 && minVersion == tls.VersionTLS13 {
+		return nil, invalidTLSOption("cipher suites cannot be restricted with a TLS 1.3 minimum")
+	}
+
+	config := &tls.Config{
+		InsecureSkipVerify: options.InsecureSkipVerify,
+		MinVersion:         minVersion,
+		CipherSuites:       cipherSuites,
+	}
+	if caPath != "" {
+		pem, err := os.ReadFile(caPath)
+		if err != nil {
+			return nil, invalidTLSOption(fmt.Sprintf("failed to read ca bundle %q: %v", caPath, err))
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, invalidTLSOption(fmt.Sprintf("ca bundle %q contains no PEM certificates", caPath))
+		}
+		config.RootCAs = pool
+	}
+
+	if certPath != "" || keyPath != "" {
+		if certPath == "" || keyPath == "" {
+			return nil, invalidTLSOption("client cert and client key must be set together")
+		}
+		certificate, err := tls.LoadX509KeyPair(certPath, keyPath)
+		if err != nil {
+			return nil, invalidTLSOption(fmt.Sprintf("failed to load client certificate %q: %v", certPath, err))
+		}
+		config.Certificates = []tls.Certificate{certificate}
+	}
+	return config, nil
+}
+
+func invalidTLSOption(message string) error {
+	return &Error{
+		Code:       ErrorCodeInvalidInput,
+		ReasonCode: contracts.ReasonCodeValidationFailed,
+		Message:    "invalid jira adapter options: " + message,
+	}
+}
@@ -9,13 +9,19 @@ import (
 
 type Adapter interface {
 	SearchIssues(ctx context.Context, request SearchIssuesRequest) (SearchIssuesResponse, error)
+	ValidateJQL(ctx context.Context, jql string) error
 	ListFields(ctx context.Context) ([]FieldDefinition, error)
+	ListProjects(ctx context.Context) ([]Project, error)
+	GetMyself(ctx context.Context) (AccountRef, error)
+	ListStatuses(ctx context.Context) ([]StatusRef, error)
+	ListSecurityLevels(ctx context.Context, projectKey string) ([]NamedRef, error)
 	GetIssue(ctx context.Context, issueKey string, fields []string) (Issue, error)
 	CreateIssue(ctx context.Context, request CreateIssueRequest) (CreatedIssue, error)
 	UpdateIssue(ctx context.Context, issueKey string, request UpdateIssueRequest) error
 	ListTransitions(ctx context.Context, issueKey string) ([]Transition, error)
 	ApplyTransition(ctx context.Context, issueKey string, transitionID string) error
 	ResolveTransition(ctx context.Context, issueKey string, selection contracts.TransitionSelection) (TransitionResolution, error)
+	SearchAssignableUsers(ctx context.Context, projectKey string, query string) ([]AccountRef, error)
 }
 
 type SearchIssuesRequest struct {
@@ -44,15 +50,28 @@ type Issue struct {
 type IssueFields struct {
 	Summary      string
 	Description  json.RawMessage
+	Environment  json.RawMessage
 	Labels       []string
 	Assignee     *AccountRef
 	Priority     *NamedRef
 	Status       *StatusRef
 	IssueType    *NamedRef
 	Reporter     *AccountRef
+	Security     *NamedRef
 	CreatedAt    string
 	UpdatedAt    string
 	CustomFields map[string]json.RawMessage
+	// Comments is filled only when the comment field was requested.
+	Comments []Comment
+}
+
+// Comment is one Jira issue comment with its ADF body.
+type Comment struct {
+	ID        string
+	Author    *AccountRef
+	Body      json.RawMessage
+	CreatedAt string
+	UpdatedAt string
 }
 
 type AccountRef struct {
@@ -79,6 +98,12 @@ type CreateIssueRequest struct {
 	Labels            []string
 	AssigneeAccountID string
 	PriorityName      string
+	// SecurityLevel is sent by ID when known, otherwise by name so Jira
+	// reports an unknown level itself.
+	SecurityLevel *NamedRef
+	// CustomFields are raw JSON values keyed by field ID, such as
+	// customfield_10010.
+	CustomFields map[string]json.RawMessage
 }
 
 type CreatedIssue struct {
@@ -90,9 +115,13 @@ type CreatedIssue struct {
 type UpdateIssueRequest struct {
 	Summary           *string
 	Description       *json.RawMessage
+	Environment       *json.RawMessage
 	Labels            *[]string
 	AssigneeAccountID *string
 	PriorityName      *string
+	// CustomFields are raw JSON values keyed by field ID; a JSON null
+	// clears the field.
+	CustomFields map[string]json.RawMessage
 }
 
 type Transition struct {
@@ -120,6 +149,12 @@ type TransitionResolution struct {
 	ReasonCode       contracts.ReasonCode
 }
 
+type Project struct {
+	ID   string
+	Key  string
+	Name string
+}
+
 type FieldDefinition struct {
 	ID     string
 	Name   string
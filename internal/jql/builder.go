@@ -0,0 +1,305 @@
+// pattern: Functional Core
+package jql
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Direction is an ORDER BY sort direction.
+type Direction string
+
+const (
+	Ascending  Direction = "ASC"
+	Descending Direction = "DESC"
+)
+
+// plainFieldPattern matches field references that never need quoting:
+// system field names and cf[12345] custom-field references.
+var plainFieldPattern = regexp.MustCompile(`^(?:[A-Za-z][A-Za-z0-9_]*|cf\[[0-9]+\])$`)
+
+// Builder composes canonical JQL from AND-ed clauses and an ORDER BY list.
+// Every value passed through the typed helpers is quoted, so user input
+// cannot change the structure of the query.
+type Builder struct {
+	clauses []string
+	orderBy []string
+}
+
+// New returns an empty builder.
+func New() *Builder {
+	return &Builder{}
+}
+
+// FromQuery seeds a builder with an existing JQL query. Any ORDER BY in
+// the query is kept and later OrderBy calls append to it.
+func FromQuery(query string) *Builder {
+	builder := New()
+	where, orderBy := SplitOrderBy(query)
+	if where != "" {
+		builder.clauses = append(builder.clauses, where)
+	}
+	for _, term := range splitOutsideQuotes(orderBy, ',') {
+		if trimmed := strings.TrimSpace(term); trimmed != "" {
+			builder.orderBy = append(builder.orderBy, collapseSpaces(trimmed))
+		}
+	}
+	return builder
+}
+
+// Where adds a pre-formed clause. It is wrapped in parentheses when
+// combined with other clauses so its own OR/AND precedence is preserved.
+func (b *Builder) Where(clause string) *Builder {
+	if trimmed := strings.TrimSpace(clause); trimmed != "" {
+		b.clauses = append(b.clauses, trimmed)
+	}
+	return b
+}
+
+// Equals adds `field = "value"`.
+func (b *Builder) Equals(field string, value string) *Builder {
+	return b.Compare(field, "=", value)
+}
+
+// Compare adds `field <op> "value"` for operators such as =, !=, >=, ~.
+func (b *Builder) Compare(field string, operator string, value string) *Builder {
+	b.clauses = append(b.clauses, Field(field)+" "+strings.TrimSpace(operator)+" "+Quote(value))
+	return b
+}
+
+// In adds `field in ("a", "b")`. An empty value list is ignored.
+func (b *Builder) In(field string, values ...string) *Builder {
+	if len(values) == 0 {
+		return b
+	}
+	quoted := make([]string, 0, len(values))
+	for _, value := range values {
+		quoted = append(quoted, Quote(value))
+	}
+	b.clauses = append(b.clauses, Field(field)+" in ("+strings.Join(quoted, ", ")+")")
+	return b
+}
+
+// OrderBy appends a sort term.
+func (b *Builder) OrderBy(field string, direction Direction) *Builder {
+	if direction != Descending {
+		direction = Ascending
+	}
+	b.orderBy = append(b.orderBy, Field(field)+" "+string(direction))
+	return b
+}
+
+// HasOrderBy reports whether the builder already carries sort terms.
+func (b *Builder) HasOrderBy() bool {
+	return len(b.orderBy) > 0
+}
+
+// String renders the canonical query.
+func (b *Builder) String() string {
+	var builder strings.Builder
+	for index, clause := range b.clauses {
+		if index > 0 {
+			builder.WriteString(" AND ")
+		}
+		if len(b.clauses) > 1 && needsParentheses(clause) {
+			builder.WriteString("(" + clause + ")")
+		} else {
+			builder.WriteString(clause)
+		}
+	}
+	if len(b.orderBy) > 0 {
+		if builder.Len() > 0 {
+			builder.WriteString(" ")
+		}
+		builder.WriteString("ORDER BY ")
+		builder.WriteString(strings.Join(b.orderBy, ", "))
+	}
+	return builder.String()
+}
+
+// Quote renders value as a JQL string literal, escaping backslashes,
+// double quotes, and control characters.
+func Quote(value string) string {
+	var builder strings.Builder
+	builder.WriteByte('"')
+	for _, char := range value {
+		switch char {
+		case '\\':
+			builder.WriteString(`\\`)
+		case '"':
+			builder.WriteString(`\"`)
+		case '\n':
+			builder.WriteString(`\n`)
+		case '\r':
+			builder.WriteString(`\r`)
+		case '\t':
+			builder.WriteString(`\t`)
+		default:
+			builder.WriteRune(char)
+		}
+	}
+	builder.WriteByte('"')
+	return builder.String()
+}
+
+// Field renders a field reference, quoting names that contain spaces or
+// reserved characters (for example "Story Points").
+func Field(name string) string {
+	trimmed := strings.TrimSpace(name)
+	if plainFieldPattern.MatchString(trimmed) {
+		return trimmed
+	}
+	return Quote(trimmed)
+}
+
+// HasOrderBy reports whether query contains a top-level ORDER BY outside
+// of string literals.
+func HasOrderBy(query string) bool {
+	_, orderBy := SplitOrderBy(query)
+	return orderBy != ""
+}
+
+// SplitOrderBy separates the filter part of query from its ORDER BY terms.
+// Both parts are trimmed; orderBy excludes the ORDER BY keywords.
+func SplitOrderBy(query string) (string, string) {
+	index := findOrderBy(query)
+	if index < 0 {
+		return strings.TrimSpace(query), ""
+	}
+
+	where := strings.TrimSpace(query[:index])
+	rest := strings.TrimSpace(query[index:])
+	rest = strings.TrimSpace(rest[len("order"):])
+	rest = strings.TrimSpace(rest[len("by"):])
+	return where, rest
+}
+
+func findOrderBy(query string) int {
+	found := -1
+	scanOutsideQuotes(query, func(index int) bool {
+		if !matchesKeyword(query, index, "order") {
+			return true
+		}
+		next := index + len("order")
+		rest := strings.TrimLeftFunc(query[next:], unicode.IsSpace)
+		if len(rest) == len(query[next:]) {
+			return true
+		}
+		if matchesKeyword(query, len(query)-len(rest), "by") {
+			found = index
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// scanOutsideQuotes calls visit with the byte offset of every character
+// that is not inside a quoted string literal. Scanning stops when visit
+// returns false.
+func scanOutsideQuotes(query string, visit func(index int) bool) {
+	inQuote := rune(0)
+	escaped := false
+	for index, char := range query {
+		if inQuote != 0 {
+			switch {
+			case escaped:
+				escaped = false
+			case char == '\\':
+				escaped = true
+			case char == inQuote:
+				inQuote = 0
+			}
+			continue
+		}
+		if char == '"' || char == '\'' {
+			inQuote = char
+			continue
+		}
+		if !visit(index) {
+			return
+		}
+	}
+}
+
+func matchesKeyword(query string, index int, keyword string) bool {
+	end := index + len(keyword)
+	if end > len(query) || !strings.EqualFold(query[index:end], keyword) {
+		return false
+	}
+	if index > 0 && isWordByte(query[index-1]) {
+		return false
+	}
+	if end < len(query) && isWordByte(query[end]) {
+		return false
+	}
+	return true
+}
+
+func isWordByte(char byte) bool {
+	return char == '_' || (char >= '0' && char <= '9') || (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z')
+}
+
+func splitOutsideQuotes(input string, separator byte) []string {
+	parts := make([]string, 0)
+	last := 0
+	scanOutsideQuotes(input, func(index int) bool {
+		if input[index] == separator {
+			parts = append(parts, input[last:index])
+			last = index + 1
+		}
+		return true
+	})
+	if last < len(input) {
+		parts = append(parts, input[last:])
+	}
+	return parts
+}
+
+func collapseSpaces(input string) string {
+	return strings.Join(strings.Fields(input), " ")
+}
+
+// needsParentheses reports whether a clause contains a top-level boolean
+// operator that could bind differently once AND-ed with other clauses.
+func needsParentheses(clause string) bool {
+	if wrappedInParentheses(clause) {
+		return false
+	}
+	found := false
+	scanOutsideQuotes(clause, func(index int) bool {
+		for _, keyword := range []string{"or", "and", "not"} {
+			if matchesKeyword(clause, index, keyword) {
+				found = true
+				return false
+			}
+		}
+		return true
+	})
+	return found
+}
+
+// wrappedInParentheses reports whether the opening parenthesis at the start
+// of clause closes at its very end.
+func wrappedInParentheses(clause string) bool {
+	if !strings.HasPrefix(clause, "(") || !strings.HasSuffix(clause, ")") {
+		return false
+	}
+	depth := 0
+	closesEarly := false
+	scanOutsideQuotes(clause, func(index int) bool {
+		switch clause[index] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 && index != len(clause)-1 {
+				closesEarly = true
+				return false
+			}
+		}
+		return true
+	})
+	return !closesEarly && depth == 0
+}
@@ -0,0 +1,70 @@
+package jql
+
+import "testing"
+
+func TestQuoteEscapesQuotesBackslashesAndSpaces(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"In Progress":         `"In Progress"`,
+		`say "hi"`:            `"say \"hi\""`,
+		`C:\path`:             `"C:\\path"`,
+		"line\nbreak":         `"line\nbreak"`,
+		`" OR project = EVIL`: `"\" OR project = EVIL"`,
+	}
+	for input, want := range cases {
+		if got := Quote(input); got != want {
+			t.Fatalf("Quote(%q) = %s, want %s", input, got, want)
+		}
+	}
+}
+
+func TestBuilderComposesCanonicalQuery(t *testing.T) {
+	t.Parallel()
+
+	query := New().
+		Equals("project", "PROJ").
+		In("status", "To Do", `Won't "Fix"`).
+		Compare("Story Points", ">=", "3").
+		Where("assignee = currentUser() OR reporter = currentUser()").
+		OrderBy("updated", Descending).
+		OrderBy("key", Ascending).
+		String()
+
+	want := `project = "PROJ" AND status in ("To Do", "Won't \"Fix\"") AND "Story Points" >= "3" AND (assignee = currentUser() OR reporter = currentUser()) ORDER BY updated DESC, key ASC`
+	if query != want {
+		t.Fatalf("unexpected query:\n got: %s\nwant: %s", query, want)
+	}
+}
+
+func TestFromQueryPreservesExistingOrdering(t *testing.T) {
+	t.Parallel()
+
+	query := FromQuery(`project = PROJ OR labels = "order by"  order   by  priority desc`).
+		Compare("updated", ">=", "2026-01-02 03:04").
+		OrderBy("key", Ascending).
+		String()
+
+	want := `(project = PROJ OR labels = "order by") AND updated >= "2026-01-02 03:04" ORDER BY priority desc, key ASC`
+	if query != want {
+		t.Fatalf("unexpected query:\n got: %s\nwant: %s", query, want)
+	}
+}
+
+func TestHasOrderByIgnoresQuotedText(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]bool{
+		"project = PROJ":                            false,
+		`summary ~ "order by"`:                      false,
+		"project = PROJ ORDER BY created":           true,
+		"project = PROJ order\tby key":              true,
+		"reorder = x AND orderby = y":               false,
+		`summary ~ "x" ORDER BY "Story Points" ASC`: true,
+	}
+	for query, want := range cases {
+		if got := HasOrderBy(query); got != want {
+			t.Fatalf("HasOrderBy(%q) = %t, want %t", query, got, want)
+		}
+	}
+}
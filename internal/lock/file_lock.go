@@ -10,11 +10,49 @@ import (
 	"sync"
 	"time"
 
+	"github.com/pweiskircher/jira-issue-sync/internal/clock"
 	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
 )
 
 var ErrAcquireTimeout = errors.New("timed out acquiring lock")
 
+// AcquireTimeoutError describes the lock that could not be acquired: how
+// long ago its file was last written and, when the payload was readable,
+// which process holds it. It matches ErrAcquireTimeout with errors.Is.
+type AcquireTimeoutError struct {
+	Path     string
+	PID      int
+	Hostname string
+	// Age is zero when the lock file disappeared before it could be read.
+	Age time.Duration
+}
+
+func (err *AcquireTimeoutError) Error() string {
+	message := fmt.Sprintf("%s: %s", ErrAcquireTimeout, err.Path)
+
+	holder := ""
+	if err.PID > 0 {
+		holder = fmt.Sprintf("PID %d", err.PID)
+		if err.Hostname != "" {
+			holder += " on " + err.Hostname
+		}
+	}
+	switch {
+	case holder != "" && err.Age > 0:
+		return fmt.Sprintf("%s (held by %s since %s ago)", message, holder, err.Age.Round(time.Second))
+	case holder != "":
+		return fmt.Sprintf("%s (held by %s)", message, holder)
+	case err.Age > 0:
+		return fmt.Sprintf("%s (held since %s ago)", message, err.Age.Round(time.Second))
+	default:
+		return message
+	}
+}
+
+func (err *AcquireTimeoutError) Is(target error) bool {
+	return target == ErrAcquireTimeout
+}
+
 type Lease interface {
 	Release() error
 	RecoveredStale() bool
@@ -28,7 +66,7 @@ type Options struct {
 	StaleAfter     time.Duration
 	AcquireTimeout time.Duration
 	PollInterval   time.Duration
-	Now            func() time.Time
+	Clock          clock.Clock
 }
 
 type FileLock struct {
@@ -36,7 +74,7 @@ type FileLock struct {
 	staleAfter     time.Duration
 	acquireTimeout time.Duration
 	pollInterval   time.Duration
-	now            func() time.Time
+	clock          clock.Clock
 }
 
 type fileLease struct {
@@ -47,6 +85,7 @@ type fileLease struct {
 
 type lockFilePayload struct {
 	PID       int    `json:"pid"`
+	Hostname  string `json:"hostname,omitempty"`
 	CreatedAt string `json:"created_at"`
 }
 
@@ -66,17 +105,12 @@ func NewFileLock(path string, options Options) *FileLock {
 		pollInterval = contracts.DefaultLockPollInterval
 	}
 
-	now := options.Now
-	if now == nil {
-		now = time.Now
-	}
-
 	return &FileLock{
 		path:           path,
 		staleAfter:     staleAfter,
 		acquireTimeout: acquireTimeout,
 		pollInterval:   pollInterval,
-		now:            now,
+		clock:          clock.OrSystem(options.Clock),
 	}
 }
 
@@ -93,7 +127,7 @@ func (l *FileLock) Acquire(ctx context.Context) (Lease, error) {
 		ctx = context.Background()
 	}
 
-	deadline := l.now().Add(l.acquireTimeout)
+	deadline := l.clock.Now().Add(l.acquireTimeout)
 	recoveredStale := false
 
 	for {
@@ -114,18 +148,14 @@ func (l *FileLock) Acquire(ctx context.Context) (Lease, error) {
 		if err := ctx.Err(); err != nil {
 			return nil, err
 		}
-		if !l.now().Before(deadline) {
-			return nil, fmt.Errorf("%w: %s", ErrAcquireTimeout, l.path)
+		if !l.clock.Now().Before(deadline) {
+			return nil, l.timeoutError()
 		}
 
-		timer := time.NewTimer(l.pollInterval)
-		select {
-		case <-ctx.Done():
-			if !timer.Stop() {
-				<-timer.C
-			}
-			return nil, ctx.Err()
-		case <-timer.C:
+		// Waiting goes through the clock so a fake clock drives the acquire
+		// deadline; on the wall clock cancellation interrupts the wait.
+		if err := clock.SleepContext(ctx, l.clock, l.pollInterval); err != nil {
+			return nil, err
 		}
 	}
 }
@@ -137,7 +167,8 @@ func (l *FileLock) tryCreateLock() error {
 	}
 	defer file.Close()
 
-	payload := lockFilePayload{PID: os.Getpid(), CreatedAt: l.now().UTC().Format(time.RFC3339Nano)}
+	hostname, _ := os.Hostname()
+	payload := lockFilePayload{PID: os.Getpid(), Hostname: hostname, CreatedAt: l.clock.Now().UTC().Format(time.RFC3339Nano)}
 	encoded, err := json.Marshal(payload)
 	if err != nil {
 		return err
@@ -148,6 +179,23 @@ func (l *FileLock) tryCreateLock() error {
 	return err
 }
 
+// timeoutError reads whatever the current lock file says about its holder.
+// Lock files from older versions or foreign writers may carry no payload.
+func (l *FileLock) timeoutError() error {
+	timeoutErr := &AcquireTimeoutError{Path: l.path}
+	if info, err := os.Stat(l.path); err == nil {
+		timeoutErr.Age = l.clock.Now().Sub(info.ModTime())
+	}
+	if encoded, err := os.ReadFile(l.path); err == nil {
+		var payload lockFilePayload
+		if json.Unmarshal(encoded, &payload) == nil {
+			timeoutErr.PID = payload.PID
+			timeoutErr.Hostname = payload.Hostname
+		}
+	}
+	return timeoutErr
+}
+
 func (l *FileLock) lockIsStale() (bool, error) {
 	info, err := os.Stat(l.path)
 	if err != nil {
@@ -157,7 +205,7 @@ func (l *FileLock) lockIsStale() (bool, error) {
 		return false, err
 	}
 
-	age := l.now().Sub(info.ModTime())
+	age := l.clock.Now().Sub(info.ModTime())
 	return age > l.staleAfter, nil
 }
 
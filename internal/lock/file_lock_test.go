@@ -5,8 +5,11 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/pweiskircher/jira-issue-sync/internal/clock"
 )
 
 func TestFileLockAcquireAndRelease(t *testing.T) {
@@ -96,3 +99,95 @@ func TestFileLockTimesOutWhenAlreadyHeld(t *testing.T) {
 		t.Fatalf("expected acquire timeout, got: %v", err)
 	}
 }
+
+func TestFileLockCancellationInterruptsPollWait(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), ".issues", ".sync", "lock")
+	lease, err := NewFileLock(path, Options{}).Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("primary acquire failed: %v", err)
+	}
+	defer lease.Release()
+
+	waiter := NewFileLock(path, Options{AcquireTimeout: time.Minute, PollInterval: time.Minute})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	started := time.Now()
+	_, err = waiter.Acquire(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context deadline error, got %v", err)
+	}
+	if waited := time.Since(started); waited > 5*time.Second {
+		t.Fatalf("expected cancellation to cut the poll wait short, waited %s", waited)
+	}
+}
+
+func TestFileLockTimeoutFollowsInjectedClock(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), ".issues", ".sync", "lock")
+	fake := clock.NewFake(time.Now())
+	primary := NewFileLock(path, Options{Clock: fake})
+
+	lease, err := primary.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("primary acquire failed: %v", err)
+	}
+	defer lease.Release()
+
+	secondary := NewFileLock(path, Options{
+		AcquireTimeout: 10 * time.Minute,
+		PollInterval:   time.Second,
+		Clock:          fake,
+	})
+
+	started := time.Now()
+	_, err = secondary.Acquire(context.Background())
+	if !errors.Is(err, ErrAcquireTimeout) {
+		t.Fatalf("expected acquire timeout, got: %v", err)
+	}
+	if elapsed := time.Since(started); elapsed > 5*time.Second {
+		t.Fatalf("expected fake clock to drive the timeout, waited %s", elapsed)
+	}
+}
+
+func TestFileLockTimeoutReportsHolderAndAge(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), ".issues", ".sync", "lock")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir failed: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(`{"pid":1234,"hostname":"build-01","created_at":"2026-03-01T09:00:00Z"}`+"\n"), 0o600); err != nil {
+		t.Fatalf("write lock failed: %v", err)
+	}
+	heldSince := time.Date(2026, time.March, 1, 9, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(path, heldSince, heldSince); err != nil {
+		t.Fatalf("chtimes failed: %v", err)
+	}
+
+	fake := clock.NewFake(heldSince.Add(3 * time.Minute))
+	locker := NewFileLock(path, Options{
+		StaleAfter:     time.Hour,
+		AcquireTimeout: time.Second,
+		PollInterval:   time.Second,
+		Clock:          fake,
+	})
+
+	_, err := locker.Acquire(context.Background())
+	if !errors.Is(err, ErrAcquireTimeout) {
+		t.Fatalf("expected acquire timeout, got: %v", err)
+	}
+	var timeoutErr *AcquireTimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("expected typed timeout error, got %T", err)
+	}
+	if timeoutErr.PID != 1234 || timeoutErr.Hostname != "build-01" {
+		t.Fatalf("unexpected holder: %#v", timeoutErr)
+	}
+	if !strings.Contains(err.Error(), "held by PID 1234 on build-01 since 3m") {
+		t.Fatalf("expected holder and age in message, got %q", err)
+	}
+}
@@ -0,0 +1,125 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+)
+
+func (level Level) String() string {
+	switch level {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	default:
+		return "unknown"
+	}
+}
+
+// Logger is the diagnostic sink shared by the CLI, transport, lock, and
+// pipeline layers. Log output never goes to stdout so JSON envelopes stay
+// machine-readable.
+type Logger interface {
+	Enabled(level Level) bool
+	Logf(level Level, format string, args ...any)
+}
+
+// Nop returns a logger that discards everything.
+func Nop() Logger {
+	return nopLogger{}
+}
+
+// New writes lines at or above minLevel to w as "[level] message".
+func New(w io.Writer, minLevel Level) Logger {
+	if w == nil {
+		return Nop()
+	}
+	return &writerLogger{writer: w, minLevel: minLevel}
+}
+
+// WithRedaction passes every formatted message through redact before it
+// reaches logger.
+func WithRedaction(logger Logger, redact func(string) string) Logger {
+	if logger == nil || redact == nil {
+		return OrNop(logger)
+	}
+	return redactingLogger{next: logger, redact: redact}
+}
+
+// OrNop substitutes the no-op logger for nil.
+func OrNop(logger Logger) Logger {
+	if logger == nil {
+		return Nop()
+	}
+	return logger
+}
+
+// Debugf logs at debug level and tolerates a nil logger.
+func Debugf(logger Logger, format string, args ...any) {
+	if logger == nil || !logger.Enabled(LevelDebug) {
+		return
+	}
+	logger.Logf(LevelDebug, format, args...)
+}
+
+// Warnf logs at warn level and tolerates a nil logger.
+func Warnf(logger Logger, format string, args ...any) {
+	if logger == nil || !logger.Enabled(LevelWarn) {
+		return
+	}
+	logger.Logf(LevelWarn, format, args...)
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Enabled(Level) bool         { return false }
+func (nopLogger) Logf(Level, string, ...any) {}
+
+type writerLogger struct {
+	mu       sync.Mutex
+	writer   io.Writer
+	minLevel Level
+}
+
+func (l *writerLogger) Enabled(level Level) bool {
+	return level >= l.minLevel
+}
+
+func (l *writerLogger) Logf(level Level, format string, args ...any) {
+	if !l.Enabled(level) {
+		return
+	}
+	message := strings.TrimRight(fmt.Sprintf(format, args...), "\n")
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, _ = fmt.Fprintf(l.writer, "[%s] %s\n", level, message)
+}
+
+type redactingLogger struct {
+	next   Logger
+	redact func(string) string
+}
+
+func (l redactingLogger) Enabled(level Level) bool {
+	return l.next.Enabled(level)
+}
+
+func (l redactingLogger) Logf(level Level, format string, args ...any) {
+	if !l.next.Enabled(level) {
+		return
+	}
+	l.next.Logf(level, "%s", l.redact(fmt.Sprintf(format, args...)))
+}
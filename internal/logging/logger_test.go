@@ -0,0 +1,33 @@
+package logging
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriterLoggerFiltersByLevelAndRedacts(t *testing.T) {
+	t.Parallel()
+
+	buffer := new(bytes.Buffer)
+	logger := WithRedaction(New(buffer, LevelInfo), func(value string) string {
+		return strings.ReplaceAll(value, "secret-token", "[REDACTED]")
+	})
+
+	Debugf(logger, "hidden debug line")
+	logger.Logf(LevelWarn, "auth failed for secret-token")
+
+	if got := buffer.String(); got != "[warn] auth failed for [REDACTED]\n" {
+		t.Fatalf("unexpected log output: %q", got)
+	}
+}
+
+func TestDebugfToleratesNilAndNopLoggers(t *testing.T) {
+	t.Parallel()
+
+	Debugf(nil, "ignored")
+	Debugf(Nop(), "ignored")
+	if OrNop(nil).Enabled(LevelWarn) {
+		t.Fatalf("expected nil logger to fall back to a disabled no-op logger")
+	}
+}
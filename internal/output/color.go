@@ -0,0 +1,71 @@
+package output
+
+import (
+	"io"
+	"os"
+
+	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
+)
+
+// pattern: Imperative Shell
+
+type ColorMode string
+
+const (
+	ColorAuto   ColorMode = "auto"
+	ColorAlways ColorMode = "always"
+	ColorNever  ColorMode = "never"
+)
+
+const (
+	ansiReset  = "\x1b[0m"
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+)
+
+// ShouldColor decides whether human output written to w gets ANSI colors.
+// In auto mode color needs a terminal and an unset or empty NO_COLOR.
+func ShouldColor(mode ColorMode, w io.Writer, lookupEnv func(string) (string, bool)) bool {
+	switch mode {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+
+	if lookupEnv != nil {
+		if value, ok := lookupEnv("NO_COLOR"); ok && value != "" {
+			return false
+		}
+	}
+	return isTerminal(w)
+}
+
+func isTerminal(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+func colorizeStatus(status contracts.PerIssueStatus) string {
+	color := ""
+	switch status {
+	case contracts.PerIssueStatusSuccess:
+		color = ansiGreen
+	case contracts.PerIssueStatusWarning:
+		color = ansiYellow
+	case contracts.PerIssueStatusError, contracts.PerIssueStatusConflict:
+		color = ansiRed
+	}
+	if color == "" {
+		return string(status)
+	}
+	return color + string(status) + ansiReset
+}
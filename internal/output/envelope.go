@@ -15,6 +15,8 @@ type Report struct {
 	DryRun      bool
 	Counts      contracts.AggregateCounts
 	Issues      []contracts.PerIssueResult
+	Timings     []contracts.PhaseTiming
+	Pagination  *contracts.PaginationMeta
 }
 
 func BuildEnvelope(report Report, duration time.Duration) (contracts.CommandEnvelope, error) {
@@ -24,9 +26,11 @@ func BuildEnvelope(report Report, duration time.Duration) (contracts.CommandEnve
 			Name:       report.CommandName,
 			DurationMS: duration.Milliseconds(),
 			DryRun:     report.DryRun,
+			Timings:    report.Timings,
 		},
-		Counts: report.Counts,
-		Issues: report.Issues,
+		Counts:     report.Counts,
+		Issues:     report.Issues,
+		Pagination: report.Pagination,
 	}
 
 	if err := contracts.ValidateEnvelopeBasics(env); err != nil {
@@ -36,6 +40,33 @@ func BuildEnvelope(report Report, duration time.Duration) (contracts.CommandEnve
 	return env, nil
 }
 
+// ExitOptions adjusts how a finished report maps to an exit code. The zero
+// value is the documented default.
+type ExitOptions struct {
+	// Strict turns warning-level issue messages into a partial exit even
+	// when every issue succeeded.
+	Strict bool
+}
+
 func ResolveExitCode(report Report, fatalErr error) contracts.ExitCode {
-	return contracts.ResolveExitCode(report.Counts, fatalErr != nil)
+	return ResolveExitCodeWithOptions(report, fatalErr, ExitOptions{})
+}
+
+func ResolveExitCodeWithOptions(report Report, fatalErr error, options ExitOptions) contracts.ExitCode {
+	code := contracts.ResolveExitCode(report.Counts, fatalErr != nil)
+	if code == contracts.ExitCodeSuccess && options.Strict && hasWarningMessage(report.Issues) {
+		return contracts.ExitCodePartial
+	}
+	return code
+}
+
+func hasWarningMessage(issues []contracts.PerIssueResult) bool {
+	for _, result := range issues {
+		for _, message := range result.Messages {
+			if message.Level == "warning" {
+				return true
+			}
+		}
+	}
+	return false
 }
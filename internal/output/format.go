@@ -0,0 +1,59 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
+)
+
+// pattern: Functional Core
+
+// IssueRow is the data a --format template sees for one issue. Status is the
+// issue's workflow status from its file; Result is the per-issue outcome.
+type IssueRow struct {
+	Key     string
+	Action  string
+	Result  string
+	Summary string
+	Status  string
+	State   string
+	Path    string
+}
+
+// ParseFormat compiles a --format template. The template is executed once
+// against an empty row so unknown fields fail here rather than mid-output.
+func ParseFormat(text string) (*template.Template, error) {
+	format, err := template.New("format").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --format template: %w", err)
+	}
+	if err := format.Execute(io.Discard, IssueRow{}); err != nil {
+		return nil, fmt.Errorf("invalid --format template: %w", err)
+	}
+	return format, nil
+}
+
+func rowFromResult(issue contracts.PerIssueResult) IssueRow {
+	row := IssueRow{Key: issue.Key, Action: issue.Action, Result: string(issue.Status)}
+	if issue.Details != nil {
+		row.Summary = issue.Details.Summary
+		row.Status = issue.Details.Status
+		row.State = issue.Details.State
+		row.Path = issue.Details.Path
+	}
+	return row
+}
+
+func writeFormattedIssue(w io.Writer, format *template.Template, issue contracts.PerIssueResult) error {
+	var line strings.Builder
+	if err := format.Execute(&line, rowFromResult(issue)); err != nil {
+		return fmt.Errorf("failed to render --format template: %w", err)
+	}
+	if _, err := fmt.Fprintln(w, strings.TrimRight(line.String(), "\n")); err != nil {
+		return fmt.Errorf("failed to write human output: %w", err)
+	}
+	return nil
+}
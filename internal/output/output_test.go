@@ -47,6 +47,31 @@ func TestResolveExitCodeUsesContractMatrix(t *testing.T) {
 	}
 }
 
+func TestResolveExitCodeStrictEscalatesWarningMessages(t *testing.T) {
+	report := Report{
+		Counts: contracts.AggregateCounts{Processed: 1, Updated: 1},
+		Issues: []contracts.PerIssueResult{{
+			Key:      "PROJ-1",
+			Action:   "updated",
+			Status:   contracts.PerIssueStatusSuccess,
+			Messages: []contracts.IssueMessage{{Level: "warning", ReasonCode: contracts.ReasonCodeTransitionAmbiguous, Text: "transition skipped"}},
+		}},
+	}
+
+	if code := ResolveExitCode(report, nil); code != contracts.ExitCodeSuccess {
+		t.Fatalf("expected warning-only report to succeed by default, got %d", code)
+	}
+	if code := ResolveExitCodeWithOptions(report, nil, ExitOptions{Strict: true}); code != contracts.ExitCodePartial {
+		t.Fatalf("expected strict mode to escalate warnings, got %d", code)
+	}
+	if code := ResolveExitCodeWithOptions(Report{Counts: report.Counts}, nil, ExitOptions{Strict: true}); code != contracts.ExitCodeSuccess {
+		t.Fatalf("expected strict mode to leave clean reports alone, got %d", code)
+	}
+	if code := ResolveExitCodeWithOptions(report, errors.New("boom"), ExitOptions{Strict: true}); code != contracts.ExitCodeFatal {
+		t.Fatalf("expected fatal errors to keep the fatal exit code, got %d", code)
+	}
+}
+
 func TestWriteJSONModeWritesEnvelopeAndDiagnostics(t *testing.T) {
 	stdout := new(bytes.Buffer)
 	stderr := new(bytes.Buffer)
@@ -86,3 +111,79 @@ func TestFormatDiagnosticNormalizesPrefix(t *testing.T) {
 		t.Fatalf("expected existing prefix to be preserved, got %q", got)
 	}
 }
+
+func TestShouldColorSuppressesNonTerminalAndNoColor(t *testing.T) {
+	noEnv := func(string) (string, bool) { return "", false }
+	noColor := func(key string) (string, bool) { return "1", key == "NO_COLOR" }
+
+	if ShouldColor(ColorAuto, new(bytes.Buffer), noEnv) {
+		t.Fatalf("expected auto mode to disable color for a non-terminal writer")
+	}
+	if ShouldColor(ColorAuto, new(bytes.Buffer), noColor) {
+		t.Fatalf("expected NO_COLOR to disable color")
+	}
+	if !ShouldColor(ColorAlways, new(bytes.Buffer), noColor) {
+		t.Fatalf("expected --color to force color")
+	}
+	if ShouldColor(ColorNever, new(bytes.Buffer), noEnv) {
+		t.Fatalf("expected --no-color to disable color")
+	}
+}
+
+func TestWriteWithOptionsColorsHumanStatusesOnlyWhenEnabled(t *testing.T) {
+	report := Report{CommandName: "status", Issues: []contracts.PerIssueResult{
+		{Key: "PROJ-1", Action: "modified", Status: contracts.PerIssueStatusSuccess},
+		{Key: "PROJ-2", Action: "conflict", Status: contracts.PerIssueStatusConflict},
+	}}
+
+	plain := new(bytes.Buffer)
+	if err := WriteWithOptions(contracts.OutputModeHuman, plain, new(bytes.Buffer), report, 0, nil, RenderOptions{}); err != nil {
+		t.Fatalf("plain write failed: %v", err)
+	}
+	if strings.Contains(plain.String(), "\x1b[") {
+		t.Fatalf("expected no ANSI codes without color, got %q", plain.String())
+	}
+
+	colored := new(bytes.Buffer)
+	if err := WriteWithOptions(contracts.OutputModeHuman, colored, new(bytes.Buffer), report, 0, nil, RenderOptions{Color: true}); err != nil {
+		t.Fatalf("colored write failed: %v", err)
+	}
+	if !strings.Contains(colored.String(), "[\x1b[32msuccess\x1b[0m]") || !strings.Contains(colored.String(), "[\x1b[31mconflict\x1b[0m]") {
+		t.Fatalf("expected colorized statuses, got %q", colored.String())
+	}
+
+	jsonOut := new(bytes.Buffer)
+	if err := WriteWithOptions(contracts.OutputModeJSON, jsonOut, new(bytes.Buffer), report, 0, nil, RenderOptions{Color: true}); err != nil {
+		t.Fatalf("json write failed: %v", err)
+	}
+	if strings.Contains(jsonOut.String(), "\x1b[") {
+		t.Fatalf("JSON output must never contain ANSI codes, got %q", jsonOut.String())
+	}
+}
+
+func TestWriteWithOptionsRendersFormatTemplatePerIssue(t *testing.T) {
+	format, err := ParseFormat("{{.Key}} {{.Status}} {{.Summary}} ({{.Result}})")
+	if err != nil {
+		t.Fatalf("parse format failed: %v", err)
+	}
+
+	report := Report{CommandName: "list", Counts: contracts.AggregateCounts{Processed: 2}, Issues: []contracts.PerIssueResult{
+		{Key: "PROJ-1", Action: "list", Status: contracts.PerIssueStatusSuccess, Details: &contracts.IssueDetails{Summary: "Fix login", Status: "In Progress"}},
+		{Key: "PROJ-2", Action: "parse-error", Status: contracts.PerIssueStatusError},
+	}}
+
+	stdout := new(bytes.Buffer)
+	if err := WriteWithOptions(contracts.OutputModeHuman, stdout, new(bytes.Buffer), report, 0, nil, RenderOptions{Format: format}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if got, want := stdout.String(), "PROJ-1 In Progress Fix login (success)\nPROJ-2   (error)\n"; got != want {
+		t.Fatalf("unexpected formatted output:\n%q\nwant:\n%q", got, want)
+	}
+
+	if _, err := ParseFormat("{{.Key"); err == nil {
+		t.Fatalf("expected syntax error at parse time")
+	}
+	if _, err := ParseFormat("{{.Assignee}}"); err == nil || !strings.Contains(err.Error(), "Assignee") {
+		t.Fatalf("expected unknown field to fail at parse time, got %v", err)
+	}
+}
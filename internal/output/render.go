@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
@@ -12,7 +13,22 @@ import (
 
 // pattern: Imperative Shell
 
+// RenderOptions tunes human-mode rendering. JSON output ignores it.
+type RenderOptions struct {
+	Color bool
+	// Quiet drops the summary and successful issues; only error and
+	// conflict issues are written, to stderr.
+	Quiet bool
+	// Format, when set, replaces the summary line and the default issue
+	// lines with one template-rendered line per issue. See ParseFormat.
+	Format *template.Template
+}
+
 func Write(mode contracts.OutputMode, stdout io.Writer, stderr io.Writer, report Report, duration time.Duration, fatalErr error) error {
+	return WriteWithOptions(mode, stdout, stderr, report, duration, fatalErr, RenderOptions{})
+}
+
+func WriteWithOptions(mode contracts.OutputMode, stdout io.Writer, stderr io.Writer, report Report, duration time.Duration, fatalErr error, options RenderOptions) error {
 	normalized := report
 	if fatalErr != nil && normalized.Counts.Errors == 0 {
 		normalized.Counts.Errors = 1
@@ -41,10 +57,21 @@ func Write(mode contracts.OutputMode, stdout io.Writer, stderr io.Writer, report
 			}
 			return nil
 		}
+		if options.Quiet {
+			return writeQuietHuman(stderr, normalized, options)
+		}
 
-		_, err := fmt.Fprintf(
-			stdout,
-			"%s: processed=%d updated=%d created=%d conflicts=%d warnings=%d errors=%d\n",
+		if options.Format != nil {
+			for _, issue := range normalized.Issues {
+				if err := writeFormattedIssue(stdout, options.Format, issue); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		summary := fmt.Sprintf(
+			"%s: processed=%d updated=%d created=%d conflicts=%d warnings=%d errors=%d",
 			normalized.CommandName,
 			normalized.Counts.Processed,
 			normalized.Counts.Updated,
@@ -53,22 +80,16 @@ func Write(mode contracts.OutputMode, stdout io.Writer, stderr io.Writer, report
 			normalized.Counts.Warnings,
 			normalized.Counts.Errors,
 		)
-		if err != nil {
+		if normalized.CommandName == string(contracts.CommandStatus) {
+			summary += fmt.Sprintf(" drafts=%d modified=%d", normalized.Counts.Drafts, normalized.Counts.Modified)
+		}
+		if _, err := fmt.Fprintln(stdout, summary); err != nil {
 			return fmt.Errorf("failed to write human output: %w", err)
 		}
 
 		for _, issue := range normalized.Issues {
-			if _, err := fmt.Fprintf(stdout, "- %s [%s] %s\n", issue.Key, issue.Status, issue.Action); err != nil {
-				return fmt.Errorf("failed to write human output: %w", err)
-			}
-			for _, message := range issue.Messages {
-				reason := ""
-				if message.ReasonCode != "" {
-					reason = " (" + string(message.ReasonCode) + ")"
-				}
-				if _, err := fmt.Fprintf(stdout, "  - %s%s: %s\n", message.Level, reason, message.Text); err != nil {
-					return fmt.Errorf("failed to write human output: %w", err)
-				}
+			if err := writeHumanIssue(stdout, issue, options); err != nil {
+				return err
 			}
 		}
 		return nil
@@ -77,6 +98,38 @@ func Write(mode contracts.OutputMode, stdout io.Writer, stderr io.Writer, report
 	}
 }
 
+func writeQuietHuman(stderr io.Writer, report Report, options RenderOptions) error {
+	for _, issue := range report.Issues {
+		if issue.Status != contracts.PerIssueStatusError && issue.Status != contracts.PerIssueStatusConflict {
+			continue
+		}
+		if err := writeHumanIssue(stderr, issue, options); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeHumanIssue(w io.Writer, issue contracts.PerIssueResult, options RenderOptions) error {
+	status := string(issue.Status)
+	if options.Color {
+		status = colorizeStatus(issue.Status)
+	}
+	if _, err := fmt.Fprintf(w, "- %s [%s] %s\n", issue.Key, status, issue.Action); err != nil {
+		return fmt.Errorf("failed to write human output: %w", err)
+	}
+	for _, message := range issue.Messages {
+		reason := ""
+		if message.ReasonCode != "" {
+			reason = " (" + string(message.ReasonCode) + ")"
+		}
+		if _, err := fmt.Fprintf(w, "  - %s%s: %s\n", message.Level, reason, message.Text); err != nil {
+			return fmt.Errorf("failed to write human output: %w", err)
+		}
+	}
+	return nil
+}
+
 func FormatDiagnostic(err error) string {
 	msg := strings.TrimSpace(err.Error())
 	if msg == "" {
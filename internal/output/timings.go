@@ -0,0 +1,49 @@
+package output
+
+import (
+	"time"
+
+	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
+)
+
+// PhaseTimings accumulates durations per phase. Phases declared up front
+// keep their declared order; others follow in the order first recorded.
+// Callers measure with time.Now/time.Since so the monotonic clock is used.
+type PhaseTimings struct {
+	order  []string
+	totals map[string]time.Duration
+}
+
+func NewPhaseTimings(phases ...string) *PhaseTimings {
+	timings := &PhaseTimings{}
+	for _, phase := range phases {
+		timings.Add(phase, 0)
+	}
+	return timings
+}
+
+func (t *PhaseTimings) Add(phase string, duration time.Duration) {
+	if t.totals == nil {
+		t.totals = make(map[string]time.Duration)
+	}
+	if _, ok := t.totals[phase]; !ok {
+		t.order = append(t.order, phase)
+	}
+	t.totals[phase] += duration
+}
+
+// Since records the time elapsed from started under phase.
+func (t *PhaseTimings) Since(phase string, started time.Time) {
+	t.Add(phase, time.Since(started))
+}
+
+func (t *PhaseTimings) Timings() []contracts.PhaseTiming {
+	if t == nil || len(t.order) == 0 {
+		return nil
+	}
+	timings := make([]contracts.PhaseTiming, 0, len(t.order))
+	for _, phase := range t.order {
+		timings = append(timings, contracts.PhaseTiming{Phase: phase, DurationUS: t.totals[phase].Microseconds()})
+	}
+	return timings
+}
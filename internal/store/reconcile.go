@@ -0,0 +1,97 @@
+package store
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
+	"github.com/pweiskircher/jira-issue-sync/internal/issue"
+)
+
+// CacheDrift lists the cache repairs LoadReconciledCache made, by key.
+type CacheDrift struct {
+	// Added are keys whose issue file exists but had no cache entry, for
+	// example a file copied in by hand.
+	Added []string
+	// Repointed are keys whose cached path was gone while a file for the
+	// key exists elsewhere.
+	Repointed []string
+	// Dropped are keys whose cached file vanished with no replacement.
+	Dropped []string
+}
+
+// Empty reports whether the cache already matched the files on disk.
+func (d CacheDrift) Empty() bool {
+	return len(d.Added) == 0 && len(d.Repointed) == 0 && len(d.Dropped) == 0
+}
+
+// LoadReconciledCache loads the cache and brings it in line with the issue
+// files actually on disk. Keys are taken from filenames, so files that
+// fail to parse still count; local drafts are skipped since the cache only
+// tracks issues that exist in Jira. Rebuilt entries carry no remote timestamp,
+// which keeps pull from treating them as unchanged. Nothing is saved; the
+// caller persists the repaired cache if it writes one anyway.
+func (s *Store) LoadReconciledCache() (Cache, CacheDrift, error) {
+	cache, err := s.LoadCache()
+	if err != nil {
+		return Cache{}, CacheDrift{}, err
+	}
+
+	pathsByKey := map[string][]string{}
+	present := map[string]bool{}
+	for _, dir := range IssueDirs {
+		names, err := s.listMarkdownFiles(dir)
+		if err != nil {
+			return Cache{}, CacheDrift{}, err
+		}
+		for _, name := range names {
+			key, ok := issue.ParseFilenameKey(name)
+			if !ok || contracts.IsLocalDraftKey(key) {
+				continue
+			}
+			relativePath := filepath.Join(dir, name)
+			pathsByKey[key] = append(pathsByKey[key], relativePath)
+			present[relativePath] = true
+		}
+	}
+
+	drift := CacheDrift{}
+	for key, entry := range cache.Issues {
+		entryPath := strings.TrimSpace(entry.Path)
+		if entryPath == "" || present[filepath.Clean(entryPath)] {
+			continue
+		}
+		if paths := pathsByKey[key]; len(paths) > 0 {
+			entry.Path = paths[0]
+			entry.Status = cachedStateForPath(paths[0])
+			cache.Issues[key] = entry
+			drift.Repointed = append(drift.Repointed, key)
+			continue
+		}
+		delete(cache.Issues, key)
+		drift.Dropped = append(drift.Dropped, key)
+	}
+	for key, paths := range pathsByKey {
+		if _, ok := cache.Issues[key]; ok {
+			continue
+		}
+		cache.Issues[key] = CacheEntry{Path: paths[0], Status: cachedStateForPath(paths[0])}
+		drift.Added = append(drift.Added, key)
+	}
+
+	sort.Strings(drift.Added)
+	sort.Strings(drift.Repointed)
+	sort.Strings(drift.Dropped)
+	return cache, drift, nil
+}
+
+// cachedStateForPath returns the state implied by an issue file's
+// directory; files in the flat root carry none.
+func cachedStateForPath(relativePath string) string {
+	dir := filepath.Dir(relativePath)
+	if dir == "." {
+		return ""
+	}
+	return dir
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
@@ -21,19 +22,92 @@ const (
 	IssueStateClosed IssueState = "closed"
 )
 
+// IssueDirs lists every directory an issue file may live in, relative to
+// the issues root: the root itself for the flat layout, then open/ and
+// closed/. Readers walk all three so either layout, or a workspace halfway
+// through a switch, loads the same way.
+var IssueDirs = []string{".", "open", "closed"}
+
+// IsIssueFile reports whether name inside dir is an issue file. In the root
+// only names starting with an issue key count, so other Markdown files kept
+// next to open/ and closed/ are left alone.
+func IsIssueFile(dir string, name string) bool {
+	if strings.ToLower(filepath.Ext(name)) != ".md" {
+		return false
+	}
+	if filepath.Clean(dir) != "." {
+		return true
+	}
+	_, ok := issue.ParseFilenameKey(name)
+	return ok
+}
+
+// IssueStateForStatus maps a Jira status name to the directory state it is
+// filed under in the split layout.
+func IssueStateForStatus(status string) IssueState {
+	normalized := strings.ToLower(strings.TrimSpace(status))
+	switch normalized {
+	case "done", "closed", "resolved", "complete", "completed", "rejected", "declined", "cancelled", "canceled", "won't do", "wont do":
+		return IssueStateClosed
+	default:
+		return IssueStateOpen
+	}
+}
+
 type Cache struct {
 	Version string                `json:"version"`
 	Issues  map[string]CacheEntry `json:"issues"`
 }
 
 type CacheEntry struct {
+	// ID is Jira's numeric issue id. Unlike the key it survives a move to
+	// another project, so it is the issue's stable identity.
+	ID              string `json:"id,omitempty"`
 	Path            string `json:"path,omitempty"`
 	Status          string `json:"status,omitempty"`
 	RemoteUpdatedAt string `json:"remote_updated_at,omitempty"`
 }
 
+// FieldsCache is .sync/fields.json: the Jira field list saved by
+// `init --discover` so custom field IDs can be looked up offline.
+type FieldsCache struct {
+	Fields []FieldsCacheEntry `json:"fields"`
+}
+
+type FieldsCacheEntry struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Custom bool   `json:"custom"`
+}
+
+// SecurityLevelsCache is .sync/security-levels.json: each project's issue
+// security levels, saved when draft publish first needs them so a level
+// name can be sent as its ID.
+type SecurityLevelsCache struct {
+	Projects map[string][]SecurityLevelsCacheEntry `json:"projects"`
+}
+
+type SecurityLevelsCacheEntry struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// PullProgress is .sync/pull-progress.json: where an interrupted pull
+// stopped. It names the next search page for JQL and the keys already
+// persisted, and is removed once a pull completes.
+type PullProgress struct {
+	JQL           string   `json:"jql"`
+	StartAt       int      `json:"start_at,omitempty"`
+	NextPageToken string   `json:"next_page_token,omitempty"`
+	ProcessedKeys []string `json:"processed_keys"`
+}
+
 type Store struct {
-	fs *internalfs.SafeFS
+	fs       *internalfs.SafeFS
+	filename issue.FilenameOptions
+	render   issue.RenderOptions
+	parse    issue.ParseOptions
+	flat     bool
 }
 
 func New(root string) (*Store, error) {
@@ -49,6 +123,74 @@ func NewDefault() (*Store, error) {
 	return New(contracts.DefaultIssuesRootDir)
 }
 
+// SetFilenameOptions changes the naming style used for issue files written
+// or reconciled through this store.
+func (s *Store) SetFilenameOptions(options issue.FilenameOptions) {
+	if s != nil {
+		s.filename = options
+	}
+}
+
+// SetRenderOptions changes how documents rendered through this store are
+// laid out on disk.
+func (s *Store) SetRenderOptions(options issue.RenderOptions) {
+	if s != nil {
+		s.render = options
+	}
+}
+
+// SetFlatLayout makes issue files written through this store go directly
+// under the issues root instead of open/ or closed/.
+func (s *Store) SetFlatLayout(flat bool) {
+	if s != nil {
+		s.flat = flat
+	}
+}
+
+// SetParseOptions enables the optional document checks Verify runs.
+func (s *Store) SetParseOptions(options issue.ParseOptions) {
+	if s != nil {
+		s.parse = options
+	}
+}
+
+// RenderDocument renders doc for writing under the store's render options.
+func (s *Store) RenderDocument(doc issue.Document) (string, error) {
+	options := issue.RenderOptions{}
+	if s != nil {
+		options = s.render
+	}
+	return issue.RenderDocumentWithOptions(doc, options)
+}
+
+// IssueFilename returns the canonical filename for key and summary under the
+// store's filename options.
+func (s *Store) IssueFilename(key, summary string) (string, error) {
+	options := issue.FilenameOptions{}
+	if s != nil {
+		options = s.filename
+	}
+	return issue.BuildFilenameWithOptions(strings.TrimSpace(key), summary, options)
+}
+
+// IssuePath returns where the issue file for key belongs: its state
+// directory, or the issues root under the flat layout.
+func (s *Store) IssuePath(state IssueState, key, summary string) (string, error) {
+	dir, err := issueDir(state)
+	if err != nil {
+		return "", err
+	}
+	if s != nil && s.flat {
+		dir = ""
+	}
+
+	filename, err := s.IssueFilename(key, summary)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, filename), nil
+}
+
 func (s *Store) Root() string {
 	if s == nil || s.fs == nil {
 		return ""
@@ -61,7 +203,10 @@ func (s *Store) EnsureLayout() error {
 		return fmt.Errorf("store is not initialized")
 	}
 
-	dirs := []string{"open", "closed", ".sync", filepath.Join(".sync", "originals")}
+	dirs := []string{".sync", filepath.Join(".sync", "originals")}
+	if !s.flat {
+		dirs = append([]string{"open", "closed"}, dirs...)
+	}
 	for _, dir := range dirs {
 		if err := s.fs.EnsureDir(dir, 0o755); err != nil {
 			return err
@@ -75,17 +220,10 @@ func (s *Store) WriteIssue(state IssueState, key, summary, markdown string) (str
 		return "", err
 	}
 
-	dir, err := issueDir(state)
-	if err != nil {
-		return "", err
-	}
-
-	filename, err := issue.BuildFilename(strings.TrimSpace(key), summary)
+	relativePath, err := s.IssuePath(state, key, summary)
 	if err != nil {
 		return "", err
 	}
-
-	relativePath := filepath.Join(dir, filename)
 	if err := s.fs.WriteFileAtomic(relativePath, normalizeText(markdown), 0o644); err != nil {
 		return "", err
 	}
@@ -99,7 +237,7 @@ func (s *Store) WriteOriginalSnapshot(key string, markdown string) (string, erro
 	}
 
 	trimmedKey := strings.TrimSpace(key)
-	if !contracts.JiraIssueKeyPattern.MatchString(trimmedKey) && !contracts.LocalDraftKeyPattern.MatchString(trimmedKey) {
+	if !contracts.JiraIssueKeyPattern.MatchString(trimmedKey) && !contracts.IsLocalDraftKey(trimmedKey) {
 		return "", fmt.Errorf("invalid issue key %q", key)
 	}
 
@@ -126,6 +264,134 @@ func (s *Store) SaveCache(cache Cache) error {
 	return s.fs.WriteFileAtomic(filepath.Join(".sync", "cache.json"), encoded, 0o644)
 }
 
+// SaveFieldsCache writes fields sorted by ID.
+func (s *Store) SaveFieldsCache(cache FieldsCache) error {
+	if err := s.EnsureLayout(); err != nil {
+		return err
+	}
+
+	fields := append([]FieldsCacheEntry(nil), cache.Fields...)
+	sort.Slice(fields, func(i, j int) bool { return fields[i].ID < fields[j].ID })
+	encoded, err := json.MarshalIndent(FieldsCache{Fields: fields}, "", "  ")
+	if err != nil {
+		return err
+	}
+	encoded = append(encoded, '\n')
+
+	return s.fs.WriteFileAtomic(filepath.Join(".sync", "fields.json"), encoded, 0o644)
+}
+
+// LoadFieldsCache returns an empty cache when fields.json does not exist.
+func (s *Store) LoadFieldsCache() (FieldsCache, error) {
+	if s == nil || s.fs == nil {
+		return FieldsCache{}, fmt.Errorf("store is not initialized")
+	}
+
+	encoded, err := s.fs.ReadFile(filepath.Join(".sync", "fields.json"))
+	if err != nil {
+		if errorsIsNotExist(err) {
+			return FieldsCache{}, nil
+		}
+		return FieldsCache{}, err
+	}
+
+	var cache FieldsCache
+	if err := json.Unmarshal(encoded, &cache); err != nil {
+		return FieldsCache{}, err
+	}
+	return cache, nil
+}
+
+// SaveSecurityLevelsCache writes each project's levels sorted by name.
+func (s *Store) SaveSecurityLevelsCache(cache SecurityLevelsCache) error {
+	if err := s.EnsureLayout(); err != nil {
+		return err
+	}
+
+	projects := make(map[string][]SecurityLevelsCacheEntry, len(cache.Projects))
+	for projectKey, levels := range cache.Projects {
+		sorted := append([]SecurityLevelsCacheEntry(nil), levels...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+		projects[projectKey] = sorted
+	}
+	encoded, err := json.MarshalIndent(SecurityLevelsCache{Projects: projects}, "", "  ")
+	if err != nil {
+		return err
+	}
+	encoded = append(encoded, '\n')
+
+	return s.fs.WriteFileAtomic(filepath.Join(".sync", "security-levels.json"), encoded, 0o644)
+}
+
+// LoadSecurityLevelsCache returns an empty cache when security-levels.json
+// does not exist.
+func (s *Store) LoadSecurityLevelsCache() (SecurityLevelsCache, error) {
+	if s == nil || s.fs == nil {
+		return SecurityLevelsCache{}, fmt.Errorf("store is not initialized")
+	}
+
+	encoded, err := s.fs.ReadFile(filepath.Join(".sync", "security-levels.json"))
+	if err != nil {
+		if errorsIsNotExist(err) {
+			return SecurityLevelsCache{}, nil
+		}
+		return SecurityLevelsCache{}, err
+	}
+
+	var cache SecurityLevelsCache
+	if err := json.Unmarshal(encoded, &cache); err != nil {
+		return SecurityLevelsCache{}, err
+	}
+	return cache, nil
+}
+
+// SavePullProgress writes progress with processed keys sorted.
+func (s *Store) SavePullProgress(progress PullProgress) error {
+	if err := s.EnsureLayout(); err != nil {
+		return err
+	}
+
+	progress.ProcessedKeys = append([]string(nil), progress.ProcessedKeys...)
+	sort.Strings(progress.ProcessedKeys)
+	encoded, err := json.MarshalIndent(progress, "", "  ")
+	if err != nil {
+		return err
+	}
+	encoded = append(encoded, '\n')
+
+	return s.fs.WriteFileAtomic(filepath.Join(".sync", "pull-progress.json"), encoded, 0o644)
+}
+
+// LoadPullProgress reports false when no pull was left unfinished.
+func (s *Store) LoadPullProgress() (PullProgress, bool, error) {
+	if s == nil || s.fs == nil {
+		return PullProgress{}, false, fmt.Errorf("store is not initialized")
+	}
+
+	encoded, err := s.fs.ReadFile(filepath.Join(".sync", "pull-progress.json"))
+	if err != nil {
+		if errorsIsNotExist(err) {
+			return PullProgress{}, false, nil
+		}
+		return PullProgress{}, false, err
+	}
+
+	var progress PullProgress
+	if err := json.Unmarshal(encoded, &progress); err != nil {
+		return PullProgress{}, false, err
+	}
+	return progress, true, nil
+}
+
+// ClearPullProgress removes the progress file; a missing file is not an
+// error.
+func (s *Store) ClearPullProgress() error {
+	if s == nil || s.fs == nil {
+		return fmt.Errorf("store is not initialized")
+	}
+	return s.fs.Remove(filepath.Join(".sync", "pull-progress.json"))
+}
+
 func (s *Store) LoadCache() (Cache, error) {
 	if s == nil || s.fs == nil {
 		return Cache{}, fmt.Errorf("store is not initialized")
@@ -154,6 +420,58 @@ func (s *Store) WriteFile(relativePath string, data []byte) error {
 	return s.fs.WriteFileAtomic(relativePath, data, 0o644)
 }
 
+// ReconcileFilename renames an issue file to its canonical name for the given
+// key and summary. The file stays in its current directory; moving between
+// open/, closed/, and the flat root is left to pull, which knows the remote
+// status.
+// The cache entry for key is updated when it pointed at the old path. It
+// returns the resulting relative path and whether a rename happened.
+func (s *Store) ReconcileFilename(relativePath string, key string, summary string) (string, bool, error) {
+	if s == nil || s.fs == nil {
+		return "", false, fmt.Errorf("store is not initialized")
+	}
+
+	cleaned := filepath.Clean(strings.TrimSpace(relativePath))
+	dir := filepath.Dir(cleaned)
+	if _, err := issueDir(IssueState(dir)); err != nil && dir != "." {
+		return "", false, fmt.Errorf("issue path %q is not inside the issues root, open/, or closed/", relativePath)
+	}
+
+	filename, err := s.IssueFilename(key, summary)
+	if err != nil {
+		return "", false, err
+	}
+
+	target := filepath.Join(dir, filename)
+	if target == cleaned {
+		return cleaned, false, nil
+	}
+	if _, err := s.fs.ReadFile(target); err == nil {
+		return "", false, fmt.Errorf("cannot rename %q: canonical path %q already exists", cleaned, target)
+	} else if !errorsIsNotExist(err) {
+		return "", false, err
+	}
+
+	cache, err := s.LoadCache()
+	if err != nil {
+		return "", false, err
+	}
+
+	if err := s.fs.Rename(cleaned, target); err != nil {
+		return "", false, err
+	}
+
+	if entry, ok := cache.Issues[strings.TrimSpace(key)]; ok && filepath.Clean(entry.Path) == cleaned {
+		entry.Path = target
+		cache.Issues[strings.TrimSpace(key)] = entry
+		if err := s.SaveCache(cache); err != nil {
+			return "", false, err
+		}
+	}
+
+	return target, true, nil
+}
+
 func (s *Store) Rename(fromRelativePath, toRelativePath string) error {
 	if s == nil || s.fs == nil {
 		return fmt.Errorf("store is not initialized")
@@ -2,11 +2,14 @@ package store
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
 	internalfs "github.com/pweiskircher/jira-issue-sync/internal/fs"
+	"github.com/pweiskircher/jira-issue-sync/internal/issue"
 )
 
 func TestStoreEnsureLayoutCreatesContractDirectories(t *testing.T) {
@@ -34,6 +37,60 @@ func TestStoreEnsureLayoutCreatesContractDirectories(t *testing.T) {
 	}
 }
 
+func TestIssueStateForStatusTreatsRejectedAsClosed(t *testing.T) {
+	t.Parallel()
+
+	closedStatuses := []string{"Rejected", "Declined", "Cancelled", "Won't Do"}
+	for _, status := range closedStatuses {
+		if got := IssueStateForStatus(status); got != IssueStateClosed {
+			t.Fatalf("expected status %q to be closed, got %q", status, got)
+		}
+	}
+}
+
+func TestStoreFlatLayoutWritesIssuesUnderRoot(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join(t.TempDir(), ".issues")
+	store, err := New(root)
+	if err != nil {
+		t.Fatalf("new store failed: %v", err)
+	}
+	store.SetFlatLayout(true)
+
+	doc := issue.Document{
+		CanonicalKey: "PROJ-3",
+		FrontMatter:  issue.FrontMatter{Key: "PROJ-3", Summary: "Flat issue", IssueType: "Task", Status: "Done"},
+	}
+	rendered, err := issue.RenderDocument(doc)
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	path, err := store.WriteIssue(IssueStateClosed, "PROJ-3", "Flat issue", rendered)
+	if err != nil {
+		t.Fatalf("write issue failed: %v", err)
+	}
+	if path != "PROJ-3-flat-issue.md" {
+		t.Fatalf("expected issue directly under the root, got %q", path)
+	}
+	for _, dir := range []string{"open", "closed"} {
+		if _, err := os.Stat(filepath.Join(root, dir)); !errors.Is(err, os.ErrNotExist) {
+			t.Fatalf("expected flat layout not to create %s/, got %v", dir, err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "README.md"), []byte("# notes\n"), 0o644); err != nil {
+		t.Fatalf("write readme failed: %v", err)
+	}
+
+	result, err := store.Verify()
+	if err != nil {
+		t.Fatalf("verify failed: %v", err)
+	}
+	if result.IssuesChecked != 1 || len(result.Problems) != 0 {
+		t.Fatalf("expected only the flat issue to be checked, got %#v", result)
+	}
+}
+
 func TestStorePersistsIssueAndSnapshotDeterministically(t *testing.T) {
 	t.Parallel()
 
@@ -105,6 +162,108 @@ func TestStoreCacheRoundTripDeterministic(t *testing.T) {
 	}
 }
 
+func TestStoreLoadReconciledCacheFollowsFilesOnDisk(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join(t.TempDir(), ".issues")
+	store, err := New(root)
+	if err != nil {
+		t.Fatalf("new store failed: %v", err)
+	}
+	if err := store.SaveCache(Cache{Issues: map[string]CacheEntry{
+		"PROJ-1": {ID: "10001", Path: filepath.Join("open", "PROJ-1-kept.md"), Status: "open", RemoteUpdatedAt: "2026-01-01T00:00:00Z"},
+		"PROJ-2": {ID: "10002", Path: filepath.Join("open", "PROJ-2-moved.md"), Status: "open"},
+		"PROJ-3": {ID: "10003", Path: filepath.Join("open", "PROJ-3-deleted.md"), Status: "open"},
+	}}); err != nil {
+		t.Fatalf("save cache failed: %v", err)
+	}
+	for _, path := range []string{
+		filepath.Join("open", "PROJ-1-kept.md"),
+		filepath.Join("closed", "PROJ-2-moved.md"),
+		filepath.Join("open", "PROJ-4-hand-made.md"),
+		filepath.Join("open", "L-1a2b3c-draft.md"),
+		"README.md",
+	} {
+		if err := store.WriteFile(path, []byte("x")); err != nil {
+			t.Fatalf("write %s: %v", path, err)
+		}
+	}
+
+	cache, drift, err := store.LoadReconciledCache()
+	if err != nil {
+		t.Fatalf("reconcile cache failed: %v", err)
+	}
+	if fmt.Sprint(drift.Added, drift.Repointed, drift.Dropped) != "[PROJ-4] [PROJ-2] [PROJ-3]" {
+		t.Fatalf("unexpected drift: %#v", drift)
+	}
+	if cache.Issues["PROJ-1"].RemoteUpdatedAt == "" {
+		t.Fatalf("expected matching entry to be kept as-is, got %#v", cache.Issues["PROJ-1"])
+	}
+	if got := cache.Issues["PROJ-2"]; got.ID != "10002" || got.Path != filepath.Join("closed", "PROJ-2-moved.md") || got.Status != "closed" {
+		t.Fatalf("expected PROJ-2 to follow its file, got %#v", got)
+	}
+	if _, ok := cache.Issues["PROJ-3"]; ok {
+		t.Fatalf("expected PROJ-3 to be dropped")
+	}
+	if got := cache.Issues["PROJ-4"]; got.Path != filepath.Join("open", "PROJ-4-hand-made.md") || got.RemoteUpdatedAt != "" {
+		t.Fatalf("expected PROJ-4 rebuilt from its filename, got %#v", got)
+	}
+	if _, ok := cache.Issues["L-1a2b3c"]; ok {
+		t.Fatalf("expected local draft to stay out of the cache")
+	}
+
+	saved, err := store.LoadCache()
+	if err != nil {
+		t.Fatalf("load cache failed: %v", err)
+	}
+	if _, ok := saved.Issues["PROJ-3"]; !ok {
+		t.Fatalf("expected reconciliation to leave the saved cache alone")
+	}
+}
+
+func TestStoreCachePersistsIssueIDsDeterministically(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join(t.TempDir(), ".issues")
+	store, err := New(root)
+	if err != nil {
+		t.Fatalf("new store failed: %v", err)
+	}
+
+	entries := map[string]CacheEntry{
+		"PROJ-10": {ID: "10010", Path: filepath.Join("open", "PROJ-10.md"), Status: "open"},
+		"PROJ-2":  {ID: "10002", Path: filepath.Join("open", "PROJ-2.md"), Status: "open", RemoteUpdatedAt: "2026-02-20T12:00:00Z"},
+	}
+	var first []byte
+	for attempt := 0; attempt < 3; attempt++ {
+		if err := store.SaveCache(Cache{Issues: entries}); err != nil {
+			t.Fatalf("save cache failed: %v", err)
+		}
+		encoded, err := store.ReadFile(filepath.Join(".sync", "cache.json"))
+		if err != nil {
+			t.Fatalf("read cache file failed: %v", err)
+		}
+		if first == nil {
+			first = encoded
+		} else if string(encoded) != string(first) {
+			t.Fatalf("cache bytes changed between saves:\n%s\n%s", first, encoded)
+		}
+	}
+
+	expected := "{\n  \"version\": \"1\",\n  \"issues\": {\n    \"PROJ-10\": {\n      \"id\": \"10010\",\n      \"path\": \"open/PROJ-10.md\",\n      \"status\": \"open\"\n    },\n    \"PROJ-2\": {\n      \"id\": \"10002\",\n      \"path\": \"open/PROJ-2.md\",\n      \"status\": \"open\",\n      \"remote_updated_at\": \"2026-02-20T12:00:00Z\"\n    }\n  }\n}\n"
+	if string(first) != expected {
+		t.Fatalf("unexpected cache file:\n%s", first)
+	}
+
+	loaded, err := store.LoadCache()
+	if err != nil {
+		t.Fatalf("load cache failed: %v", err)
+	}
+	if loaded.Issues["PROJ-10"].ID != "10010" || loaded.Issues["PROJ-2"].ID != "10002" {
+		t.Fatalf("expected ids to round-trip, got %#v", loaded.Issues)
+	}
+}
+
 func TestStoreWriteRejectsEscapingPath(t *testing.T) {
 	t.Parallel()
 
@@ -119,3 +278,97 @@ func TestStoreWriteRejectsEscapingPath(t *testing.T) {
 		t.Fatalf("expected path safety error, got: %v", err)
 	}
 }
+
+func TestStoreVerifyReportsFilenameMismatchAndOrphanSnapshot(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join(t.TempDir(), ".issues")
+	store, err := New(root)
+	if err != nil {
+		t.Fatalf("new store failed: %v", err)
+	}
+
+	canonical, err := issue.RenderDocument(issue.Document{
+		CanonicalKey: "PROJ-1",
+		FrontMatter: issue.FrontMatter{
+			SchemaVersion: contracts.IssueFileSchemaVersionV1,
+			Key:           "PROJ-1",
+			Summary:       "Renamed summary",
+			IssueType:     "Task",
+			Status:        "Open",
+		},
+	})
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+
+	if err := store.WriteFile(filepath.Join("open", "PROJ-1-old-summary.md"), []byte(canonical)); err != nil {
+		t.Fatalf("write issue failed: %v", err)
+	}
+	if _, err := store.WriteOriginalSnapshot("PROJ-1", canonical); err != nil {
+		t.Fatalf("write snapshot failed: %v", err)
+	}
+	if _, err := store.WriteOriginalSnapshot("PROJ-9", canonical); err != nil {
+		t.Fatalf("write orphan snapshot failed: %v", err)
+	}
+	if err := store.SaveCache(Cache{Issues: map[string]CacheEntry{
+		"PROJ-1": {Path: filepath.Join("open", "PROJ-1-old-summary.md"), Status: "open"},
+		"PROJ-7": {Path: filepath.Join("closed", "PROJ-7-gone.md"), Status: "closed"},
+	}}); err != nil {
+		t.Fatalf("save cache failed: %v", err)
+	}
+
+	result, err := store.Verify()
+	if err != nil {
+		t.Fatalf("verify failed: %v", err)
+	}
+	if result.IssuesChecked != 1 {
+		t.Fatalf("expected one checked issue, got %d", result.IssuesChecked)
+	}
+
+	expected := []struct {
+		code VerifyProblemCode
+		key  string
+		path string
+	}{
+		{VerifyProblemFilenameMismatch, "PROJ-1", filepath.Join("open", "PROJ-1-old-summary.md")},
+		{VerifyProblemCacheMissingFile, "PROJ-7", filepath.Join(".sync", "cache.json")},
+		{VerifyProblemOrphanSnapshot, "PROJ-9", filepath.Join(".sync", "originals", "PROJ-9.md")},
+	}
+	if len(result.Problems) != len(expected) {
+		t.Fatalf("unexpected problems: %#v", result.Problems)
+	}
+	for index, want := range expected {
+		got := result.Problems[index]
+		if got.Code != want.code || got.Key != want.key || got.Path != want.path {
+			t.Fatalf("unexpected problem at %d: %#v", index, got)
+		}
+	}
+}
+
+func TestStoreReconcileFilenameFollowsConfiguredStyle(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join(t.TempDir(), ".issues")
+	store, err := New(root)
+	if err != nil {
+		t.Fatalf("new store failed: %v", err)
+	}
+
+	oldPath, err := store.WriteIssue(IssueStateOpen, "PROJ-42", "Fix Login Flow", "body")
+	if err != nil {
+		t.Fatalf("write issue failed: %v", err)
+	}
+
+	store.SetFilenameOptions(issue.FilenameOptions{Style: contracts.FilenameStyleKeyOnly})
+	renamedPath, renamed, err := store.ReconcileFilename(oldPath, "PROJ-42", "Fix Login Flow")
+	if err != nil {
+		t.Fatalf("reconcile failed: %v", err)
+	}
+	if !renamed || renamedPath != filepath.Join("open", "PROJ-42.md") {
+		t.Fatalf("expected rename to key-only path, got %q renamed=%v", renamedPath, renamed)
+	}
+	if _, err := os.Stat(filepath.Join(root, oldPath)); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected old path to be gone, got %v", err)
+	}
+}
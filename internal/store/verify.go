@@ -0,0 +1,223 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
+	"github.com/pweiskircher/jira-issue-sync/internal/issue"
+)
+
+// VerifyProblemCode classifies a workspace inconsistency found by Verify.
+type VerifyProblemCode string
+
+const (
+	VerifyProblemParseFailed       VerifyProblemCode = "parse_failed"
+	VerifyProblemFilenameMismatch  VerifyProblemCode = "filename_mismatch"
+	VerifyProblemDuplicateKey      VerifyProblemCode = "duplicate_key"
+	VerifyProblemCacheMissingFile  VerifyProblemCode = "cache_missing_file"
+	VerifyProblemCachePathMismatch VerifyProblemCode = "cache_path_mismatch"
+	VerifyProblemCacheUnreadable   VerifyProblemCode = "cache_unreadable"
+	VerifyProblemOrphanSnapshot    VerifyProblemCode = "orphan_snapshot"
+	VerifyProblemStaleRawADF       VerifyProblemCode = "stale_raw_adf"
+)
+
+// VerifyProblem is one deterministic finding reported by Verify.
+type VerifyProblem struct {
+	Code       VerifyProblemCode
+	ReasonCode contracts.ReasonCode
+	Key        string
+	Path       string
+	Message    string
+}
+
+// VerifyResult lists every problem found in the workspace, sorted by key,
+// path, and code.
+type VerifyResult struct {
+	IssuesChecked int
+	Problems      []VerifyProblem
+}
+
+// Verify walks the issues root, open/, closed/, and the originals directory and reports
+// parse failures, non-canonical filenames, stale cache entries, and
+// snapshots without a matching issue file, plus stale raw ADF blocks when
+// SetParseOptions supplied a converter. It never modifies the workspace.
+func (s *Store) Verify() (VerifyResult, error) {
+	if s == nil || s.fs == nil {
+		return VerifyResult{}, fmt.Errorf("store is not initialized")
+	}
+
+	result := VerifyResult{}
+	pathsByKey := make(map[string][]string)
+
+	for _, dir := range IssueDirs {
+		names, err := s.listMarkdownFiles(dir)
+		if err != nil {
+			return VerifyResult{}, err
+		}
+
+		for _, name := range names {
+			relativePath := filepath.Join(dir, name)
+			result.IssuesChecked++
+
+			content, err := s.fs.ReadFile(relativePath)
+			if err != nil {
+				return VerifyResult{}, err
+			}
+
+			doc, warnings, parseErr := issue.ParseDocumentWithOptions(relativePath, string(content), s.parse)
+			if parseErr != nil {
+				key, _ := issue.ParseFilenameKey(relativePath)
+				reason := contracts.ReasonCodeValidationFailed
+				var typed *issue.ParseError
+				if errors.As(parseErr, &typed) && typed.ReasonCode != "" {
+					reason = typed.ReasonCode
+				}
+				result.Problems = append(result.Problems, VerifyProblem{
+					Code:       VerifyProblemParseFailed,
+					ReasonCode: reason,
+					Key:        key,
+					Path:       relativePath,
+					Message:    parseErr.Error(),
+				})
+				if key != "" {
+					pathsByKey[key] = append(pathsByKey[key], relativePath)
+				}
+				continue
+			}
+
+			key := doc.CanonicalKey
+			pathsByKey[key] = append(pathsByKey[key], relativePath)
+
+			expected, err := s.IssueFilename(key, doc.FrontMatter.Summary)
+			if err == nil && expected != name {
+				result.Problems = append(result.Problems, VerifyProblem{
+					Code:       VerifyProblemFilenameMismatch,
+					ReasonCode: contracts.ReasonCodeValidationFailed,
+					Key:        key,
+					Path:       relativePath,
+					Message:    fmt.Sprintf("filename does not match canonical name %q", expected),
+				})
+			}
+			for _, warning := range warnings {
+				result.Problems = append(result.Problems, VerifyProblem{
+					Code:       VerifyProblemStaleRawADF,
+					ReasonCode: warning.ReasonCode,
+					Key:        key,
+					Path:       relativePath,
+					Message:    warning.Message,
+				})
+			}
+		}
+	}
+
+	for key, paths := range pathsByKey {
+		if len(paths) < 2 {
+			continue
+		}
+		sorted := append([]string(nil), paths...)
+		sort.Strings(sorted)
+		for _, path := range sorted {
+			result.Problems = append(result.Problems, VerifyProblem{
+				Code:       VerifyProblemDuplicateKey,
+				ReasonCode: contracts.ReasonCodeValidationFailed,
+				Key:        key,
+				Path:       path,
+				Message:    "issue key appears in multiple files: " + strings.Join(sorted, ", "),
+			})
+		}
+	}
+
+	cachePath := filepath.Join(".sync", "cache.json")
+	cache, err := s.LoadCache()
+	if err != nil {
+		result.Problems = append(result.Problems, VerifyProblem{
+			Code:       VerifyProblemCacheUnreadable,
+			ReasonCode: contracts.ReasonCodeValidationFailed,
+			Path:       cachePath,
+			Message:    err.Error(),
+		})
+	} else {
+		for key, entry := range cache.Issues {
+			entryPath := strings.TrimSpace(entry.Path)
+			if entryPath == "" {
+				continue
+			}
+			paths := pathsByKey[key]
+			if len(paths) == 0 {
+				result.Problems = append(result.Problems, VerifyProblem{
+					Code:       VerifyProblemCacheMissingFile,
+					ReasonCode: contracts.ReasonCodeValidationFailed,
+					Key:        key,
+					Path:       cachePath,
+					Message:    fmt.Sprintf("cache entry points to %q but no issue file exists", entryPath),
+				})
+				continue
+			}
+			if len(paths) == 1 && filepath.Clean(entryPath) != paths[0] {
+				result.Problems = append(result.Problems, VerifyProblem{
+					Code:       VerifyProblemCachePathMismatch,
+					ReasonCode: contracts.ReasonCodeValidationFailed,
+					Key:        key,
+					Path:       cachePath,
+					Message:    fmt.Sprintf("cache entry points to %q but issue file is %q", entryPath, paths[0]),
+				})
+			}
+		}
+	}
+
+	originalsDir := filepath.Join(".sync", "originals")
+	snapshots, err := s.listMarkdownFiles(originalsDir)
+	if err != nil {
+		return VerifyResult{}, err
+	}
+	for _, name := range snapshots {
+		key := strings.TrimSuffix(name, filepath.Ext(name))
+		if len(pathsByKey[key]) > 0 {
+			continue
+		}
+		result.Problems = append(result.Problems, VerifyProblem{
+			Code:       VerifyProblemOrphanSnapshot,
+			ReasonCode: contracts.ReasonCodeValidationFailed,
+			Key:        key,
+			Path:       filepath.Join(originalsDir, name),
+			Message:    "original snapshot has no matching issue file",
+		})
+	}
+
+	sort.SliceStable(result.Problems, func(i, j int) bool {
+		left, right := result.Problems[i], result.Problems[j]
+		if left.Key != right.Key {
+			return left.Key < right.Key
+		}
+		if left.Path != right.Path {
+			return left.Path < right.Path
+		}
+		return left.Code < right.Code
+	})
+
+	return result, nil
+}
+
+func (s *Store) listMarkdownFiles(dir string) ([]string, error) {
+	entries, err := s.fs.ReadDir(dir)
+	if err != nil {
+		if errorsIsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !IsIssueFile(dir, entry.Name()) {
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	return names, nil
+}
@@ -20,6 +20,9 @@ type Runner func(context.Context) (output.Report, error)
 type Plan struct {
 	Push Runner
 	Pull Runner
+	// StopOnPushConflict skips the pull stage when push reported any
+	// conflict, so the user can reconcile before remote changes land.
+	StopOnPushConflict bool
 }
 
 func Execute(ctx context.Context, plan Plan) (output.Report, error) {
@@ -34,6 +37,9 @@ func Execute(ctx context.Context, plan Plan) (output.Report, error) {
 	if err != nil {
 		return report, err
 	}
+	if plan.StopOnPushConflict && report.Counts.Conflicts > 0 {
+		return report, nil
+	}
 
 	pullReport, err := runStage(ctx, StagePull, plan.Pull)
 	report = MergeReports(report, pullReport)
@@ -46,6 +52,12 @@ func Execute(ctx context.Context, plan Plan) (output.Report, error) {
 
 func runStage(ctx context.Context, stage Stage, runner Runner) (output.Report, error) {
 	report, err := runner(ctx)
+	for index := range report.Timings {
+		report.Timings[index].Phase = string(stage) + "." + report.Timings[index].Phase
+	}
+	for index := range report.Issues {
+		report.Issues[index].Phase = string(stage)
+	}
 	if err != nil {
 		return report, fmt.Errorf("failed to execute %s stage: %w", stage, err)
 	}
@@ -57,6 +69,9 @@ func MergeReports(left output.Report, right output.Report) output.Report {
 		Counts: mergeCounts(left.Counts, right.Counts),
 		Issues: append(append(make([]contracts.PerIssueResult, 0, len(left.Issues)+len(right.Issues)), left.Issues...), right.Issues...),
 	}
+	if len(left.Timings)+len(right.Timings) > 0 {
+		merged.Timings = append(append(make([]contracts.PhaseTiming, 0, len(left.Timings)+len(right.Timings)), left.Timings...), right.Timings...)
+	}
 	return merged
 }
 
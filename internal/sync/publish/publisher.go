@@ -2,17 +2,21 @@ package publish
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
 	"github.com/pweiskircher/jira-issue-sync/internal/converter"
 	"github.com/pweiskircher/jira-issue-sync/internal/issue"
 	"github.com/pweiskircher/jira-issue-sync/internal/jira"
+	"github.com/pweiskircher/jira-issue-sync/internal/jql"
 	"github.com/pweiskircher/jira-issue-sync/internal/store"
 )
 
@@ -21,6 +25,17 @@ type Options struct {
 	Store      *store.Store
 	Converter  converter.Adapter
 	ProjectKey string
+	// AssigneeIsAccountID is the profile's assignee_is_account_id setting;
+	// see jira.ResolveAssignee.
+	AssigneeIsAccountID bool
+	// DraftKeyPrefix is the workspace's resolved draft_key_prefix, used to
+	// describe the expected key format in errors.
+	DraftKeyPrefix string
+	// PreserveLabelCase sends draft labels with their case intact instead
+	// of lowercased.
+	PreserveLabelCase bool
+	// WriteSecurityLevel creates the issue with the draft's security level.
+	WriteSecurityLevel bool
 }
 
 type Input struct {
@@ -46,8 +61,12 @@ func PublishDraft(ctx context.Context, options Options, input Input) (Result, er
 	}
 
 	localKey := strings.TrimSpace(input.LocalKey)
-	if !contracts.LocalDraftKeyPattern.MatchString(localKey) {
-		return Result{}, fmt.Errorf("draft publish requires local key in L-<hex> format")
+	if !contracts.IsLocalDraftKey(localKey) {
+		prefix := strings.TrimSpace(options.DraftKeyPrefix)
+		if prefix == "" {
+			prefix = contracts.DefaultDraftKeyPrefix
+		}
+		return Result{}, fmt.Errorf("draft publish requires local key in %s-<hex> format, got %q", prefix, localKey)
 	}
 
 	projectKey := strings.TrimSpace(options.ProjectKey)
@@ -62,22 +81,73 @@ func PublishDraft(ctx context.Context, options Options, input Input) (Result, er
 
 	created := false
 	if remoteKey == "" {
-		createRequest, requestErr := buildCreateIssueRequest(projectKey, input.Document, options.Converter)
-		if requestErr != nil {
-			return Result{}, requestErr
+		// Jira has no idempotent create, so a create whose response was
+		// lost leaves only the pending marker behind. Its nonce names the
+		// marker label, letting a retry find that issue instead of creating
+		// a second one without adopting another workspace's draft.
+		nonce, err := loadPendingNonce(options.Store, localKey)
+		if err != nil {
+			return Result{}, err
 		}
-		createdIssue, createErr := options.Adapter.CreateIssue(ctx, createRequest)
-		if createErr != nil {
-			return Result{}, createErr
+		if nonce != "" {
+			remoteKey, err = findMarkedIssue(ctx, options.Adapter, projectKey, localKey, contracts.DraftMarkerLabel(localKey, nonce))
+			if err != nil {
+				return Result{}, err
+			}
 		}
-		remoteKey = strings.TrimSpace(createdIssue.Key)
-		if !contracts.JiraIssueKeyPattern.MatchString(remoteKey) {
-			return Result{}, fmt.Errorf("jira create issue response returned invalid key")
+		if remoteKey == "" {
+			createRequest, requestErr := buildCreateIssueRequest(projectKey, input.Document, options.Converter, options.PreserveLabelCase)
+			if requestErr != nil {
+				return Result{}, requestErr
+			}
+			createRequest.AssigneeAccountID, requestErr = jira.ResolveAssignee(ctx, options.Adapter, projectKey, createRequest.AssigneeAccountID, options.AssigneeIsAccountID)
+			if requestErr != nil {
+				return Result{}, requestErr
+			}
+			levelName := strings.TrimSpace(input.Document.FrontMatter.SecurityLevel)
+			if options.WriteSecurityLevel && levelName != "" {
+				level, levelErr := resolveSecurityLevel(ctx, options.Adapter, options.Store, projectKey, levelName)
+				if levelErr != nil {
+					return Result{}, levelErr
+				}
+				createRequest.SecurityLevel = &level
+			}
+			if nonce == "" {
+				if nonce, err = writePendingMarker(options.Store, localKey); err != nil {
+					return Result{}, err
+				}
+			}
+			createRequest.Labels = append(createRequest.Labels, contracts.DraftMarkerLabel(localKey, nonce))
+			createdIssue, createErr := options.Adapter.CreateIssue(ctx, createRequest)
+			if createErr != nil {
+				if level := createRequest.SecurityLevel; level != nil && level.ID == "" {
+					return Result{}, fmt.Errorf("security level %q is not listed for project %s: %w", level.Name, projectKey, createErr)
+				}
+				return Result{}, createErr
+			}
+			remoteKey = strings.TrimSpace(createdIssue.Key)
+			if !contracts.JiraIssueKeyPattern.MatchString(remoteKey) {
+				return Result{}, fmt.Errorf("jira create issue response returned invalid key")
+			}
+			created = true
+		}
+
+		// Record the remote key before anything else can fail so a rerun
+		// after a crash resumes this publish instead of creating again.
+		if err := options.Store.WriteFile(MarkerPath(localKey), []byte(remoteKey+"\n")); err != nil {
+			return Result{}, fmt.Errorf("issue %s was created but the publish marker could not be written: %w", remoteKey, err)
 		}
-		created = true
 	}
 
-	published, canonical, err := renderPublishedDocument(input.Document, localKey, remoteKey)
+	// The marker label has served its purpose once the key is recorded
+	// locally. Resetting labels also runs on resumed publishes, so a crash
+	// between create and this point cannot leave the label behind.
+	labels := contracts.NormalizeLabelsWithCase(input.Document.FrontMatter.Labels, options.PreserveLabelCase)
+	if err := options.Adapter.UpdateIssue(ctx, remoteKey, jira.UpdateIssueRequest{Labels: &labels}); err != nil {
+		return Result{}, fmt.Errorf("issue %s was created but its draft marker label could not be removed: %w", remoteKey, err)
+	}
+
+	published, canonical, err := renderPublishedDocument(options.Store, input.Document, localKey, remoteKey)
 	if err != nil {
 		return Result{}, err
 	}
@@ -89,7 +159,7 @@ func PublishDraft(ctx context.Context, options Options, input Input) (Result, er
 		return Result{}, err
 	}
 
-	targetFilename, err := issue.BuildFilename(remoteKey, published.FrontMatter.Summary)
+	targetFilename, err := options.Store.IssueFilename(remoteKey, published.FrontMatter.Summary)
 	if err != nil {
 		return Result{}, err
 	}
@@ -107,16 +177,81 @@ func PublishDraft(ctx context.Context, options Options, input Input) (Result, er
 	if err := options.Store.Remove(localSnapshotPath(localKey)); err != nil {
 		return Result{}, err
 	}
+	if err := options.Store.Remove(MarkerPath(localKey)); err != nil {
+		return Result{}, err
+	}
 
 	return Result{RemoteKey: remoteKey, Created: created}, nil
 }
 
-func buildCreateIssueRequest(projectKey string, local issue.Document, markdownConverter converter.Adapter) (jira.CreateIssueRequest, error) {
+// resolveSecurityLevel looks name up in the cached level list for
+// projectKey, refreshing the cache from Jira on a miss. A name Jira does not
+// list comes back without an ID, so the create sends it by name and Jira's
+// own rejection reaches the user.
+func resolveSecurityLevel(ctx context.Context, adapter jira.Adapter, workspaceStore *store.Store, projectKey string, name string) (jira.NamedRef, error) {
+	cache, err := workspaceStore.LoadSecurityLevelsCache()
+	if err != nil {
+		return jira.NamedRef{}, fmt.Errorf("failed to read security level cache: %w", err)
+	}
+	if id := lookupSecurityLevel(cache.Projects[projectKey], name); id != "" {
+		return jira.NamedRef{ID: id, Name: name}, nil
+	}
+
+	levels, err := adapter.ListSecurityLevels(ctx, projectKey)
+	if err != nil {
+		return jira.NamedRef{}, fmt.Errorf("failed to list security levels for project %s: %w", projectKey, err)
+	}
+	entries := make([]store.SecurityLevelsCacheEntry, 0, len(levels))
+	for _, level := range levels {
+		entries = append(entries, store.SecurityLevelsCacheEntry{ID: level.ID, Name: level.Name})
+	}
+	if cache.Projects == nil {
+		cache.Projects = make(map[string][]store.SecurityLevelsCacheEntry)
+	}
+	cache.Projects[projectKey] = entries
+	if err := workspaceStore.SaveSecurityLevelsCache(cache); err != nil {
+		return jira.NamedRef{}, fmt.Errorf("failed to write security level cache: %w", err)
+	}
+	return jira.NamedRef{ID: lookupSecurityLevel(entries, name), Name: name}, nil
+}
+
+func lookupSecurityLevel(levels []store.SecurityLevelsCacheEntry, name string) string {
+	for _, level := range levels {
+		if strings.EqualFold(strings.TrimSpace(level.Name), name) {
+			return strings.TrimSpace(level.ID)
+		}
+	}
+	return ""
+}
+
+// findMarkedIssue returns the key of an issue in projectKey that carries the
+// draft marker label, or "" if none exists yet.
+func findMarkedIssue(ctx context.Context, adapter jira.Adapter, projectKey string, localKey string, label string) (string, error) {
+	response, err := adapter.SearchIssues(ctx, jira.SearchIssuesRequest{
+		JQL:        jql.New().Equals("project", projectKey).Equals("labels", label).OrderBy("key", jql.Ascending).String(),
+		MaxResults: 1,
+		Fields:     []string{"labels"},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to check for an issue already created from draft %s: %w", localKey, err)
+	}
+	for _, candidate := range response.Issues {
+		if !slices.Contains(candidate.Fields.Labels, label) {
+			continue
+		}
+		if key := strings.TrimSpace(candidate.Key); contracts.JiraIssueKeyPattern.MatchString(key) {
+			return key, nil
+		}
+	}
+	return "", nil
+}
+
+func buildCreateIssueRequest(projectKey string, local issue.Document, markdownConverter converter.Adapter, preserveLabelCase bool) (jira.CreateIssueRequest, error) {
 	request := jira.CreateIssueRequest{
 		ProjectKey:        projectKey,
 		IssueTypeName:     strings.TrimSpace(local.FrontMatter.IssueType),
 		Summary:           strings.TrimSpace(local.FrontMatter.Summary),
-		Labels:            append([]string(nil), local.FrontMatter.Labels...),
+		Labels:            contracts.NormalizeLabelsWithCase(local.FrontMatter.Labels, preserveLabelCase),
 		AssigneeAccountID: strings.TrimSpace(local.FrontMatter.Assignee),
 		PriorityName:      strings.TrimSpace(local.FrontMatter.Priority),
 	}
@@ -138,13 +273,13 @@ func buildCreateIssueRequest(projectKey string, local issue.Document, markdownCo
 	return request, nil
 }
 
-func renderPublishedDocument(local issue.Document, localKey string, remoteKey string) (issue.Document, string, error) {
+func renderPublishedDocument(workspaceStore *store.Store, local issue.Document, localKey string, remoteKey string) (issue.Document, string, error) {
 	rewritten := local
 	rewritten.CanonicalKey = remoteKey
 	rewritten.FrontMatter.Key = remoteKey
 	rewritten.MarkdownBody = contracts.RewriteTempIDReferences(local.MarkdownBody, map[string]string{localKey: remoteKey})
 
-	canonical, err := issue.RenderDocument(rewritten)
+	canonical, err := workspaceStore.RenderDocument(rewritten)
 	if err != nil {
 		return issue.Document{}, "", err
 	}
@@ -152,6 +287,15 @@ func renderPublishedDocument(local issue.Document, localKey string, remoteKey st
 }
 
 func loadPublishedKeyMarker(workspaceStore *store.Store, localKey string) (string, error) {
+	marker, err := workspaceStore.ReadFile(MarkerPath(localKey))
+	if err == nil {
+		if markerKey := strings.TrimSpace(string(marker)); contracts.JiraIssueKeyPattern.MatchString(markerKey) {
+			return markerKey, nil
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+
 	content, err := workspaceStore.ReadFile(localSnapshotPath(localKey))
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
@@ -177,6 +321,48 @@ func loadPublishedKeyMarker(workspaceStore *store.Store, localKey string) (strin
 	return markerKey, nil
 }
 
+// loadPendingNonce returns the marker label nonce recorded before an earlier
+// create of localKey, or "" if no create has been attempted.
+func loadPendingNonce(workspaceStore *store.Store, localKey string) (string, error) {
+	marker, err := workspaceStore.ReadFile(MarkerPath(localKey))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+		return "", err
+	}
+	nonce, ok := strings.CutPrefix(strings.TrimSpace(string(marker)), pendingMarkerPrefix)
+	if !ok {
+		return "", nil
+	}
+	return strings.TrimSpace(nonce), nil
+}
+
+// writePendingMarker draws a fresh marker label nonce for localKey and
+// records it before the create it labels.
+func writePendingMarker(workspaceStore *store.Store, localKey string) (string, error) {
+	random := make([]byte, 4)
+	if _, err := rand.Read(random); err != nil {
+		return "", fmt.Errorf("failed to generate draft marker nonce: %w", err)
+	}
+	nonce := hex.EncodeToString(random)
+	if err := workspaceStore.WriteFile(MarkerPath(localKey), []byte(pendingMarkerPrefix+nonce+"\n")); err != nil {
+		return "", fmt.Errorf("failed to write publish marker for %s: %w", localKey, err)
+	}
+	return nonce, nil
+}
+
+// pendingMarkerPrefix starts a publish marker written before CreateIssue;
+// the rest of the line is the marker label nonce.
+const pendingMarkerPrefix = "pending "
+
+// MarkerPath is the store-relative publish marker for a draft. It holds the
+// marker label nonce until CreateIssue returns, then the remote key until
+// the end of a publish; it is removed once the draft is fully replaced.
+func MarkerPath(localKey string) string {
+	return filepath.Join(".sync", "publish", localKey)
+}
+
 func localSnapshotPath(localKey string) string {
 	return filepath.Join(".sync", "originals", localKey+".md")
 }
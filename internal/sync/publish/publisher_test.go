@@ -0,0 +1,423 @@
+package publish
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
+	"github.com/pweiskircher/jira-issue-sync/internal/issue"
+	"github.com/pweiskircher/jira-issue-sync/internal/jira"
+	"github.com/pweiskircher/jira-issue-sync/internal/store"
+	pullsync "github.com/pweiskircher/jira-issue-sync/internal/sync/pull"
+)
+
+// createCountingAdapter plays a Jira that keeps the one issue it creates, so
+// label probes see it even when the create response was lost.
+type createCountingAdapter struct {
+	creates        int
+	createErr      error
+	created        *jira.Issue
+	createRequests []jira.CreateIssueRequest
+	updateRequests []jira.UpdateIssueRequest
+	securityLevels []jira.NamedRef
+	levelListings  int
+}
+
+func (a *createCountingAdapter) CreateIssue(_ context.Context, request jira.CreateIssueRequest) (jira.CreatedIssue, error) {
+	a.creates++
+	a.createRequests = append(a.createRequests, request)
+	a.created = &jira.Issue{Key: "PROJ-42", Fields: jira.IssueFields{Labels: append([]string(nil), request.Labels...)}}
+	if a.createErr != nil {
+		return jira.CreatedIssue{}, a.createErr
+	}
+	return jira.CreatedIssue{Key: "PROJ-42"}, nil
+}
+
+func (a *createCountingAdapter) SearchIssues(_ context.Context, request jira.SearchIssuesRequest) (jira.SearchIssuesResponse, error) {
+	if a.created == nil {
+		return jira.SearchIssuesResponse{}, nil
+	}
+	for _, label := range a.created.Fields.Labels {
+		if strings.Contains(request.JQL, `labels = "`+label+`"`) {
+			return jira.SearchIssuesResponse{Total: 1, Issues: []jira.Issue{*a.created}}, nil
+		}
+	}
+	return jira.SearchIssuesResponse{}, nil
+}
+func (a *createCountingAdapter) ValidateJQL(context.Context, string) error {
+	panic("unexpected call")
+}
+func (a *createCountingAdapter) ListFields(context.Context) ([]jira.FieldDefinition, error) {
+	panic("unexpected call")
+}
+func (a *createCountingAdapter) ListProjects(context.Context) ([]jira.Project, error) {
+	panic("unexpected call")
+}
+func (a *createCountingAdapter) ListStatuses(context.Context) ([]jira.StatusRef, error) {
+	panic("unexpected call")
+}
+func (a *createCountingAdapter) ListSecurityLevels(context.Context, string) ([]jira.NamedRef, error) {
+	a.levelListings++
+	return a.securityLevels, nil
+}
+func (a *createCountingAdapter) GetMyself(context.Context) (jira.AccountRef, error) {
+	panic("unexpected call")
+}
+func (a *createCountingAdapter) GetIssue(context.Context, string, []string) (jira.Issue, error) {
+	panic("unexpected call")
+}
+func (a *createCountingAdapter) UpdateIssue(_ context.Context, _ string, request jira.UpdateIssueRequest) error {
+	a.updateRequests = append(a.updateRequests, request)
+	if request.Labels != nil && a.created != nil {
+		a.created.Fields.Labels = append([]string(nil), (*request.Labels)...)
+	}
+	return nil
+}
+func (a *createCountingAdapter) ListTransitions(context.Context, string) ([]jira.Transition, error) {
+	panic("unexpected call")
+}
+func (a *createCountingAdapter) ApplyTransition(context.Context, string, string) error {
+	panic("unexpected call")
+}
+func (a *createCountingAdapter) ResolveTransition(context.Context, string, contracts.TransitionSelection) (jira.TransitionResolution, error) {
+	panic("unexpected call")
+}
+func (a *createCountingAdapter) SearchAssignableUsers(context.Context, string, string) ([]jira.AccountRef, error) {
+	panic("unexpected call")
+}
+
+func TestPublishDraftResumesFromMarkerAfterCrashPostCreate(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	workspaceStore, err := store.New(root)
+	if err != nil {
+		t.Fatalf("store init failed: %v", err)
+	}
+
+	const localKey = "L-abcd1234"
+	draft := issue.Document{
+		CanonicalKey: localKey,
+		FrontMatter: issue.FrontMatter{
+			SchemaVersion: contracts.IssueFileSchemaVersionV1,
+			Key:           localKey,
+			Summary:       "Crash safe",
+			IssueType:     "Task",
+			Status:        "Open",
+		},
+		MarkdownBody: "body",
+	}
+	rendered, err := issue.RenderDocument(draft)
+	if err != nil {
+		t.Fatalf("render draft failed: %v", err)
+	}
+	relativePath := filepath.Join("open", localKey+"-crash-safe.md")
+	if err := workspaceStore.WriteFile(relativePath, []byte(rendered)); err != nil {
+		t.Fatalf("write draft failed: %v", err)
+	}
+
+	// Block the remote snapshot write so the run "crashes" after the remote
+	// issue exists and the marker has been recorded.
+	blocker := filepath.Join(root, ".sync", "originals", "PROJ-42.md")
+	if err := os.MkdirAll(filepath.Join(blocker, "nested"), 0o755); err != nil {
+		t.Fatalf("create blocker failed: %v", err)
+	}
+
+	adapter := &createCountingAdapter{}
+	options := Options{Adapter: adapter, Store: workspaceStore, Converter: pullsync.NewADFMarkdownConverter(pullsync.ConverterOptions{}), ProjectKey: "PROJ"}
+	input := Input{LocalKey: localKey, RelativePath: relativePath, Document: draft}
+
+	if _, err := PublishDraft(context.Background(), options, input); err == nil {
+		t.Fatalf("expected the simulated crash to fail the first publish")
+	}
+	if adapter.creates != 1 {
+		t.Fatalf("expected one create before the crash, got %d", adapter.creates)
+	}
+
+	if err := os.RemoveAll(blocker); err != nil {
+		t.Fatalf("remove blocker failed: %v", err)
+	}
+
+	result, err := PublishDraft(context.Background(), options, input)
+	if err != nil {
+		t.Fatalf("rerun publish failed: %v", err)
+	}
+	if adapter.creates != 1 {
+		t.Fatalf("expected rerun to reuse the created issue, got %d creates", adapter.creates)
+	}
+	if result.RemoteKey != "PROJ-42" || result.Created {
+		t.Fatalf("unexpected rerun result: %#v", result)
+	}
+	if _, err := os.Stat(filepath.Join(root, ".sync", "publish", localKey)); !os.IsNotExist(err) {
+		t.Fatalf("expected publish marker to be removed after success, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "open", "PROJ-42-crash-safe.md")); err != nil {
+		t.Fatalf("expected published issue file, got %v", err)
+	}
+}
+
+func TestPublishDraftRetryFindsIssueCreatedBeforeLostResponse(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	workspaceStore, err := store.New(root)
+	if err != nil {
+		t.Fatalf("store init failed: %v", err)
+	}
+
+	const localKey = "L-feed0001"
+	draft := issue.Document{
+		CanonicalKey: localKey,
+		FrontMatter: issue.FrontMatter{
+			SchemaVersion: contracts.IssueFileSchemaVersionV1,
+			Key:           localKey,
+			Summary:       "Lost response",
+			IssueType:     "Task",
+			Status:        "Open",
+			Labels:        []string{"backend"},
+		},
+		MarkdownBody: "body",
+	}
+	rendered, err := issue.RenderDocument(draft)
+	if err != nil {
+		t.Fatalf("render draft failed: %v", err)
+	}
+	relativePath := filepath.Join("open", localKey+"-lost-response.md")
+	if err := workspaceStore.WriteFile(relativePath, []byte(rendered)); err != nil {
+		t.Fatalf("write draft failed: %v", err)
+	}
+
+	// Jira creates the issue but the response never arrives, so no publish
+	// marker is written locally.
+	adapter := &createCountingAdapter{createErr: errors.New("connection reset")}
+	options := Options{Adapter: adapter, Store: workspaceStore, Converter: pullsync.NewADFMarkdownConverter(pullsync.ConverterOptions{}), ProjectKey: "PROJ"}
+	input := Input{LocalKey: localKey, RelativePath: relativePath, Document: draft}
+
+	if _, err := PublishDraft(context.Background(), options, input); err == nil {
+		t.Fatalf("expected the lost create response to fail the first publish")
+	}
+	nonce, err := loadPendingNonce(workspaceStore, localKey)
+	if err != nil || nonce == "" {
+		t.Fatalf("expected the pending marker to record a nonce, got %q, %v", nonce, err)
+	}
+	if got := adapter.created.Fields.Labels; len(got) != 2 || got[1] != contracts.DraftMarkerLabel(localKey, nonce) {
+		t.Fatalf("expected create to carry the draft marker label, got %v", got)
+	}
+
+	adapter.createErr = nil
+	result, err := PublishDraft(context.Background(), options, input)
+	if err != nil {
+		t.Fatalf("retried publish failed: %v", err)
+	}
+	if adapter.creates != 1 {
+		t.Fatalf("expected the retry to find the marked issue, got %d creates", adapter.creates)
+	}
+	if result.RemoteKey != "PROJ-42" || result.Created {
+		t.Fatalf("unexpected retry result: %#v", result)
+	}
+	if got := adapter.created.Fields.Labels; len(got) != 1 || got[0] != "backend" {
+		t.Fatalf("expected the marker label to be removed, got %v", got)
+	}
+	if _, err := os.Stat(filepath.Join(root, "open", "PROJ-42-lost-response.md")); err != nil {
+		t.Fatalf("expected published issue file, got %v", err)
+	}
+}
+
+func TestPublishDraftDoesNotAdoptIssueMarkedForSameKeyElsewhere(t *testing.T) {
+	t.Parallel()
+
+	const localKey = "L-1"
+	draft := issue.Document{
+		CanonicalKey: localKey,
+		FrontMatter: issue.FrontMatter{
+			SchemaVersion: contracts.IssueFileSchemaVersionV1,
+			Key:           localKey,
+			Summary:       "Renumbered",
+			IssueType:     "Task",
+			Status:        "Open",
+		},
+		MarkdownBody: "body",
+	}
+	rendered, err := issue.RenderDocument(draft)
+	if err != nil {
+		t.Fatalf("render draft failed: %v", err)
+	}
+	relativePath := filepath.Join("open", localKey+"-renumbered.md")
+	input := Input{LocalKey: localKey, RelativePath: relativePath, Document: draft}
+
+	// A teammate's renumbered L-1 reached Jira but lost its create
+	// response, so its marker label is still on the issue.
+	adapter := &createCountingAdapter{createErr: errors.New("connection reset")}
+	teammate, err := store.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("store init failed: %v", err)
+	}
+	if err := teammate.WriteFile(relativePath, []byte(rendered)); err != nil {
+		t.Fatalf("write draft failed: %v", err)
+	}
+	if _, err := PublishDraft(context.Background(), Options{Adapter: adapter, Store: teammate, Converter: pullsync.NewADFMarkdownConverter(pullsync.ConverterOptions{}), ProjectKey: "PROJ"}, input); err == nil {
+		t.Fatalf("expected the lost create response to fail the teammate's publish")
+	}
+	teammateLabels := append([]string(nil), adapter.created.Fields.Labels...)
+
+	// Our own L-1's first create never reaches Jira, but it leaves a
+	// pending marker, so the retry probes by label.
+	workspaceStore, err := store.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("store init failed: %v", err)
+	}
+	if err := workspaceStore.WriteFile(relativePath, []byte(rendered)); err != nil {
+		t.Fatalf("write draft failed: %v", err)
+	}
+	options := Options{Adapter: adapter, Store: workspaceStore, Converter: pullsync.NewADFMarkdownConverter(pullsync.ConverterOptions{}), ProjectKey: "PROJ"}
+	if _, err := PublishDraft(context.Background(), options, input); err == nil {
+		t.Fatalf("expected our first publish to fail")
+	}
+	adapter.created.Fields.Labels = teammateLabels
+
+	adapter.createErr = nil
+	result, err := PublishDraft(context.Background(), options, input)
+	if err != nil {
+		t.Fatalf("retried publish failed: %v", err)
+	}
+	if adapter.creates != 3 || !result.Created {
+		t.Fatalf("expected the retry to create its own issue instead of adopting the teammate's, got %d creates and %#v", adapter.creates, result)
+	}
+}
+
+func publishSecurityLevelDraft(t *testing.T, workspaceStore *store.Store, adapter *createCountingAdapter, level string, write bool) error {
+	t.Helper()
+
+	const localKey = "L-5ec0"
+	draft := issue.Document{
+		CanonicalKey: localKey,
+		FrontMatter: issue.FrontMatter{
+			SchemaVersion: contracts.IssueFileSchemaVersionV1,
+			Key:           localKey,
+			Summary:       "Restricted",
+			IssueType:     "Task",
+			Status:        "Open",
+			SecurityLevel: level,
+		},
+	}
+	rendered, err := issue.RenderDocument(draft)
+	if err != nil {
+		t.Fatalf("render draft failed: %v", err)
+	}
+	relativePath := filepath.Join("open", localKey+"-restricted.md")
+	if err := workspaceStore.WriteFile(relativePath, []byte(rendered)); err != nil {
+		t.Fatalf("write draft failed: %v", err)
+	}
+
+	_, err = PublishDraft(context.Background(), Options{
+		Adapter:            adapter,
+		Store:              workspaceStore,
+		Converter:          pullsync.NewADFMarkdownConverter(pullsync.ConverterOptions{}),
+		ProjectKey:         "PROJ",
+		WriteSecurityLevel: write,
+	}, Input{LocalKey: localKey, RelativePath: relativePath, Document: draft})
+	return err
+}
+
+func TestPublishDraftSendsSecurityLevelIDFromCachedLevels(t *testing.T) {
+	t.Parallel()
+
+	cachedStore, err := store.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("store init failed: %v", err)
+	}
+	if err := cachedStore.SaveSecurityLevelsCache(store.SecurityLevelsCache{Projects: map[string][]store.SecurityLevelsCacheEntry{
+		"PROJ": {{ID: "10001", Name: "Internal"}},
+	}}); err != nil {
+		t.Fatalf("seed cache failed: %v", err)
+	}
+	cached := &createCountingAdapter{}
+	if err := publishSecurityLevelDraft(t, cachedStore, cached, "internal", true); err != nil {
+		t.Fatalf("publish failed: %v", err)
+	}
+	if cached.levelListings != 0 {
+		t.Fatalf("expected a cache hit to skip listing levels, got %d listings", cached.levelListings)
+	}
+	if level := cached.createRequests[0].SecurityLevel; level == nil || level.ID != "10001" {
+		t.Fatalf("expected cached level ID in create request, got %#v", level)
+	}
+
+	refreshStore, err := store.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("store init failed: %v", err)
+	}
+	refreshed := &createCountingAdapter{securityLevels: []jira.NamedRef{{ID: "10002", Name: "Confidential"}}}
+	if err := publishSecurityLevelDraft(t, refreshStore, refreshed, "Confidential", true); err != nil {
+		t.Fatalf("publish failed: %v", err)
+	}
+	if refreshed.levelListings != 1 {
+		t.Fatalf("expected a cache miss to list levels once, got %d", refreshed.levelListings)
+	}
+	if level := refreshed.createRequests[0].SecurityLevel; level == nil || level.ID != "10002" {
+		t.Fatalf("expected listed level ID in create request, got %#v", level)
+	}
+	cache, err := refreshStore.LoadSecurityLevelsCache()
+	if err != nil || len(cache.Projects["PROJ"]) != 1 || cache.Projects["PROJ"][0].ID != "10002" {
+		t.Fatalf("expected listed levels to be cached, got %#v, %v", cache, err)
+	}
+
+	disabledStore, err := store.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("store init failed: %v", err)
+	}
+	disabled := &createCountingAdapter{}
+	if err := publishSecurityLevelDraft(t, disabledStore, disabled, "Internal", false); err != nil {
+		t.Fatalf("publish failed: %v", err)
+	}
+	if disabled.createRequests[0].SecurityLevel != nil || disabled.levelListings != 0 {
+		t.Fatalf("expected security level to stay read-only without write_security_level, got %#v", disabled.createRequests[0].SecurityLevel)
+	}
+}
+
+func TestPublishDraftSurfacesJiraErrorForUnknownSecurityLevel(t *testing.T) {
+	t.Parallel()
+
+	workspaceStore, err := store.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("store init failed: %v", err)
+	}
+	adapter := &createCountingAdapter{
+		securityLevels: []jira.NamedRef{{ID: "10001", Name: "Internal"}},
+		createErr:      errors.New("jira request failed with status 400: security: Security level: Secret is not valid"),
+	}
+
+	err = publishSecurityLevelDraft(t, workspaceStore, adapter, "Secret", true)
+	if err == nil {
+		t.Fatalf("expected publish to fail for an unknown security level")
+	}
+	if level := adapter.createRequests[0].SecurityLevel; level == nil || level.ID != "" || level.Name != "Secret" {
+		t.Fatalf("expected unknown level to be sent by name, got %#v", level)
+	}
+	if !strings.Contains(err.Error(), `security level "Secret" is not listed for project PROJ`) || !strings.Contains(err.Error(), "Security level: Secret is not valid") {
+		t.Fatalf("expected error to name the level and keep Jira's message, got %v", err)
+	}
+}
+
+func TestPublishDraftRejectsNonDraftKeyNamingConfiguredPrefix(t *testing.T) {
+	t.Parallel()
+
+	workspaceStore, err := store.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("store init failed: %v", err)
+	}
+
+	_, err = PublishDraft(context.Background(), Options{
+		Adapter:        &createCountingAdapter{},
+		Store:          workspaceStore,
+		Converter:      pullsync.NewADFMarkdownConverter(pullsync.ConverterOptions{}),
+		ProjectKey:     "PROJ",
+		DraftKeyPrefix: "draft",
+	}, Input{LocalKey: "PROJ-1"})
+	if err == nil || !strings.Contains(err.Error(), `local key in draft-<hex> format, got "PROJ-1"`) {
+		t.Fatalf("expected error naming the configured prefix, got %v", err)
+	}
+}
@@ -0,0 +1,47 @@
+package pull
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pweiskircher/jira-issue-sync/internal/converter"
+	"github.com/pweiskircher/jira-issue-sync/internal/jira"
+)
+
+// commentsNotice opens the rendered section so a reader of the file knows
+// edits below the marker go nowhere.
+const commentsNotice = "_Comments from Jira are read-only here; push ignores edits to this section._"
+
+// renderComments renders comments oldest first, each under a heading with
+// its author and creation time. Ties on the timestamp fall back to the
+// comment id so the output never depends on Jira's response order.
+func renderComments(markdownConverter converter.Adapter, comments []jira.Comment) (string, string, error) {
+	ordered := append([]jira.Comment(nil), comments...)
+	sort.SliceStable(ordered, func(i int, j int) bool {
+		if ordered[i].CreatedAt != ordered[j].CreatedAt {
+			return ordered[i].CreatedAt < ordered[j].CreatedAt
+		}
+		return ordered[i].ID < ordered[j].ID
+	})
+
+	sections := []string{"## Comments", commentsNotice}
+	for _, comment := range ordered {
+		body, errorCode, err := convertRemoteADF(markdownConverter, comment.Body)
+		if err != nil {
+			return "", errorCode, err
+		}
+		author := accountRefValue(comment.Author)
+		if author == "" {
+			author = "Unknown"
+		}
+		heading := "### " + author
+		if comment.CreatedAt != "" {
+			heading += " at " + comment.CreatedAt
+		}
+		sections = append(sections, heading)
+		if text := strings.TrimSpace(body.markdown); text != "" {
+			sections = append(sections, text)
+		}
+	}
+	return strings.Join(sections, "\n\n"), "", nil
+}
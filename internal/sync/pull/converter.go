@@ -9,11 +9,20 @@ import (
 	"github.com/pweiskircher/jira-issue-sync/internal/converter"
 )
 
+// ConverterOptions tunes the markdown produced from ADF.
+type ConverterOptions struct {
+	// Flavor is contracts.MarkdownFlavorCommonMark (the default) or
+	// contracts.MarkdownFlavorGFM.
+	Flavor string
+}
+
 // ADFMarkdownConverter provides a deterministic MVP ADF -> Markdown projection.
-type ADFMarkdownConverter struct{}
+type ADFMarkdownConverter struct {
+	gfm bool
+}
 
-func NewADFMarkdownConverter() ADFMarkdownConverter {
-	return ADFMarkdownConverter{}
+func NewADFMarkdownConverter(options ConverterOptions) ADFMarkdownConverter {
+	return ADFMarkdownConverter{gfm: strings.TrimSpace(options.Flavor) == contracts.MarkdownFlavorGFM}
 }
 
 func (c ADFMarkdownConverter) ToMarkdown(adfJSON string) (converter.MarkdownResult, error) {
@@ -22,7 +31,7 @@ func (c ADFMarkdownConverter) ToMarkdown(adfJSON string) (converter.MarkdownResu
 		return converter.MarkdownResult{}, nil
 	}
 
-	rawDoc, err := converter.ValidateAndCanonicalizeRawADF(trimmed)
+	rawDoc, risks, err := converter.ValidateRawADFWithSeverity(trimmed)
 	if err != nil {
 		return converter.MarkdownResult{}, err
 	}
@@ -41,14 +50,14 @@ func (c ADFMarkdownConverter) ToMarkdown(adfJSON string) (converter.MarkdownResu
 
 	lines := make([]string, 0, len(envelope.Content))
 	for _, node := range envelope.Content {
-		line := strings.TrimSpace(renderNode(node))
+		line := strings.TrimSpace(c.renderNode(node))
 		if line == "" {
 			continue
 		}
 		lines = append(lines, line)
 	}
 
-	return converter.MarkdownResult{Markdown: strings.Join(lines, "\n\n")}, nil
+	return converter.MarkdownResult{Markdown: strings.Join(lines, "\n\n"), Risks: risks}, nil
 }
 
 func (c ADFMarkdownConverter) ToADF(markdown string) (converter.ADFResult, error) {
@@ -60,13 +69,7 @@ func (c ADFMarkdownConverter) ToADF(markdown string) (converter.ADFResult, error
 	payload := map[string]any{
 		"version": 1,
 		"type":    "doc",
-		"content": []map[string]any{{
-			"type": "paragraph",
-			"content": []map[string]any{{
-				"type": "text",
-				"text": trimmed,
-			}},
-		}},
+		"content": markdownToADFBlocks(trimmed),
 	}
 
 	encoded, err := json.Marshal(payload)
@@ -76,53 +79,163 @@ func (c ADFMarkdownConverter) ToADF(markdown string) (converter.ADFResult, error
 	return converter.ADFResult{ADFJSON: string(encoded)}, nil
 }
 
-func renderNode(raw json.RawMessage) string {
+func (c ADFMarkdownConverter) renderNode(raw json.RawMessage) string {
 	var node map[string]any
 	if err := json.Unmarshal(raw, &node); err != nil {
 		return ""
 	}
 
 	nodeType, _ := node["type"].(string)
+	if c.gfm {
+		if rendered, ok := c.renderGFMNode(nodeType, node); ok {
+			return rendered
+		}
+	}
 	switch nodeType {
 	case "text":
 		text, _ := node["text"].(string)
 		return text
 	case "hardBreak":
-		return "\n"
-	case "bulletList":
-		children := renderChildren(node)
-		if len(children) == 0 {
-			return ""
-		}
-		lines := make([]string, 0, len(children))
-		for _, child := range children {
-			child = strings.TrimSpace(child)
-			if child == "" {
+		// A trailing backslash survives editors that strip trailing spaces.
+		return "\\\n"
+	case "bulletList", "orderedList":
+		return c.renderList(node, nodeType == "orderedList")
+	default:
+		return strings.TrimSpace(strings.Join(c.renderChildren(node), ""))
+	}
+}
+
+// renderList writes one item per marker line. An item's later lines, hard
+// breaks and nested lists included, are indented to its content column so
+// they stay inside the item.
+func (c ADFMarkdownConverter) renderList(node map[string]any, ordered bool) string {
+	number := 1
+	if attrs, ok := node["attrs"].(map[string]any); ok {
+		if order, ok := attrs["order"].(float64); ok && order >= 0 {
+			number = int(order)
+		}
+	}
+
+	lines := make([]string, 0)
+	for _, item := range childNodes(node) {
+		blocks := make([]string, 0)
+		for _, child := range childNodes(item) {
+			encoded, err := json.Marshal(child)
+			if err != nil {
 				continue
 			}
-			lines = append(lines, "- "+child)
-		}
-		return strings.Join(lines, "\n")
-	case "orderedList":
-		children := renderChildren(node)
-		if len(children) == 0 {
-			return ""
-		}
-		lines := make([]string, 0, len(children))
-		for index, child := range children {
-			child = strings.TrimSpace(child)
-			if child == "" {
+			if block := strings.TrimSpace(c.renderNode(encoded)); block != "" {
+				blocks = append(blocks, block)
+			}
+		}
+		if len(blocks) == 0 {
+			continue
+		}
+
+		marker := "- "
+		if ordered {
+			marker = fmt.Sprintf("%d. ", number)
+			number++
+		}
+		indent := strings.Repeat(" ", len(marker))
+		for index, line := range strings.Split(strings.Join(blocks, "\n"), "\n") {
+			switch {
+			case index == 0:
+				lines = append(lines, marker+line)
+			case line == "":
+				lines = append(lines, "")
+			default:
+				lines = append(lines, indent+line)
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderGFMNode handles the nodes GFM has syntax for: struck-through text,
+// task lists, and tables. Other nodes fall through to the shared rendering.
+func (c ADFMarkdownConverter) renderGFMNode(nodeType string, node map[string]any) (string, bool) {
+	switch nodeType {
+	case "text":
+		text, _ := node["text"].(string)
+		if text != "" && hasMark(node, "strike") {
+			return "~~" + text + "~~", true
+		}
+		return "", false
+	case "taskList":
+		lines := make([]string, 0)
+		for _, child := range childNodes(node) {
+			childType, _ := child["type"].(string)
+			if childType != "taskItem" {
 				continue
 			}
-			lines = append(lines, fmt.Sprintf("%d. %s", index+1, child))
+			text := strings.TrimSpace(strings.Join(c.renderChildren(child), ""))
+			box := "[ ]"
+			if attrs, ok := child["attrs"].(map[string]any); ok {
+				if state, _ := attrs["state"].(string); strings.EqualFold(state, "DONE") {
+					box = "[x]"
+				}
+			}
+			lines = append(lines, strings.TrimSpace("- "+box+" "+text))
+		}
+		return strings.Join(lines, "\n"), true
+	case "table":
+		rows := make([][]string, 0)
+		columns := 0
+		for _, row := range childNodes(node) {
+			cells := make([]string, 0)
+			for _, cell := range childNodes(row) {
+				text := strings.TrimSpace(strings.Join(c.renderChildren(cell), " "))
+				cells = append(cells, strings.ReplaceAll(text, "|", `\|`))
+			}
+			if len(cells) > columns {
+				columns = len(cells)
+			}
+			rows = append(rows, cells)
+		}
+		if len(rows) == 0 || columns == 0 {
+			return "", true
+		}
+
+		lines := make([]string, 0, len(rows)+1)
+		for index, cells := range rows {
+			for len(cells) < columns {
+				cells = append(cells, "")
+			}
+			lines = append(lines, "| "+strings.Join(cells, " | ")+" |")
+			if index == 0 {
+				lines = append(lines, "|"+strings.Repeat(" --- |", columns))
+			}
 		}
-		return strings.Join(lines, "\n")
+		return strings.Join(lines, "\n"), true
 	default:
-		return strings.TrimSpace(strings.Join(renderChildren(node), ""))
+		return "", false
+	}
+}
+
+func hasMark(node map[string]any, markType string) bool {
+	marks, _ := node["marks"].([]any)
+	for _, rawMark := range marks {
+		mark, _ := rawMark.(map[string]any)
+		if value, _ := mark["type"].(string); value == markType {
+			return true
+		}
+	}
+	return false
+}
+
+func childNodes(node map[string]any) []map[string]any {
+	rawChildren, _ := node["content"].([]any)
+	children := make([]map[string]any, 0, len(rawChildren))
+	for _, rawChild := range rawChildren {
+		if child, ok := rawChild.(map[string]any); ok {
+			children = append(children, child)
+		}
 	}
+	return children
 }
 
-func renderChildren(node map[string]any) []string {
+func (c ADFMarkdownConverter) renderChildren(node map[string]any) []string {
 	rawChildren, ok := node["content"].([]any)
 	if !ok || len(rawChildren) == 0 {
 		return nil
@@ -134,7 +247,7 @@ func renderChildren(node map[string]any) []string {
 		if err != nil {
 			continue
 		}
-		value := renderNode(encoded)
+		value := c.renderNode(encoded)
 		if value == "" {
 			continue
 		}
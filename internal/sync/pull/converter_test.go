@@ -0,0 +1,119 @@
+package pull
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
+)
+
+func TestADFMarkdownConverterRendersStrikethroughPerFlavor(t *testing.T) {
+	t.Parallel()
+
+	adf := `{"version":1,"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"keep "},{"type":"text","text":"drop","marks":[{"type":"strike"}]}]}]}`
+
+	cases := map[string]string{
+		"":                                 "keep drop",
+		contracts.MarkdownFlavorCommonMark: "keep drop",
+		contracts.MarkdownFlavorGFM:        "keep ~~drop~~",
+	}
+	for flavor, want := range cases {
+		result, err := NewADFMarkdownConverter(ConverterOptions{Flavor: flavor}).ToMarkdown(adf)
+		if err != nil {
+			t.Fatalf("flavor %q: convert failed: %v", flavor, err)
+		}
+		if result.Markdown != want {
+			t.Fatalf("flavor %q: expected %q, got %q", flavor, want, result.Markdown)
+		}
+	}
+}
+
+func TestADFMarkdownConverterRendersGFMTaskListsAndTables(t *testing.T) {
+	t.Parallel()
+
+	adf := `{"version":1,"type":"doc","content":[` +
+		`{"type":"taskList","attrs":{"localId":"l1"},"content":[` +
+		`{"type":"taskItem","attrs":{"localId":"t1","state":"DONE"},"content":[{"type":"text","text":"ship it"}]},` +
+		`{"type":"taskItem","attrs":{"localId":"t2","state":"TODO"},"content":[{"type":"text","text":"announce"}]}]},` +
+		`{"type":"table","content":[` +
+		`{"type":"tableRow","content":[{"type":"tableHeader","content":[{"type":"paragraph","content":[{"type":"text","text":"Name"}]}]},{"type":"tableHeader","content":[{"type":"paragraph","content":[{"type":"text","text":"Value"}]}]}]},` +
+		`{"type":"tableRow","content":[{"type":"tableCell","content":[{"type":"paragraph","content":[{"type":"text","text":"a|b"}]}]},{"type":"tableCell","content":[{"type":"paragraph","content":[{"type":"text","text":"1"}]}]}]}]}]}`
+
+	result, err := NewADFMarkdownConverter(ConverterOptions{Flavor: contracts.MarkdownFlavorGFM}).ToMarkdown(adf)
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+
+	want := "- [x] ship it\n- [ ] announce\n\n| Name | Value |\n| --- | --- |\n| a\\|b | 1 |"
+	if result.Markdown != want {
+		t.Fatalf("unexpected markdown:\n%s\nwant:\n%s", result.Markdown, want)
+	}
+}
+
+func TestADFMarkdownConverterRoundTripsNestedLists(t *testing.T) {
+	t.Parallel()
+
+	adf := `{"version":1,"type":"doc","content":[{"type":"bulletList","content":[` +
+		`{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"parent"}]},` +
+		`{"type":"orderedList","content":[` +
+		`{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"first child"}]}]},` +
+		`{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"second child"}]}]}]}]},` +
+		`{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"sibling"}]}]}]}]}`
+
+	converter := NewADFMarkdownConverter(ConverterOptions{})
+	result, err := converter.ToMarkdown(adf)
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	want := "- parent\n  1. first child\n  2. second child\n- sibling"
+	if result.Markdown != want {
+		t.Fatalf("unexpected markdown:\n%s\nwant:\n%s", result.Markdown, want)
+	}
+
+	back, err := converter.ToADF(result.Markdown)
+	if err != nil {
+		t.Fatalf("convert back failed: %v", err)
+	}
+	var wantADF, gotADF any
+	if err := json.Unmarshal([]byte(adf), &wantADF); err != nil {
+		t.Fatalf("decode input adf failed: %v", err)
+	}
+	if err := json.Unmarshal([]byte(back.ADFJSON), &gotADF); err != nil {
+		t.Fatalf("decode round trip adf failed: %v", err)
+	}
+	if !reflect.DeepEqual(gotADF, wantADF) {
+		t.Fatalf("round trip changed adf:\n%s\nwant:\n%s", back.ADFJSON, adf)
+	}
+	again, err := converter.ToMarkdown(back.ADFJSON)
+	if err != nil {
+		t.Fatalf("reconvert failed: %v", err)
+	}
+	if again.Markdown != want {
+		t.Fatalf("round trip changed markdown:\n%s\nwant:\n%s", again.Markdown, want)
+	}
+}
+
+func TestADFMarkdownConverterKeepsHardBreaks(t *testing.T) {
+	t.Parallel()
+
+	adf := `{"version":1,"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"line one"},{"type":"hardBreak"},{"type":"text","text":"line two"}]}]}`
+
+	converter := NewADFMarkdownConverter(ConverterOptions{})
+	result, err := converter.ToMarkdown(adf)
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	if result.Markdown != "line one\\\nline two" {
+		t.Fatalf("expected backslash line break, got %q", result.Markdown)
+	}
+
+	back, err := converter.ToADF(result.Markdown)
+	if err != nil {
+		t.Fatalf("convert back failed: %v", err)
+	}
+	want := `{"content":[{"content":[{"text":"line one","type":"text"},{"type":"hardBreak"},{"text":"line two","type":"text"}],"type":"paragraph"}],"type":"doc","version":1}`
+	if back.ADFJSON != want {
+		t.Fatalf("unexpected adf:\n%s\nwant:\n%s", back.ADFJSON, want)
+	}
+}
@@ -0,0 +1,192 @@
+package pull
+
+import (
+	"strconv"
+	"strings"
+)
+
+// listFrame tracks one open list while parsing markdown into ADF. The content
+// column decides whether a later line nests inside the current item.
+type listFrame struct {
+	list          map[string]any
+	ordered       bool
+	markerIndent  int
+	contentIndent int
+	item          map[string]any
+	paragraph     map[string]any
+	lines         []string
+}
+
+// markdownToADFBlocks parses the block structure push needs to keep intact:
+// paragraphs, hard breaks, and nested bullet/ordered lists. Everything else,
+// fenced code included, is carried as paragraph text.
+func markdownToADFBlocks(markdown string) []map[string]any {
+	blocks := make([]map[string]any, 0)
+	paragraph := make([]string, 0)
+	stack := make([]*listFrame, 0)
+	inFence := false
+	blankBefore := false
+
+	flushParagraph := func() {
+		if len(paragraph) > 0 {
+			blocks = append(blocks, paragraphNode(paragraph))
+			paragraph = paragraph[:0]
+		}
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if inFence || strings.HasPrefix(trimmed, "```") {
+			if !inFence {
+				stack = stack[:0]
+			}
+			if strings.HasPrefix(trimmed, "```") {
+				inFence = !inFence
+			}
+			paragraph = append(paragraph, line)
+			continue
+		}
+		if trimmed == "" {
+			flushParagraph()
+			blankBefore = true
+			continue
+		}
+
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		if marker, ordered, start, ok := listMarker(trimmed); ok {
+			flushParagraph()
+			for len(stack) > 0 {
+				top := stack[len(stack)-1]
+				if indent >= top.contentIndent || (indent >= top.markerIndent && top.ordered == ordered) {
+					break
+				}
+				stack = stack[:len(stack)-1]
+			}
+
+			var frame *listFrame
+			if len(stack) > 0 && indent < stack[len(stack)-1].contentIndent {
+				frame = stack[len(stack)-1]
+			} else {
+				listType := "bulletList"
+				if ordered {
+					listType = "orderedList"
+				}
+				list := map[string]any{"type": listType, "content": []map[string]any{}}
+				if ordered && start != 1 {
+					list["attrs"] = map[string]any{"order": start}
+				}
+				if len(stack) == 0 {
+					blocks = append(blocks, list)
+				} else {
+					parent := stack[len(stack)-1]
+					appendContent(parent.item, list)
+					parent.paragraph = nil
+				}
+				frame = &listFrame{list: list, ordered: ordered, markerIndent: indent}
+				stack = append(stack, frame)
+			}
+
+			frame.contentIndent = indent + len(marker)
+			frame.lines = []string{strings.TrimSpace(trimmed[len(marker):])}
+			frame.paragraph = paragraphNode(frame.lines)
+			frame.item = map[string]any{"type": "listItem", "content": []map[string]any{frame.paragraph}}
+			appendContent(frame.list, frame.item)
+			blankBefore = false
+			continue
+		}
+
+		if blankBefore {
+			for len(stack) > 0 && indent < stack[len(stack)-1].contentIndent {
+				stack = stack[:len(stack)-1]
+			}
+		}
+		if len(stack) > 0 {
+			frame := stack[len(stack)-1]
+			if frame.paragraph == nil || blankBefore {
+				frame.lines = nil
+				frame.paragraph = map[string]any{"type": "paragraph"}
+				appendContent(frame.item, frame.paragraph)
+			}
+			frame.lines = append(frame.lines, trimmed)
+			frame.paragraph["content"] = inlineContent(frame.lines)
+			blankBefore = false
+			continue
+		}
+
+		paragraph = append(paragraph, line)
+		blankBefore = false
+	}
+	flushParagraph()
+
+	return blocks
+}
+
+// listMarker recognizes "- ", "* ", "+ ", and "N. " item markers and returns
+// the marker including its trailing space.
+func listMarker(trimmed string) (string, bool, int, bool) {
+	for _, bullet := range []string{"- ", "* ", "+ "} {
+		if strings.HasPrefix(trimmed, bullet) {
+			return bullet, false, 0, true
+		}
+	}
+
+	digits := 0
+	for digits < len(trimmed) && digits < 9 && trimmed[digits] >= '0' && trimmed[digits] <= '9' {
+		digits++
+	}
+	if digits == 0 || !strings.HasPrefix(trimmed[digits:], ". ") {
+		return "", false, 0, false
+	}
+	start, err := strconv.Atoi(trimmed[:digits])
+	if err != nil {
+		return "", false, 0, false
+	}
+	return trimmed[:digits+2], true, start, true
+}
+
+func paragraphNode(lines []string) map[string]any {
+	return map[string]any{"type": "paragraph", "content": inlineContent(lines)}
+}
+
+// inlineContent turns paragraph lines into text nodes. A line ending in a
+// backslash or two spaces is a hard break; other line ends stay in the text.
+func inlineContent(lines []string) []map[string]any {
+	text := strings.TrimSpace(strings.Join(lines, "\n"))
+	parts := strings.Split(text, "\n")
+
+	content := make([]map[string]any, 0, len(parts))
+	pending := ""
+	for index, part := range parts {
+		last := index == len(parts)-1
+		hardBreak := false
+		switch {
+		case last:
+		case strings.HasSuffix(part, "\\"):
+			part, hardBreak = strings.TrimSuffix(part, "\\"), true
+		case strings.HasSuffix(part, "  "):
+			part, hardBreak = strings.TrimRight(part, " "), true
+		}
+
+		pending += part
+		if hardBreak {
+			if pending != "" {
+				content = append(content, map[string]any{"type": "text", "text": pending})
+			}
+			content = append(content, map[string]any{"type": "hardBreak"})
+			pending = ""
+			continue
+		}
+		if !last {
+			pending += "\n"
+		}
+	}
+	if pending != "" {
+		content = append(content, map[string]any{"type": "text", "text": pending})
+	}
+	return content
+}
+
+func appendContent(node map[string]any, child map[string]any) {
+	content, _ := node["content"].([]map[string]any)
+	node["content"] = append(content, child)
+}
@@ -13,10 +13,14 @@ import (
 	"sync"
 	"time"
 
+	"github.com/pweiskircher/jira-issue-sync/internal/clock"
 	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
 	"github.com/pweiskircher/jira-issue-sync/internal/converter"
 	"github.com/pweiskircher/jira-issue-sync/internal/issue"
 	"github.com/pweiskircher/jira-issue-sync/internal/jira"
+	"github.com/pweiskircher/jira-issue-sync/internal/jql"
+	"github.com/pweiskircher/jira-issue-sync/internal/logging"
+	"github.com/pweiskircher/jira-issue-sync/internal/output"
 	"github.com/pweiskircher/jira-issue-sync/internal/store"
 )
 
@@ -28,9 +32,42 @@ type Pipeline struct {
 	Converter          converter.Adapter
 	PageSize           int
 	Concurrency        int
-	Now                func() time.Time
+	Clock              clock.Clock
 	CustomFieldAliases map[string]string
 	PullFields         []string
+	Logger             logging.Logger
+	// SkipEnvironment leaves the Jira environment field out of issue files.
+	SkipEnvironment bool
+	// PreserveLabelCase writes remote labels as Jira returns them instead
+	// of lowercased.
+	PreserveLabelCase bool
+	// IncludeComments appends the read-only comments section to each issue
+	// file. PullFields must request the comment field.
+	IncludeComments bool
+	// DryRun runs fetch and prepare but leaves issue files, snapshots, and
+	// the cache untouched; changed issues are reported as would-be actions.
+	DryRun bool
+	// MaxErrors stops persisting, in key order, once more than this many
+	// issues have failed. Zero means unlimited.
+	MaxErrors int
+	// MaxIssues aborts the pull before anything is written when the search
+	// matches more than this many issues. Zero means unlimited.
+	MaxIssues int
+}
+
+// TooManyIssuesError stops a pull whose search matches more issues than
+// Pipeline.MaxIssues allows. Total is zero when Jira did not report a
+// total and the cap was crossed while paging.
+type TooManyIssuesError struct {
+	Limit int
+	Total int
+}
+
+func (e *TooManyIssuesError) Error() string {
+	if e.Total > 0 {
+		return fmt.Sprintf("search matches %d issues, more than the limit of %d (max_pull_issues / --max-results-total); narrow the JQL or raise the limit", e.Total, e.Limit)
+	}
+	return fmt.Sprintf("search matches more than %d issues (max_pull_issues / --max-results-total); narrow the JQL or raise the limit", e.Limit)
 }
 
 type Outcome struct {
@@ -44,18 +81,27 @@ type Outcome struct {
 type Result struct {
 	Outcomes []Outcome
 	Cache    store.Cache
+	Timings  []contracts.PhaseTiming
 }
 
 type preparedIssue struct {
 	key             string
+	issueID         string
 	summary         string
 	canonical       string
 	state           store.IssueState
 	remoteUpdatedAt string
 	changed         bool
-	err             error
-	reasonCode      contracts.ReasonCode
-	errorCode       string
+	previousPath    string
+	desiredPath     string
+	// movedFrom is the cached key this issue had before it was moved to
+	// another project in Jira.
+	movedFrom  string
+	err        error
+	reasonCode contracts.ReasonCode
+	errorCode  string
+	// warnings are reported without failing the issue.
+	warnings []contracts.IssueMessage
 }
 
 func (p Pipeline) Execute(ctx context.Context, jql string) (Result, error) {
@@ -84,43 +130,120 @@ func (p Pipeline) Execute(ctx context.Context, jql string) (Result, error) {
 		concurrency = contracts.DefaultPullConcurrency
 	}
 
-	now := p.Now
-	if now == nil {
-		now = time.Now
-	}
-
 	fetchFields := p.PullFields
 	if len(fetchFields) == 0 {
 		fetchFields = defaultPullFields
 	}
 
-	fetched, err := fetchIssues(ctx, p.Adapter, trimmedJQL, pageSize, fetchFields)
+	// Files added or removed by hand since the last pull are folded into
+	// the cache first, so a stale entry cannot hide an issue from the
+	// unchanged check or leave a hand-made copy beside the pulled file.
+	cache, drift, err := p.Store.LoadReconciledCache()
 	if err != nil {
 		return Result{}, err
 	}
-	if len(fetched) == 0 {
-		cache, cacheErr := p.Store.LoadCache()
-		if cacheErr != nil {
-			return Result{}, cacheErr
+	if !drift.Empty() {
+		logging.Debugf(p.Logger, "cache drift: added %v, repointed %v, dropped %v", drift.Added, drift.Repointed, drift.Dropped)
+	}
+
+	query := withStableOrdering(trimmedJQL)
+	// A rejected query fails here with Jira's own message rather than as a
+	// generic search error. An instance that does not serve the parse
+	// endpoint skips the check; transport and auth failures would fail the
+	// search the same way, so they stop the run now.
+	if err := p.Adapter.ValidateJQL(ctx, query); err != nil {
+		if !jira.IsErrorCode(err, jira.ErrorCodeUnexpectedStatus) && !jira.IsErrorCode(err, jira.ErrorCodeResponseDecode) {
+			return Result{}, err
+		}
+		logging.Debugf(p.Logger, "skipping JQL preflight: %v", err)
+	}
+	cursor := pageCursor{}
+	seen := make(map[string]struct{})
+	if !p.DryRun {
+		progress, found, progressErr := p.Store.LoadPullProgress()
+		if progressErr != nil {
+			return Result{}, progressErr
+		}
+		if found && progress.JQL == query {
+			cursor = pageCursor{startAt: progress.StartAt, nextPageToken: progress.NextPageToken}
+			for _, key := range progress.ProcessedKeys {
+				seen[key] = struct{}{}
+			}
+			logging.Debugf(p.Logger, "pull resume: skipping %d issues persisted by an interrupted run", len(progress.ProcessedKeys))
 		}
-		return Result{Cache: cache}, nil
 	}
 
-	sort.Slice(fetched, func(i int, j int) bool {
-		return fetched[i].Key < fetched[j].Key
-	})
+	settings := prepareSettings{
+		syncedAt:           clock.OrSystem(p.Clock).Now().UTC(),
+		converter:          p.Converter,
+		customFieldAliases: p.CustomFieldAliases,
+		skipEnvironment:    p.SkipEnvironment || excludesField(fetchFields, "environment"),
+		skipDescription:    excludesField(fetchFields, "description"),
+		preserveLabelCase:  p.PreserveLabelCase,
+		includeComments:    p.IncludeComments,
+		render:             p.Store.RenderDocument,
+	}
+
+	// Each page is converted and persisted before the next is fetched, and
+	// the cursor is saved after it, so an interrupted pull resumes where it
+	// stopped instead of starting over.
+	timings := output.NewPhaseTimings("fetch", "convert", "persist")
+	duplicates := make(map[string]int)
+	prepared := make([]preparedIssue, 0)
+	failed := 0
+	phaseStarted := time.Now()
+	err = fetchPages(ctx, p.Adapter, query, pageSize, fetchFields, cursor, seen, duplicates, p.MaxIssues, func(page []jira.Issue, next pageCursor, last bool) (bool, error) {
+		timings.Since("fetch", phaseStarted)
+		defer func() { phaseStarted = time.Now() }()
+
+		sort.Slice(page, func(i int, j int) bool {
+			return page[i].Key < page[j].Key
+		})
 
-	prepared := prepareIssues(fetched, concurrency, now().UTC(), p.Converter, p.CustomFieldAliases)
-	sort.Slice(prepared, func(i int, j int) bool {
-		return prepared[i].key < prepared[j].key
-	})
+		convertStarted := time.Now()
+		pagePrepared := prepareIssues(page, concurrency, settings)
+		sort.Slice(pagePrepared, func(i int, j int) bool {
+			return pagePrepared[i].key < pagePrepared[j].key
+		})
+		timings.Since("convert", convertStarted)
+
+		persistStarted := time.Now()
+		persisted, persistErr := p.persist(cache, pagePrepared, failed)
+		if persistErr != nil {
+			return false, persistErr
+		}
+		timings.Since("persist", persistStarted)
+		logging.Debugf(p.Logger, "pull page: %d issues with %d workers in %s", len(page), concurrency, time.Since(phaseStarted).Round(time.Millisecond))
+
+		for _, entry := range persisted {
+			if entry.err != nil {
+				failed++
+			}
+		}
+		prepared = append(prepared, persisted...)
+		if len(persisted) < len(pagePrepared) {
+			return false, nil
+		}
 
-	cache, persisted, err := p.persist(prepared)
+		if !p.DryRun && !last {
+			if err := p.Store.SavePullProgress(store.PullProgress{JQL: query, StartAt: next.startAt, NextPageToken: next.nextPageToken, ProcessedKeys: sortedKeys(seen)}); err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+	})
 	if err != nil {
 		return Result{}, err
 	}
+	if !p.DryRun {
+		if err := p.Store.ClearPullProgress(); err != nil {
+			return Result{}, err
+		}
+	}
 
-	prepared = persisted
+	sort.Slice(prepared, func(i int, j int) bool {
+		return prepared[i].key < prepared[j].key
+	})
 
 	outcomes := make([]Outcome, 0, len(prepared))
 	for _, entry := range prepared {
@@ -138,41 +261,78 @@ func (p Pipeline) Execute(ctx context.Context, jql string) (Result, error) {
 			continue
 		}
 
-		action := "unchanged"
-		message := "issue unchanged"
-		if entry.changed {
-			action = "pull"
-			message = "synchronized issue snapshot"
-		}
+		var outcome Outcome
+		if p.DryRun && entry.changed {
+			outcome = dryRunOutcome(entry)
+		} else {
+			action := "unchanged"
+			message := "issue unchanged"
+			if entry.changed {
+				action = "pull"
+				message = "synchronized issue snapshot"
+			}
+			if entry.movedFrom != "" {
+				action = "rename"
+				message = "issue moved from " + entry.movedFrom + " in Jira; renamed local file and snapshot"
+			}
 
-		outcomes = append(outcomes, Outcome{
-			Key:     entry.key,
-			Action:  action,
-			Status:  contracts.PerIssueStatusSuccess,
-			Updated: entry.changed,
-			Messages: []contracts.IssueMessage{{
-				Level: "info",
-				Text:  message,
-			}},
-		})
+			outcome = Outcome{
+				Key:     entry.key,
+				Action:  action,
+				Status:  contracts.PerIssueStatusSuccess,
+				Updated: entry.changed,
+				Messages: []contracts.IssueMessage{{
+					Level: "info",
+					Text:  message,
+				}},
+			}
+		}
+		if dropped := duplicates[entry.key]; dropped > 0 {
+			outcome.Status = contracts.PerIssueStatusWarning
+			outcome.Messages = append(outcome.Messages, contracts.IssueMessage{
+				Level:      "warning",
+				ReasonCode: contracts.ReasonCodePullDuplicateDropped,
+				Text:       fmt.Sprintf("dropped %d duplicate copies returned across search pages", dropped),
+			})
+		}
+		if len(entry.warnings) > 0 && entry.changed {
+			outcome.Status = contracts.PerIssueStatusWarning
+			outcome.Messages = append(outcome.Messages, entry.warnings...)
+		}
+		outcomes = append(outcomes, outcome)
 	}
 
-	return Result{Outcomes: outcomes, Cache: cache}, nil
+	return Result{Outcomes: outcomes, Cache: cache, Timings: timings.Timings()}, nil
 }
 
-func (p Pipeline) persist(prepared []preparedIssue) (store.Cache, []preparedIssue, error) {
-	cache, err := p.Store.LoadCache()
-	if err != nil {
-		return store.Cache{}, nil, err
+func sortedKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
 	}
+	sort.Strings(keys)
+	return keys
+}
 
+// persist writes one page of prepared issues into cache and saves it.
+// failed counts issues that already failed on earlier pages, so MaxErrors
+// applies across the whole run.
+func (p Pipeline) persist(cache store.Cache, prepared []preparedIssue, failed int) ([]preparedIssue, error) {
 	for index := range prepared {
+		if index > 0 && prepared[index-1].err != nil {
+			failed++
+		}
+		if p.MaxErrors > 0 && failed > p.MaxErrors {
+			prepared = prepared[:index]
+			break
+		}
+
 		entry := &prepared[index]
 		if entry.err != nil {
 			continue
 		}
 
-		desiredPath, desiredPathErr := issuePath(entry.state, entry.key, entry.summary)
+		desiredPath, desiredPathErr := p.Store.IssuePath(entry.state, entry.key, entry.summary)
 		if desiredPathErr != nil {
 			entry.err = desiredPathErr
 			entry.reasonCode = contracts.ReasonCodeValidationFailed
@@ -180,19 +340,34 @@ func (p Pipeline) persist(prepared []preparedIssue) (store.Cache, []preparedIssu
 			continue
 		}
 
-		if persistedUnchanged, unchangedErr := p.isPersistedIssueUnchanged(cache, *entry, desiredPath); unchangedErr != nil {
-			entry.err = unchangedErr
-			entry.reasonCode = contracts.ReasonCodeValidationFailed
-			entry.errorCode = "read_existing_issue_failed"
-			continue
-		} else if persistedUnchanged {
-			entry.changed = false
-			continue
+		// Another cached key with the same id is either the key the issue
+		// had before a move or a stale duplicate; both are folded into this
+		// key, so the unchanged shortcut does not apply.
+		entry.movedFrom, _ = movedIssueKey(cache, entry.issueID, entry.key)
+		if entry.movedFrom == "" {
+			if persistedUnchanged, unchangedErr := p.isPersistedIssueUnchanged(cache, *entry, desiredPath); unchangedErr != nil {
+				entry.err = unchangedErr
+				entry.reasonCode = contracts.ReasonCodeValidationFailed
+				entry.errorCode = "read_existing_issue_failed"
+				continue
+			} else if persistedUnchanged {
+				entry.changed = false
+				continue
+			}
 		}
 
 		previousPath := ""
 		if previous, ok := cache.Issues[entry.key]; ok {
 			previousPath = previous.Path
+		} else if entry.movedFrom != "" {
+			previousPath = cache.Issues[entry.movedFrom].Path
+		}
+
+		if p.DryRun {
+			entry.changed = true
+			entry.previousPath = previousPath
+			entry.desiredPath = desiredPath
+			continue
 		}
 
 		path, writeErr := p.Store.WriteIssue(entry.state, entry.key, entry.summary, entry.canonical)
@@ -219,37 +394,86 @@ func (p Pipeline) persist(prepared []preparedIssue) (store.Cache, []preparedIssu
 			}
 		}
 
+		if entry.movedFrom != "" {
+			stalePath := cache.Issues[entry.movedFrom].Path
+			if stalePath != "" && stalePath != path && stalePath != previousPath {
+				if removeErr := p.Store.Remove(stalePath); removeErr != nil {
+					entry.err = removeErr
+					entry.reasonCode = contracts.ReasonCodeValidationFailed
+					entry.errorCode = "cleanup_old_path_failed"
+					continue
+				}
+			}
+			if removeErr := p.Store.Remove(filepath.Join(".sync", "originals", entry.movedFrom+".md")); removeErr != nil {
+				entry.err = removeErr
+				entry.reasonCode = contracts.ReasonCodeValidationFailed
+				entry.errorCode = "cleanup_old_snapshot_failed"
+				continue
+			}
+			delete(cache.Issues, entry.movedFrom)
+		}
+
 		cache.Issues[entry.key] = store.CacheEntry{
+			ID:              entry.issueID,
 			Path:            path,
 			Status:          string(entry.state),
 			RemoteUpdatedAt: entry.remoteUpdatedAt,
 		}
 	}
 
+	if p.DryRun {
+		return prepared, nil
+	}
+
 	if err := p.Store.SaveCache(cache); err != nil {
-		return store.Cache{}, nil, err
+		return nil, err
 	}
 
-	return cache, prepared, nil
+	return prepared, nil
 }
 
-func issuePath(state store.IssueState, key string, summary string) (string, error) {
-	dir := ""
-	switch state {
-	case store.IssueStateOpen:
-		dir = "open"
-	case store.IssueStateClosed:
-		dir = "closed"
-	default:
-		return "", fmt.Errorf("unsupported issue state %q", state)
+// movedIssueKey finds a cached key that belongs to issueID under another
+// key. Jira keeps the id when an issue moves projects and only the key
+// changes, so a match means the local file should follow the new key.
+// Candidates are checked in key order so the result is deterministic.
+func movedIssueKey(cache store.Cache, issueID string, key string) (string, bool) {
+	if issueID == "" {
+		return "", false
+	}
+	keys := make([]string, 0, len(cache.Issues))
+	for cachedKey, cached := range cache.Issues {
+		if cachedKey != key && cached.ID == issueID {
+			keys = append(keys, cachedKey)
+		}
 	}
+	if len(keys) == 0 {
+		return "", false
+	}
+	sort.Strings(keys)
+	return keys[0], true
+}
 
-	filename, err := issue.BuildFilename(strings.TrimSpace(key), summary)
-	if err != nil {
-		return "", err
+func dryRunOutcome(entry preparedIssue) Outcome {
+	action := "would-pull"
+	text := "dry-run: would write " + entry.desiredPath
+	if entry.previousPath != "" && entry.previousPath != entry.desiredPath {
+		action = "would-rename"
+		text = "dry-run: would move " + entry.previousPath + " to " + entry.desiredPath
+	}
+	if entry.movedFrom != "" {
+		text += " (issue moved from " + entry.movedFrom + ")"
 	}
 
-	return filepath.Join(dir, filename), nil
+	return Outcome{
+		Key:    entry.key,
+		Action: action,
+		Status: contracts.PerIssueStatusSkipped,
+		Messages: []contracts.IssueMessage{{
+			Level:      "info",
+			ReasonCode: contracts.ReasonCodeDryRunNoWrite,
+			Text:       text,
+		}},
+	}
 }
 
 func (p Pipeline) isPersistedIssueUnchanged(cache store.Cache, entry preparedIssue, desiredPath string) (bool, error) {
@@ -284,6 +508,8 @@ func (p Pipeline) isPersistedIssueUnchanged(cache store.Cache, entry preparedIss
 		return false, nil
 	}
 
+	// Caches written before issue ids were tracked pick the id up here.
+	previous.ID = entry.issueID
 	cache.Issues[entry.key] = previous
 	return true, nil
 }
@@ -304,74 +530,142 @@ func isCanonicalTextEqual(existing []byte, canonical string) bool {
 }
 
 func normalizePullText(input string) []byte {
-	normalized := contracts.NormalizeSingleValue(contracts.NormalizationNormalizeLineEndings, input)
+	normalized := issue.StripVolatileFrontMatter(input)
 	if normalized == "" {
 		return []byte{}
 	}
 
-	lines := strings.Split(normalized, "\n")
-	filtered := make([]string, 0, len(lines))
-	for _, line := range lines {
-		if strings.HasPrefix(strings.TrimSpace(line), "synced_at:") {
-			continue
-		}
-		filtered = append(filtered, line)
-	}
-
-	normalized = strings.Join(filtered, "\n")
 	if !strings.HasSuffix(normalized, "\n") {
 		normalized += "\n"
 	}
 	return []byte(normalized)
 }
 
-func fetchIssues(ctx context.Context, adapter jira.Adapter, jql string, pageSize int, fields []string) ([]jira.Issue, error) {
-	issues := make([]jira.Issue, 0)
-	startAt := 0
-	nextPageToken := ""
-	usingTokenPagination := false
+// withStableOrdering appends ORDER BY key ASC to queries without an explicit
+// ordering so pagination stays consistent when issues change mid-pull.
+// A user-supplied ORDER BY is left untouched.
+func withStableOrdering(query string) string {
+	if jql.HasOrderBy(query) {
+		return query
+	}
+	return jql.FromQuery(query).OrderBy("key", jql.Ascending).String()
+}
+
+// pageCursor is where the next search page starts: startAt for offset
+// pagination, nextPageToken for token pagination.
+type pageCursor struct {
+	startAt       int
+	nextPageToken string
+}
+
+// fetchPages pages through search results from cursor and hands each page
+// to handle with the cursor of the page after it; handle returns false to
+// stop early. Issues already in seen (possible when data shifts mid-pull)
+// are dropped and counted per key in duplicates.
+func fetchPages(
+	ctx context.Context,
+	adapter jira.Adapter,
+	jql string,
+	pageSize int,
+	fields []string,
+	cursor pageCursor,
+	seen map[string]struct{},
+	duplicates map[string]int,
+	maxIssues int,
+	handle func(page []jira.Issue, next pageCursor, last bool) (bool, error),
+) error {
+	usingTokenPagination := cursor.nextPageToken != ""
 
 	for {
 		response, err := adapter.SearchIssues(ctx, jira.SearchIssuesRequest{
 			JQL:           jql,
-			StartAt:       startAt,
+			StartAt:       cursor.startAt,
 			MaxResults:    pageSize,
 			Fields:        append([]string(nil), fields...),
-			NextPageToken: nextPageToken,
+			NextPageToken: cursor.nextPageToken,
 		})
 		if err != nil {
-			return nil, err
+			return err
 		}
-
-		issues = append(issues, response.Issues...)
-		if len(response.Issues) == 0 {
-			break
+		// Jira's total is known from the first offset page, so the cap
+		// normally trips before anything is persisted. Token pages carry
+		// no total; there the count of distinct keys seen is checked
+		// before each page is handed on.
+		if maxIssues > 0 && response.Total > maxIssues {
+			return &TooManyIssuesError{Limit: maxIssues, Total: response.Total}
 		}
 
-		if response.NextPageToken != "" || response.IsLast {
-			usingTokenPagination = true
+		page := make([]jira.Issue, 0, len(response.Issues))
+		for _, fetched := range response.Issues {
+			key := strings.TrimSpace(fetched.Key)
+			if _, exists := seen[key]; exists {
+				duplicates[key]++
+				continue
+			}
+			seen[key] = struct{}{}
+			page = append(page, fetched)
+		}
+		if maxIssues > 0 && len(seen) > maxIssues {
+			return &TooManyIssuesError{Limit: maxIssues}
 		}
-		if usingTokenPagination {
-			if response.IsLast || response.NextPageToken == "" {
-				break
+
+		next := cursor
+		last := len(response.Issues) == 0
+		if !last {
+			if response.NextPageToken != "" || response.IsLast {
+				usingTokenPagination = true
+			}
+			if usingTokenPagination {
+				next = pageCursor{nextPageToken: response.NextPageToken}
+				last = response.IsLast || response.NextPageToken == ""
+			} else {
+				// A short page only ends the search when measured against
+				// the page size Jira echoes back: the adapter and Jira may
+				// both cap the requested pageSize, so comparing against it
+				// would stop early.
+				next = pageCursor{startAt: response.StartAt + len(response.Issues)}
+				last = (response.Total > 0 && next.startAt >= response.Total) ||
+					(response.MaxResults > 0 && len(response.Issues) < response.MaxResults)
 			}
-			nextPageToken = response.NextPageToken
-			continue
 		}
 
-		startAt = response.StartAt + len(response.Issues)
-		if response.Total > 0 && startAt >= response.Total {
-			break
+		more, err := handle(page, next, last)
+		if err != nil {
+			return err
 		}
-		if response.MaxResults > 0 && len(response.Issues) < response.MaxResults {
-			break
+		if last || !more {
+			return nil
 		}
+		cursor = next
 	}
+}
 
-	return issues, nil
+// prepareSettings carries the per-run inputs shared by every prepared issue.
+// excludesField reports whether fields carries Jira's "-name" exclusion
+// for field.
+func excludesField(fields []string, field string) bool {
+	for _, candidate := range fields {
+		if strings.TrimSpace(candidate) == "-"+field {
+			return true
+		}
+	}
+	return false
 }
 
-func prepareIssues(issues []jira.Issue, concurrency int, syncedAt time.Time, markdownConverter converter.Adapter, customFieldAliases map[string]string) []preparedIssue {
+type prepareSettings struct {
+	syncedAt           time.Time
+	converter          converter.Adapter
+	customFieldAliases map[string]string
+	skipEnvironment    bool
+	// skipDescription leaves the description out even if Jira returned
+	// it, matching a "-description" entry in the requested fields.
+	skipDescription   bool
+	preserveLabelCase bool
+	includeComments   bool
+	render            func(issue.Document) (string, error)
+}
+
+func prepareIssues(issues []jira.Issue, concurrency int, settings prepareSettings) []preparedIssue {
 	prepared := make([]preparedIssue, len(issues))
 	jobs := make(chan int, len(issues))
 
@@ -389,7 +683,7 @@ func prepareIssues(issues []jira.Issue, concurrency int, syncedAt time.Time, mar
 		go func() {
 			defer wg.Done()
 			for index := range jobs {
-				prepared[index] = prepareIssue(issues[index], syncedAt, markdownConverter, customFieldAliases)
+				prepared[index] = prepareIssue(issues[index], settings)
 			}
 		}()
 	}
@@ -403,31 +697,35 @@ func prepareIssues(issues []jira.Issue, concurrency int, syncedAt time.Time, mar
 	return prepared
 }
 
-func prepareIssue(remote jira.Issue, syncedAt time.Time, markdownConverter converter.Adapter, customFieldAliases map[string]string) preparedIssue {
+func prepareIssue(remote jira.Issue, settings prepareSettings) preparedIssue {
 	key := strings.TrimSpace(remote.Key)
 	if key == "" {
 		return preparedIssue{key: remote.Key, err: errors.New("issue key is missing"), reasonCode: contracts.ReasonCodeValidationFailed, errorCode: "missing_key"}
 	}
 
-	rawADF := strings.TrimSpace(string(remote.Fields.Description))
-	markdownResult, err := markdownConverter.ToMarkdown(rawADF)
+	if settings.skipDescription {
+		remote.Fields.Description = nil
+	}
+	description, errorCode, err := convertRemoteADF(settings.converter, remote.Fields.Description)
 	if err != nil {
-		reason := contracts.ReasonCodeValidationFailed
-		if converterErr := asConverterError(err); converterErr != nil {
-			reason = converterErr.ReasonCode
+		return preparedIssue{key: key, err: err, reasonCode: converterReason(err), errorCode: errorCode}
+	}
+	warnings := riskWarnings("description", description.risks)
+
+	var environment convertedADF
+	if !settings.skipEnvironment && len(remote.Fields.Environment) > 0 {
+		environment, errorCode, err = convertRemoteADF(settings.converter, remote.Fields.Environment)
+		if err != nil {
+			return preparedIssue{key: key, err: fmt.Errorf("environment: %w", err), reasonCode: converterReason(err), errorCode: errorCode}
 		}
-		return preparedIssue{key: key, err: err, reasonCode: reason, errorCode: "adf_to_markdown_failed"}
+		warnings = append(warnings, riskWarnings("environment", environment.risks)...)
 	}
 
-	canonicalADF := ""
-	if rawADF != "" {
-		canonicalADF, err = converter.ValidateAndCanonicalizeRawADF(rawADF)
+	comments := ""
+	if settings.includeComments && len(remote.Fields.Comments) > 0 {
+		comments, errorCode, err = renderComments(settings.converter, remote.Fields.Comments)
 		if err != nil {
-			reason := contracts.ReasonCodeValidationFailed
-			if converterErr := asConverterError(err); converterErr != nil {
-				reason = converterErr.ReasonCode
-			}
-			return preparedIssue{key: key, err: err, reasonCode: reason, errorCode: "adf_validation_failed"}
+			return preparedIssue{key: key, err: fmt.Errorf("comments: %w", err), reasonCode: converterReason(err), errorCode: errorCode}
 		}
 	}
 
@@ -441,40 +739,80 @@ func prepareIssue(remote jira.Issue, syncedAt time.Time, markdownConverter conve
 			Status:        statusValue(remote.Fields.Status),
 			Priority:      namedRefValue(remote.Fields.Priority),
 			Assignee:      accountRefValue(remote.Fields.Assignee),
-			Labels:        append([]string(nil), remote.Fields.Labels...),
+			Labels:        contracts.NormalizeLabelsWithCase(remote.Fields.Labels, settings.preserveLabelCase),
 			Reporter:      accountRefValue(remote.Fields.Reporter),
+			SecurityLevel: namedRefValue(remote.Fields.Security),
 			CreatedAt:     strings.TrimSpace(remote.Fields.CreatedAt),
 			UpdatedAt:     strings.TrimSpace(remote.Fields.UpdatedAt),
-			SyncedAt:      syncedAt.Format(time.RFC3339Nano),
-			CustomFields:  mapAliasedCustomFields(remote.Fields.CustomFields, customFieldAliases),
+			SyncedAt:      settings.syncedAt.Format(time.RFC3339),
+			CustomFields:  mapAliasedCustomFields(remote.Fields.CustomFields, settings.customFieldAliases),
 		},
-		MarkdownBody: markdownResult.Markdown,
-		RawADFJSON:   canonicalADF,
+		MarkdownBody:        description.markdown,
+		RawADFJSON:          description.canonical,
+		EnvironmentMarkdown: environment.markdown,
+		EnvironmentADFJSON:  environment.canonical,
+		Comments:            comments,
 	}
 
-	canonical, renderErr := issue.RenderDocument(doc)
+	canonical, renderErr := settings.render(doc)
 	if renderErr != nil {
 		return preparedIssue{key: key, err: renderErr, reasonCode: contracts.ReasonCodeValidationFailed, errorCode: "render_document_failed"}
 	}
 
 	return preparedIssue{
 		key:             key,
+		issueID:         strings.TrimSpace(remote.ID),
 		summary:         doc.FrontMatter.Summary,
 		canonical:       canonical,
-		state:           issueStateFromStatus(doc.FrontMatter.Status),
+		state:           store.IssueStateForStatus(doc.FrontMatter.Status),
 		remoteUpdatedAt: doc.FrontMatter.UpdatedAt,
 		changed:         true,
+		warnings:        warnings,
+	}
+}
+
+// convertRemoteADF renders a remote ADF field to markdown and its canonical
+// raw form. The error code names the failing step for the issue diagnostic.
+func convertRemoteADF(markdownConverter converter.Adapter, raw json.RawMessage) (convertedADF, string, error) {
+	rawADF := strings.TrimSpace(string(raw))
+	markdownResult, err := markdownConverter.ToMarkdown(rawADF)
+	if err != nil {
+		return convertedADF{}, "adf_to_markdown_failed", err
+	}
+	if rawADF == "" {
+		return convertedADF{markdown: markdownResult.Markdown}, "", nil
+	}
+	canonicalADF, err := converter.ValidateAndCanonicalizeRawADF(rawADF)
+	if err != nil {
+		return convertedADF{}, "adf_validation_failed", err
+	}
+	return convertedADF{markdown: markdownResult.Markdown, canonical: canonicalADF, risks: markdownResult.Risks}, "", nil
+}
+
+type convertedADF struct {
+	markdown  string
+	canonical string
+	// risks are soft ADF validation warnings, such as unknown node types.
+	risks []converter.RiskSignal
+}
+
+func riskWarnings(field string, risks []converter.RiskSignal) []contracts.IssueMessage {
+	warnings := make([]contracts.IssueMessage, 0, len(risks))
+	for _, risk := range risks {
+		warnings = append(warnings, contracts.IssueMessage{
+			Level:      "warning",
+			ReasonCode: risk.ReasonCode,
+			Text:       field + ": " + risk.Message,
+		})
 	}
+	return warnings
 }
 
-func issueStateFromStatus(status string) store.IssueState {
-	normalized := strings.ToLower(strings.TrimSpace(status))
-	switch normalized {
-	case "done", "closed", "resolved", "complete", "completed", "rejected", "declined", "cancelled", "canceled", "won't do", "wont do":
-		return store.IssueStateClosed
-	default:
-		return store.IssueStateOpen
+func converterReason(err error) contracts.ReasonCode {
+	if converterErr := asConverterError(err); converterErr != nil {
+		return converterErr.ReasonCode
 	}
+	return contracts.ReasonCodeValidationFailed
 }
 
 func namedRefValue(ref *jira.NamedRef) string {
@@ -3,11 +3,17 @@ package pull
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/pweiskircher/jira-issue-sync/internal/clock"
 	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
+	"github.com/pweiskircher/jira-issue-sync/internal/issue"
 	"github.com/pweiskircher/jira-issue-sync/internal/jira"
 	"github.com/pweiskircher/jira-issue-sync/internal/store"
 )
@@ -15,6 +21,7 @@ import (
 type paginationAdapterStub struct {
 	requests []jira.SearchIssuesRequest
 	search   func(context.Context, jira.SearchIssuesRequest) (jira.SearchIssuesResponse, error)
+	validate func(context.Context, string) error
 }
 
 func (s *paginationAdapterStub) SearchIssues(ctx context.Context, request jira.SearchIssuesRequest) (jira.SearchIssuesResponse, error) {
@@ -22,10 +29,33 @@ func (s *paginationAdapterStub) SearchIssues(ctx context.Context, request jira.S
 	return s.search(ctx, request)
 }
 
+func (s *paginationAdapterStub) ValidateJQL(ctx context.Context, jql string) error {
+	if s.validate == nil {
+		return nil
+	}
+	return s.validate(ctx, jql)
+}
+
 func (s *paginationAdapterStub) ListFields(context.Context) ([]jira.FieldDefinition, error) {
 	panic("unexpected call")
 }
 
+func (s *paginationAdapterStub) ListProjects(context.Context) ([]jira.Project, error) {
+	panic("unexpected call")
+}
+
+func (s *paginationAdapterStub) ListStatuses(context.Context) ([]jira.StatusRef, error) {
+	panic("unexpected call")
+}
+
+func (s *paginationAdapterStub) ListSecurityLevels(context.Context, string) ([]jira.NamedRef, error) {
+	panic("unexpected call")
+}
+
+func (s *paginationAdapterStub) GetMyself(context.Context) (jira.AccountRef, error) {
+	panic("unexpected call")
+}
+
 func (s *paginationAdapterStub) GetIssue(context.Context, string, []string) (jira.Issue, error) {
 	panic("unexpected call")
 }
@@ -44,19 +74,20 @@ func (s *paginationAdapterStub) ApplyTransition(context.Context, string, string)
 func (s *paginationAdapterStub) ResolveTransition(context.Context, string, contracts.TransitionSelection) (jira.TransitionResolution, error) {
 	panic("unexpected call")
 }
+func (s *paginationAdapterStub) SearchAssignableUsers(context.Context, string, string) ([]jira.AccountRef, error) {
+	panic("unexpected call")
+}
 
-func TestIssueStateFromStatusTreatsRejectedAsClosed(t *testing.T) {
-	t.Parallel()
-
-	closedStatuses := []string{"Rejected", "Declined", "Cancelled", "Won't Do"}
-	for _, status := range closedStatuses {
-		if got := issueStateFromStatus(status); got != "closed" {
-			t.Fatalf("expected status %q to be closed, got %q", status, got)
-		}
-	}
+func collectPages(adapter jira.Adapter, pageSize int) ([]jira.Issue, error) {
+	issues := make([]jira.Issue, 0)
+	err := fetchPages(context.Background(), adapter, "project = PROJ", pageSize, []string{"*navigable"}, pageCursor{}, map[string]struct{}{}, map[string]int{}, 0, func(page []jira.Issue, _ pageCursor, _ bool) (bool, error) {
+		issues = append(issues, page...)
+		return true, nil
+	})
+	return issues, err
 }
 
-func TestFetchIssuesUsesTokenPaginationWhenAvailable(t *testing.T) {
+func TestFetchPagesUsesTokenPaginationWhenAvailable(t *testing.T) {
 	t.Parallel()
 
 	adapter := &paginationAdapterStub{}
@@ -84,7 +115,7 @@ func TestFetchIssuesUsesTokenPaginationWhenAvailable(t *testing.T) {
 		}
 	}
 
-	issues, err := fetchIssues(context.Background(), adapter, "project = PROJ", 50, []string{"*navigable"})
+	issues, err := collectPages(adapter, 50)
 	if err != nil {
 		t.Fatalf("fetch issues failed: %v", err)
 	}
@@ -93,6 +124,81 @@ func TestFetchIssuesUsesTokenPaginationWhenAvailable(t *testing.T) {
 	}
 }
 
+func TestFetchPagesPaginatesByEchoedPageSizeWhenRequestIsOversized(t *testing.T) {
+	t.Parallel()
+
+	page := func(startAt int, count int) []jira.Issue {
+		issues := make([]jira.Issue, 0, count)
+		for i := 0; i < count; i++ {
+			issues = append(issues, jira.Issue{Key: fmt.Sprintf("PROJ-%d", startAt+i+1)})
+		}
+		return issues
+	}
+
+	adapter := &paginationAdapterStub{}
+	adapter.search = func(_ context.Context, request jira.SearchIssuesRequest) (jira.SearchIssuesResponse, error) {
+		if request.MaxResults != 500 {
+			t.Fatalf("expected requested page size to pass through, got %d", request.MaxResults)
+		}
+		switch request.StartAt {
+		case 0, 100:
+			return jira.SearchIssuesResponse{StartAt: request.StartAt, MaxResults: 100, Issues: page(request.StartAt, 100)}, nil
+		case 200:
+			return jira.SearchIssuesResponse{StartAt: 200, MaxResults: 100, Issues: page(200, 40)}, nil
+		default:
+			t.Fatalf("unexpected startAt %d", request.StartAt)
+			return jira.SearchIssuesResponse{}, nil
+		}
+	}
+
+	issues, err := collectPages(adapter, 500)
+	if err != nil {
+		t.Fatalf("fetch issues failed: %v", err)
+	}
+	if len(issues) != 240 {
+		t.Fatalf("expected 240 issues across capped pages, got %d", len(issues))
+	}
+	if len(adapter.requests) != 3 {
+		t.Fatalf("expected 3 page requests, got %d", len(adapter.requests))
+	}
+}
+
+func TestPipelineValidatesJQLBeforeSearching(t *testing.T) {
+	t.Parallel()
+
+	issueStore, err := store.New(filepath.Join(t.TempDir(), contracts.DefaultIssuesRootDir))
+	if err != nil {
+		t.Fatalf("store init failed: %v", err)
+	}
+
+	adapter := &paginationAdapterStub{}
+	adapter.search = func(_ context.Context, _ jira.SearchIssuesRequest) (jira.SearchIssuesResponse, error) {
+		return jira.SearchIssuesResponse{}, nil
+	}
+	adapter.validate = func(context.Context, string) error {
+		return &jira.Error{Code: jira.ErrorCodeInvalidJQL, Message: "invalid JQL: field 'projct' does not exist"}
+	}
+	pipeline := Pipeline{Adapter: adapter, Store: issueStore, Converter: NewADFMarkdownConverter(ConverterOptions{})}
+
+	if _, err := pipeline.Execute(context.Background(), "projct = PROJ"); !jira.IsErrorCode(err, jira.ErrorCodeInvalidJQL) {
+		t.Fatalf("expected invalid jql to stop the pull, got %v", err)
+	}
+	if len(adapter.requests) != 0 {
+		t.Fatalf("expected no search after a rejected query, got %d", len(adapter.requests))
+	}
+
+	// Instances without the parse endpoint still pull.
+	adapter.validate = func(context.Context, string) error {
+		return &jira.Error{Code: jira.ErrorCodeUnexpectedStatus, StatusCode: 404, Message: "jira request failed"}
+	}
+	if _, err := pipeline.Execute(context.Background(), "project = PROJ"); err != nil {
+		t.Fatalf("expected pull to skip an unavailable preflight, got %v", err)
+	}
+	if len(adapter.requests) != 1 {
+		t.Fatalf("expected the search to run, got %d requests", len(adapter.requests))
+	}
+}
+
 func TestPipelineMarksUnchangedIssueWithoutRewriting(t *testing.T) {
 	t.Parallel()
 
@@ -120,15 +226,13 @@ func TestPipelineMarksUnchangedIssueWithoutRewriting(t *testing.T) {
 		}, nil
 	}
 
-	now := func() time.Time {
-		return time.Date(2026, time.February, 25, 21, 0, 0, 0, time.UTC)
-	}
+	now := clock.NewFake(time.Date(2026, time.February, 25, 21, 0, 0, 0, time.UTC))
 
 	pipeline := Pipeline{
 		Adapter:   adapter,
 		Store:     issueStore,
-		Converter: NewADFMarkdownConverter(),
-		Now:       now,
+		Converter: NewADFMarkdownConverter(ConverterOptions{}),
+		Clock:     now,
 	}
 
 	first, err := pipeline.Execute(context.Background(), "project = PROJ")
@@ -153,3 +257,482 @@ func TestPipelineMarksUnchangedIssueWithoutRewriting(t *testing.T) {
 		t.Fatalf("expected unchanged action, got %#v", second.Outcomes[0])
 	}
 }
+
+func TestPipelineAppendsStableOrderingOnlyWhenMissing(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"project = PROJ":                        "project = PROJ ORDER BY key ASC",
+		"project = PROJ OR labels = ops":        "project = PROJ OR labels = ops ORDER BY key ASC",
+		`summary ~ "order by"`:                  `summary ~ "order by" ORDER BY key ASC`,
+		"project = PROJ ORDER BY updated DESC":  "project = PROJ ORDER BY updated DESC",
+		"project = PROJ order by priority, key": "project = PROJ order by priority, key",
+	}
+
+	for input, want := range cases {
+		root := t.TempDir()
+		issueStore, err := store.New(filepath.Join(root, contracts.DefaultIssuesRootDir))
+		if err != nil {
+			t.Fatalf("store init failed: %v", err)
+		}
+
+		adapter := &paginationAdapterStub{}
+		adapter.search = func(_ context.Context, _ jira.SearchIssuesRequest) (jira.SearchIssuesResponse, error) {
+			return jira.SearchIssuesResponse{}, nil
+		}
+
+		pipeline := Pipeline{Adapter: adapter, Store: issueStore, Converter: NewADFMarkdownConverter(ConverterOptions{})}
+		if _, err := pipeline.Execute(context.Background(), input); err != nil {
+			t.Fatalf("execute failed for %q: %v", input, err)
+		}
+		if len(adapter.requests) != 1 || adapter.requests[0].JQL != want {
+			t.Fatalf("unexpected JQL for %q: %#v", input, adapter.requests)
+		}
+	}
+}
+
+func TestPipelineDropsDuplicateKeysAcrossPages(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	issueStore, err := store.New(filepath.Join(root, contracts.DefaultIssuesRootDir))
+	if err != nil {
+		t.Fatalf("store init failed: %v", err)
+	}
+
+	shared := jira.Issue{
+		Key: "PROJ-2",
+		Fields: jira.IssueFields{
+			Summary:   "Shifted between pages",
+			Status:    &jira.StatusRef{Name: "Open"},
+			IssueType: &jira.NamedRef{Name: "Task"},
+		},
+	}
+
+	adapter := &paginationAdapterStub{}
+	adapter.search = func(_ context.Context, request jira.SearchIssuesRequest) (jira.SearchIssuesResponse, error) {
+		switch request.StartAt {
+		case 0:
+			return jira.SearchIssuesResponse{
+				StartAt:    0,
+				MaxResults: 2,
+				Total:      4,
+				Issues: []jira.Issue{{
+					Key:    "PROJ-1",
+					Fields: jira.IssueFields{Summary: "First", Status: &jira.StatusRef{Name: "Open"}, IssueType: &jira.NamedRef{Name: "Task"}},
+				}, shared},
+			}, nil
+		case 2:
+			return jira.SearchIssuesResponse{
+				StartAt:    2,
+				MaxResults: 2,
+				Total:      4,
+				Issues: []jira.Issue{shared, {
+					Key:    "PROJ-3",
+					Fields: jira.IssueFields{Summary: "Third", Status: &jira.StatusRef{Name: "Open"}, IssueType: &jira.NamedRef{Name: "Task"}},
+				}},
+			}, nil
+		default:
+			t.Fatalf("unexpected page request: %#v", request)
+			return jira.SearchIssuesResponse{}, nil
+		}
+	}
+
+	pipeline := Pipeline{Adapter: adapter, Store: issueStore, Converter: NewADFMarkdownConverter(ConverterOptions{}), PageSize: 2}
+	result, err := pipeline.Execute(context.Background(), "project = PROJ")
+	if err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+
+	if len(result.Outcomes) != 3 {
+		t.Fatalf("expected 3 outcomes after dedupe, got %#v", result.Outcomes)
+	}
+
+	var duplicate *Outcome
+	for index := range result.Outcomes {
+		if result.Outcomes[index].Key == "PROJ-2" {
+			duplicate = &result.Outcomes[index]
+			continue
+		}
+		if result.Outcomes[index].Status != contracts.PerIssueStatusSuccess {
+			t.Fatalf("expected success for %s, got %#v", result.Outcomes[index].Key, result.Outcomes[index])
+		}
+	}
+	if duplicate == nil {
+		t.Fatalf("expected outcome for PROJ-2, got %#v", result.Outcomes)
+	}
+	if duplicate.Status != contracts.PerIssueStatusWarning || !duplicate.Updated {
+		t.Fatalf("expected updated warning outcome for duplicate key, got %#v", duplicate)
+	}
+
+	foundReason := false
+	for _, message := range duplicate.Messages {
+		if message.ReasonCode == contracts.ReasonCodePullDuplicateDropped {
+			foundReason = true
+		}
+	}
+	if !foundReason {
+		t.Fatalf("expected %s message, got %#v", contracts.ReasonCodePullDuplicateDropped, duplicate.Messages)
+	}
+}
+
+func TestPipelineResumesInterruptedPullFromSavedPage(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	issueStore, err := store.New(filepath.Join(root, contracts.DefaultIssuesRootDir))
+	if err != nil {
+		t.Fatalf("store init failed: %v", err)
+	}
+
+	remote := func(key string) jira.Issue {
+		return jira.Issue{Key: key, Fields: jira.IssueFields{Summary: "Issue " + key, Status: &jira.StatusRef{Name: "Open"}, IssueType: &jira.NamedRef{Name: "Task"}}}
+	}
+	page := func(request jira.SearchIssuesRequest) (jira.SearchIssuesResponse, error) {
+		switch request.StartAt {
+		case 0:
+			return jira.SearchIssuesResponse{StartAt: 0, MaxResults: 2, Total: 4, Issues: []jira.Issue{remote("PROJ-1"), remote("PROJ-2")}}, nil
+		case 2:
+			return jira.SearchIssuesResponse{StartAt: 2, MaxResults: 2, Total: 4, Issues: []jira.Issue{remote("PROJ-3"), remote("PROJ-4")}}, nil
+		default:
+			t.Fatalf("unexpected page request: %#v", request)
+			return jira.SearchIssuesResponse{}, nil
+		}
+	}
+
+	interrupted := &paginationAdapterStub{}
+	interrupted.search = func(_ context.Context, request jira.SearchIssuesRequest) (jira.SearchIssuesResponse, error) {
+		if request.StartAt == 2 {
+			return jira.SearchIssuesResponse{}, fmt.Errorf("connection reset")
+		}
+		return page(request)
+	}
+	pipeline := Pipeline{Adapter: interrupted, Store: issueStore, Converter: NewADFMarkdownConverter(ConverterOptions{}), PageSize: 2}
+	if _, err := pipeline.Execute(context.Background(), "project = PROJ"); err == nil {
+		t.Fatalf("expected interrupted pull to fail")
+	}
+
+	progress, found, err := issueStore.LoadPullProgress()
+	if err != nil || !found {
+		t.Fatalf("expected saved pull progress, found=%v err=%v", found, err)
+	}
+	if progress.StartAt != 2 || len(progress.ProcessedKeys) != 2 {
+		t.Fatalf("unexpected pull progress: %#v", progress)
+	}
+
+	resumed := &paginationAdapterStub{}
+	resumed.search = func(_ context.Context, request jira.SearchIssuesRequest) (jira.SearchIssuesResponse, error) {
+		return page(request)
+	}
+	pipeline.Adapter = resumed
+	result, err := pipeline.Execute(context.Background(), "project = PROJ")
+	if err != nil {
+		t.Fatalf("resumed pull failed: %v", err)
+	}
+
+	if len(resumed.requests) != 1 || resumed.requests[0].StartAt != 2 {
+		t.Fatalf("expected resumed pull to fetch only the unfinished page, got %#v", resumed.requests)
+	}
+	if len(result.Outcomes) != 2 || result.Outcomes[0].Key != "PROJ-3" || result.Outcomes[1].Key != "PROJ-4" {
+		t.Fatalf("expected only the remaining issues, got %#v", result.Outcomes)
+	}
+	for _, key := range []string{"PROJ-1", "PROJ-2", "PROJ-3", "PROJ-4"} {
+		if _, ok := result.Cache.Issues[key]; !ok {
+			t.Fatalf("expected %s in cache after resume, got %#v", key, result.Cache.Issues)
+		}
+	}
+	if _, found, _ := issueStore.LoadPullProgress(); found {
+		t.Fatalf("expected completed pull to clear progress")
+	}
+
+	fresh := &paginationAdapterStub{}
+	fresh.search = func(_ context.Context, request jira.SearchIssuesRequest) (jira.SearchIssuesResponse, error) {
+		return page(request)
+	}
+	pipeline.Adapter = fresh
+	if err := issueStore.SavePullProgress(store.PullProgress{JQL: "project = OTHER ORDER BY key ASC", StartAt: 2}); err != nil {
+		t.Fatalf("seed progress failed: %v", err)
+	}
+	if _, err := pipeline.Execute(context.Background(), "project = PROJ"); err != nil {
+		t.Fatalf("fresh pull failed: %v", err)
+	}
+	if len(fresh.requests) != 2 || fresh.requests[0].StartAt != 0 {
+		t.Fatalf("expected progress for another JQL to be ignored, got %#v", fresh.requests)
+	}
+}
+
+func TestPipelineMapsEnvironmentUnlessSkipped(t *testing.T) {
+	t.Parallel()
+
+	for _, skip := range []bool{false, true} {
+		root := t.TempDir()
+		issuesRoot := filepath.Join(root, contracts.DefaultIssuesRootDir)
+		issueStore, err := store.New(issuesRoot)
+		if err != nil {
+			t.Fatalf("store init failed: %v", err)
+		}
+
+		adapter := &paginationAdapterStub{}
+		adapter.search = func(_ context.Context, _ jira.SearchIssuesRequest) (jira.SearchIssuesResponse, error) {
+			return jira.SearchIssuesResponse{Total: 1, Issues: []jira.Issue{{
+				Key: "PROJ-3",
+				Fields: jira.IssueFields{
+					Summary:     "Crash",
+					Description: json.RawMessage(`{"version":1,"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"steps"}]}]}`),
+					Environment: json.RawMessage(`{"version":1,"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"Firefox 130"}]}]}`),
+					Status:      &jira.StatusRef{Name: "Open"},
+					IssueType:   &jira.NamedRef{Name: "Bug"},
+				},
+			}}}, nil
+		}
+
+		pipeline := Pipeline{Adapter: adapter, Store: issueStore, Converter: NewADFMarkdownConverter(ConverterOptions{}), SkipEnvironment: skip}
+		if _, err := pipeline.Execute(context.Background(), "project = PROJ"); err != nil {
+			t.Fatalf("execute failed (skip=%v): %v", skip, err)
+		}
+
+		content, err := os.ReadFile(filepath.Join(issuesRoot, "open", "PROJ-3-crash.md"))
+		if err != nil {
+			t.Fatalf("read pulled issue failed (skip=%v): %v", skip, err)
+		}
+		doc, err := issue.ParseDocument("PROJ-3-crash.md", string(content))
+		if err != nil {
+			t.Fatalf("parse pulled issue failed (skip=%v): %v", skip, err)
+		}
+		if doc.MarkdownBody != "steps" {
+			t.Fatalf("unexpected description (skip=%v): %q", skip, doc.MarkdownBody)
+		}
+		if skip {
+			if doc.EnvironmentMarkdown != "" || doc.EnvironmentADFJSON != "" {
+				t.Fatalf("expected skipped environment to stay out of the file, got:\n%s", content)
+			}
+			continue
+		}
+		if doc.EnvironmentMarkdown != "Firefox 130" || doc.EnvironmentADFJSON == "" {
+			t.Fatalf("expected environment markdown and raw ADF, got:\n%s", content)
+		}
+	}
+}
+
+func TestPipelineAbortsBeforeWritingWhenSearchTotalExceedsCap(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	issuesRoot := filepath.Join(root, contracts.DefaultIssuesRootDir)
+	issueStore, err := store.New(issuesRoot)
+	if err != nil {
+		t.Fatalf("store init failed: %v", err)
+	}
+
+	adapter := &paginationAdapterStub{}
+	adapter.search = func(_ context.Context, request jira.SearchIssuesRequest) (jira.SearchIssuesResponse, error) {
+		return jira.SearchIssuesResponse{StartAt: request.StartAt, MaxResults: 2, Total: 25000, Issues: []jira.Issue{
+			{Key: "PROJ-1", Fields: jira.IssueFields{Summary: "One", Status: &jira.StatusRef{Name: "Open"}, IssueType: &jira.NamedRef{Name: "Task"}}},
+			{Key: "PROJ-2", Fields: jira.IssueFields{Summary: "Two", Status: &jira.StatusRef{Name: "Open"}, IssueType: &jira.NamedRef{Name: "Task"}}},
+		}}, nil
+	}
+
+	pipeline := Pipeline{Adapter: adapter, Store: issueStore, Converter: NewADFMarkdownConverter(ConverterOptions{}), MaxIssues: 1000}
+	_, err = pipeline.Execute(context.Background(), "project = PROJ")
+	var tooMany *TooManyIssuesError
+	if !errors.As(err, &tooMany) || tooMany.Total != 25000 || tooMany.Limit != 1000 {
+		t.Fatalf("expected TooManyIssuesError for 25000 > 1000, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "narrow the JQL") {
+		t.Fatalf("expected the error to suggest a narrower JQL, got %q", err.Error())
+	}
+	if len(adapter.requests) != 1 {
+		t.Fatalf("expected the pull to stop after the first page, got %d searches", len(adapter.requests))
+	}
+	if entries, _ := os.ReadDir(filepath.Join(issuesRoot, "open")); len(entries) != 0 {
+		t.Fatalf("expected no issue files before the abort, got %d", len(entries))
+	}
+
+	pipeline.MaxIssues = 0
+	adapter.search = func(_ context.Context, _ jira.SearchIssuesRequest) (jira.SearchIssuesResponse, error) {
+		return jira.SearchIssuesResponse{Total: 2, IsLast: true, Issues: []jira.Issue{
+			{Key: "PROJ-1", Fields: jira.IssueFields{Summary: "One", Status: &jira.StatusRef{Name: "Open"}, IssueType: &jira.NamedRef{Name: "Task"}}},
+		}}, nil
+	}
+	if _, err := pipeline.Execute(context.Background(), "project = PROJ"); err != nil {
+		t.Fatalf("expected an uncapped pull to succeed, got %v", err)
+	}
+}
+
+func TestPipelineMapsSecurityLevelName(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	issuesRoot := filepath.Join(root, contracts.DefaultIssuesRootDir)
+	issueStore, err := store.New(issuesRoot)
+	if err != nil {
+		t.Fatalf("store init failed: %v", err)
+	}
+
+	adapter := &paginationAdapterStub{}
+	adapter.search = func(_ context.Context, _ jira.SearchIssuesRequest) (jira.SearchIssuesResponse, error) {
+		return jira.SearchIssuesResponse{Total: 2, Issues: []jira.Issue{
+			{Key: "PROJ-4", Fields: jira.IssueFields{
+				Summary:   "Restricted",
+				Status:    &jira.StatusRef{Name: "Open"},
+				IssueType: &jira.NamedRef{Name: "Bug"},
+				Security:  &jira.NamedRef{ID: "10001", Name: " Internal "},
+			}},
+			{Key: "PROJ-5", Fields: jira.IssueFields{
+				Summary:   "Public",
+				Status:    &jira.StatusRef{Name: "Open"},
+				IssueType: &jira.NamedRef{Name: "Bug"},
+			}},
+		}}, nil
+	}
+
+	pipeline := Pipeline{Adapter: adapter, Store: issueStore, Converter: NewADFMarkdownConverter(ConverterOptions{})}
+	if _, err := pipeline.Execute(context.Background(), "project = PROJ"); err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+
+	for path, want := range map[string]string{"PROJ-4-restricted.md": "Internal", "PROJ-5-public.md": ""} {
+		content, err := os.ReadFile(filepath.Join(issuesRoot, "open", path))
+		if err != nil {
+			t.Fatalf("read %s failed: %v", path, err)
+		}
+		doc, err := issue.ParseDocument(path, string(content))
+		if err != nil {
+			t.Fatalf("parse %s failed: %v", path, err)
+		}
+		if doc.FrontMatter.SecurityLevel != want {
+			t.Fatalf("expected %s security level %q, got:\n%s", path, want, content)
+		}
+		if want == "" && strings.Contains(string(content), "security_level") {
+			t.Fatalf("expected no security_level line without a level, got:\n%s", content)
+		}
+	}
+}
+
+func TestPipelineRenamesIssueMovedToAnotherProject(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	issuesRoot := filepath.Join(root, contracts.DefaultIssuesRootDir)
+	issueStore, err := store.New(issuesRoot)
+	if err != nil {
+		t.Fatalf("store init failed: %v", err)
+	}
+
+	remoteKey := "OLD-1"
+	adapter := &paginationAdapterStub{}
+	adapter.search = func(_ context.Context, _ jira.SearchIssuesRequest) (jira.SearchIssuesResponse, error) {
+		return jira.SearchIssuesResponse{
+			Total: 1,
+			Issues: []jira.Issue{{
+				ID:  "10001",
+				Key: remoteKey,
+				Fields: jira.IssueFields{
+					Summary:   "Move me",
+					Status:    &jira.StatusRef{Name: "Open"},
+					IssueType: &jira.NamedRef{Name: "Task"},
+					UpdatedAt: "2026-02-20T12:00:00Z",
+				},
+			}},
+		}, nil
+	}
+
+	pipeline := Pipeline{
+		Adapter:   adapter,
+		Store:     issueStore,
+		Converter: NewADFMarkdownConverter(ConverterOptions{}),
+		Clock:     clock.NewFake(time.Date(2026, time.February, 25, 21, 0, 0, 0, time.UTC)),
+	}
+	if _, err := pipeline.Execute(context.Background(), "project = OLD"); err != nil {
+		t.Fatalf("first execute failed: %v", err)
+	}
+	oldName, err := issueStore.IssueFilename("OLD-1", "Move me")
+	if err != nil {
+		t.Fatalf("old filename failed: %v", err)
+	}
+
+	remoteKey = "NEW-7"
+	result, err := pipeline.Execute(context.Background(), "project = NEW")
+	if err != nil {
+		t.Fatalf("second execute failed: %v", err)
+	}
+	if len(result.Outcomes) != 1 || result.Outcomes[0].Key != "NEW-7" || result.Outcomes[0].Action != "rename" {
+		t.Fatalf("expected a rename outcome for NEW-7, got %#v", result.Outcomes)
+	}
+
+	newName, err := issueStore.IssueFilename("NEW-7", "Move me")
+	if err != nil {
+		t.Fatalf("new filename failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(issuesRoot, "open", newName)); err != nil {
+		t.Fatalf("expected renamed issue file: %v", err)
+	}
+	for _, stale := range []string{filepath.Join("open", oldName), filepath.Join(".sync", "originals", "OLD-1.md")} {
+		if _, err := os.Stat(filepath.Join(issuesRoot, stale)); !os.IsNotExist(err) {
+			t.Fatalf("expected %s to be removed, stat err=%v", stale, err)
+		}
+	}
+
+	cache, err := issueStore.LoadCache()
+	if err != nil {
+		t.Fatalf("load cache failed: %v", err)
+	}
+	if _, ok := cache.Issues["OLD-1"]; ok {
+		t.Fatalf("expected OLD-1 to leave the cache, got %#v", cache.Issues)
+	}
+	if entry := cache.Issues["NEW-7"]; entry.ID != "10001" || entry.Path != filepath.Join("open", newName) {
+		t.Fatalf("unexpected NEW-7 cache entry: %#v", entry)
+	}
+}
+
+func TestPipelinePullsUnknownADFNodeTypesWithWarning(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	issueStore, err := store.New(filepath.Join(root, contracts.DefaultIssuesRootDir))
+	if err != nil {
+		t.Fatalf("store init failed: %v", err)
+	}
+
+	adapter := &paginationAdapterStub{}
+	adapter.search = func(_ context.Context, _ jira.SearchIssuesRequest) (jira.SearchIssuesResponse, error) {
+		return jira.SearchIssuesResponse{
+			Total: 1,
+			Issues: []jira.Issue{{
+				Key: "PROJ-1",
+				Fields: jira.IssueFields{
+					Summary:     "Newer ADF",
+					Description: json.RawMessage(`{"version":1,"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"kept"}]},{"type":"futureWidget"}]}`),
+					Status:      &jira.StatusRef{Name: "Open"},
+					IssueType:   &jira.NamedRef{Name: "Task"},
+					UpdatedAt:   "2026-02-20T12:00:00Z",
+				},
+			}},
+		}, nil
+	}
+
+	result, err := Pipeline{
+		Adapter:   adapter,
+		Store:     issueStore,
+		Converter: NewADFMarkdownConverter(ConverterOptions{}),
+		Clock:     clock.NewFake(time.Date(2026, time.February, 25, 21, 0, 0, 0, time.UTC)),
+	}.Execute(context.Background(), "project = PROJ")
+	if err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+
+	if len(result.Outcomes) != 1 {
+		t.Fatalf("unexpected outcomes: %#v", result.Outcomes)
+	}
+	outcome := result.Outcomes[0]
+	if outcome.Action != "pull" || !outcome.Updated || outcome.Status != contracts.PerIssueStatusWarning {
+		t.Fatalf("expected a written issue with a warning, got %#v", outcome)
+	}
+	last := outcome.Messages[len(outcome.Messages)-1]
+	if last.ReasonCode != contracts.ReasonCodeADFUnknownNode {
+		t.Fatalf("expected adf_unknown_node warning, got %#v", outcome.Messages)
+	}
+	if _, ok := result.Cache.Issues["PROJ-1"]; !ok {
+		t.Fatalf("expected PROJ-1 to be cached after a successful write")
+	}
+}
@@ -0,0 +1,139 @@
+package pull
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/pweiskircher/jira-issue-sync/internal/clock"
+	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
+	"github.com/pweiskircher/jira-issue-sync/internal/jira"
+)
+
+// RepairSnapshots rebuilds the original snapshots of keys from the remote
+// issues. Working files and the cache are left alone, so local edits stay
+// pending and the next push compares them against the fresh base.
+func (p Pipeline) RepairSnapshots(ctx context.Context, keys []string) (Result, error) {
+	if p.Adapter == nil {
+		return Result{}, fmt.Errorf("pull adapter is not configured")
+	}
+	if p.Store == nil {
+		return Result{}, fmt.Errorf("pull store is not configured")
+	}
+	if p.Converter == nil {
+		return Result{}, fmt.Errorf("pull converter is not configured")
+	}
+
+	fetchFields, settings := p.singleIssueSettings()
+	outcomes := make([]Outcome, 0, len(keys))
+	for _, key := range keys {
+		remote, err := p.Adapter.GetIssue(ctx, key, fetchFields)
+		if err != nil {
+			outcomes = append(outcomes, repairErrorOutcome(key, fetchReason(err), formatIssueError("fetch_issue_failed", err)))
+			continue
+		}
+
+		entry := prepareIssue(remote, settings)
+		if entry.err != nil {
+			outcomes = append(outcomes, repairErrorOutcome(key, entry.reasonCode, formatIssueError(entry.errorCode, entry.err)))
+			continue
+		}
+
+		if p.DryRun {
+			outcomes = append(outcomes, Outcome{
+				Key:    key,
+				Action: "would-repair-snapshot",
+				Status: contracts.PerIssueStatusSkipped,
+				Messages: []contracts.IssueMessage{{
+					Level:      "info",
+					ReasonCode: contracts.ReasonCodeDryRunNoWrite,
+					Text:       "dry-run: would rewrite original snapshot from remote",
+				}},
+			})
+			continue
+		}
+
+		if _, err := p.Store.WriteOriginalSnapshot(entry.key, entry.canonical); err != nil {
+			outcomes = append(outcomes, repairErrorOutcome(key, contracts.ReasonCodeValidationFailed, formatIssueError("write_snapshot_failed", err)))
+			continue
+		}
+		outcomes = append(outcomes, Outcome{
+			Key:     key,
+			Action:  "repair-snapshot",
+			Status:  contracts.PerIssueStatusSuccess,
+			Updated: true,
+			Messages: []contracts.IssueMessage{{
+				Level: "info",
+				Text:  "rewrote original snapshot from remote; working file untouched",
+			}},
+		})
+	}
+
+	return Result{Outcomes: outcomes}, nil
+}
+
+// FetchCanonical fetches one remote issue and renders it exactly as pull
+// would write it, without touching the workspace.
+func (p Pipeline) FetchCanonical(ctx context.Context, key string) (string, error) {
+	if p.Adapter == nil {
+		return "", fmt.Errorf("pull adapter is not configured")
+	}
+	if p.Store == nil {
+		return "", fmt.Errorf("pull store is not configured")
+	}
+	if p.Converter == nil {
+		return "", fmt.Errorf("pull converter is not configured")
+	}
+
+	fetchFields, settings := p.singleIssueSettings()
+	remote, err := p.Adapter.GetIssue(ctx, key, fetchFields)
+	if err != nil {
+		return "", err
+	}
+	entry := prepareIssue(remote, settings)
+	if entry.err != nil {
+		return "", entry.err
+	}
+	return entry.canonical, nil
+}
+
+// singleIssueSettings returns the fields and conversion settings used when
+// issues are fetched one key at a time instead of through a search.
+func (p Pipeline) singleIssueSettings() ([]string, prepareSettings) {
+	fetchFields := p.PullFields
+	if len(fetchFields) == 0 {
+		fetchFields = defaultPullFields
+	}
+	return fetchFields, prepareSettings{
+		syncedAt:           clock.OrSystem(p.Clock).Now().UTC(),
+		converter:          p.Converter,
+		customFieldAliases: p.CustomFieldAliases,
+		skipEnvironment:    p.SkipEnvironment || excludesField(fetchFields, "environment"),
+		skipDescription:    excludesField(fetchFields, "description"),
+		preserveLabelCase:  p.PreserveLabelCase,
+		includeComments:    p.IncludeComments,
+		render:             p.Store.RenderDocument,
+	}
+}
+
+func fetchReason(err error) contracts.ReasonCode {
+	var typed *jira.Error
+	if errors.As(err, &typed) && typed.ReasonCode != "" {
+		return typed.ReasonCode
+	}
+	return contracts.ReasonCodeValidationFailed
+}
+
+func repairErrorOutcome(key string, reasonCode contracts.ReasonCode, text string) Outcome {
+	return Outcome{
+		Key:    strings.TrimSpace(key),
+		Action: "repair-error",
+		Status: contracts.PerIssueStatusError,
+		Messages: []contracts.IssueMessage{{
+			Level:      "error",
+			ReasonCode: reasonCode,
+			Text:       text,
+		}},
+	}
+}
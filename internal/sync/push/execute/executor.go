@@ -19,6 +19,25 @@ type Options struct {
 	Converter           converter.Adapter
 	DryRun              bool
 	TransitionSelection contracts.TransitionSelection
+	// Exclude names writable fields this run must leave untouched; status
+	// suppresses the transition.
+	Exclude map[contracts.JiraField]bool
+	// ReadonlyFields are denied to push by the profile; see
+	// pushplan.IssueInput.
+	ReadonlyFields map[contracts.JiraField]bool
+	// DescriptionRiskPolicy decides whether risky description and
+	// environment edits are blocked, pushed with a warning, or pushed.
+	DescriptionRiskPolicy string
+	// ProjectKey is the profile's project, searched when an assignee has to
+	// be resolved to an accountId.
+	ProjectKey string
+	// AssigneeIsAccountID is the profile's assignee_is_account_id setting.
+	AssigneeIsAccountID bool
+	// PreserveLabelCase is the profile's preserve_label_case setting.
+	PreserveLabelCase bool
+	// TransitionCache, when set, is shared across the issues of one push run
+	// to skip repeated transition lookups.
+	TransitionCache *TransitionCache
 }
 
 type Input struct {
@@ -35,7 +54,7 @@ type Outcome struct {
 }
 
 func ExecuteIssue(ctx context.Context, options Options, input Input) Outcome {
-	planInput, adfPayload, adfReason, adfErr := buildPlanInput(options.Converter, input)
+	planInput, payloads, adfReason, adfErr := buildPlanInput(options.Converter, input)
 	if adfErr != nil {
 		return Outcome{Result: contracts.PerIssueResult{
 			Key:    input.Key,
@@ -49,7 +68,12 @@ func ExecuteIssue(ctx context.Context, options Options, input Input) Outcome {
 		}}
 	}
 
+	planInput.ReadonlyFields = options.ReadonlyFields
+	planInput.DescriptionRiskPolicy = options.DescriptionRiskPolicy
+	planInput.AssigneeIsAccountID = options.AssigneeIsAccountID
+	planInput.PreserveLabelCase = options.PreserveLabelCase
 	plan := pushplan.BuildIssuePlan(planInput)
+	withheld := excludeFields(&plan, options.Exclude) || len(plan.Skipped) > 0
 	messages := messagesFromPlan(plan)
 	result := contracts.PerIssueResult{Key: input.Key, Action: string(plan.Action)}
 
@@ -62,7 +86,7 @@ func ExecuteIssue(ctx context.Context, options Options, input Input) Outcome {
 			result.Status = contracts.PerIssueStatusSkipped
 		}
 		result.Messages = messages
-		return Outcome{Result: result, FullyApplied: result.Status == contracts.PerIssueStatusSkipped}
+		return Outcome{Result: result, FullyApplied: result.Status == contracts.PerIssueStatusSkipped && !withheld}
 	}
 
 	if options.DryRun {
@@ -76,7 +100,19 @@ func ExecuteIssue(ctx context.Context, options Options, input Input) Outcome {
 	}
 
 	remoteUpdated := false
-	if request, hasUpdate := buildUpdateRequest(plan, adfPayload); hasUpdate {
+	request, hasUpdate := buildUpdateRequest(plan, payloads)
+	if request.AssigneeAccountID != nil {
+		accountID, err := jira.ResolveAssignee(ctx, options.Adapter, options.ProjectKey, *request.AssigneeAccountID, options.AssigneeIsAccountID)
+		if err != nil {
+			messages = append(messages, contracts.IssueMessage{Level: "error", ReasonCode: reasonFromError(err), Text: strings.TrimSpace(err.Error())})
+			result.Status = contracts.PerIssueStatusError
+			result.Action = "push-error"
+			result.Messages = messages
+			return Outcome{Result: result}
+		}
+		request.AssigneeAccountID = &accountID
+	}
+	if hasUpdate {
 		if err := options.Adapter.UpdateIssue(ctx, input.Key, request); err != nil {
 			messages = append(messages, contracts.IssueMessage{Level: "error", ReasonCode: reasonFromError(err), Text: "failed to apply issue update: " + strings.TrimSpace(err.Error())})
 			result.Status = contracts.PerIssueStatusError
@@ -88,7 +124,9 @@ func ExecuteIssue(ctx context.Context, options Options, input Input) Outcome {
 	}
 
 	transitionSkipped := false
-	if plan.Transition != nil {
+	if plan.Transition != nil && applyCachedTransition(ctx, options, input, plan.Transition.TargetStatus) {
+		remoteUpdated = true
+	} else if plan.Transition != nil {
 		resolution, err := options.Adapter.ResolveTransition(ctx, input.Key, options.TransitionSelection)
 		if err != nil {
 			messages = append(messages, contracts.IssueMessage{Level: "error", ReasonCode: reasonFromError(err), Text: "failed to resolve transition: " + strings.TrimSpace(err.Error())})
@@ -97,6 +135,7 @@ func ExecuteIssue(ctx context.Context, options Options, input Input) Outcome {
 			result.Messages = messages
 			return Outcome{Result: result, RemoteUpdated: remoteUpdated}
 		}
+		options.TransitionCache.remember(input.Key, input.Remote.FrontMatter.IssueType, plan.Transition.TargetStatus, resolution)
 
 		switch resolution.Kind {
 		case jira.TransitionResolutionSelected:
@@ -127,25 +166,71 @@ func ExecuteIssue(ctx context.Context, options Options, input Input) Outcome {
 		result.Action = "updated"
 	}
 
-	fullyApplied := result.Status == contracts.PerIssueStatusSuccess && plan.Action == pushplan.ActionUpdate
+	fullyApplied := result.Status == contracts.PerIssueStatusSuccess && plan.Action == pushplan.ActionUpdate && !withheld
+	if result.Status == contracts.PerIssueStatusSuccess && plan.HasWarnedRisk() {
+		result.Status = contracts.PerIssueStatusWarning
+	}
 	return Outcome{Result: result, RemoteUpdated: remoteUpdated, FullyApplied: fullyApplied}
 }
 
-func buildPlanInput(markdownConverter converter.Adapter, input Input) (pushplan.IssueInput, *json.RawMessage, contracts.ReasonCode, error) {
+// applyCachedTransition applies a transition cached for the same project,
+// issue type, and target status. A cached transition that fails to apply is forgotten and
+// reported as not applied, so the caller falls back to a fresh resolution.
+func applyCachedTransition(ctx context.Context, options Options, input Input, targetStatus string) bool {
+	issueType := input.Remote.FrontMatter.IssueType
+	transition, ok := options.TransitionCache.lookup(input.Key, issueType, targetStatus)
+	if !ok {
+		return false
+	}
+	if err := options.Adapter.ApplyTransition(ctx, input.Key, transition.ID); err != nil {
+		options.TransitionCache.forget(input.Key, issueType, targetStatus)
+		return false
+	}
+	return true
+}
+
+// adfPayloads holds the converted ADF for each ADF-backed field; nil means
+// the local markdown converted to an empty document.
+type adfPayloads struct {
+	description *json.RawMessage
+	environment *json.RawMessage
+}
+
+func buildPlanInput(markdownConverter converter.Adapter, input Input) (pushplan.IssueInput, adfPayloads, contracts.ReasonCode, error) {
+	descriptionRisk, descriptionPayload, reason, err := convertLocalADF(markdownConverter, input.Local.MarkdownBody, input.Local.RawADFJSON)
+	if err != nil {
+		return pushplan.IssueInput{}, adfPayloads{}, reason, fmt.Errorf("failed to convert markdown description to adf: %w", err)
+	}
+	environmentRisk, environmentPayload, reason, err := convertLocalADF(markdownConverter, input.Local.EnvironmentMarkdown, input.Local.EnvironmentADFJSON)
+	if err != nil {
+		return pushplan.IssueInput{}, adfPayloads{}, reason, fmt.Errorf("failed to convert markdown environment to adf: %w", err)
+	}
+
+	planInput := pushplan.IssueInput{
+		Local:           input.Local,
+		Original:        &input.Original,
+		Remote:          input.Remote,
+		DescriptionRisk: descriptionRisk,
+		EnvironmentRisk: environmentRisk,
+	}
+	return planInput, adfPayloads{description: descriptionPayload, environment: environmentPayload}, "", nil
+}
+
+func convertLocalADF(markdownConverter converter.Adapter, markdown string, rawADF string) (pushplan.DescriptionRiskInput, *json.RawMessage, contracts.ReasonCode, error) {
 	rawState := pushplan.RawADFStateValid
-	if strings.TrimSpace(input.Local.RawADFJSON) == "" {
+	if strings.TrimSpace(rawADF) == "" {
 		rawState = pushplan.RawADFStateMissing
-	} else if _, err := converter.ValidateAndCanonicalizeRawADF(input.Local.RawADFJSON); err != nil {
+	} else if _, err := converter.ValidateAndCanonicalizeRawADF(rawADF); err != nil {
 		rawState = pushplan.RawADFStateMalformed
 	}
 
-	adfResult, err := markdownConverter.ToADF(input.Local.MarkdownBody)
+	adfResult, err := markdownConverter.ToADF(markdown)
 	if err != nil {
 		reason := contracts.ReasonCodeValidationFailed
 		if typed := asConverterError(err); typed != nil && typed.ReasonCode != "" {
 			reason = typed.ReasonCode
 		}
-		return pushplan.IssueInput{}, nil, reason, fmt.Errorf("failed to convert markdown description to adf: %w", err)
+		return pushplan.DescriptionRiskInput{}, nil, reason, err
 	}
 
 	trimmedADF := strings.TrimSpace(adfResult.ADFJSON)
@@ -154,20 +239,76 @@ func buildPlanInput(markdownConverter converter.Adapter, input Input) (pushplan.
 		asRaw := json.RawMessage(trimmedADF)
 		payload = &asRaw
 	}
+	return pushplan.DescriptionRiskInput{ConverterRisks: adfResult.Risks, LocalRawADF: rawState}, payload, "", nil
+}
 
-	planInput := pushplan.IssueInput{
-		Local:    input.Local,
-		Original: &input.Original,
-		Remote:   input.Remote,
-		DescriptionRisk: pushplan.DescriptionRiskInput{
-			ConverterRisks: adfResult.Risks,
-			LocalRawADF:    rawState,
-		},
-	}
-	return planInput, payload, "", nil
+// excludeFields drops excluded fields from the plan, including their conflicts
+// and blocks, and reports whether anything was withheld. A withheld change
+// keeps the original snapshot stale so a later push still sees it.
+func excludeFields(plan *pushplan.IssuePlan, excluded map[contracts.JiraField]bool) bool {
+	if len(excluded) == 0 {
+		return false
+	}
+
+	withheld := false
+	drop := func(field contracts.JiraField, pending bool) bool {
+		if excluded[field] && pending {
+			withheld = true
+			return true
+		}
+		return false
+	}
+	if drop(contracts.JiraFieldSummary, plan.Updates.Summary != nil) {
+		plan.Updates.Summary = nil
+	}
+	if drop(contracts.JiraFieldDescription, plan.Updates.Description != nil) {
+		plan.Updates.Description = nil
+	}
+	if drop(contracts.JiraFieldLabels, plan.Updates.Labels != nil) {
+		plan.Updates.Labels = nil
+	}
+	if drop(contracts.JiraFieldAssignee, plan.Updates.Assignee != nil) {
+		plan.Updates.Assignee = nil
+	}
+	if drop(contracts.JiraFieldPriority, plan.Updates.Priority != nil) {
+		plan.Updates.Priority = nil
+	}
+	if drop(contracts.JiraFieldEnvironment, plan.Updates.Environment != nil) {
+		plan.Updates.Environment = nil
+	}
+	if drop(contracts.JiraFieldStatus, plan.Transition != nil) {
+		plan.Transition = nil
+	}
+
+	conflicts := plan.Conflicts[:0]
+	for _, fieldConflict := range plan.Conflicts {
+		if !drop(fieldConflict.Field, true) {
+			conflicts = append(conflicts, fieldConflict)
+		}
+	}
+	plan.Conflicts = conflicts
+
+	blocked := plan.Blocked[:0]
+	for _, blockedField := range plan.Blocked {
+		if !drop(blockedField.Field, true) {
+			blocked = append(blocked, blockedField)
+		}
+	}
+	plan.Blocked = blocked
+
+	accepted := plan.Accepted[:0]
+	for _, risk := range plan.Accepted {
+		if !excluded[risk.Field] {
+			accepted = append(accepted, risk)
+		}
+	}
+	plan.Accepted = accepted
+
+	plan.Action = pushplan.ResolveAction(*plan)
+	return withheld
 }
 
-func buildUpdateRequest(plan pushplan.IssuePlan, descriptionPayload *json.RawMessage) (jira.UpdateIssueRequest, bool) {
+func buildUpdateRequest(plan pushplan.IssuePlan, payloads adfPayloads) (jira.UpdateIssueRequest, bool) {
 	request := jira.UpdateIssueRequest{
 		Summary:      plan.Updates.Summary,
 		Labels:       plan.Updates.Labels,
@@ -177,10 +318,17 @@ func buildUpdateRequest(plan pushplan.IssuePlan, descriptionPayload *json.RawMes
 		request.AssigneeAccountID = plan.Updates.Assignee
 	}
 	if plan.Updates.Description != nil {
-		request.Description = descriptionPayload
+		request.Description = payloads.description
+	}
+	if plan.Updates.Environment != nil {
+		// An empty payload clears the field remotely.
+		request.Environment = payloads.environment
+		if request.Environment == nil {
+			request.Environment = &json.RawMessage{}
+		}
 	}
 
-	hasUpdate := request.Summary != nil || request.Description != nil || request.Labels != nil || request.AssigneeAccountID != nil || request.PriorityName != nil
+	hasUpdate := request.Summary != nil || request.Description != nil || request.Labels != nil || request.AssigneeAccountID != nil || request.PriorityName != nil || request.Environment != nil
 	return request, hasUpdate
 }
 
@@ -201,7 +349,7 @@ func statusFromPlan(plan pushplan.IssuePlan) contracts.PerIssueStatus {
 }
 
 func messagesFromPlan(plan pushplan.IssuePlan) []contracts.IssueMessage {
-	messages := make([]contracts.IssueMessage, 0, len(plan.Conflicts)+len(plan.Blocked))
+	messages := make([]contracts.IssueMessage, 0, len(plan.Conflicts)+len(plan.Blocked)+len(plan.Skipped))
 	for _, conflict := range plan.Conflicts {
 		messages = append(messages, contracts.IssueMessage{Level: "error", ReasonCode: conflict.ReasonCode, Text: strings.TrimSpace(conflict.Message)})
 	}
@@ -212,6 +360,20 @@ func messagesFromPlan(plan pushplan.IssuePlan) []contracts.IssueMessage {
 		}
 		messages = append(messages, contracts.IssueMessage{Level: "warning", ReasonCode: reasonCode, Text: strings.TrimSpace(blocked.Message)})
 	}
+	for _, skipped := range plan.Skipped {
+		level := "info"
+		if skipped.Warning {
+			level = "warning"
+		}
+		messages = append(messages, contracts.IssueMessage{Level: level, ReasonCode: skipped.ReasonCode, Text: strings.TrimSpace(skipped.Message)})
+	}
+	for _, accepted := range plan.Accepted {
+		level := "info"
+		if accepted.Policy == contracts.DescriptionRiskPolicyWarn {
+			level = "warning"
+		}
+		messages = append(messages, contracts.IssueMessage{Level: level, ReasonCode: accepted.ReasonCodes[0], Text: strings.TrimSpace(accepted.Message)})
+	}
 	return messages
 }
 
@@ -0,0 +1,64 @@
+package execute
+
+import (
+	"strings"
+
+	"github.com/pweiskircher/jira-issue-sync/internal/jira"
+)
+
+// TransitionCache remembers the transition selected for a project, issue
+// type, and target status during one push run. Transition IDs belong to a
+// workflow, which can differ between projects, so entries never cross
+// projects. Jira transitions are per issue, so an entry is only a guess: it
+// is dropped when a fresh resolution comes back ambiguous or unavailable,
+// or when applying it fails. A nil cache is valid and never hits.
+type TransitionCache struct {
+	entries map[transitionCacheKey]jira.Transition
+}
+
+type transitionCacheKey struct {
+	project      string
+	issueType    string
+	targetStatus string
+}
+
+func NewTransitionCache() *TransitionCache {
+	return &TransitionCache{entries: make(map[transitionCacheKey]jira.Transition)}
+}
+
+// newTransitionCacheKey keys by the issue key's project prefix, so PROJ-1
+// and OPS-1 never share a cached transition.
+func newTransitionCacheKey(issueKey string, issueType string, targetStatus string) transitionCacheKey {
+	project, _, _ := strings.Cut(strings.TrimSpace(issueKey), "-")
+	return transitionCacheKey{
+		project:      strings.ToUpper(project),
+		issueType:    strings.ToLower(strings.TrimSpace(issueType)),
+		targetStatus: strings.ToLower(strings.TrimSpace(targetStatus)),
+	}
+}
+
+func (c *TransitionCache) lookup(issueKey string, issueType string, targetStatus string) (jira.Transition, bool) {
+	if c == nil {
+		return jira.Transition{}, false
+	}
+	transition, ok := c.entries[newTransitionCacheKey(issueKey, issueType, targetStatus)]
+	return transition, ok
+}
+
+func (c *TransitionCache) remember(issueKey string, issueType string, targetStatus string, resolution jira.TransitionResolution) {
+	if c == nil {
+		return
+	}
+	if resolution.Kind != jira.TransitionResolutionSelected || strings.TrimSpace(resolution.Transition.ID) == "" {
+		c.forget(issueKey, issueType, targetStatus)
+		return
+	}
+	c.entries[newTransitionCacheKey(issueKey, issueType, targetStatus)] = resolution.Transition
+}
+
+func (c *TransitionCache) forget(issueKey string, issueType string, targetStatus string) {
+	if c == nil {
+		return
+	}
+	delete(c.entries, newTransitionCacheKey(issueKey, issueType, targetStatus))
+}
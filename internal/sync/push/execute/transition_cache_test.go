@@ -0,0 +1,77 @@
+package execute
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
+	"github.com/pweiskircher/jira-issue-sync/internal/issue"
+	"github.com/pweiskircher/jira-issue-sync/internal/jira"
+	pullsync "github.com/pweiskircher/jira-issue-sync/internal/sync/pull"
+)
+
+// transitionAdapterStub resolves transitions from a per-issue table and
+// records every transition applied. Other Adapter methods are not used.
+type transitionAdapterStub struct {
+	jira.Adapter
+	transitions map[string]jira.Transition
+	resolved    []string
+	applied     map[string]string
+}
+
+func (s *transitionAdapterStub) ResolveTransition(_ context.Context, issueKey string, _ contracts.TransitionSelection) (jira.TransitionResolution, error) {
+	s.resolved = append(s.resolved, issueKey)
+	return jira.TransitionResolution{Kind: jira.TransitionResolutionSelected, Transition: s.transitions[issueKey]}, nil
+}
+
+func (s *transitionAdapterStub) ApplyTransition(_ context.Context, issueKey string, transitionID string) error {
+	s.applied[issueKey] = transitionID
+	return nil
+}
+
+func TestTransitionCacheDoesNotCrossProjects(t *testing.T) {
+	t.Parallel()
+
+	adapter := &transitionAdapterStub{
+		transitions: map[string]jira.Transition{
+			"PROJ-1": {ID: "31", ToStatusName: "Done"},
+			"PROJ-2": {ID: "31", ToStatusName: "Done"},
+			"OPS-1":  {ID: "51", ToStatusName: "Done"},
+		},
+		applied: map[string]string{},
+	}
+	options := Options{
+		Adapter:         adapter,
+		Converter:       pullsync.NewADFMarkdownConverter(pullsync.ConverterOptions{}),
+		TransitionCache: NewTransitionCache(),
+	}
+
+	for _, key := range []string{"PROJ-1", "OPS-1", "PROJ-2"} {
+		outcome := ExecuteIssue(context.Background(), options, statusChangeInput(key, "To Do", "Done"))
+		if outcome.Result.Status != contracts.PerIssueStatusSuccess {
+			t.Fatalf("expected %s to transition, got %#v", key, outcome.Result)
+		}
+	}
+
+	if len(adapter.resolved) != 2 || adapter.resolved[0] != "PROJ-1" || adapter.resolved[1] != "OPS-1" {
+		t.Fatalf("expected one resolution per project, got %v", adapter.resolved)
+	}
+	if adapter.applied["OPS-1"] != "51" {
+		t.Fatalf("expected OPS-1 to use its own workflow's transition, got %q", adapter.applied["OPS-1"])
+	}
+	if adapter.applied["PROJ-2"] != "31" {
+		t.Fatalf("expected PROJ-2 to reuse the PROJ transition, got %q", adapter.applied["PROJ-2"])
+	}
+}
+
+func statusChangeInput(key string, remoteStatus string, localStatus string) Input {
+	document := func(status string) issue.Document {
+		return issue.Document{
+			CanonicalKey: key,
+			FrontMatter:  issue.FrontMatter{SchemaVersion: contracts.IssueFileSchemaVersionV1, Key: key, Summary: "Same", IssueType: "Task", Status: status},
+			MarkdownBody: "body",
+			RawADFJSON:   `{"version":1,"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"body"}]}]}`,
+		}
+	}
+	return Input{Key: key, Local: document(localStatus), Original: document(remoteStatus), Remote: document(remoteStatus)}
+}
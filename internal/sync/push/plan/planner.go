@@ -3,7 +3,9 @@ package plan
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/pweiskircher/jira-issue-sync/internal/conflict"
 	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
@@ -17,6 +19,16 @@ var writableFieldOrder = []contracts.JiraField{
 	contracts.JiraFieldAssignee,
 	contracts.JiraFieldPriority,
 	contracts.JiraFieldStatus,
+	contracts.JiraFieldEnvironment,
+}
+
+// readonlyMetadataFields are front matter fields Jira owns. push never
+// sends them, so a local edit is reported instead of silently dropped.
+var readonlyMetadataFields = []contracts.JiraField{
+	contracts.JiraFieldIssueType,
+	contracts.JiraFieldReporter,
+	contracts.JiraFieldSecurityLevel,
+	contracts.JiraFieldCreatedAt,
 }
 
 type normalizedWritableFields struct {
@@ -26,6 +38,7 @@ type normalizedWritableFields struct {
 	Assignee    string
 	Priority    string
 	Status      string
+	Environment string
 }
 
 // BuildIssuePlan creates a deterministic per-issue push plan.
@@ -38,7 +51,7 @@ func BuildIssuePlan(input IssueInput) IssuePlan {
 			Message:    "original snapshot is required for three-way planning",
 		})
 		plan.Reasons = appendUniqueReasonCode(plan.Reasons, contracts.ReasonCodeConflictBaseSnapshotMissing)
-		plan.Action = resolveAction(plan)
+		plan.Action = ResolveAction(plan)
 		return plan
 	}
 
@@ -48,36 +61,47 @@ func BuildIssuePlan(input IssueInput) IssuePlan {
 			Message:     message,
 		})
 		plan.Reasons = appendUniqueReasonCode(plan.Reasons, reasonCode)
-		plan.Action = resolveAction(plan)
+		plan.Action = ResolveAction(plan)
 		return plan
 	}
 
-	local := normalizeWritableFields(input.Local)
-	base := normalizeWritableFields(*input.Original)
-	remote := normalizeWritableFields(input.Remote)
+	local := normalizeWritableFields(input.Local, input.PreserveLabelCase)
+	base := normalizeWritableFields(*input.Original, input.PreserveLabelCase)
+	remote := normalizeWritableFields(input.Remote, input.PreserveLabelCase)
 
 	for _, field := range writableFieldOrder {
+		if input.ReadonlyFields[field] {
+			skipReadonlyField(&plan, field, base, local)
+			continue
+		}
+
 		switch field {
 		case contracts.JiraFieldSummary:
 			comparison := conflict.CompareComparable(base.Summary, local.Summary, remote.Summary)
 			applyFieldComparison(&plan, field, comparison, func() {
+				if blockOverlongSummary(&plan, local.Summary) {
+					return
+				}
 				value := local.Summary
 				plan.Updates.Summary = &value
 			})
 		case contracts.JiraFieldDescription:
-			comparison := conflict.CompareComparable(base.Description, local.Description, remote.Description)
-			applyDescriptionComparison(&plan, comparison, local.Description, strings.TrimSpace(input.Original.RawADFJSON) != "", input.DescriptionRisk)
+			comparison := conflict.Compare(base.Description, local.Description, remote.Description, markdownContentEqual)
+			applyADFFieldComparison(&plan, field, comparison, strings.TrimSpace(input.Original.RawADFJSON) != "", input.DescriptionRisk, input.DescriptionRiskPolicy, func() {
+				value := local.Description
+				plan.Updates.Description = &value
+			})
 		case contracts.JiraFieldLabels:
 			comparison := conflict.Compare(base.Labels, local.Labels, remote.Labels, func(left, right []string) bool {
 				return reflect.DeepEqual(left, right)
 			})
-			applyFieldComparison(&plan, field, comparison, func() {
-				value := append([]string(nil), local.Labels...)
-				plan.Updates.Labels = &value
-			})
+			applyLabelComparison(&plan, comparison, base.Labels, local.Labels, remote.Labels)
 		case contracts.JiraFieldAssignee:
 			comparison := conflict.CompareComparable(base.Assignee, local.Assignee, remote.Assignee)
 			applyFieldComparison(&plan, field, comparison, func() {
+				if blockImplausibleAccountID(&plan, local.Assignee, input.AssigneeIsAccountID) {
+					return
+				}
 				value := local.Assignee
 				plan.Updates.Assignee = &value
 			})
@@ -87,6 +111,12 @@ func BuildIssuePlan(input IssueInput) IssuePlan {
 				value := local.Priority
 				plan.Updates.Priority = &value
 			})
+		case contracts.JiraFieldEnvironment:
+			comparison := conflict.Compare(base.Environment, local.Environment, remote.Environment, markdownContentEqual)
+			applyADFFieldComparison(&plan, field, comparison, strings.TrimSpace(input.Original.EnvironmentADFJSON) != "", input.EnvironmentRisk, input.DescriptionRiskPolicy, func() {
+				value := local.Environment
+				plan.Updates.Environment = &value
+			})
 		case contracts.JiraFieldStatus:
 			comparison := conflict.CompareComparable(base.Status, local.Status, remote.Status)
 			applyFieldComparison(&plan, field, comparison, func() {
@@ -94,11 +124,102 @@ func BuildIssuePlan(input IssueInput) IssuePlan {
 			})
 		}
 	}
+	reportReadonlyMetadataEdits(&plan, input.Original.FrontMatter, input.Local.FrontMatter)
+	if strings.TrimSpace(input.Original.Comments) != strings.TrimSpace(input.Local.Comments) {
+		plan.Skipped = append(plan.Skipped, SkippedField{
+			Field:      contracts.JiraFieldComments,
+			ReasonCode: contracts.DefaultUnsupportedFieldReasonCode,
+			Message:    "the comments section is read-only; local edits to it will not be synced",
+			Warning:    true,
+		})
+		plan.Reasons = appendUniqueReasonCode(plan.Reasons, contracts.DefaultUnsupportedFieldReasonCode)
+	}
 
-	plan.Action = resolveAction(plan)
+	plan.Action = ResolveAction(plan)
 	return plan
 }
 
+// reportReadonlyMetadataEdits warns about local edits to fields push cannot
+// write, following their warn_and_ignore policy.
+func reportReadonlyMetadataEdits(plan *IssuePlan, base issue.FrontMatter, local issue.FrontMatter) {
+	for _, field := range readonlyMetadataFields {
+		before, after := readonlyMetadataValue(base, field), readonlyMetadataValue(local, field)
+		if before == after {
+			continue
+		}
+		plan.Skipped = append(plan.Skipped, SkippedField{
+			Field:      field,
+			ReasonCode: contracts.DefaultUnsupportedFieldReasonCode,
+			Message:    fmt.Sprintf("field %q is read-only in Jira; local change from %q to %q will not be synced", field, before, after),
+			Warning:    true,
+		})
+		plan.Reasons = appendUniqueReasonCode(plan.Reasons, contracts.DefaultUnsupportedFieldReasonCode)
+	}
+}
+
+func readonlyMetadataValue(frontMatter issue.FrontMatter, field contracts.JiraField) string {
+	switch field {
+	case contracts.JiraFieldIssueType:
+		return strings.TrimSpace(frontMatter.IssueType)
+	case contracts.JiraFieldReporter:
+		return strings.TrimSpace(frontMatter.Reporter)
+	case contracts.JiraFieldSecurityLevel:
+		return strings.TrimSpace(frontMatter.SecurityLevel)
+	case contracts.JiraFieldCreatedAt:
+		return strings.TrimSpace(frontMatter.CreatedAt)
+	}
+	return ""
+}
+
+// skipReadonlyField records a local edit to a field the profile denies to
+// push. Unchanged read-only fields are skipped silently.
+func skipReadonlyField(plan *IssuePlan, field contracts.JiraField, base normalizedWritableFields, local normalizedWritableFields) {
+	changed := false
+	switch field {
+	case contracts.JiraFieldSummary:
+		changed = base.Summary != local.Summary
+	case contracts.JiraFieldDescription:
+		changed = !markdownContentEqual(base.Description, local.Description)
+	case contracts.JiraFieldLabels:
+		changed = !reflect.DeepEqual(base.Labels, local.Labels)
+	case contracts.JiraFieldAssignee:
+		changed = base.Assignee != local.Assignee
+	case contracts.JiraFieldPriority:
+		changed = base.Priority != local.Priority
+	case contracts.JiraFieldStatus:
+		changed = base.Status != local.Status
+	case contracts.JiraFieldEnvironment:
+		changed = !markdownContentEqual(base.Environment, local.Environment)
+	}
+	if !changed {
+		return
+	}
+
+	plan.Skipped = append(plan.Skipped, SkippedField{
+		Field:      field,
+		ReasonCode: contracts.ReasonCodeFieldReadonlySkipped,
+		Message:    fmt.Sprintf("field %q is read-only for this profile; local change was not pushed", field),
+	})
+	plan.Reasons = appendUniqueReasonCode(plan.Reasons, contracts.ReasonCodeFieldReadonlySkipped)
+}
+
+// blockOverlongSummary blocks a summary Jira would reject with an opaque 400,
+// so the other field updates still go out.
+func blockOverlongSummary(plan *IssuePlan, summary string) bool {
+	length := utf8.RuneCountInString(summary)
+	if length <= contracts.MaxSummaryLength {
+		return false
+	}
+
+	plan.Blocked = append(plan.Blocked, BlockedField{
+		Field:       contracts.JiraFieldSummary,
+		ReasonCodes: []contracts.ReasonCode{contracts.ReasonCodeSummaryTooLong},
+		Message:     fmt.Sprintf("summary is %d characters; Jira accepts at most %d", length, contracts.MaxSummaryLength),
+	})
+	plan.Reasons = appendUniqueReasonCode(plan.Reasons, contracts.ReasonCodeSummaryTooLong)
+	return true
+}
+
 func applyFieldComparison[T any](plan *IssuePlan, field contracts.JiraField, comparison conflict.Comparison[T], applyLocalChange func()) {
 	if plan == nil {
 		return
@@ -117,12 +238,124 @@ func applyFieldComparison[T any](plan *IssuePlan, field contracts.JiraField, com
 	}
 }
 
-func applyDescriptionComparison(
+// blockImplausibleAccountID blocks an assignee given as a literal accountId
+// that cannot be one, such as an email, before Jira is asked to assign it.
+func blockImplausibleAccountID(plan *IssuePlan, assignee string, valuesAreAccountIDs bool) bool {
+	accountID, literal := contracts.ParseAssigneeAccountID(assignee, valuesAreAccountIDs)
+	if !literal || contracts.IsPlausibleAccountID(accountID) {
+		return false
+	}
+
+	plan.Blocked = append(plan.Blocked, BlockedField{
+		Field:       contracts.JiraFieldAssignee,
+		ReasonCodes: []contracts.ReasonCode{contracts.ReasonCodeValidationFailed},
+		Message:     fmt.Sprintf("assignee %q is not a valid Jira accountId", assignee),
+	})
+	plan.Reasons = appendUniqueReasonCode(plan.Reasons, contracts.ReasonCodeValidationFailed)
+	return true
+}
+
+// applyLabelComparison plans label updates like any other field, but spells
+// out conflicts: edits to disjoint labels on each side are called out as
+// orthogonal (re-pull and push again to keep both) rather than lumped in with
+// both sides editing the same label.
+func applyLabelComparison(plan *IssuePlan, comparison conflict.Comparison[[]string], base []string, local []string, remote []string) {
+	if plan == nil {
+		return
+	}
+
+	switch comparison.Outcome {
+	case conflict.OutcomeLocalChanged:
+		value := append([]string(nil), local...)
+		plan.Updates.Labels = &value
+	case conflict.OutcomeConflict:
+		localAdded, localRemoved := LabelDelta(base, local)
+		remoteAdded, remoteRemoved := LabelDelta(base, remote)
+		changes := fmt.Sprintf("local %s; remote %s", describeLabelDelta(localAdded, localRemoved), describeLabelDelta(remoteAdded, remoteRemoved))
+
+		message := fmt.Sprintf("labels changed independently on both sides (%s); pull to take the remote labels, re-apply the local change, and push again", changes)
+		if overlap := overlappingLabels(append(localAdded, localRemoved...), append(remoteAdded, remoteRemoved...)); len(overlap) > 0 {
+			message = fmt.Sprintf("labels %s were changed on both sides (%s); resolve the label set manually", strings.Join(overlap, ", "), changes)
+		}
+
+		plan.Conflicts = append(plan.Conflicts, FieldConflict{
+			Field:      contracts.JiraFieldLabels,
+			ReasonCode: contracts.ReasonCodeConflictFieldChangedBoth,
+			Message:    message,
+		})
+		plan.Reasons = appendUniqueReasonCode(plan.Reasons, contracts.ReasonCodeConflictFieldChangedBoth)
+	}
+}
+
+// LabelDelta returns the labels present in current but not base (added) and
+// those present in base but not current (removed), in input order. Callers
+// pass labels already normalized with contracts.NormalizeLabels or
+// contracts.NormalizeLabelsPreservingCase.
+func LabelDelta(base []string, current []string) ([]string, []string) {
+	baseSet := make(map[string]struct{}, len(base))
+	for _, label := range base {
+		baseSet[label] = struct{}{}
+	}
+	currentSet := make(map[string]struct{}, len(current))
+	for _, label := range current {
+		currentSet[label] = struct{}{}
+	}
+
+	added := make([]string, 0)
+	for _, label := range current {
+		if _, ok := baseSet[label]; !ok {
+			added = append(added, label)
+		}
+	}
+	removed := make([]string, 0)
+	for _, label := range base {
+		if _, ok := currentSet[label]; !ok {
+			removed = append(removed, label)
+		}
+	}
+	return added, removed
+}
+
+func describeLabelDelta(added []string, removed []string) string {
+	parts := make([]string, 0, 2)
+	if len(added) > 0 {
+		parts = append(parts, "added "+strings.Join(added, ", "))
+	}
+	if len(removed) > 0 {
+		parts = append(parts, "removed "+strings.Join(removed, ", "))
+	}
+	if len(parts) == 0 {
+		return "unchanged"
+	}
+	return strings.Join(parts, " and ")
+}
+
+func overlappingLabels(left []string, right []string) []string {
+	rightSet := make(map[string]struct{}, len(right))
+	for _, label := range right {
+		rightSet[label] = struct{}{}
+	}
+	overlap := make([]string, 0)
+	for _, label := range left {
+		if _, ok := rightSet[label]; ok {
+			overlap = append(overlap, label)
+		}
+	}
+	sort.Strings(overlap)
+	return overlap
+}
+
+// applyADFFieldComparison plans an ADF-backed field (description or
+// environment). When conversion back to ADF is risky, the block policy
+// blocks the local edit; warn and allow plan it and record the accepted risk.
+func applyADFFieldComparison(
 	plan *IssuePlan,
+	field contracts.JiraField,
 	comparison conflict.Comparison[string],
-	localDescription string,
 	hadBaselineRawADF bool,
-	descriptionRiskInput DescriptionRiskInput,
+	riskInput DescriptionRiskInput,
+	riskPolicy string,
+	applyLocalChange func(),
 ) {
 	if plan == nil {
 		return
@@ -130,15 +363,28 @@ func applyDescriptionComparison(
 
 	switch comparison.Outcome {
 	case conflict.OutcomeLocalChanged:
-		riskReasonCodes := classifyDescriptionRisk(hadBaselineRawADF, descriptionRiskInput)
+		riskReasonCodes := classifyDescriptionRisk(hadBaselineRawADF, riskInput)
+		if len(riskReasonCodes) > 0 && (riskPolicy == contracts.DescriptionRiskPolicyWarn || riskPolicy == contracts.DescriptionRiskPolicyAllow) {
+			plan.Accepted = append(plan.Accepted, AcceptedRisk{
+				Field:       field,
+				Policy:      riskPolicy,
+				ReasonCodes: riskReasonCodes,
+				Message:     fmt.Sprintf("%s was pushed despite conversion risk (description_risk_policy=%s)", field, riskPolicy),
+			})
+			for _, reasonCode := range riskReasonCodes {
+				plan.Reasons = appendUniqueReasonCode(plan.Reasons, reasonCode)
+			}
+			applyLocalChange()
+			return
+		}
 		if len(riskReasonCodes) > 0 {
 			reasonCodes := make([]contracts.ReasonCode, 0, len(riskReasonCodes)+1)
 			reasonCodes = append(reasonCodes, contracts.ReasonCodeDescriptionRiskyBlocked)
 			reasonCodes = append(reasonCodes, riskReasonCodes...)
 			plan.Blocked = append(plan.Blocked, BlockedField{
-				Field:       contracts.JiraFieldDescription,
+				Field:       field,
 				ReasonCodes: reasonCodes,
-				Message:     "description update was blocked because conversion risk was detected",
+				Message:     string(field) + " update was blocked because conversion risk was detected",
 			})
 			for _, reasonCode := range reasonCodes {
 				plan.Reasons = appendUniqueReasonCode(plan.Reasons, reasonCode)
@@ -146,13 +392,12 @@ func applyDescriptionComparison(
 			return
 		}
 
-		value := localDescription
-		plan.Updates.Description = &value
+		applyLocalChange()
 	case conflict.OutcomeConflict:
 		plan.Conflicts = append(plan.Conflicts, FieldConflict{
-			Field:      contracts.JiraFieldDescription,
+			Field:      field,
 			ReasonCode: contracts.ReasonCodeConflictFieldChangedBoth,
-			Message:    "field \"description\" changed both locally and remotely",
+			Message:    fmt.Sprintf("field %q changed both locally and remotely", field),
 		})
 		plan.Reasons = appendUniqueReasonCode(plan.Reasons, contracts.ReasonCodeConflictFieldChangedBoth)
 	}
@@ -222,13 +467,41 @@ func validateIssueKeys(input IssueInput) (contracts.ReasonCode, string, bool) {
 	return "", "", false
 }
 
-func normalizeWritableFields(document issue.Document) normalizedWritableFields {
+// markdownContentEqual compares ADF-backed markdown ignoring what editors
+// tend to change on save: trailing spaces and the length of blank-line runs.
+// A cosmetic-only edit therefore plans no update and cannot trip the risk
+// check.
+func markdownContentEqual(left string, right string) bool {
+	return cosmeticWhitespaceKey(left) == cosmeticWhitespaceKey(right)
+}
+
+func cosmeticWhitespaceKey(value string) string {
+	lines := strings.Split(value, "\n")
+	kept := make([]string, 0, len(lines))
+	blank := false
+	for _, line := range lines {
+		line = strings.TrimRight(line, " \t")
+		if line == "" {
+			blank = true
+			continue
+		}
+		if blank && len(kept) > 0 {
+			kept = append(kept, "")
+		}
+		blank = false
+		kept = append(kept, line)
+	}
+	return strings.Join(kept, "\n")
+}
+
+func normalizeWritableFields(document issue.Document, preserveLabelCase bool) normalizedWritableFields {
 	return normalizedWritableFields{
 		Summary:     contracts.NormalizeSingleValue(contracts.NormalizationTrimOuterWhitespace, document.FrontMatter.Summary),
 		Description: contracts.NormalizeSingleValue(contracts.NormalizationNormalizeLineEndings, document.MarkdownBody),
-		Labels:      contracts.NormalizeLabels(document.FrontMatter.Labels),
+		Labels:      contracts.NormalizeLabelsWithCase(document.FrontMatter.Labels, preserveLabelCase),
 		Assignee:    contracts.NormalizeSingleValue(contracts.NormalizationTrimEmptyToNull, document.FrontMatter.Assignee),
 		Priority:    contracts.NormalizeSingleValue(contracts.NormalizationTrimAndTitleCase, document.FrontMatter.Priority),
 		Status:      contracts.NormalizeSingleValue(contracts.NormalizationTrimOuterWhitespace, document.FrontMatter.Status),
+		Environment: contracts.NormalizeSingleValue(contracts.NormalizationNormalizeLineEndings, document.EnvironmentMarkdown),
 	}
 }
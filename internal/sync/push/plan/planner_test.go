@@ -2,6 +2,7 @@ package plan
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/pweiskircher/jira-issue-sync/internal/contracts"
@@ -68,6 +69,62 @@ func TestBuildIssuePlanDetectsConflictAndKeepsSafeChanges(t *testing.T) {
 	}
 }
 
+func TestBuildIssuePlanExplainsOrthogonalLabelConflict(t *testing.T) {
+	base := testDocument("PROJ-1", "Same", "Body", "To Do", []string{"backend", "urgent"}, "", "", "")
+	local := testDocument("PROJ-1", "Same", "Body", "To Do", []string{"backend", "frontend", "urgent"}, "", "", "")
+	remote := testDocument("PROJ-1", "Same", "Body", "To Do", []string{"backend"}, "", "", "")
+
+	plan := BuildIssuePlan(IssueInput{Local: local, Original: &base, Remote: remote})
+
+	if len(plan.Conflicts) != 1 || plan.Conflicts[0].Field != contracts.JiraFieldLabels {
+		t.Fatalf("expected one label conflict, got %#v", plan.Conflicts)
+	}
+	message := plan.Conflicts[0].Message
+	for _, want := range []string{"independently", "local added frontend", "remote removed urgent"} {
+		if !strings.Contains(message, want) {
+			t.Fatalf("expected %q in conflict message, got %q", want, message)
+		}
+	}
+	if plan.Updates.Labels != nil {
+		t.Fatalf("expected no label update while conflicted, got %#v", plan.Updates.Labels)
+	}
+}
+
+func TestBuildIssuePlanNamesLabelsTouchedOnBothSides(t *testing.T) {
+	base := testDocument("PROJ-1", "Same", "Body", "To Do", []string{"backend"}, "", "", "")
+	local := testDocument("PROJ-1", "Same", "Body", "To Do", []string{"backend", "frontend", "ops"}, "", "", "")
+	remote := testDocument("PROJ-1", "Same", "Body", "To Do", []string{"frontend"}, "", "", "")
+
+	plan := BuildIssuePlan(IssueInput{Local: local, Original: &base, Remote: remote})
+
+	if len(plan.Conflicts) != 1 || !strings.Contains(plan.Conflicts[0].Message, "labels frontend were changed on both sides") {
+		t.Fatalf("expected overlapping label conflict, got %#v", plan.Conflicts)
+	}
+}
+
+func TestBuildIssuePlanPreservesLabelCaseWithoutSpuriousChanges(t *testing.T) {
+	base := testDocument("PROJ-1", "Same", "Body", "To Do", []string{"FrontEnd", "backend"}, "", "", "")
+	local := testDocument("PROJ-1", "Same", "Body", "To Do", []string{"backend", "FrontEnd"}, "", "", "")
+	remote := testDocument("PROJ-1", "Same", "Body", "To Do", []string{"FrontEnd", "backend"}, "", "", "")
+
+	plan := BuildIssuePlan(IssueInput{Local: local, Original: &base, Remote: remote, PreserveLabelCase: true})
+	if plan.Action != ActionNoop || plan.Updates.Labels != nil {
+		t.Fatalf("expected no change for case-preserved labels, got action=%s labels=%#v", plan.Action, plan.Updates.Labels)
+	}
+
+	// A case-only edit is a real change when case is preserved, and
+	// invisible under the default lowercasing.
+	local = testDocument("PROJ-1", "Same", "Body", "To Do", []string{"Backend", "FrontEnd"}, "", "", "")
+	plan = BuildIssuePlan(IssueInput{Local: local, Original: &base, Remote: remote, PreserveLabelCase: true})
+	if plan.Updates.Labels == nil || !reflect.DeepEqual(*plan.Updates.Labels, []string{"Backend", "FrontEnd"}) {
+		t.Fatalf("expected case-preserved label update, got %#v", plan.Updates.Labels)
+	}
+	plan = BuildIssuePlan(IssueInput{Local: local, Original: &base, Remote: remote})
+	if plan.Action != ActionNoop {
+		t.Fatalf("expected lowercased labels to compare equal, got action=%s labels=%#v", plan.Action, plan.Updates.Labels)
+	}
+}
+
 func TestBuildIssuePlanBlocksRiskyDescriptionWhenRawADFIsMissing(t *testing.T) {
 	base := testDocument("PROJ-1", "Summary", "Old", "To Do", nil, "", "", `{"version":1,"type":"doc","content":[]}`)
 	local := testDocument("PROJ-1", "Summary", "New", "To Do", nil, "", "", "")
@@ -130,6 +187,45 @@ func TestBuildIssuePlanBlocksRiskyDescriptionFromConverterSignals(t *testing.T)
 	}
 }
 
+func TestBuildIssuePlanAppliesDescriptionRiskPolicy(t *testing.T) {
+	base := testDocument("PROJ-1", "Summary", "Old", "To Do", nil, "", "", "")
+	local := testDocument("PROJ-1", "Summary", "New", "To Do", nil, "", "", "")
+	remote := testDocument("PROJ-1", "Summary", "Old", "To Do", nil, "", "", "")
+	risk := DescriptionRiskInput{
+		LocalRawADF:    RawADFStateValid,
+		ConverterRisks: []converter.RiskSignal{{ReasonCode: contracts.ReasonCodeADFUnknownNode, Message: "lossy conversion"}},
+	}
+
+	for _, policy := range []string{"", contracts.DescriptionRiskPolicyBlock} {
+		plan := BuildIssuePlan(IssueInput{Local: local, Original: &base, Remote: remote, DescriptionRisk: risk, DescriptionRiskPolicy: policy})
+		if plan.Updates.Description != nil || len(plan.Blocked) != 1 || len(plan.Accepted) != 0 || plan.Action != ActionBlocked {
+			t.Fatalf("policy %q: expected a blocked description, got %#v", policy, plan)
+		}
+	}
+
+	for _, policy := range []string{contracts.DescriptionRiskPolicyWarn, contracts.DescriptionRiskPolicyAllow} {
+		plan := BuildIssuePlan(IssueInput{Local: local, Original: &base, Remote: remote, DescriptionRisk: risk, DescriptionRiskPolicy: policy})
+		if plan.Updates.Description == nil || *plan.Updates.Description != "New" {
+			t.Fatalf("policy %q: expected the description update to be planned, got %#v", policy, plan.Updates)
+		}
+		if len(plan.Blocked) != 0 || plan.Action != ActionUpdate {
+			t.Fatalf("policy %q: expected a clean update, got action=%s blocked=%#v", policy, plan.Action, plan.Blocked)
+		}
+		want := []AcceptedRisk{{
+			Field:       contracts.JiraFieldDescription,
+			Policy:      policy,
+			ReasonCodes: []contracts.ReasonCode{contracts.ReasonCodeADFUnknownNode},
+			Message:     "description was pushed despite conversion risk (description_risk_policy=" + policy + ")",
+		}}
+		if !reflect.DeepEqual(plan.Accepted, want) {
+			t.Fatalf("policy %q: unexpected accepted risks: %#v", policy, plan.Accepted)
+		}
+		if plan.HasWarnedRisk() != (policy == contracts.DescriptionRiskPolicyWarn) {
+			t.Fatalf("policy %q: unexpected HasWarnedRisk", policy)
+		}
+	}
+}
+
 func TestBuildIssuePlanAllowsSafeDescriptionUpdate(t *testing.T) {
 	base := testDocument("PROJ-1", "Summary", "Old", "To Do", nil, "", "", "")
 	local := testDocument("PROJ-1", "Summary", "New", "To Do", nil, "", "", "")
@@ -152,6 +248,136 @@ func TestBuildIssuePlanAllowsSafeDescriptionUpdate(t *testing.T) {
 	}
 }
 
+func TestBuildIssuePlanIgnoresWhitespaceOnlyDescriptionEdit(t *testing.T) {
+	base := testDocument("PROJ-1", "Summary", "First paragraph\n\nSecond paragraph", "To Do", nil, "", "", "")
+	local := testDocument("PROJ-1", "Summary", "First paragraph   \n\n\n\nSecond paragraph\t\n", "To Do", nil, "", "", "")
+	remote := testDocument("PROJ-1", "Summary", "First paragraph\n\nSecond paragraph", "To Do", nil, "", "", "")
+
+	plan := BuildIssuePlan(IssueInput{
+		Local:    local,
+		Original: &base,
+		Remote:   remote,
+		DescriptionRisk: DescriptionRiskInput{
+			LocalRawADF: RawADFStateMissing,
+		},
+	})
+
+	if plan.Updates.Description != nil {
+		t.Fatalf("expected no description update for whitespace-only edit, got %q", *plan.Updates.Description)
+	}
+	if plan.Action != ActionNoop || len(plan.Blocked) != 0 {
+		t.Fatalf("expected noop without blocks, got action=%s blocked=%#v", plan.Action, plan.Blocked)
+	}
+
+	local.MarkdownBody = "First paragraph\n\nSecond paragraph, edited"
+	plan = BuildIssuePlan(IssueInput{Local: local, Original: &base, Remote: remote, DescriptionRisk: DescriptionRiskInput{LocalRawADF: RawADFStateValid}})
+	if plan.Updates.Description == nil || *plan.Updates.Description != "First paragraph\n\nSecond paragraph, edited" {
+		t.Fatalf("expected content edit to still update description, got %#v", plan.Updates.Description)
+	}
+}
+
+func TestBuildIssuePlanSkipsReadonlyStatusFromProfile(t *testing.T) {
+	base := testDocument("PROJ-1", "Old", "Body", "To Do", nil, "", "", "")
+	local := testDocument("PROJ-1", "New", "Body", "Done", nil, "", "", "")
+	remote := testDocument("PROJ-1", "Old", "Body", "To Do", nil, "", "", "")
+	readonly := contracts.ResolveReadonlyFields(contracts.ProjectProfile{ProjectKey: "PROJ", ReadonlyFields: []string{"status"}})
+
+	plan := BuildIssuePlan(IssueInput{Local: local, Original: &base, Remote: remote, ReadonlyFields: readonly})
+
+	if plan.Transition != nil {
+		t.Fatalf("expected no transition for read-only status, got %#v", plan.Transition)
+	}
+	if plan.Updates.Summary == nil || *plan.Updates.Summary != "New" {
+		t.Fatalf("expected summary update to remain, got %#v", plan.Updates.Summary)
+	}
+	if plan.Action != ActionUpdate {
+		t.Fatalf("skipped fields must not block the plan, got action=%s", plan.Action)
+	}
+	if len(plan.Skipped) != 1 || plan.Skipped[0].Field != contracts.JiraFieldStatus || plan.Skipped[0].ReasonCode != contracts.ReasonCodeFieldReadonlySkipped {
+		t.Fatalf("expected status skip, got %#v", plan.Skipped)
+	}
+
+	remote.FrontMatter.Status = "In Progress"
+	local.FrontMatter.Summary = "Old"
+	plan = BuildIssuePlan(IssueInput{Local: local, Original: &base, Remote: remote, ReadonlyFields: readonly})
+	if plan.Action != ActionNoop || len(plan.Conflicts) != 0 || len(plan.Skipped) != 1 {
+		t.Fatalf("expected read-only status to skip rather than conflict, got %#v", plan)
+	}
+}
+
+func TestBuildIssuePlanWarnsAboutLocalIssueTypeChange(t *testing.T) {
+	base := testDocument("PROJ-1", "Old", "Body", "To Do", nil, "", "", "")
+	base.FrontMatter.IssueType = "Task"
+	local := testDocument("PROJ-1", "New", "Body", "To Do", nil, "", "", "")
+	local.FrontMatter.IssueType = "Bug"
+	remote := base
+
+	plan := BuildIssuePlan(IssueInput{Local: local, Original: &base, Remote: remote})
+
+	if plan.Updates.Summary == nil || *plan.Updates.Summary != "New" || plan.Action != ActionUpdate {
+		t.Fatalf("expected the summary update to go ahead, got %#v", plan)
+	}
+	if len(plan.Skipped) != 1 {
+		t.Fatalf("expected one skipped read-only field, got %#v", plan.Skipped)
+	}
+	skipped := plan.Skipped[0]
+	if skipped.Field != contracts.JiraFieldIssueType || skipped.ReasonCode != contracts.ReasonCodeUnsupportedFieldIgnored || !skipped.Warning {
+		t.Fatalf("expected issue_type warning, got %#v", skipped)
+	}
+	if !strings.Contains(skipped.Message, `"Task" to "Bug"`) {
+		t.Fatalf("expected message to name both values, got %q", skipped.Message)
+	}
+
+	local.FrontMatter.IssueType = " Task "
+	if plan := BuildIssuePlan(IssueInput{Local: local, Original: &base, Remote: remote}); len(plan.Skipped) != 0 {
+		t.Fatalf("expected whitespace-only difference to be ignored, got %#v", plan.Skipped)
+	}
+}
+
+func TestBuildIssuePlanHonorsWritableFieldAllowList(t *testing.T) {
+	base := testDocument("PROJ-1", "Old", "Body", "To Do", nil, "", "Low", "")
+	local := testDocument("PROJ-1", "New", "Body", "To Do", nil, "", "High", "")
+	remote := testDocument("PROJ-1", "Old", "Body", "To Do", nil, "", "Low", "")
+	readonly := contracts.ResolveReadonlyFields(contracts.ProjectProfile{ProjectKey: "PROJ", WritableFields: []string{"Summary"}})
+
+	plan := BuildIssuePlan(IssueInput{Local: local, Original: &base, Remote: remote, ReadonlyFields: readonly})
+
+	if plan.Updates.Summary == nil || plan.Updates.Priority != nil {
+		t.Fatalf("expected only the allowed summary update, got %#v", plan.Updates)
+	}
+	if len(plan.Skipped) != 1 || plan.Skipped[0].Field != contracts.JiraFieldPriority {
+		t.Fatalf("expected priority skip, got %#v", plan.Skipped)
+	}
+}
+
+func TestBuildIssuePlanBlocksOverlongSummaryButKeepsOtherUpdates(t *testing.T) {
+	longSummary := strings.Repeat("é", contracts.MaxSummaryLength+1)
+	base := testDocument("PROJ-1", "Old", "Body", "To Do", nil, "", "Low", "")
+	local := testDocument("PROJ-1", longSummary, "Body", "To Do", nil, "", "High", "")
+	remote := testDocument("PROJ-1", "Old", "Body", "To Do", nil, "", "Low", "")
+
+	plan := BuildIssuePlan(IssueInput{Local: local, Original: &base, Remote: remote})
+
+	if plan.Updates.Summary != nil {
+		t.Fatalf("expected over-length summary to stay out of the update, got %q", *plan.Updates.Summary)
+	}
+	if plan.Updates.Priority == nil || *plan.Updates.Priority != "High" {
+		t.Fatalf("expected priority update to remain, got %#v", plan.Updates.Priority)
+	}
+	if len(plan.Blocked) != 1 || plan.Blocked[0].Field != contracts.JiraFieldSummary || !reflect.DeepEqual(plan.Blocked[0].ReasonCodes, []contracts.ReasonCode{contracts.ReasonCodeSummaryTooLong}) {
+		t.Fatalf("expected summary_too_long block, got %#v", plan.Blocked)
+	}
+	if plan.Action != ActionUpdatePartial {
+		t.Fatalf("unexpected action: got=%s want=%s", plan.Action, ActionUpdatePartial)
+	}
+
+	local.FrontMatter.Summary = strings.Repeat("é", contracts.MaxSummaryLength)
+	plan = BuildIssuePlan(IssueInput{Local: local, Original: &base, Remote: remote})
+	if plan.Updates.Summary == nil || len(plan.Blocked) != 0 {
+		t.Fatalf("expected a summary at the limit to push, got %#v", plan)
+	}
+}
+
 func TestBuildIssuePlanValidatesConsistentIssueKeys(t *testing.T) {
 	base := testDocument("PROJ-1", "Summary", "Body", "To Do", nil, "", "", "")
 	local := testDocument("PROJ-1", "Summary", "Body", "To Do", nil, "", "", "")
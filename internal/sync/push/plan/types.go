@@ -39,6 +39,21 @@ type IssueInput struct {
 	Original        *issue.Document
 	Remote          issue.Document
 	DescriptionRisk DescriptionRiskInput
+	// EnvironmentRisk applies the description risk gate to the ADF-backed
+	// environment field.
+	EnvironmentRisk DescriptionRiskInput
+	// ReadonlyFields are writable fields the profile denies to push; they
+	// plan no update or transition.
+	ReadonlyFields map[contracts.JiraField]bool
+	// DescriptionRiskPolicy is a contracts.DescriptionRiskPolicy* value.
+	// Empty means block.
+	DescriptionRiskPolicy string
+	// AssigneeIsAccountID treats every assignee value as a literal
+	// accountId; see contracts.ParseAssigneeAccountID.
+	AssigneeIsAccountID bool
+	// PreserveLabelCase compares labels case-sensitively instead of
+	// lowercasing all three sides first.
+	PreserveLabelCase bool
 }
 
 // UpdateSet contains safe, conflict-free writable field updates.
@@ -48,6 +63,7 @@ type UpdateSet struct {
 	Labels      *[]string
 	Assignee    *string
 	Priority    *string
+	Environment *string
 }
 
 // TransitionPlan captures a desired status transition.
@@ -69,6 +85,24 @@ type BlockedField struct {
 	Message     string
 }
 
+// SkippedField records a local change that was deliberately not planned.
+type SkippedField struct {
+	Field      contracts.JiraField
+	ReasonCode contracts.ReasonCode
+	Message    string
+	// Warning reports the skip as a warning rather than info.
+	Warning bool
+}
+
+// AcceptedRisk records a risky ADF-backed update planned anyway because the
+// description risk policy is warn or allow.
+type AcceptedRisk struct {
+	Field       contracts.JiraField
+	Policy      string
+	ReasonCodes []contracts.ReasonCode
+	Message     string
+}
+
 // IssuePlan is an actionable deterministic plan for one issue.
 type IssuePlan struct {
 	Key        string
@@ -77,9 +111,22 @@ type IssuePlan struct {
 	Transition *TransitionPlan
 	Conflicts  []FieldConflict
 	Blocked    []BlockedField
+	Skipped    []SkippedField
+	Accepted   []AcceptedRisk
 	Reasons    []contracts.ReasonCode
 }
 
+// HasWarnedRisk reports whether a risky update was accepted under the warn
+// policy, which makes an otherwise clean push a warning.
+func (plan IssuePlan) HasWarnedRisk() bool {
+	for _, accepted := range plan.Accepted {
+		if accepted.Policy == contracts.DescriptionRiskPolicyWarn {
+			return true
+		}
+	}
+	return false
+}
+
 func (plan IssuePlan) HasExecutableChanges() bool {
 	if plan.Transition != nil {
 		return true
@@ -88,14 +135,17 @@ func (plan IssuePlan) HasExecutableChanges() bool {
 		plan.Updates.Description != nil ||
 		plan.Updates.Labels != nil ||
 		plan.Updates.Assignee != nil ||
-		plan.Updates.Priority != nil
+		plan.Updates.Priority != nil ||
+		plan.Updates.Environment != nil
 }
 
 func (plan IssuePlan) HasConflictsOrBlocks() bool {
 	return len(plan.Conflicts) > 0 || len(plan.Blocked) > 0
 }
 
-func resolveAction(plan IssuePlan) Action {
+// ResolveAction classifies a plan from its executable changes, conflicts, and
+// blocks. Callers that narrow a plan after planning use it to reclassify.
+func ResolveAction(plan IssuePlan) Action {
 	hasChanges := plan.HasExecutableChanges()
 	hasBlocks := plan.HasConflictsOrBlocks()
 
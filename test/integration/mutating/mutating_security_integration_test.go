@@ -33,7 +33,7 @@ func TestMutatingCommandsEnforceLockAndRecoverStaleLock(t *testing.T) {
 			command: contracts.CommandInit,
 			prepareRun: func(t *testing.T, workspace string) (func(context.Context) error, func(t *testing.T)) {
 				run := func(context.Context) error {
-					_, err := commands.RunInit(workspace, commands.InitOptions{ProjectKey: "PROJ", Profile: "default"})
+					_, err := commands.RunInit(context.Background(), workspace, commands.InitOptions{ProjectKey: "PROJ", Profile: "default"})
 					return err
 				}
 				verify := func(t *testing.T) {
@@ -412,10 +412,30 @@ func (s *integrationAdapterStub) SearchIssues(_ context.Context, request jira.Se
 	return jira.SearchIssuesResponse{StartAt: request.StartAt, Total: len(s.pullIssues), Issues: s.pullIssues}, nil
 }
 
+func (s *integrationAdapterStub) ValidateJQL(context.Context, string) error {
+	return nil
+}
+
 func (s *integrationAdapterStub) ListFields(context.Context) ([]jira.FieldDefinition, error) {
 	return nil, nil
 }
 
+func (s *integrationAdapterStub) ListProjects(context.Context) ([]jira.Project, error) {
+	return nil, nil
+}
+
+func (s *integrationAdapterStub) ListStatuses(context.Context) ([]jira.StatusRef, error) {
+	return nil, nil
+}
+
+func (s *integrationAdapterStub) ListSecurityLevels(context.Context, string) ([]jira.NamedRef, error) {
+	return nil, nil
+}
+
+func (s *integrationAdapterStub) GetMyself(context.Context) (jira.AccountRef, error) {
+	return jira.AccountRef{}, nil
+}
+
 func (s *integrationAdapterStub) GetIssue(_ context.Context, issueKey string, _ []string) (jira.Issue, error) {
 	s.getCalls++
 	if issue, ok := s.issues[issueKey]; ok {
@@ -456,3 +476,7 @@ func (s *integrationAdapterStub) ResolveTransition(_ context.Context, issueKey s
 	}
 	return jira.TransitionResolution{Kind: jira.TransitionResolutionUnavailable, ReasonCode: contracts.ReasonCodeTransitionUnavailable}, nil
 }
+
+func (s *integrationAdapterStub) SearchAssignableUsers(context.Context, string, string) ([]jira.AccountRef, error) {
+	panic("unexpected call")
+}
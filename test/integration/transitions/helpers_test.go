@@ -99,10 +99,30 @@ func (s *transitionAdapterStub) SearchIssues(context.Context, jira.SearchIssuesR
 	return jira.SearchIssuesResponse{}, nil
 }
 
+func (s *transitionAdapterStub) ValidateJQL(context.Context, string) error {
+	return nil
+}
+
 func (s *transitionAdapterStub) ListFields(context.Context) ([]jira.FieldDefinition, error) {
 	return nil, nil
 }
 
+func (s *transitionAdapterStub) ListProjects(context.Context) ([]jira.Project, error) {
+	return nil, nil
+}
+
+func (s *transitionAdapterStub) ListStatuses(context.Context) ([]jira.StatusRef, error) {
+	return nil, nil
+}
+
+func (s *transitionAdapterStub) ListSecurityLevels(context.Context, string) ([]jira.NamedRef, error) {
+	return nil, nil
+}
+
+func (s *transitionAdapterStub) GetMyself(context.Context) (jira.AccountRef, error) {
+	return jira.AccountRef{}, nil
+}
+
 func (s *transitionAdapterStub) GetIssue(_ context.Context, issueKey string, _ []string) (jira.Issue, error) {
 	if issue, ok := s.issues[issueKey]; ok {
 		return issue, nil
@@ -136,3 +156,7 @@ func (s *transitionAdapterStub) ResolveTransition(_ context.Context, issueKey st
 	}
 	return jira.TransitionResolution{Kind: jira.TransitionResolutionUnavailable, ReasonCode: contracts.ReasonCodeTransitionUnavailable}, nil
 }
+
+func (s *transitionAdapterStub) SearchAssignableUsers(context.Context, string, string) ([]jira.AccountRef, error) {
+	panic("unexpected call")
+}